@@ -2,11 +2,16 @@ package main
 
 import (
 	"collab-consumer/internal/cache"
+	"collab-consumer/internal/digest"
+	"collab-consumer/internal/handler"
 	"collab-consumer/internal/kafka"
+	"collab-consumer/internal/mailer"
+	"collab-consumer/internal/userclient"
 	"context"
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -24,6 +29,29 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// newMailer chọn cách gửi email theo MAILER: smtp, hoặc file (mặc định) ghi .eml vào MAIL_SINK_DIR
+func newMailer() mailer.Mailer {
+	from := getEnv("MAIL_FROM", "Collab <no-reply@collab.local>")
+	switch getEnv("MAILER", "file") {
+	case "smtp":
+		return mailer.NewSMTPMailer(getEnv("SMTP_HOST", "localhost"), getEnv("SMTP_PORT", "587"), getEnv("SMTP_USERNAME", ""), getEnv("SMTP_PASSWORD", ""), from)
+	case "file":
+		return mailer.NewFileMailer(getEnv("MAIL_SINK_DIR", "./mail"), from)
+	default:
+		log.Fatalf("Unknown MAILER %q, expected smtp or file", getEnv("MAILER", "file"))
+		return nil
+	}
+}
+
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, true
+		}
+	}
+	return time.Sunday, false
+}
+
 func main() {
 	// Initialize Redis
 	redisHost := getEnv("REDIS_HOST", "localhost")
@@ -55,18 +83,43 @@ func main() {
 	topic2 := getEnv("KAFKA_TOPIC_ASSET", "asset.changes")
 	groupID2 := getEnv("KAFKA_GROUP_ID", "collab-consumer-group")
 
+	// Email delivery: gửi ngay hoặc gom digest theo lựa chọn của từng user
+	digestHour, err := strconv.Atoi(getEnv("DIGEST_HOUR", "8"))
+	if err != nil || digestHour < 0 || digestHour > 23 {
+		log.Fatalf("Invalid DIGEST_HOUR %q, expected 0-23", getEnv("DIGEST_HOUR", "8"))
+	}
+	digestWeekday, ok := parseWeekday(getEnv("DIGEST_WEEKDAY", "monday"))
+	if !ok {
+		log.Fatalf("Invalid DIGEST_WEEKDAY %q", getEnv("DIGEST_WEEKDAY", "monday"))
+	}
+	digestWorker := digest.NewWorker(
+		cache.NewPreferenceCache(cache.GetRedisClient()),
+		cache.NewDigestQueue(cache.GetRedisClient()),
+		userclient.NewClient(getEnv("USER_SERVICE_ENDPOINT", "http://localhost:4000/query")),
+		digest.NewRenderer(getEnv("APP_BASE_URL", "http://localhost:3000")),
+		newMailer(),
+		digestHour,
+		digestWeekday,
+	)
+	notificationHandler := handler.NewNotificationHandler(cache.NewNotificationInbox(cache.GetRedisClient()), digestWorker)
+
 	log.Println("Starting consumers...")
 
 	// Start consumers for different topics
-	wg.Add(2)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		kafka.StartTeamConsumer(ctx, brokers, topic1, groupID1, notificationHandler)
+	}()
+
 	go func() {
 		defer wg.Done()
-		kafka.StartTeamConsumer(ctx, brokers, topic1, groupID1)
+		kafka.StartAssetChangesConsumer(ctx, brokers, topic2, groupID2, notificationHandler)
 	}()
 
 	go func() {
 		defer wg.Done()
-		kafka.StartAssetChangesConsumer(ctx, brokers, topic2, groupID2)
+		digestWorker.Run(ctx)
 	}()
 
 	// Set up signal handling for graceful shutdown
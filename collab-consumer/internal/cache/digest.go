@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// maxDigestItems giới hạn số thông báo chờ trong một digest, giữ lại các thông báo mới nhất
+const maxDigestItems = 200
+
+// DigestQueue gom thông báo chờ gửi theo digest:
+//
+//	digest:{period}:pending         SET các user có thông báo đang chờ
+//	digest:{period}:items:{userId}  LIST JSON của Notification, cũ trước
+//	digest:{period}:slot            slot (ngày) đã được gửi gần nhất
+type DigestQueue struct {
+	rdb *redis.Client
+}
+
+func NewDigestQueue(rdb *redis.Client) *DigestQueue {
+	return &DigestQueue{rdb: rdb}
+}
+
+func digestKey(period, suffix string) string {
+	return fmt.Sprintf("digest:%s:%s", period, suffix)
+}
+
+// Push thêm thông báo vào digest của user
+func (q *DigestQueue) Push(ctx context.Context, period, userID string, items ...*Notification) error {
+	values := make([]interface{}, len(items))
+	for i, n := range items {
+		payload, err := json.Marshal(n)
+		if err != nil {
+			return err
+		}
+		values[i] = payload
+	}
+
+	itemsKey := digestKey(period, "items:"+userID)
+	_, err := q.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, itemsKey, values...)
+		pipe.LTrim(ctx, itemsKey, -maxDigestItems, -1)
+		pipe.SAdd(ctx, digestKey(period, "pending"), userID)
+		return nil
+	})
+	return err
+}
+
+// PopUser lấy ra một user đang có digest chờ gửi, trả về "" khi không còn ai.
+// SPOP đảm bảo mỗi user chỉ được một instance consumer xử lý.
+func (q *DigestQueue) PopUser(ctx context.Context, period string) (string, error) {
+	userID, err := q.rdb.SPop(ctx, digestKey(period, "pending")).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return userID, err
+}
+
+// Drain lấy và xoá toàn bộ thông báo đang chờ trong digest của user
+func (q *DigestQueue) Drain(ctx context.Context, period, userID string) ([]*Notification, error) {
+	itemsKey := digestKey(period, "items:"+userID)
+
+	var values *redis.StringSliceCmd
+	_, err := q.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		values = pipe.LRange(ctx, itemsKey, 0, -1)
+		pipe.Del(ctx, itemsKey)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*Notification, 0, len(values.Val()))
+	for _, raw := range values.Val() {
+		var n Notification
+		if err := json.Unmarshal([]byte(raw), &n); err != nil {
+			return nil, err
+		}
+		items = append(items, &n)
+	}
+	return items, nil
+}
+
+// ClaimSlot ghi nhận slot của period và trả về true nếu instance này là instance đầu tiên thấy slot đó
+func (q *DigestQueue) ClaimSlot(ctx context.Context, period, slot string) (bool, error) {
+	previous, err := q.rdb.SetArgs(ctx, digestKey(period, "slot"), slot, redis.SetArgs{Get: true}).Result()
+	if err == redis.Nil {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return previous != slot, nil
+}
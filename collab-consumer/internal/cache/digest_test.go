@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDigestQueueClaimSlot(t *testing.T) {
+	_, rdb := newFakeRedis(t)
+	queue := NewDigestQueue(rdb)
+	ctx := context.Background()
+
+	steps := []struct {
+		period string
+		slot   string
+		want   bool
+	}{
+		{DeliveryDaily, "2025-10-14", true},
+		// Instance khác (hoặc tick sau) thấy cùng slot thì không gửi lại
+		{DeliveryDaily, "2025-10-14", false},
+		{DeliveryDaily, "2025-10-14", false},
+		// Slot của period khác được claim riêng
+		{DeliveryWeekly, "2025-10-13", true},
+		{DeliveryWeekly, "2025-10-13", false},
+		{DeliveryDaily, "2025-10-15", true},
+		{DeliveryDaily, "2025-10-15", false},
+	}
+
+	for i, step := range steps {
+		claimed, err := queue.ClaimSlot(ctx, step.period, step.slot)
+		if err != nil {
+			t.Fatalf("step %d: ClaimSlot: %v", i, err)
+		}
+		if claimed != step.want {
+			t.Errorf("step %d: ClaimSlot(%s, %s) = %v, want %v", i, step.period, step.slot, claimed, step.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeRedis là server RESP2 tối giản trong bộ nhớ, chỉ hỗ trợ các lệnh cache trong package này dùng tới
+type fakeRedis struct {
+	listener net.Listener
+	mu       sync.Mutex
+	strings  map[string]string
+	hashes   map[string]map[string]string
+}
+
+// newFakeRedis khởi động server giả và trả về client trỏ tới nó; server đóng khi test kết thúc
+func newFakeRedis(t *testing.T) (*fakeRedis, *redis.Client) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeRedis{listener: listener, strings: make(map[string]string), hashes: make(map[string]map[string]string)}
+	go f.serve()
+
+	rdb := redis.NewClient(&redis.Options{Addr: listener.Addr().String(), DisableIdentity: true, MaxRetries: -1})
+	t.Cleanup(func() {
+		_ = rdb.Close()
+		f.Close()
+	})
+	return f, rdb
+}
+
+func (f *fakeRedis) Close() {
+	_ = f.listener.Close()
+}
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		if _, err := io.WriteString(conn, f.exec(args)); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) exec(args []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "HSET":
+		hash := f.hashes[args[1]]
+		if hash == nil {
+			hash = make(map[string]string)
+			f.hashes[args[1]] = hash
+		}
+		added := 0
+		for i := 2; i+1 < len(args); i += 2 {
+			if _, ok := hash[args[i]]; !ok {
+				added++
+			}
+			hash[args[i]] = args[i+1]
+		}
+		return fmt.Sprintf(":%d\r\n", added)
+	case "HGET":
+		value, ok := f.hashes[args[1]][args[2]]
+		return bulk(value, ok)
+	case "SET":
+		// Chỉ hỗ trợ SET key value [GET]
+		previous, ok := f.strings[args[1]]
+		f.strings[args[1]] = args[2]
+		if len(args) > 3 && strings.EqualFold(args[3], "GET") {
+			return bulk(previous, ok)
+		}
+		return "+OK\r\n"
+	default:
+		// HELLO cũng rơi vào đây: go-redis coi lỗi của server là không hỗ trợ RESP3 và dùng RESP2
+		return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+	}
+}
+
+func bulk(value string, ok bool) string {
+	if !ok {
+		return "$-1\r\n"
+	}
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+}
+
+// readCommand đọc một lệnh dạng mảng bulk string
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected command %q", line)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
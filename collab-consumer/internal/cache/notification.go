@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Layout inbox dùng chung với collab-service (internal/infrastructure/external/cache/notification_store.go):
+//
+//	notifications:{userId}         ZSET id -> thời điểm tạo (unix ms), mới nhất điểm cao nhất
+//	notifications:{userId}:items   HASH id -> JSON của Notification
+//	notifications:{userId}:unread  SET các id chưa đọc
+const (
+	// MaxInboxSize là số thông báo giữ lại cho mỗi user, cũ hơn bị cắt bỏ
+	MaxInboxSize = 500
+	inboxTTL     = 90 * 24 * time.Hour
+)
+
+type Notification struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	ActorID   string `json:"actorId,omitempty"`
+	AssetType string `json:"assetType,omitempty"`
+	AssetID   string `json:"assetId,omitempty"`
+	CommentID string `json:"commentId,omitempty"`
+	TeamID    string `json:"teamId,omitempty"`
+	ExpiresAt string `json:"expiresAt,omitempty"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type NotificationInbox struct {
+	rdb     *redis.Client
+	maxSize int64
+}
+
+func NewNotificationInbox(rdb *redis.Client) *NotificationInbox {
+	return &NotificationInbox{rdb: rdb, maxSize: MaxInboxSize}
+}
+
+func inboxKey(userID string) string {
+	return fmt.Sprintf("notifications:%s", userID)
+}
+
+func inboxItemsKey(userID string) string {
+	return fmt.Sprintf("notifications:%s:items", userID)
+}
+
+func inboxUnreadKey(userID string) string {
+	return fmt.Sprintf("notifications:%s:unread", userID)
+}
+
+// Add thêm thông báo chưa đọc vào inbox của user, trả về false nếu thông báo đã có.
+// ID trùng (message Kafka bị giao lại) được bỏ qua để không tạo thông báo đôi hoặc đánh dấu lại là chưa đọc.
+func (c *NotificationInbox) Add(ctx context.Context, userID string, n *Notification, createdAt time.Time) (bool, error) {
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return false, err
+	}
+
+	added, err := c.rdb.HSetNX(ctx, inboxItemsKey(userID), n.ID, payload).Result()
+	if err != nil || !added {
+		return false, err
+	}
+
+	_, err = c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, inboxKey(userID), redis.Z{Score: float64(createdAt.UnixMilli()), Member: n.ID})
+		pipe.SAdd(ctx, inboxUnreadKey(userID), n.ID)
+		pipe.Expire(ctx, inboxKey(userID), inboxTTL)
+		pipe.Expire(ctx, inboxItemsKey(userID), inboxTTL)
+		pipe.Expire(ctx, inboxUnreadKey(userID), inboxTTL)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, c.trim(ctx, userID)
+}
+
+// trim bỏ các thông báo cũ nhất khi inbox vượt maxSize
+func (c *NotificationInbox) trim(ctx context.Context, userID string) error {
+	stale, err := c.rdb.ZRange(ctx, inboxKey(userID), 0, -c.maxSize-1).Result()
+	if err != nil || len(stale) == 0 {
+		return err
+	}
+
+	members := make([]interface{}, len(stale))
+	for i, id := range stale {
+		members[i] = id
+	}
+	_, err = c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, inboxKey(userID), members...)
+		pipe.HDel(ctx, inboxItemsKey(userID), stale...)
+		pipe.SRem(ctx, inboxUnreadKey(userID), members...)
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Chế độ gửi thông báo qua email, user chọn riêng cho từng loại event qua collab-service
+const (
+	DeliveryInstant = "instant"
+	DeliveryDaily   = "daily"
+	DeliveryWeekly  = "weekly"
+	DeliveryOff     = "off"
+
+	// DefaultDelivery áp dụng cho loại event user chưa chọn
+	DefaultDelivery = DeliveryDaily
+)
+
+// PreferenceCache đọc lựa chọn gửi thông báo của user, lưu bởi collab-service tại
+// HASH notification_prefs:{userId} (loại event -> chế độ gửi), không có TTL
+type PreferenceCache struct {
+	rdb *redis.Client
+}
+
+func NewPreferenceCache(rdb *redis.Client) *PreferenceCache {
+	return &PreferenceCache{rdb: rdb}
+}
+
+func preferenceKey(userID string) string {
+	return fmt.Sprintf("notification_prefs:%s", userID)
+}
+
+// GetDelivery trả về chế độ gửi của user cho loại event, mặc định DefaultDelivery
+func (c *PreferenceCache) GetDelivery(ctx context.Context, userID, eventType string) (string, error) {
+	mode, err := c.rdb.HGet(ctx, preferenceKey(userID), eventType).Result()
+	if err == redis.Nil {
+		return DefaultDelivery, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	switch mode {
+	case DeliveryInstant, DeliveryDaily, DeliveryWeekly, DeliveryOff:
+		return mode, nil
+	default:
+		return DefaultDelivery, nil
+	}
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPreferenceCacheGetDelivery(t *testing.T) {
+	_, rdb := newFakeRedis(t)
+	ctx := context.Background()
+	if err := rdb.HSet(ctx, preferenceKey("user-1"),
+		"COMMENT_ADDED", DeliveryInstant,
+		"NOTE_SHARED", DeliveryOff,
+		"USER_MENTIONED", "hourly",
+	).Err(); err != nil {
+		t.Fatal(err)
+	}
+	prefs := NewPreferenceCache(rdb)
+
+	tests := []struct {
+		name      string
+		userID    string
+		eventType string
+		want      string
+	}{
+		{"chosen mode", "user-1", "COMMENT_ADDED", DeliveryInstant},
+		{"off", "user-1", "NOTE_SHARED", DeliveryOff},
+		{"unknown mode falls back to the default", "user-1", "USER_MENTIONED", DefaultDelivery},
+		{"event type without a choice", "user-1", "MEMBER_ADDED", DefaultDelivery},
+		{"user without preferences", "user-2", "COMMENT_ADDED", DefaultDelivery},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := prefs.GetDelivery(ctx, tt.userID, tt.eventType)
+			if err != nil {
+				t.Fatalf("GetDelivery: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetDelivery(%s, %s) = %q, want %q", tt.userID, tt.eventType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreferenceCacheGetDeliveryRedisDown(t *testing.T) {
+	server, rdb := newFakeRedis(t)
+	server.Close()
+
+	// Lỗi Redis được trả về để Worker.Deliver tự dùng DefaultDelivery, không bị nuốt thành chế độ mặc định ở đây
+	mode, err := NewPreferenceCache(rdb).GetDelivery(context.Background(), "user-1", "COMMENT_ADDED")
+	if err == nil {
+		t.Fatalf("GetDelivery = %q, want an error when Redis is unreachable", mode)
+	}
+}
@@ -0,0 +1,161 @@
+package digest
+
+import (
+	"bytes"
+	"collab-consumer/internal/cache"
+	"collab-consumer/internal/userclient"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var (
+	htmlTemplate = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/digest.html.tmpl"))
+	textTemplate = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/digest.txt.tmpl"))
+)
+
+type itemView struct {
+	Text string
+	Link string
+	Time string
+}
+
+type digestView struct {
+	Recipient    string
+	Intro        string
+	Items        []itemView
+	SettingsLink string
+}
+
+// systemActor là actor của thay đổi do job nền của collab-service thực hiện, ví dụ share hoặc membership hết hạn
+const systemActor = "system"
+
+// Renderer dựng nội dung email từ các thông báo, link trỏ về web app tại baseURL
+type Renderer struct {
+	baseURL string
+}
+
+func NewRenderer(baseURL string) *Renderer {
+	return &Renderer{baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// Render trả về subject, phần text và phần HTML của email; period rỗng nghĩa là email gửi ngay
+func (r *Renderer) Render(period string, recipient *userclient.User, items []*cache.Notification, users map[string]*userclient.User) (string, string, string, error) {
+	view := digestView{
+		Recipient:    recipient.Username,
+		SettingsLink: r.baseURL + "/settings/notifications",
+	}
+	for _, n := range items {
+		view.Items = append(view.Items, itemView{
+			Text: describe(n, users),
+			Link: r.link(n),
+			Time: formatTime(n.CreatedAt),
+		})
+	}
+
+	var subject string
+	switch period {
+	case cache.DeliveryDaily:
+		subject = fmt.Sprintf("Your daily digest: %d new notification%s", len(items), plural(len(items)))
+		view.Intro = "Here is what happened since yesterday's digest:"
+	case cache.DeliveryWeekly:
+		subject = fmt.Sprintf("Your weekly digest: %d new notification%s", len(items), plural(len(items)))
+		view.Intro = "Here is what happened since last week's digest:"
+	default:
+		subject = view.Items[0].Text
+		view.Intro = "You have a new notification:"
+	}
+
+	var text, html bytes.Buffer
+	if err := textTemplate.Execute(&text, view); err != nil {
+		return "", "", "", err
+	}
+	if err := htmlTemplate.Execute(&html, view); err != nil {
+		return "", "", "", err
+	}
+	return subject, text.String(), html.String(), nil
+}
+
+func (r *Renderer) link(n *cache.Notification) string {
+	switch {
+	case n.TeamID != "":
+		return fmt.Sprintf("%s/teams/%s", r.baseURL, n.TeamID)
+	case n.AssetType == "FOLDER":
+		return fmt.Sprintf("%s/folders/%s", r.baseURL, n.AssetID)
+	case n.CommentID != "":
+		return fmt.Sprintf("%s/notes/%s#comment-%s", r.baseURL, n.AssetID, n.CommentID)
+	default:
+		return fmt.Sprintf("%s/notes/%s", r.baseURL, n.AssetID)
+	}
+}
+
+func describe(n *cache.Notification, users map[string]*userclient.User) string {
+	actor := "Someone"
+	if user, ok := users[n.ActorID]; ok {
+		actor = user.Username
+	}
+
+	switch n.Type {
+	case "USER_MENTIONED":
+		if n.CommentID != "" {
+			return actor + " mentioned you in a comment"
+		}
+		return actor + " mentioned you in a note"
+	case "COMMENT_ADDED":
+		return actor + " commented on your note"
+	case "NOTE_SHARED":
+		return actor + " shared a note with you"
+	case "FOLDER_SHARED":
+		return actor + " shared a folder with you"
+	case "ACCESS_REQUESTED":
+		return fmt.Sprintf("%s requested access to your %s", actor, strings.ToLower(n.AssetType))
+	case "ACCESS_REQUEST_APPROVED":
+		return fmt.Sprintf("%s approved your request for access to a %s", actor, strings.ToLower(n.AssetType))
+	case "ACCESS_REQUEST_DENIED":
+		return fmt.Sprintf("%s denied your request for access to a %s", actor, strings.ToLower(n.AssetType))
+	case "NOTE_UNSHARED", "FOLDER_UNSHARED":
+		asset := strings.ToLower(n.AssetType)
+		if n.ActorID == systemActor {
+			return fmt.Sprintf("Your access to a %s has expired", asset)
+		}
+		return fmt.Sprintf("%s removed your access to a %s", actor, asset)
+	case "SHARE_EXPIRING":
+		return fmt.Sprintf("Your access to a %s expires %s", strings.ToLower(n.AssetType), formatTime(n.ExpiresAt))
+	case "MEMBER_ADDED":
+		return actor + " added you to a team"
+	case "MEMBER_REMOVED":
+		if n.ActorID == systemActor {
+			return "Your team membership has expired"
+		}
+		return actor + " removed you from a team"
+	case "MANAGER_ADDED":
+		return actor + " made you a manager of a team"
+	case "TEAM_ARCHIVED":
+		return actor + " archived a team you belong to"
+	case "TEAM_RESTORED":
+		return actor + " restored a team you belong to"
+	default:
+		return fmt.Sprintf("%s: %s", actor, n.Type)
+	}
+}
+
+func formatTime(raw string) string {
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return raw
+	}
+	return t.UTC().Format("Jan 2, 15:04 UTC")
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
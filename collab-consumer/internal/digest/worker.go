@@ -0,0 +1,177 @@
+package digest
+
+import (
+	"collab-consumer/internal/cache"
+	"collab-consumer/internal/mailer"
+	"collab-consumer/internal/userclient"
+	"context"
+	"log"
+	"time"
+)
+
+const sendTimeout = 30 * time.Second
+
+// Worker gửi thông báo qua email theo lựa chọn của người nhận: gửi ngay, gom vào digest ngày/tuần, hoặc không gửi.
+// Digest ngày được gửi lúc hour giờ (UTC), digest tuần lúc hour giờ của weekday.
+type Worker struct {
+	prefs    *cache.PreferenceCache
+	queue    *cache.DigestQueue
+	users    *userclient.Client
+	renderer *Renderer
+	mailer   mailer.Mailer
+	hour     int
+	weekday  time.Weekday
+}
+
+func NewWorker(prefs *cache.PreferenceCache, queue *cache.DigestQueue, users *userclient.Client, renderer *Renderer, m mailer.Mailer, hour int, weekday time.Weekday) *Worker {
+	return &Worker{
+		prefs:    prefs,
+		queue:    queue,
+		users:    users,
+		renderer: renderer,
+		mailer:   m,
+		hour:     hour,
+		weekday:  weekday,
+	}
+}
+
+// Deliver chuyển thông báo mới của recipient sang kênh email
+func (w *Worker) Deliver(ctx context.Context, recipient string, n *cache.Notification) {
+	mode, err := w.prefs.GetDelivery(ctx, recipient, n.Type)
+	if err != nil {
+		log.Printf("⚠️ Failed to read notification preferences of user %s: %v", recipient, err)
+		mode = cache.DefaultDelivery
+	}
+
+	switch mode {
+	case cache.DeliveryOff:
+		return
+	case cache.DeliveryInstant:
+		err := w.send(ctx, "", recipient, []*cache.Notification{n})
+		if err == nil {
+			return
+		}
+		// Không gửi được ngay thì vẫn còn digest ngày, thông báo không bị mất
+		log.Printf("❌ Failed to email %s notification to user %s, falling back to daily digest: %v", n.Type, recipient, err)
+		mode = cache.DeliveryDaily
+	}
+
+	if err := w.queue.Push(ctx, mode, recipient, n); err != nil {
+		log.Printf("❌ Failed to queue %s notification for user %s: %v", n.Type, recipient, err)
+	}
+}
+
+// Run kiểm tra lịch gửi digest mỗi phút cho tới khi ctx bị huỷ
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	log.Printf("Digest worker started, daily at %02d:00 UTC, weekly on %s", w.hour, w.weekday)
+	for {
+		w.tick(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) tick(ctx context.Context, now time.Time) {
+	for _, period := range []string{cache.DeliveryDaily, cache.DeliveryWeekly} {
+		// Chỉ instance đầu tiên thấy slot mới gửi digest của slot đó
+		claimed, err := w.queue.ClaimSlot(ctx, period, w.slot(period, now))
+		if err != nil {
+			log.Printf("❌ Failed to check %s digest schedule: %v", period, err)
+			continue
+		}
+		if claimed {
+			w.flush(ctx, period)
+		}
+	}
+}
+
+// slot là ngày của lần gửi gần nhất (không sau now) theo lịch của period
+func (w *Worker) slot(period string, now time.Time) string {
+	now = now.UTC()
+	boundary := time.Date(now.Year(), now.Month(), now.Day(), w.hour, 0, 0, 0, time.UTC)
+	if boundary.After(now) {
+		boundary = boundary.AddDate(0, 0, -1)
+	}
+	if period == cache.DeliveryWeekly {
+		for boundary.Weekday() != w.weekday {
+			boundary = boundary.AddDate(0, 0, -1)
+		}
+	}
+	return boundary.Format(time.DateOnly)
+}
+
+// flush gửi digest cho mọi user đang chờ; digest gửi lỗi được đưa lại hàng đợi cho lần sau
+func (w *Worker) flush(ctx context.Context, period string) {
+	failed := make(map[string][]*cache.Notification)
+	sent := 0
+
+	for {
+		recipient, err := w.queue.PopUser(ctx, period)
+		if err != nil {
+			log.Printf("❌ Failed to read %s digest queue: %v", period, err)
+			break
+		}
+		if recipient == "" {
+			break
+		}
+
+		items, err := w.queue.Drain(ctx, period, recipient)
+		if err != nil {
+			log.Printf("❌ Failed to read %s digest of user %s: %v", period, recipient, err)
+			continue
+		}
+		if len(items) == 0 {
+			continue
+		}
+
+		if err := w.send(ctx, period, recipient, items); err != nil {
+			log.Printf("❌ Failed to email %s digest to user %s: %v", period, recipient, err)
+			failed[recipient] = items
+			continue
+		}
+		sent++
+	}
+
+	for recipient, items := range failed {
+		if err := w.queue.Push(ctx, period, recipient, items...); err != nil {
+			log.Printf("❌ Failed to requeue %s digest of user %s, %d notifications dropped: %v", period, recipient, len(items), err)
+		}
+	}
+	if sent > 0 || len(failed) > 0 {
+		log.Printf("Sent %d %s digests, %d failed", sent, period, len(failed))
+	}
+}
+
+// send gửi email chứa items tới recipient; user không còn tồn tại hoặc không có email thì bỏ qua
+func (w *Worker) send(ctx context.Context, period, recipient string, items []*cache.Notification) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	ids := []string{recipient}
+	for _, n := range items {
+		if n.ActorID != "" {
+			ids = append(ids, n.ActorID)
+		}
+	}
+	users, err := w.users.GetUsers(ctx, ids)
+	if err != nil {
+		return err
+	}
+	user, ok := users[recipient]
+	if !ok || user.Email == "" {
+		log.Printf("⚠️ User %s has no email address, skipping %d notifications", recipient, len(items))
+		return nil
+	}
+
+	subject, text, html, err := w.renderer.Render(period, user, items, users)
+	if err != nil {
+		return err
+	}
+	return w.mailer.Send(ctx, &mailer.Message{To: user.Email, Subject: subject, Text: text, HTML: html})
+}
@@ -0,0 +1,60 @@
+package digest
+
+import (
+	"collab-consumer/internal/cache"
+	"testing"
+	"time"
+)
+
+func TestWorkerSlot(t *testing.T) {
+	ict := time.FixedZone("ICT", 7*60*60)
+	at := func(day, hour, minute int) time.Time {
+		// Tháng 10/2025: ngày 13 là thứ Hai
+		return time.Date(2025, time.October, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name   string
+		hour   int
+		period string
+		now    time.Time
+		want   string
+	}{
+		{"daily before the hour belongs to yesterday", 8, cache.DeliveryDaily, at(15, 7, 59), "2025-10-14"},
+		{"daily at the hour starts today", 8, cache.DeliveryDaily, at(15, 8, 0), "2025-10-15"},
+		{"daily late in the day", 8, cache.DeliveryDaily, at(15, 23, 59), "2025-10-15"},
+		{"daily at midnight", 0, cache.DeliveryDaily, at(15, 0, 0), "2025-10-15"},
+		{"daily before the hour on the first of the month", 8, cache.DeliveryDaily, at(1, 7, 0), "2025-09-30"},
+		{"daily uses UTC, not the local zone", 8, cache.DeliveryDaily, time.Date(2025, time.October, 15, 9, 30, 0, 0, ict), "2025-10-14"},
+		{"weekly mid-week walks back to Monday", 8, cache.DeliveryWeekly, at(15, 10, 0), "2025-10-13"},
+		{"weekly on Monday before the hour is last week", 8, cache.DeliveryWeekly, at(13, 7, 59), "2025-10-06"},
+		{"weekly on Monday at the hour", 8, cache.DeliveryWeekly, at(13, 8, 0), "2025-10-13"},
+		{"weekly on Sunday night", 8, cache.DeliveryWeekly, at(19, 23, 0), "2025-10-13"},
+		{"weekly on Tuesday before the hour", 8, cache.DeliveryWeekly, at(14, 7, 0), "2025-10-13"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &Worker{hour: tt.hour, weekday: time.Monday}
+			if got := w.slot(tt.period, tt.now); got != tt.want {
+				t.Errorf("slot(%s, %s) = %s, want %s", tt.period, tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkerSlotIsStableWithinPeriod(t *testing.T) {
+	w := &Worker{hour: 8, weekday: time.Friday}
+	start := time.Date(2025, time.October, 17, 8, 0, 0, 0, time.UTC) // thứ Sáu
+
+	// Mọi tick trong tuần sau lần gửi cho cùng slot, nên ClaimSlot chỉ trả true một lần
+	want := w.slot(cache.DeliveryWeekly, start)
+	for now := start; now.Before(start.AddDate(0, 0, 7)); now = now.Add(time.Hour) {
+		if got := w.slot(cache.DeliveryWeekly, now); got != want {
+			t.Fatalf("slot at %s = %s, want %s", now, got, want)
+		}
+	}
+	if got := w.slot(cache.DeliveryWeekly, start.AddDate(0, 0, 7)); got == want {
+		t.Errorf("slot did not advance after a week, still %s", got)
+	}
+}
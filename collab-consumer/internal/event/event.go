@@ -10,13 +10,21 @@ type Event struct {
 	TeamID      string `json:"teamId,omitempty"`
 	PerformedBy string `json:"performedBy,omitempty"`
 	TargetUser  string `json:"targetUserId,omitempty"`
+	// MemberIDs là các thành viên bị ảnh hưởng bởi thay đổi trên cả team (TEAM_ARCHIVED, TEAM_RESTORED)
+	MemberIDs []string `json:"memberIds,omitempty"`
 
 	AssetType string `json:"assetType,omitempty"`
 	AssetID   string `json:"assetId,omitempty"`
 	OwnerID   string `json:"ownerId,omitempty"`
 	ActionBy  string `json:"actionBy,omitempty"`
+	CommentID string `json:"commentId,omitempty"`
+	ReplyTo   string `json:"replyTo,omitempty"`
+	ExpiresAt string `json:"expiresAt,omitempty"`
 
 	Timestamp string `json:"timestamp"`
+
+	// MessageID định danh message Kafka (topic-partition-offset), không có trong payload
+	MessageID string `json:"-"`
 }
 
 // Parse raw Kafka message
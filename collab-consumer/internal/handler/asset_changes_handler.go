@@ -41,6 +41,18 @@ func (h *AssetChangesHandler) HandleAssetEvent(ctx context.Context, e *event.Eve
 		if err := h.cache.SetUserAccess(ctx, e.AssetID, e.OwnerID, e.AssetType); err != nil {
 			log.Printf("⚠️ Failed to set user access for NOTE_CREATED: %v", err)
 		}
+	case "FOLDER_RESTORED":
+		if err := h.cache.SetUserAccess(ctx, e.AssetID, e.OwnerID, "OWNER"); err != nil {
+			log.Printf("⚠️ Failed to set user access for FOLDER_RESTORED: %v", err)
+		}
+	case "NOTE_RESTORED":
+		if err := h.cache.SetUserAccess(ctx, e.AssetID, e.OwnerID, e.AssetType); err != nil {
+			log.Printf("⚠️ Failed to set user access for NOTE_RESTORED: %v", err)
+		}
+	case "NOTE_COPIED":
+		if err := h.cache.SetUserAccess(ctx, e.AssetID, e.OwnerID, e.AssetType); err != nil {
+			log.Printf("⚠️ Failed to set user access for NOTE_COPIED: %v", err)
+		}
 	case "NOTE_UPDATED":
 		if err := h.cache.SetUserAccess(ctx, e.AssetID, e.TargetUser, e.AssetType); err != nil {
 			log.Printf("⚠️ Failed to set user access for NOTE_UPDATED: %v", err)
@@ -57,6 +69,13 @@ func (h *AssetChangesHandler) HandleAssetEvent(ctx context.Context, e *event.Eve
 		if err := h.cache.RemoveUserAccess(ctx, e.AssetID, e.TargetUser); err != nil {
 			log.Printf("⚠️ Failed to remove user access for NOTE_UNSHARED: %v", err)
 		}
+	case "FOLDER_TEAM_SHARED", "FOLDER_TEAM_UNSHARED", "NOTE_TEAM_SHARED", "NOTE_TEAM_UNSHARED":
+		// Quyền qua team được collab-service resolve qua rosters lúc truy vấn, không cache theo user
+	case "FOLDER_MOVED", "NOTE_MOVED":
+		// Quyền kế thừa từ folder cha được resolve lúc truy vấn, di chuyển folder không đổi share trực tiếp
+	case "COMMENT_ADDED", "USER_MENTIONED", "SHARE_EXPIRING", "ACCESS_REQUESTED", "ACCESS_REQUEST_APPROVED", "ACCESS_REQUEST_DENIED":
+		// Comment, mention, nhắc share sắp hết hạn và yêu cầu truy cập không thay đổi quyền truy cập, chỉ tạo thông báo.
+		// Yêu cầu được duyệt đến dưới dạng NOTE_SHARED/FOLDER_SHARED.
 	default:
 		log.Printf("⚠️ Unknown event: %s", e.EventType)
 	}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"collab-consumer/internal/cache"
+	"collab-consumer/internal/digest"
+	"collab-consumer/internal/event"
+	"context"
+	"log"
+	"time"
+)
+
+// NotificationHandler chuyển event từ asset.changes và team.activity thành thông báo trong inbox của người nhận,
+// rồi chuyển thông báo mới sang email qua digest worker
+type NotificationHandler struct {
+	inbox  *cache.NotificationInbox
+	digest *digest.Worker
+}
+
+func NewNotificationHandler(inbox *cache.NotificationInbox, worker *digest.Worker) *NotificationHandler {
+	return &NotificationHandler{inbox: inbox, digest: worker}
+}
+
+func (h *NotificationHandler) HandleAssetEvent(ctx context.Context, e *event.Event) {
+	var recipient string
+	switch e.EventType {
+	case "USER_MENTIONED", "ACCESS_REQUESTED", "ACCESS_REQUEST_APPROVED", "ACCESS_REQUEST_DENIED":
+		// Yêu cầu truy cập báo cho owner, duyệt/từ chối báo cho người yêu cầu; đều đặt người nhận ở targetUserId
+		recipient = e.TargetUser
+	case "COMMENT_ADDED":
+		// Báo cho chủ note; người khác trong thread chỉ nhận khi được mention
+		recipient = e.OwnerID
+	case "NOTE_SHARED", "FOLDER_SHARED", "SHARE_EXPIRING":
+		// Với event share, collab-service đặt ownerId là user được share
+		recipient = e.OwnerID
+	case "NOTE_UNSHARED", "FOLDER_UNSHARED":
+		// Báo cho user bị thu hồi quyền hoặc có share vừa hết hạn
+		recipient = e.TargetUser
+	default:
+		return
+	}
+
+	h.notify(ctx, recipient, e.ActionBy, &cache.Notification{
+		Type:      e.EventType,
+		ActorID:   e.ActionBy,
+		AssetType: e.AssetType,
+		AssetID:   e.AssetID,
+		CommentID: e.CommentID,
+		ExpiresAt: e.ExpiresAt,
+	}, e.MessageID)
+}
+
+func (h *NotificationHandler) HandleTeamEvent(ctx context.Context, e *event.Event) {
+	var recipients []string
+	switch e.EventType {
+	case "MEMBER_ADDED", "MANAGER_ADDED", "MEMBER_REMOVED":
+		recipients = []string{e.TargetUser}
+	case "TEAM_ARCHIVED", "TEAM_RESTORED":
+		// Báo cho mọi thành viên của team
+		recipients = e.MemberIDs
+	default:
+		return
+	}
+
+	for _, recipient := range recipients {
+		h.notify(ctx, recipient, e.PerformedBy, &cache.Notification{
+			Type:    e.EventType,
+			ActorID: e.PerformedBy,
+			TeamID:  e.TeamID,
+		}, e.MessageID)
+	}
+}
+
+// notify bỏ qua thông báo không có người nhận hoặc do chính người nhận gây ra
+func (h *NotificationHandler) notify(ctx context.Context, recipient, actor string, n *cache.Notification, id string) {
+	if recipient == "" || recipient == actor {
+		return
+	}
+
+	now := time.Now().UTC()
+	n.ID = id
+	n.CreatedAt = now.Format(time.RFC3339)
+	added, err := h.inbox.Add(ctx, recipient, n, now)
+	if err != nil {
+		log.Printf("❌ Failed to add %s notification for user %s: %v", n.Type, recipient, err)
+	}
+	// Message bị giao lại không gửi email lần nữa; inbox lỗi thì email vẫn được gửi
+	if added || err != nil {
+		h.digest.Deliver(ctx, recipient, n)
+	}
+}
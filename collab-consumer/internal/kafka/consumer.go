@@ -5,12 +5,13 @@ import (
 	"collab-consumer/internal/event"
 	"collab-consumer/internal/handler"
 	"context"
+	"fmt"
 	"log"
 
 	"github.com/segmentio/kafka-go"
 )
 
-func StartTeamConsumer(ctx context.Context, brokers []string, topic, groupID string) {
+func StartTeamConsumer(ctx context.Context, brokers []string, topic, groupID string, notificationHandler *handler.NotificationHandler) {
 	r := kafka.NewReader(kafka.ReaderConfig{
 		Brokers:  brokers,
 		GroupID:  groupID,
@@ -41,11 +42,13 @@ func StartTeamConsumer(ctx context.Context, brokers []string, topic, groupID str
 			log.Printf("Error parsing event: %v", err)
 			continue
 		}
+		ev.MessageID = fmt.Sprintf("%s-%d-%d", m.Topic, m.Partition, m.Offset)
 
 		// gọi handler
 		if err := teamHandler.HandleTeamEvent(ctx, ev); err != nil {
 			log.Printf("Error handling event: %v", err)
 		}
+		notificationHandler.HandleTeamEvent(ctx, ev)
 
 		// Commit offset sau khi xử lý thành công
 		if err := r.CommitMessages(ctx, m); err != nil {
@@ -54,7 +57,7 @@ func StartTeamConsumer(ctx context.Context, brokers []string, topic, groupID str
 	}
 }
 
-func StartAssetChangesConsumer(ctx context.Context, brokers []string, topic, groupID string) {
+func StartAssetChangesConsumer(ctx context.Context, brokers []string, topic, groupID string, notificationHandler *handler.NotificationHandler) {
 	r := kafka.NewReader(kafka.ReaderConfig{
 		Brokers:  brokers,
 		GroupID:  groupID,
@@ -85,11 +88,13 @@ func StartAssetChangesConsumer(ctx context.Context, brokers []string, topic, gro
 			log.Printf("Error parsing event: %v", err)
 			continue
 		}
+		ev.MessageID = fmt.Sprintf("%s-%d-%d", m.Topic, m.Partition, m.Offset)
 
 		// gọi handler
 		if err := assetHandler.HandleAssetEvent(ctx, ev); err != nil {
 			log.Printf("Error handling event: %v", err)
 		}
+		notificationHandler.HandleAssetEvent(ctx, ev)
 
 		// Commit offset sau khi xử lý thành công
 		if err := r.CommitMessages(ctx, m); err != nil {
@@ -0,0 +1,100 @@
+package mailer
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	msg := &Message{
+		From:    "Collab <no-reply@example.com>",
+		To:      "alice@example.com",
+		Subject: "Bản tin hằng ngày: 3 thông báo mới",
+		Text:    "Xin chào Alice,\n\n" + strings.Repeat("bob đã bình luận trên note. ", 5) + "\nHẹn gặp lại =)",
+		HTML:    `<p style="color:#333">Xin chào <b>Alice</b></p>`,
+	}
+
+	raw, err := Build(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Mọi dòng kết thúc bằng CRLF, không có LF đứng riêng
+	if n := bytes.Count(raw, []byte("\n")); n != bytes.Count(raw, []byte("\r\n")) {
+		t.Errorf("message has %d bare LF line endings", n-bytes.Count(raw, []byte("\r\n")))
+	}
+	// Quoted-printable giữ mọi dòng của phần body không quá 76 ký tự
+	_, body, _ := strings.Cut(string(raw), "\r\n\r\n")
+	for _, line := range strings.Split(body, "\r\n") {
+		if len(line) > 76 {
+			t.Errorf("body line longer than 76 characters: %q", line)
+		}
+	}
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parsed.Header.Get("To"); got != msg.To {
+		t.Errorf("To = %q, want %q", got, msg.To)
+	}
+
+	encodedSubject := parsed.Header.Get("Subject")
+	if !strings.HasPrefix(encodedSubject, "=?utf-8?q?") {
+		t.Errorf("Subject is not Q-encoded: %q", encodedSubject)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(encodedSubject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != msg.Subject {
+		t.Errorf("Subject = %q, want %q", subject, msg.Subject)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, want multipart/alternative", mediaType)
+	}
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	for _, want := range []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", strings.ReplaceAll(msg.Text, "\n", "\r\n")},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		// NextRawPart giữ nguyên Content-Transfer-Encoding để kiểm tra phần quoted-printable
+		part, err := reader.NextRawPart()
+		if err != nil {
+			t.Fatalf("%s part: %v", want.contentType, err)
+		}
+		if got := part.Header.Get("Content-Type"); got != want.contentType {
+			t.Errorf("Content-Type = %q, want %q", got, want.contentType)
+		}
+		if got := part.Header.Get("Content-Transfer-Encoding"); got != "quoted-printable" {
+			t.Errorf("%s Content-Transfer-Encoding = %q, want quoted-printable", want.contentType, got)
+		}
+
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		if err != nil {
+			t.Fatalf("%s part: %v", want.contentType, err)
+		}
+		if got := strings.TrimSuffix(string(body), "\r\n"); got != want.body {
+			t.Errorf("%s body = %q, want %q", want.contentType, got, want.body)
+		}
+	}
+
+	if _, err := reader.NextRawPart(); err != io.EOF {
+		t.Errorf("expected exactly two parts, got err %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package userclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const getUsersQuery = `query GetUsers($userIds: [ID!]) {
+  users(userIds: $userIds) {
+    code
+    message
+    users {
+      userId
+      username
+      email
+    }
+  }
+}`
+
+type User struct {
+	ID       string `json:"userId"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// Client gọi GraphQL API của user-service để lấy email và username người dùng
+type Client struct {
+	endpoint string
+	http     *http.Client
+}
+
+func NewClient(endpoint string) *Client {
+	return &Client{endpoint: endpoint, http: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// GetUsers trả về user theo ID; ID không tồn tại không có trong map
+func (c *Client) GetUsers(ctx context.Context, userIDs []string) (map[string]*User, error) {
+	users := make(map[string]*User, len(userIDs))
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"query":     getUsersQuery,
+		"variables": map[string]any{"userIds": userIDs},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("user-service returned %s", resp.Status)
+	}
+
+	var result struct {
+		Data struct {
+			Users struct {
+				Users []*User `json:"users"`
+			} `json:"users"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("user-service: %s", result.Errors[0].Message)
+	}
+
+	for _, user := range result.Data.Users.Users {
+		users[user.ID] = user
+	}
+	return users, nil
+}
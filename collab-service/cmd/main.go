@@ -4,7 +4,9 @@ import (
 	"collab-service/config"
 	"collab-service/internal/infrastructure/database"
 	"collab-service/internal/infrastructure/external/cache"
+	"collab-service/internal/infrastructure/external/event"
 	"collab-service/internal/infrastructure/logger"
+	"collab-service/internal/infrastructure/scheduler"
 	httpHandler "collab-service/internal/interface/http"
 	"context"
 	"fmt"
@@ -53,6 +55,16 @@ func main() {
 	// Setup routes
 	router := httpHandler.SetupRoutes()
 
+	// Start background jobs registered by the modules
+	scheduler.GetScheduler().Start()
+	defer scheduler.GetScheduler().Stop()
+
+	// Start stream consumers subscribed by the modules
+	event.GetTeamActivityConsumer().Start()
+	defer event.GetTeamActivityConsumer().Stop()
+	event.GetAssetChangeConsumer().Start()
+	defer event.GetAssetChangeConsumer().Stop()
+
 	// Create HTTP server manually (so we can shut it down)
 	srv := &http.Server{
 		Addr:    ":" + config.GetConfig().Port,
@@ -4,6 +4,8 @@ import (
 	"collab-service/internal/infrastructure/logger"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -26,6 +28,35 @@ type Config struct {
 	KafkaAddresses      []string
 	TeamActivityTopic   string
 	AssetChangeTopic    string
+	TeamActivityGroupID string
+	AssetChangeGroupID  string
+	TeamRetention       time.Duration
+	TeamPurgeInterval   time.Duration
+	MembershipSweep     time.Duration
+	NoteRevisionLimit   int
+	NoteCollabSnapshot  time.Duration
+	TrashRetention      time.Duration
+	TrashPurgeInterval  time.Duration
+	AttachmentStorage   string
+	AttachmentLocalDir  string
+	AttachmentMaxSize   int64
+	TeamAttachmentQuota int64
+	UserAttachmentQuota int64
+	S3Endpoint          string
+	S3Region            string
+	S3Bucket            string
+	S3AccessKey         string
+	S3SecretKey         string
+	S3PathStyle         bool
+	WebhookDispatch     time.Duration
+	WebhookTimeout      time.Duration
+	WebhookMaxAttempts  int
+	WebhookDisableAfter int
+	WebhookRetention    time.Duration
+	WebhookPurge        time.Duration
+	WebhookAllowPrivate bool
+	ShareExpirySweep    time.Duration
+	ShareExpiryReminder time.Duration
 }
 
 // LoadEnv loads environment variables from .env file
@@ -60,6 +91,35 @@ func GetConfig() Config {
 		KafkaAddresses:      []string{GetEnv("KAFKA_ADDRESS", "localhost:9092")},
 		TeamActivityTopic:   GetEnv("TEAM_ACTIVITY_TOPIC", "team-activity"),
 		AssetChangeTopic:    GetEnv("ASSET_CHANGE_TOPIC", "asset-change"),
+		TeamActivityGroupID: GetEnv("TEAM_ACTIVITY_GROUP_ID", "collab-service-activity"),
+		AssetChangeGroupID:  GetEnv("ASSET_CHANGE_GROUP_ID", "collab-service-asset-changes"),
+		TeamRetention:       GetEnvDuration("TEAM_ARCHIVE_RETENTION", 30*24*time.Hour),
+		TeamPurgeInterval:   GetEnvDuration("TEAM_PURGE_INTERVAL", time.Hour),
+		MembershipSweep:     GetEnvDuration("MEMBERSHIP_SWEEP_INTERVAL", 5*time.Minute),
+		NoteRevisionLimit:   GetEnvInt("NOTE_REVISION_LIMIT", 50),
+		NoteCollabSnapshot:  GetEnvDuration("NOTE_COLLAB_SNAPSHOT_INTERVAL", 30*time.Second),
+		TrashRetention:      GetEnvDuration("TRASH_RETENTION", 30*24*time.Hour),
+		TrashPurgeInterval:  GetEnvDuration("TRASH_PURGE_INTERVAL", time.Hour),
+		AttachmentStorage:   GetEnv("ATTACHMENT_STORAGE", "local"),
+		AttachmentLocalDir:  GetEnv("ATTACHMENT_LOCAL_DIR", "./data/attachments"),
+		AttachmentMaxSize:   int64(GetEnvInt("ATTACHMENT_MAX_SIZE", 25<<20)),
+		TeamAttachmentQuota: int64(GetEnvInt("TEAM_ATTACHMENT_QUOTA", 1<<30)),
+		UserAttachmentQuota: int64(GetEnvInt("USER_ATTACHMENT_QUOTA", 256<<20)),
+		S3Endpoint:          GetEnv("S3_ENDPOINT", ""),
+		S3Region:            GetEnv("S3_REGION", "us-east-1"),
+		S3Bucket:            GetEnv("S3_BUCKET", ""),
+		S3AccessKey:         GetEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:         GetEnv("S3_SECRET_KEY", ""),
+		S3PathStyle:         GetEnv("S3_PATH_STYLE", "false") == "true",
+		WebhookDispatch:     GetEnvDuration("WEBHOOK_DISPATCH_INTERVAL", 5*time.Second),
+		WebhookTimeout:      GetEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		WebhookMaxAttempts:  GetEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookDisableAfter: GetEnvInt("WEBHOOK_DISABLE_AFTER", 20),
+		WebhookRetention:    GetEnvDuration("WEBHOOK_DELIVERY_RETENTION", 30*24*time.Hour),
+		WebhookPurge:        GetEnvDuration("WEBHOOK_PURGE_INTERVAL", time.Hour),
+		WebhookAllowPrivate: GetEnv("WEBHOOK_ALLOW_PRIVATE", "false") == "true",
+		ShareExpirySweep:    GetEnvDuration("SHARE_EXPIRY_SWEEP_INTERVAL", 5*time.Minute),
+		ShareExpiryReminder: GetEnvDuration("SHARE_EXPIRY_REMINDER_WINDOW", 24*time.Hour),
 	}
 }
 
@@ -71,3 +131,22 @@ func GetEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+// GetEnvDuration returns an environment variable parsed as a time.Duration (e.g. "720h")
+// or the default value if it is unset or invalid
+func GetEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// GetEnvInt returns an environment variable parsed as an int or the default value if it is unset or invalid
+func GetEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
@@ -6,6 +6,7 @@ toolchain go1.24.7
 
 require (
 	ariga.io/atlas-provider-gorm v0.6.0
+	github.com/gorilla/websocket v1.5.3
 	github.com/joho/godotenv v1.5.1
 	github.com/machinebox/graphql v0.2.2
 	github.com/stretchr/testify v1.11.1
@@ -0,0 +1,268 @@
+package application
+
+import (
+	"collab-service/internal/domain/authz"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/external/event"
+	"collab-service/internal/infrastructure/logger"
+	"collab-service/internal/interface/http/middleware"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const maxAccessRequestMessageLen = 500
+
+// AccessRequestService cho user xin quyền trên note/folder họ chưa truy cập được, và cho owner duyệt hoặc từ chối.
+// Duyệt yêu cầu đi qua NoteService.ShareNote/FolderService.ShareFolder nên có cùng kiểm tra quyền và event NOTE_SHARED/FOLDER_SHARED.
+type AccessRequestService struct {
+	noteService   *NoteService
+	folderService *FolderService
+	noteRepo      entity.NoteRepository
+	folderRepo    entity.FolderRepository
+	requestRepo   entity.AccessRequestRepository
+	eventProducer *event.AssetChangeProducer
+}
+
+func NewAccessRequestService(noteService *NoteService, folderService *FolderService, noteRepo entity.NoteRepository, folderRepo entity.FolderRepository, requestRepo entity.AccessRequestRepository) *AccessRequestService {
+	return &AccessRequestService{
+		noteService:   noteService,
+		folderService: folderService,
+		noteRepo:      noteRepo,
+		folderRepo:    folderRepo,
+		requestRepo:   requestRepo,
+		eventProducer: event.GetAssetChangeProducer(),
+	}
+}
+
+// Create gửi yêu cầu quyền READ hoặc WRITE trên asset tới owner
+func (s *AccessRequestService) Create(c *gin.Context, assetType entity.AccessRequestAssetType, assetID uuid.UUID, accessLevel entity.AccessLevel, message string) (*entity.AccessRequest, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	ctx := c.Request.Context()
+
+	if err := checkRequestedAccessLevel(accessLevel); err != nil {
+		return nil, err
+	}
+	message = strings.TrimSpace(message)
+	if utf8.RuneCountInString(message) > maxAccessRequestMessageLen {
+		return nil, NewBadRequestError(fmt.Sprintf("message must be at most %d characters", maxAccessRequestMessageLen))
+	}
+
+	owner, err := s.getOwner(c, assetType, assetID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.accessLevel(c, assetType, assetID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if current.AtLeast(accessLevel) {
+		return nil, NewConflictError(fmt.Sprintf("you already have %s access to this %s", current, accessRequestAssetName(assetType)))
+	}
+
+	if _, err := s.requestRepo.GetPending(ctx, assetType, assetID, userID); err == nil {
+		return nil, NewConflictError(fmt.Sprintf("you already have a pending access request for this %s", accessRequestAssetName(assetType)))
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	request, err := s.requestRepo.Create(ctx, &entity.AccessRequest{
+		AssetType:   assetType,
+		AssetID:     assetID,
+		RequesterID: userID,
+		AccessLevel: accessLevel,
+		Message:     message,
+		Status:      entity.AccessRequestPending,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go s.eventProducer.Produce(event.NewAccessRequestEvent(event.AccessRequested, accessRequestEventAssetType(assetType),
+		assetID.String(), owner.String(), userID.String(), owner.String(), time.Now().String(), accessLevel))
+
+	return request, nil
+}
+
+// List trả về yêu cầu truy cập của asset cho owner, mới nhất trước; status nil là mọi trạng thái
+func (s *AccessRequestService) List(c *gin.Context, assetType entity.AccessRequestAssetType, assetID uuid.UUID, status *entity.AccessRequestStatus) ([]*entity.AccessRequest, error) {
+	if err := s.requireOwner(c, assetType, assetID); err != nil {
+		return nil, err
+	}
+	return s.requestRepo.ListByAsset(c.Request.Context(), assetType, assetID, status)
+}
+
+// Approve cấp quyền cho người yêu cầu qua luồng share thông thường.
+// accessLevel khác nil cho phép owner cấp mức khác với mức được yêu cầu; expiresAt khác nil tạo share có thời hạn.
+func (s *AccessRequestService) Approve(c *gin.Context, assetType entity.AccessRequestAssetType, assetID, requestID uuid.UUID, accessLevel *entity.AccessLevel, expiresAt *time.Time) (*entity.AccessRequest, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	request, err := s.getPendingOfAsset(c, assetType, assetID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	level := request.AccessLevel
+	if accessLevel != nil {
+		if err := checkRequestedAccessLevel(*accessLevel); err != nil {
+			return nil, err
+		}
+		level = *accessLevel
+	}
+	// Kiểm tra trước khi nhận xử lý để input sai không để lại yêu cầu APPROVED mà không có share
+	if err := checkShareExpiry(level, expiresAt); err != nil {
+		return nil, err
+	}
+
+	// Nhận xử lý yêu cầu trước khi share để hai owner duyệt/từ chối cùng lúc không cấp quyền cho yêu cầu đã bị từ chối
+	decided, err := s.requestRepo.Decide(c.Request.Context(), request.ID, entity.AccessRequestApproved, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !decided {
+		return nil, NewConflictError(fmt.Sprintf("access request %s has already been handled", requestID))
+	}
+
+	if err := s.grant(c, assetType, assetID, request.RequesterID, level, expiresAt); err != nil {
+		// Không cấp được quyền: trả yêu cầu về PENDING để owner có thể duyệt lại
+		if reopenErr := s.requestRepo.Reopen(context.WithoutCancel(c.Request.Context()), request.ID, userID); reopenErr != nil {
+			logger.Error("failed to reopen access request", "request_id", request.ID.String(), "error", reopenErr.Error())
+		}
+		return nil, err
+	}
+
+	// Luôn báo cho người yêu cầu, kể cả khi họ đã có đủ quyền và không có share mới
+	go s.eventProducer.Produce(event.NewAccessRequestEvent(event.AccessRequestApproved, accessRequestEventAssetType(assetType),
+		assetID.String(), userID.String(), userID.String(), request.RequesterID.String(), time.Now().String(), level))
+
+	return s.requestRepo.GetByID(c.Request.Context(), request.ID)
+}
+
+// grant share asset cho người yêu cầu. Người yêu cầu có thể đã được cấp đủ quyền theo cách khác;
+// khi đó không share lại để tránh hạ quyền của họ.
+func (s *AccessRequestService) grant(c *gin.Context, assetType entity.AccessRequestAssetType, assetID, requesterID uuid.UUID, level entity.AccessLevel, expiresAt *time.Time) error {
+	current, err := s.accessLevel(c, assetType, assetID, requesterID)
+	if err != nil || current.AtLeast(level) {
+		return err
+	}
+	if assetType == entity.AccessRequestNote {
+		return s.noteService.ShareNote(c, assetID, requesterID, level, expiresAt)
+	}
+	return s.folderService.ShareFolder(c, assetID, requesterID, level, expiresAt)
+}
+
+// Deny từ chối yêu cầu và báo cho người yêu cầu
+func (s *AccessRequestService) Deny(c *gin.Context, assetType entity.AccessRequestAssetType, assetID, requestID uuid.UUID) (*entity.AccessRequest, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	request, err := s.getPendingOfAsset(c, assetType, assetID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	decided, err := s.requestRepo.Decide(c.Request.Context(), request.ID, entity.AccessRequestDenied, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !decided {
+		return nil, NewConflictError(fmt.Sprintf("access request %s has already been handled", requestID))
+	}
+
+	go s.eventProducer.Produce(event.NewAccessRequestEvent(event.AccessRequestDenied, accessRequestEventAssetType(assetType),
+		assetID.String(), userID.String(), userID.String(), request.RequesterID.String(), time.Now().String(), request.AccessLevel))
+
+	return s.requestRepo.GetByID(c.Request.Context(), request.ID)
+}
+
+// getPendingOfAsset kiểm tra caller là owner rồi lấy yêu cầu còn PENDING của asset
+func (s *AccessRequestService) getPendingOfAsset(c *gin.Context, assetType entity.AccessRequestAssetType, assetID, requestID uuid.UUID) (*entity.AccessRequest, error) {
+	if err := s.requireOwner(c, assetType, assetID); err != nil {
+		return nil, err
+	}
+
+	request, err := s.requestRepo.GetByID(c.Request.Context(), requestID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && (request.AssetType != assetType || request.AssetID != assetID)) {
+		return nil, NewNotFoundError(fmt.Sprintf("access request %s not found", requestID))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != entity.AccessRequestPending {
+		return nil, NewConflictError(fmt.Sprintf("access request %s has already been %s", requestID, strings.ToLower(string(request.Status))))
+	}
+	return request, nil
+}
+
+func (s *AccessRequestService) requireOwner(c *gin.Context, assetType entity.AccessRequestAssetType, assetID uuid.UUID) error {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	accessLevel, _ := s.accessLevel(c, assetType, assetID, userID)
+	return authorize(authz.OnAsset(userID, accessLevel), authz.Share, accessRequestResource(assetType),
+		fmt.Sprintf("only the owner can manage access requests of this %s", accessRequestAssetName(assetType)))
+}
+
+// getOwner trả về owner của asset; asset không tồn tại hoặc nằm trong thùng rác trả về 404
+func (s *AccessRequestService) getOwner(c *gin.Context, assetType entity.AccessRequestAssetType, assetID uuid.UUID) (uuid.UUID, error) {
+	ctx := c.Request.Context()
+
+	var err error
+	if assetType == entity.AccessRequestNote {
+		_, err = s.noteRepo.GetByID(ctx, assetID)
+	} else {
+		_, err = s.folderRepo.GetByID(ctx, assetID)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return uuid.Nil, NewNotFoundError(fmt.Sprintf("%s %s not found", accessRequestAssetName(assetType), assetID))
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if assetType == entity.AccessRequestNote {
+		return s.noteRepo.GetOwner(ctx, assetID)
+	}
+	return s.folderRepo.GetOwner(ctx, assetID)
+}
+
+func (s *AccessRequestService) accessLevel(c *gin.Context, assetType entity.AccessRequestAssetType, assetID, userID uuid.UUID) (entity.AccessLevel, error) {
+	if assetType == entity.AccessRequestNote {
+		return s.noteRepo.GetAccessLevel(c.Request.Context(), assetID, userID)
+	}
+	return s.folderRepo.GetAccessLevel(c.Request.Context(), assetID, userID)
+}
+
+// checkRequestedAccessLevel chỉ cho xin hoặc cấp READ và WRITE; quyền OWNER không thể chuyển qua yêu cầu truy cập
+func checkRequestedAccessLevel(accessLevel entity.AccessLevel) error {
+	if accessLevel != entity.AccessLevelRead && accessLevel != entity.AccessLevelWrite {
+		return NewBadRequestError("access_level must be READ or WRITE")
+	}
+	return nil
+}
+
+func accessRequestAssetName(assetType entity.AccessRequestAssetType) string {
+	if assetType == entity.AccessRequestNote {
+		return "note"
+	}
+	return "folder"
+}
+
+func accessRequestResource(assetType entity.AccessRequestAssetType) entity.ResourceType {
+	if assetType == entity.AccessRequestNote {
+		return entity.ResourceNote
+	}
+	return entity.ResourceFolder
+}
+
+func accessRequestEventAssetType(assetType entity.AccessRequestAssetType) event.AssetType {
+	if assetType == entity.AccessRequestNote {
+		return event.Note
+	}
+	return event.Folder
+}
@@ -0,0 +1,201 @@
+package application
+
+import (
+	"bufio"
+	"collab-service/internal/domain/authz"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/logger"
+	"collab-service/internal/interface/http/middleware"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const maxAttachmentNameLength = 255
+
+// AttachmentService quản lý file đính kèm của note: metadata trong DB, nội dung trong BlobStore
+type AttachmentService struct {
+	noteRepo       entity.NoteRepository
+	attachmentRepo entity.AttachmentRepository
+	store          entity.BlobStore
+	maxSize        int64
+	quota          entity.AttachmentQuota
+}
+
+func NewAttachmentService(noteRepo entity.NoteRepository, attachmentRepo entity.AttachmentRepository, store entity.BlobStore, maxSize int64, quota entity.AttachmentQuota) *AttachmentService {
+	return &AttachmentService{
+		noteRepo:       noteRepo,
+		attachmentRepo: attachmentRepo,
+		store:          store,
+		maxSize:        maxSize,
+		quota:          quota,
+	}
+}
+
+// MaxSize là kích thước tối đa của một file đính kèm (byte)
+func (s *AttachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Upload lưu file đính kèm cho note; cần quyền ghi trên note và không vượt quota của team nào đang truy cập note,
+// hoặc quota của owner note khi note không share cho team nào
+func (s *AttachmentService) Upload(c *gin.Context, noteID uuid.UUID, fileName string, size int64, content io.Reader) (*entity.Attachment, error) {
+	if err := s.checkAccess(c, noteID, authz.Edit); err != nil {
+		return nil, err
+	}
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	ctx := c.Request.Context()
+
+	if size > s.maxSize {
+		return nil, NewPayloadTooLargeError(fmt.Sprintf("attachment exceeds the maximum size of %d bytes", s.maxSize))
+	}
+	// Đoán MIME từ nội dung, chỉ dựa vào đuôi file khi nội dung không nhận diện được
+	reader := bufio.NewReaderSize(content, 512)
+	head, _ := reader.Peek(512)
+	fileName = sanitizeAttachmentName(fileName)
+	mimeType := http.DetectContentType(head)
+	if mimeType == "application/octet-stream" {
+		if byExt := mime.TypeByExtension(filepath.Ext(fileName)); byExt != "" {
+			mimeType = byExt
+		}
+	}
+
+	attachment := &entity.Attachment{
+		ID:         uuid.New(),
+		NoteID:     noteID,
+		FileName:   fileName,
+		MimeType:   mimeType,
+		Size:       size,
+		UploadedBy: userID,
+	}
+	attachment.StorageKey = fmt.Sprintf("notes/%s/%s", noteID, attachment.ID)
+
+	// Từ chối sớm trước khi ghi blob; Create kiểm tra lại dưới lock
+	if err := quotaError(s.attachmentRepo.CheckQuota(ctx, attachment, s.quota)); err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	if err := s.store.Put(ctx, attachment.StorageKey, io.TeeReader(reader, hash), size, mimeType); err != nil {
+		return nil, err
+	}
+	attachment.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	saved, err := s.attachmentRepo.Create(ctx, attachment, s.quota)
+	if err != nil {
+		deleteBlobs(context.WithoutCancel(ctx), s.store, []string{attachment.StorageKey})
+		return nil, quotaError(err)
+	}
+	return saved, nil
+}
+
+// List returns the attachments of a note the caller can read
+func (s *AttachmentService) List(c *gin.Context, noteID uuid.UUID) ([]*entity.Attachment, error) {
+	if err := s.checkAccess(c, noteID, authz.View); err != nil {
+		return nil, err
+	}
+	return s.attachmentRepo.ListByNote(c.Request.Context(), noteID)
+}
+
+// Get trả về metadata của file đính kèm sau khi kiểm tra quyền đọc note
+func (s *AttachmentService) Get(c *gin.Context, noteID, attachmentID uuid.UUID) (*entity.Attachment, error) {
+	if err := s.checkAccess(c, noteID, authz.View); err != nil {
+		return nil, err
+	}
+	return s.getOfNote(c.Request.Context(), noteID, attachmentID)
+}
+
+// Open mở nội dung file đính kèm từ offset, length < 0 nghĩa là tới hết file
+func (s *AttachmentService) Open(ctx context.Context, attachment *entity.Attachment, offset, length int64) (io.ReadCloser, error) {
+	content, err := s.store.Get(ctx, attachment.StorageKey, offset, length)
+	if errors.Is(err, entity.ErrBlobNotFound) {
+		return nil, NewNotFoundError(fmt.Sprintf("content of attachment %s not found", attachment.ID))
+	}
+	return content, err
+}
+
+// Delete xoá file đính kèm; cần quyền ghi trên note
+func (s *AttachmentService) Delete(c *gin.Context, noteID, attachmentID uuid.UUID) error {
+	if err := s.checkAccess(c, noteID, authz.Edit); err != nil {
+		return err
+	}
+
+	attachment, err := s.getOfNote(c.Request.Context(), noteID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if err := s.attachmentRepo.Delete(c.Request.Context(), attachment.ID); err != nil {
+		return err
+	}
+
+	deleteBlobs(c.Request.Context(), s.store, []string{attachment.StorageKey})
+	return nil
+}
+
+// checkAccess kiểm tra caller được thực hiện action trên note, với quyền lấy từ NoteRepository.GetAccessLevel
+func (s *AttachmentService) checkAccess(c *gin.Context, noteID uuid.UUID, action authz.Action) error {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	accessLevel, _ := s.noteRepo.GetAccessLevel(c.Request.Context(), noteID, userID)
+	principal := authz.OnAsset(userID, accessLevel)
+	if err := authorize(principal, authz.View, entity.ResourceNote, "You do not have permission to view this note"); err != nil {
+		return err
+	}
+	return authorize(principal, action, entity.ResourceNote, "You do not have write permission for this note")
+}
+
+func (s *AttachmentService) getOfNote(ctx context.Context, noteID, attachmentID uuid.UUID) (*entity.Attachment, error) {
+	attachment, err := s.attachmentRepo.GetByID(ctx, attachmentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && attachment.NoteID != noteID) {
+		return nil, NewNotFoundError(fmt.Sprintf("attachment %s not found on note %s", attachmentID, noteID))
+	}
+	return attachment, err
+}
+
+// quotaError chuyển lỗi vượt quota của repository thành 413
+func quotaError(err error) error {
+	var quotaErr *entity.AttachmentQuotaError
+	if errors.As(err, &quotaErr) {
+		return NewPayloadTooLargeError(quotaErr.Error())
+	}
+	return err
+}
+
+// deleteBlobs xoá nội dung trong blob store; metadata đã bị xoá nên lỗi chỉ được ghi log
+func deleteBlobs(ctx context.Context, store entity.BlobStore, keys []string) {
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			logger.Error("failed to delete attachment blob", "key", key, "error", err.Error())
+		}
+	}
+}
+
+// sanitizeAttachmentName bỏ phần đường dẫn client gửi kèm và ký tự điều khiển trong tên file
+func sanitizeAttachmentName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(name))
+
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	if utf8.RuneCountInString(name) > maxAttachmentNameLength {
+		name = string([]rune(name)[:maxAttachmentNameLength])
+	}
+	return name
+}
@@ -0,0 +1,14 @@
+package application
+
+import (
+	"collab-service/internal/domain/authz"
+	"collab-service/internal/domain/entity"
+)
+
+// authorize hỏi lớp phân quyền authz; principal không được phép nhận lỗi 403 kèm message
+func authorize(principal authz.Principal, action authz.Action, resource entity.ResourceType, message string) error {
+	if !authz.Allowed(principal, action, resource) {
+		return NewForbiddenError(message)
+	}
+	return nil
+}
@@ -0,0 +1,316 @@
+package application
+
+import (
+	"collab-service/internal/domain/authz"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/external/event"
+	"collab-service/internal/interface/http/middleware"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const MaxCommentLength = 10000
+
+// CommentThread là một comment gốc cùng các reply của nó, cũ trước
+type CommentThread struct {
+	Root    *entity.Comment
+	Replies []*entity.Comment
+}
+
+// CommentService quản lý thread comment trên note.
+// Ai đọc được note (READ) thì được comment và reply; resolve/reopen cần WRITE; sửa/xoá chỉ tác giả.
+type CommentService struct {
+	noteRepo      entity.NoteRepository
+	commentRepo   entity.CommentRepository
+	mentions      *MentionNotifier
+	eventProducer *event.AssetChangeProducer
+}
+
+func NewCommentService(noteRepo entity.NoteRepository, commentRepo entity.CommentRepository, mentions *MentionNotifier) *CommentService {
+	return &CommentService{
+		noteRepo:      noteRepo,
+		commentRepo:   commentRepo,
+		mentions:      mentions,
+		eventProducer: event.GetAssetChangeProducer(),
+	}
+}
+
+// ListThreads returns the comment threads of a note, optionally filtered by resolved state
+func (s *CommentService) ListThreads(c *gin.Context, noteID uuid.UUID, resolved *bool) ([]*CommentThread, error) {
+	if _, err := s.requireAccess(c, noteID, authz.View); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepo.ListByNote(c.Request.Context(), noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	threads := make([]*CommentThread, 0)
+	byRoot := make(map[uuid.UUID]*CommentThread)
+	for _, comment := range comments {
+		if comment.IsReply() {
+			continue
+		}
+		if resolved != nil && comment.IsResolved() != *resolved {
+			continue
+		}
+		thread := &CommentThread{Root: comment, Replies: []*entity.Comment{}}
+		byRoot[comment.ID] = thread
+		threads = append(threads, thread)
+	}
+	for _, comment := range comments {
+		if comment.IsReply() {
+			if thread, ok := byRoot[*comment.ParentID]; ok {
+				thread.Replies = append(thread.Replies, comment)
+			}
+		}
+	}
+	return threads, nil
+}
+
+// AddComment mở thread mới trên note, anchor (nếu có) là đoạn [Start, End) tính theo rune trong body hiện tại
+func (s *CommentService) AddComment(c *gin.Context, noteID uuid.UUID, body string, anchor *entity.CommentAnchor) (*entity.Comment, error) {
+	userID, err := s.requireAccess(c, noteID, authz.Comment)
+	if err != nil {
+		return nil, err
+	}
+	if body, err = normalizeCommentBody(body); err != nil {
+		return nil, err
+	}
+
+	if anchor != nil {
+		note, err := s.noteRepo.GetByID(c.Request.Context(), noteID)
+		if err != nil {
+			return nil, err
+		}
+		runes := []rune(note.Body)
+		if anchor.Start < 0 || anchor.End <= anchor.Start || anchor.End > len(runes) {
+			return nil, NewBadRequestError(fmt.Sprintf("anchor must satisfy 0 <= start < end <= %d", len(runes)))
+		}
+		anchor = &entity.CommentAnchor{Start: anchor.Start, End: anchor.End, Quote: string(runes[anchor.Start:anchor.End])}
+	}
+
+	comment, err := s.commentRepo.Create(c.Request.Context(), &entity.Comment{
+		NoteID:   noteID,
+		AuthorID: userID,
+		Body:     body,
+		Anchor:   anchor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.produceCommentAdded(c.Request.Context(), comment)
+	s.mentions.Notify(c.Request.Context(), noteID, userID, &comment.ID, "", body)
+	return comment, nil
+}
+
+// Reply thêm reply vào thread; reply vào một reply được gắn vào comment gốc của thread đó
+func (s *CommentService) Reply(c *gin.Context, noteID, commentID uuid.UUID, body string) (*entity.Comment, error) {
+	userID, err := s.requireAccess(c, noteID, authz.Comment)
+	if err != nil {
+		return nil, err
+	}
+	if body, err = normalizeCommentBody(body); err != nil {
+		return nil, err
+	}
+
+	root, err := s.getThreadRoot(c.Request.Context(), noteID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := s.commentRepo.Create(c.Request.Context(), &entity.Comment{
+		NoteID:   noteID,
+		ParentID: &root.ID,
+		AuthorID: userID,
+		Body:     body,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.produceCommentAdded(c.Request.Context(), reply)
+	s.mentions.Notify(c.Request.Context(), noteID, userID, &reply.ID, "", body)
+	return reply, nil
+}
+
+// Edit sửa nội dung comment, chỉ tác giả được sửa
+func (s *CommentService) Edit(c *gin.Context, noteID, commentID uuid.UUID, body string) (*entity.Comment, error) {
+	comment, err := s.getOwnComment(c, noteID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if body, err = normalizeCommentBody(body); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.commentRepo.UpdateBody(c.Request.Context(), comment.ID, body, now); err != nil {
+		return nil, err
+	}
+	s.mentions.Notify(c.Request.Context(), noteID, comment.AuthorID, &comment.ID, comment.Body, body)
+	comment.Body = body
+	comment.EditedAt = &now
+	return comment, nil
+}
+
+// Delete xoá comment của chính mình. Comment gốc còn reply được giữ lại dạng đã xoá để thread không mất ngữ cảnh.
+func (s *CommentService) Delete(c *gin.Context, noteID, commentID uuid.UUID) error {
+	comment, err := s.getOwnComment(c, noteID, commentID)
+	if err != nil {
+		return err
+	}
+	ctx := c.Request.Context()
+
+	if !comment.IsReply() {
+		replies, err := s.commentRepo.CountReplies(ctx, comment.ID)
+		if err != nil {
+			return err
+		}
+		if replies > 0 {
+			return s.commentRepo.MarkDeleted(ctx, comment.ID, time.Now())
+		}
+		return s.commentRepo.Delete(ctx, comment.ID)
+	}
+
+	if err := s.commentRepo.Delete(ctx, comment.ID); err != nil {
+		return err
+	}
+	// Reply cuối cùng của một comment gốc đã xoá: dọn luôn thread rỗng
+	root, err := s.commentRepo.GetByID(ctx, *comment.ParentID)
+	if err != nil || !root.IsDeleted() {
+		return err
+	}
+	remaining, err := s.commentRepo.CountReplies(ctx, root.ID)
+	if err != nil || remaining > 0 {
+		return err
+	}
+	return s.commentRepo.Delete(ctx, root.ID)
+}
+
+// Resolve đánh dấu thread chứa comment là đã xử lý; cần quyền WRITE trên note
+func (s *CommentService) Resolve(c *gin.Context, noteID, commentID uuid.UUID) (*entity.Comment, error) {
+	userID, err := s.requireAccess(c, noteID, authz.Edit)
+	if err != nil {
+		return nil, err
+	}
+	root, err := s.getThreadRoot(c.Request.Context(), noteID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if root.IsResolved() {
+		return root, nil
+	}
+
+	now := time.Now()
+	if err := s.commentRepo.SetResolved(c.Request.Context(), root.ID, &userID, &now); err != nil {
+		return nil, err
+	}
+	root.ResolvedAt = &now
+	root.ResolvedBy = &userID
+	return root, nil
+}
+
+// Reopen mở lại thread đã resolve; cần quyền WRITE trên note
+func (s *CommentService) Reopen(c *gin.Context, noteID, commentID uuid.UUID) (*entity.Comment, error) {
+	if _, err := s.requireAccess(c, noteID, authz.Edit); err != nil {
+		return nil, err
+	}
+	root, err := s.getThreadRoot(c.Request.Context(), noteID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if !root.IsResolved() {
+		return root, nil
+	}
+
+	if err := s.commentRepo.SetResolved(c.Request.Context(), root.ID, nil, nil); err != nil {
+		return nil, err
+	}
+	root.ResolvedAt = nil
+	root.ResolvedBy = nil
+	return root, nil
+}
+
+// requireAccess trả về user hiện tại nếu user được thực hiện action trên note
+func (s *CommentService) requireAccess(c *gin.Context, noteID uuid.UUID, action authz.Action) (uuid.UUID, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	accessLevel, _ := s.noteRepo.GetAccessLevel(c.Request.Context(), noteID, userID)
+	principal := authz.OnAsset(userID, accessLevel)
+	if err := authorize(principal, authz.View, entity.ResourceNote, "You do not have permission to view this note"); err != nil {
+		return userID, err
+	}
+	if err := authorize(principal, action, entity.ResourceNote, "You need write permission on this note to resolve comments"); err != nil {
+		return userID, err
+	}
+	return userID, nil
+}
+
+func (s *CommentService) getComment(ctx context.Context, noteID, commentID uuid.UUID) (*entity.Comment, error) {
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && comment.NoteID != noteID) {
+		return nil, NewNotFoundError(fmt.Sprintf("comment %s not found on note %s", commentID, noteID))
+	}
+	return comment, err
+}
+
+func (s *CommentService) getThreadRoot(ctx context.Context, noteID, commentID uuid.UUID) (*entity.Comment, error) {
+	comment, err := s.getComment(ctx, noteID, commentID)
+	if err != nil || !comment.IsReply() {
+		return comment, err
+	}
+	return s.getComment(ctx, noteID, *comment.ParentID)
+}
+
+// getOwnComment trả về comment nếu user hiện tại đọc được note và là tác giả
+func (s *CommentService) getOwnComment(c *gin.Context, noteID, commentID uuid.UUID) (*entity.Comment, error) {
+	userID, err := s.requireAccess(c, noteID, authz.View)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.getComment(c.Request.Context(), noteID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.IsDeleted() {
+		return nil, NewNotFoundError(fmt.Sprintf("comment %s has been deleted", commentID))
+	}
+	author := authz.Principal{UserID: userID, Actor: comment.AuthorID == userID}
+	if err := authorize(author, authz.Edit, entity.ResourceComment, "Only the author can change this comment"); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (s *CommentService) produceCommentAdded(ctx context.Context, comment *entity.Comment) {
+	ownerID, err := s.noteRepo.GetOwner(ctx, comment.NoteID)
+	if err != nil {
+		ownerID = comment.AuthorID
+	}
+	replyTo := ""
+	if comment.ParentID != nil {
+		replyTo = comment.ParentID.String()
+	}
+	go s.eventProducer.Produce(event.NewCommentEvent(comment.NoteID.String(), ownerID.String(), comment.AuthorID.String(), comment.ID.String(), replyTo, time.Now().String()))
+}
+
+func normalizeCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", NewBadRequestError("comment body is required")
+	}
+	if utf8.RuneCountInString(body) > MaxCommentLength {
+		return "", NewBadRequestError(fmt.Sprintf("comment must be at most %d characters", MaxCommentLength))
+	}
+	return body, nil
+}
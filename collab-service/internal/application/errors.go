@@ -59,6 +59,38 @@ func NewConflictError(msg string) *HTTPError {
 	}
 }
 
+// NewGoneError trả về lỗi 410, dùng khi tài nguyên đã tồn tại nhưng không còn hiệu lực (ví dụ link hết hạn)
+func NewGoneError(msg string) *HTTPError {
+	return &HTTPError{
+		Code:    http.StatusGone,
+		Message: msg,
+	}
+}
+
+// NewPreconditionFailedError trả về lỗi 412, dùng khi If-Match không khớp version hiện tại
+func NewPreconditionFailedError(msg string) *HTTPError {
+	return &HTTPError{
+		Code:    http.StatusPreconditionFailed,
+		Message: msg,
+	}
+}
+
+// NewPayloadTooLargeError trả về lỗi 413, dùng khi file vượt giới hạn kích thước hoặc quota
+func NewPayloadTooLargeError(msg string) *HTTPError {
+	return &HTTPError{
+		Code:    http.StatusRequestEntityTooLarge,
+		Message: msg,
+	}
+}
+
+// NewTooManyRequestsError trả về lỗi 429
+func NewTooManyRequestsError(msg string) *HTTPError {
+	return &HTTPError{
+		Code:    http.StatusTooManyRequests,
+		Message: msg,
+	}
+}
+
 // NewInternalServerError trả về lỗi 500
 func NewInternalServerError(msg string) *HTTPError {
 	return &HTTPError{
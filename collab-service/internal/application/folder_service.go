@@ -1,31 +1,49 @@
 package application
 
 import (
+	"collab-service/internal/domain/authz"
 	"collab-service/internal/domain/entity"
 	"collab-service/internal/infrastructure/external/event"
 	"collab-service/internal/interface/http/middleware"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type FolderService struct {
 	folderRepo    entity.FolderRepository
+	teamRepo      entity.TeamRepository
 	eventProducer *event.AssetChangeProducer
 }
 
-func NewFolderService(repo entity.FolderRepository) *FolderService {
+func NewFolderService(repo entity.FolderRepository, teamRepo entity.TeamRepository) *FolderService {
 	return &FolderService{
 		folderRepo:    repo,
+		teamRepo:      teamRepo,
 		eventProducer: event.GetAssetChangeProducer(),
 	}
 }
 
-func (s *FolderService) Create(c *gin.Context, name string) (*entity.Folder, error) {
+// Create tạo folder mới; với parentID khác nil folder được tạo bên trong folder cha và kế thừa quyền của cha
+func (s *FolderService) Create(c *gin.Context, name string, parentID *uuid.UUID) (*entity.Folder, error) {
 	folder := entity.NewFolder(name)
 
 	userId, _ := middleware.GetUserInfoFromGin(c)
+	if parentID != nil {
+		parentAccess, err := s.folderRepo.GetAccessLevel(c.Request.Context(), *parentID, userId)
+		if err != nil {
+			return nil, NewNotFoundError(fmt.Sprintf("parent folder %s not found", *parentID))
+		}
+		if err := authorize(authz.OnAsset(userId, parentAccess), authz.Edit, entity.ResourceFolder, "You do not have permission to create folders in the parent folder"); err != nil {
+			return nil, err
+		}
+		folder.ParentID = parentID
+	}
+
 	folder.Shared = append(folder.Shared, entity.FolderShare{
 		UserID:      userId,
 		AccessLevel: entity.AccessLevelOwner,
@@ -54,6 +72,15 @@ func (s *FolderService) Create(c *gin.Context, name string) (*entity.Folder, err
 }
 
 func (s *FolderService) GetFolderByID(c *gin.Context, id uuid.UUID) (*entity.Folder, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	accessLevel, err := s.folderRepo.GetAccessLevel(c.Request.Context(), id, userID)
+	if err != nil {
+		return nil, NewNotFoundError(err.Error())
+	}
+	if err := authorize(authz.OnAsset(userID, accessLevel), authz.View, entity.ResourceFolder, "You do not have permission to view this folder"); err != nil {
+		return nil, err
+	}
+
 	folder, err := s.folderRepo.GetByID(c.Request.Context(), id)
 	if err != nil {
 		return nil, NewNotFoundError(err.Error())
@@ -71,22 +98,46 @@ func (s *FolderService) GetAllFolderCanAccess(c *gin.Context) ([]*entity.Folder,
 	return folders, nil
 }
 
-func (s *FolderService) ShareFolder(c *gin.Context, folderID, userID uuid.UUID, accessLevel entity.AccessLevel) error {
-	// Check if the user has permission to share the folder
+// ExplainAccess giải thích vì sao user có quyền hiện tại trên folder, kể cả quyền kế thừa từ folder cha.
+// userID nil là chính caller; xem quyền của người khác chỉ dành cho owner.
+func (s *FolderService) ExplainAccess(c *gin.Context, folderID uuid.UUID, userID *uuid.UUID) (*entity.AccessExplanation, error) {
 	currentUserID, _ := middleware.GetUserInfoFromGin(c)
 
-	currentAccessLevel, _ := s.folderRepo.GetAccessLevel(c.Request.Context(), folderID, currentUserID)
-	if !currentAccessLevel.GreaterThan(entity.AccessLevelOwner) {
-		return NewForbiddenError("You do not have permission to share this folder")
+	explanation, err := s.folderRepo.ExplainAccess(c.Request.Context(), folderID, currentUserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewNotFoundError(fmt.Sprintf("folder %s not found", folderID))
+	}
+	if err != nil || userID == nil || *userID == currentUserID {
+		return explanation, err
 	}
 
-	userAccessLevel, _ := s.folderRepo.GetAccessLevel(c.Request.Context(), folderID, userID)
+	if err := authorize(authz.OnAsset(currentUserID, explanation.AccessLevel), authz.Share, entity.ResourceFolder, "Only the owner can explain another user's access to this folder"); err != nil {
+		return nil, err
+	}
+	return s.folderRepo.ExplainAccess(c.Request.Context(), folderID, *userID)
+}
 
-	if userAccessLevel != entity.AccessLevelNone {
-		return s.folderRepo.ChangeAccessLevel(c.Request.Context(), folderID, userID, accessLevel)
+// ShareFolder cấp quyền trên folder cho user; expiresAt khác nil thì share tự hết hạn sau thời điểm đó
+func (s *FolderService) ShareFolder(c *gin.Context, folderID, userID uuid.UUID, accessLevel entity.AccessLevel, expiresAt *time.Time) error {
+	// Check if the user has permission to share the folder
+	currentUserID, _ := middleware.GetUserInfoFromGin(c)
+
+	currentAccessLevel, _ := s.folderRepo.GetAccessLevel(c.Request.Context(), folderID, currentUserID)
+	if err := authorize(authz.OnAsset(currentUserID, currentAccessLevel), authz.Share, entity.ResourceFolder, "You do not have permission to share this folder"); err != nil {
+		return err
+	}
+	if err := checkShareExpiry(accessLevel, expiresAt); err != nil {
+		return err
 	}
 
-	err := s.folderRepo.ShareFolder(c.Request.Context(), folderID, userID, accessLevel)
+	// ShareFolder cập nhật share trực tiếp nếu đã có; không dựa vào quyền hiệu lực vì quyền đó có thể đến từ team
+	err := s.folderRepo.ShareFolder(c.Request.Context(), folderID, userID, accessLevel, expiresAt)
+	if errors.Is(err, entity.ErrShareTargetIsOwner) {
+		return NewConflictError(fmt.Sprintf("user %s already owns this folder", userID))
+	}
+	if err != nil {
+		return err
+	}
 
 	go s.eventProducer.Produce(event.NewAssetEvent(
 		event.FolderShared,
@@ -98,32 +149,88 @@ func (s *FolderService) ShareFolder(c *gin.Context, folderID, userID uuid.UUID,
 		accessLevel,
 	))
 
-	return err
+	return nil
 }
 
 func (s *FolderService) RevokeAccess(c *gin.Context, folderID, userID uuid.UUID) error {
 	// Check if the user has permission to revoke access
 	currentUserID, _ := middleware.GetUserInfoFromGin(c)
 	currentAccessLevel, _ := s.folderRepo.GetAccessLevel(c.Request.Context(), folderID, currentUserID)
-	if !currentAccessLevel.GreaterThan(entity.AccessLevelOwner) {
-		return NewForbiddenError("You do not have permission to revoke access for this folder")
+	if err := authorize(authz.OnAsset(currentUserID, currentAccessLevel), authz.Share, entity.ResourceFolder, "You do not have permission to revoke access for this folder"); err != nil {
+		return err
 	}
 
 	err := s.folderRepo.RevokeAccess(c.Request.Context(), folderID, userID)
 
-	go s.eventProducer.Produce(event.NewAssetEvent(
+	go s.eventProducer.Produce(event.NewUnshareEvent(
 		event.FolderUnshared,
 		event.Folder,
 		folderID.String(),
-		currentUserID.String(),
 		userID.String(),
+		currentUserID.String(),
 		time.Now().String(),
-		entity.AccessLevelNone,
 	))
 
 	return err
 }
 
+// ShareFolderWithTeam cấp quyền trên folder cho mọi thành viên hiện tại và tương lai của team
+func (s *FolderService) ShareFolderWithTeam(c *gin.Context, folderID, teamID uuid.UUID, accessLevel entity.AccessLevel, expiresAt *time.Time) error {
+	currentUserID, _ := middleware.GetUserInfoFromGin(c)
+
+	currentAccessLevel, _ := s.folderRepo.GetAccessLevel(c.Request.Context(), folderID, currentUserID)
+	if err := authorize(authz.OnAsset(currentUserID, currentAccessLevel), authz.Share, entity.ResourceFolder, "You do not have permission to share this folder"); err != nil {
+		return err
+	}
+
+	if err := checkTeamShareTarget(c.Request.Context(), s.teamRepo, teamID, accessLevel); err != nil {
+		return err
+	}
+	if err := checkShareExpiry(accessLevel, expiresAt); err != nil {
+		return err
+	}
+
+	if err := s.folderRepo.ShareFolderWithTeam(c.Request.Context(), folderID, teamID, accessLevel, expiresAt); err != nil {
+		return err
+	}
+
+	go s.eventProducer.Produce(event.NewTeamShareEvent(
+		event.FolderTeamShared,
+		event.Folder,
+		folderID.String(),
+		teamID.String(),
+		currentUserID.String(),
+		time.Now().String(),
+		accessLevel,
+	))
+
+	return nil
+}
+
+func (s *FolderService) RevokeTeamAccess(c *gin.Context, folderID, teamID uuid.UUID) error {
+	currentUserID, _ := middleware.GetUserInfoFromGin(c)
+	currentAccessLevel, _ := s.folderRepo.GetAccessLevel(c.Request.Context(), folderID, currentUserID)
+	if err := authorize(authz.OnAsset(currentUserID, currentAccessLevel), authz.Share, entity.ResourceFolder, "You do not have permission to revoke access for this folder"); err != nil {
+		return err
+	}
+
+	if err := s.folderRepo.RevokeTeamAccess(c.Request.Context(), folderID, teamID); err != nil {
+		return err
+	}
+
+	go s.eventProducer.Produce(event.NewTeamShareEvent(
+		event.FolderTeamUnshared,
+		event.Folder,
+		folderID.String(),
+		teamID.String(),
+		currentUserID.String(),
+		time.Now().String(),
+		entity.AccessLevelNone,
+	))
+
+	return nil
+}
+
 func (s *FolderService) Update(c *gin.Context, folder *entity.Folder) error {
 
 	userID, _ := middleware.GetUserInfoFromGin(c)
@@ -133,11 +240,14 @@ func (s *FolderService) Update(c *gin.Context, folder *entity.Folder) error {
 		return NewNotFoundError(err.Error())
 	}
 
-	if !role.GreaterThan(entity.AccessLevelWrite) {
-		return NewForbiddenError("You do not have permission to update this folder")
+	if err := authorize(authz.OnAsset(userID, role), authz.Edit, entity.ResourceFolder, "You do not have permission to update this folder"); err != nil {
+		return err
 	}
 
 	if err := s.folderRepo.Update(c.Request.Context(), folder); err != nil {
+		if errors.Is(err, entity.ErrStaleVersion) {
+			return NewPreconditionFailedError("folder has been modified by someone else, reload it and try again")
+		}
 		return NewBadRequestError(err.Error())
 	}
 
@@ -163,16 +273,23 @@ func (s *FolderService) Delete(c *gin.Context, id uuid.UUID) error {
 
 	userID, _ := middleware.GetUserInfoFromGin(c)
 
-	ownerID, err := s.folderRepo.GetOwner(c.Request.Context(), id)
+	// Owner của folder hoặc của một folder cha đều được xoá, kéo theo toàn bộ folder con và note bên dưới
+	accessLevel, err := s.folderRepo.GetAccessLevel(c.Request.Context(), id, userID)
 	if err != nil {
 		return NewNotFoundError(err.Error())
 	}
 
-	if userID != ownerID {
-		return NewForbiddenError("You do not have permission to delete this folder")
+	if err := authorize(authz.OnAsset(userID, accessLevel), authz.Delete, entity.ResourceFolder, "You do not have permission to delete this folder"); err != nil {
+		return err
 	}
 
-	if err := s.folderRepo.Delete(c.Request.Context(), id); err != nil {
+	ownerID, err := s.folderRepo.GetOwner(c.Request.Context(), id)
+	if err != nil {
+		ownerID = userID
+	}
+
+	// Folder cùng cây con vào thùng rác, có thể khôi phục cho tới khi bị purge
+	if err := s.folderRepo.Trash(c.Request.Context(), id, userID); err != nil {
 		return NewBadRequestError(err.Error())
 	}
 
@@ -193,3 +310,86 @@ func (s *FolderService) Delete(c *gin.Context, id uuid.UUID) error {
 
 	return nil
 }
+
+// GetChildren trả về các folder con trực tiếp; user cần quyền đọc trên folder cha
+func (s *FolderService) GetChildren(c *gin.Context, folderID uuid.UUID) ([]*entity.Folder, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	accessLevel, err := s.folderRepo.GetAccessLevel(c.Request.Context(), folderID, userID)
+	if err != nil {
+		return nil, NewNotFoundError(err.Error())
+	}
+	if err := authorize(authz.OnAsset(userID, accessLevel), authz.View, entity.ResourceFolder, "You do not have permission to view this folder"); err != nil {
+		return nil, err
+	}
+
+	return s.folderRepo.GetChildren(c.Request.Context(), folderID)
+}
+
+// GetBreadcrumbs trả về đường dẫn từ folder cao nhất mà user nhìn thấy xuống tới folder hiện tại
+func (s *FolderService) GetBreadcrumbs(c *gin.Context, folderID uuid.UUID) ([]*entity.Folder, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	accessLevel, err := s.folderRepo.GetAccessLevel(c.Request.Context(), folderID, userID)
+	if err != nil {
+		return nil, NewNotFoundError(err.Error())
+	}
+	if err := authorize(authz.OnAsset(userID, accessLevel), authz.View, entity.ResourceFolder, "You do not have permission to view this folder"); err != nil {
+		return nil, err
+	}
+
+	return s.folderRepo.GetBreadcrumbs(c.Request.Context(), folderID, userID)
+}
+
+// MoveFolder chuyển folder (cùng toàn bộ cây con) vào folder cha mới; parentID nil đưa folder về gốc.
+// User cần quyền owner trên folder và quyền ghi trên folder cha mới.
+func (s *FolderService) MoveFolder(c *gin.Context, folderID uuid.UUID, parentID *uuid.UUID) error {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	accessLevel, err := s.folderRepo.GetAccessLevel(ctx, folderID, userID)
+	if err != nil {
+		return NewNotFoundError(err.Error())
+	}
+	if err := authorize(authz.OnAsset(userID, accessLevel), authz.Move, entity.ResourceFolder, "You do not have permission to move this folder"); err != nil {
+		return err
+	}
+
+	if parentID != nil {
+		parentAccess, err := s.folderRepo.GetAccessLevel(ctx, *parentID, userID)
+		if err != nil {
+			return NewNotFoundError(fmt.Sprintf("parent folder %s not found", *parentID))
+		}
+		if err := authorize(authz.OnAsset(userID, parentAccess), authz.Edit, entity.ResourceFolder, "You do not have permission to move folders into the target folder"); err != nil {
+			return err
+		}
+
+		// Không cho chuyển folder vào chính nó hoặc vào một folder con của nó
+		subtree, err := s.folderRepo.GetSubtreeIDs(ctx, folderID)
+		if err != nil {
+			return err
+		}
+		for _, id := range subtree {
+			if id == *parentID {
+				return NewBadRequestError("cannot move a folder into itself or one of its sub-folders")
+			}
+		}
+	}
+
+	if err := s.folderRepo.SetParent(ctx, folderID, parentID); err != nil {
+		return NewBadRequestError(err.Error())
+	}
+
+	target := ""
+	if parentID != nil {
+		target = parentID.String()
+	}
+	go s.eventProducer.Produce(event.NewMoveEvent(
+		event.FolderMoved,
+		event.Folder,
+		folderID.String(),
+		target,
+		userID.String(),
+		time.Now().String(),
+	))
+
+	return nil
+}
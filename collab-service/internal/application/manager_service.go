@@ -1,10 +1,14 @@
 package application
 
 import (
+	"collab-service/internal/domain/authz"
 	"collab-service/internal/domain/entity"
+	"collab-service/internal/interface/http/middleware"
 	"context"
 	"errors"
+	"fmt"
 
+	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
@@ -27,12 +31,11 @@ func NewManagerService(assetRepo entity.ManagerRepository, teamRepo entity.TeamR
 	}
 }
 
-// GetTeamAssets retrieves all assets that team members own or can access
-func (s *ManagerService) GetTeamAssets(ctx context.Context, teamID string) ([]*entity.Note, error) {
-	teamUUID, err := uuid.Parse(teamID)
-	if err != nil {
-		return nil, err
-	}
+// GetTeamAssets retrieves all assets that members of the team, or of any of its
+// sub-teams, own or can access. Only managers of the team, directly or through a parent team, may see them.
+func (s *ManagerService) GetTeamAssets(c *gin.Context, teamUUID uuid.UUID) ([]*entity.Note, error) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserInfoFromGin(c)
 
 	// Verify team exists
 	exists, err := s.teamRepo.ExistsByID(ctx, teamUUID)
@@ -43,22 +46,41 @@ func (s *ManagerService) GetTeamAssets(ctx context.Context, teamID string) ([]*e
 		return nil, ErrTeamNotFound
 	}
 
-	// Get assets for the team
-	assets, err := s.assetRepo.GetAssetsByTeamID(ctx, teamUUID)
+	// The global MANAGER user type is not enough: the caller must manage this team
+	role, err := s.teamRepo.GetRole(ctx, teamUUID, userID)
 	if err != nil {
 		return nil, err
 	}
+	if err := authorize(authz.InTeam(userID, role), authz.ManageTeam, entity.ResourceTeam,
+		fmt.Sprintf("only managers of team %s can view its assets", teamUUID)); err != nil {
+		return nil, err
+	}
 
-	return assets, nil
-}
+	// Aggregate over the whole subtree
+	subtree, err := s.teamRepo.GetSubtree(ctx, teamUUID)
+	if err != nil {
+		return nil, err
+	}
+	teamIDs := make([]uuid.UUID, len(subtree))
+	for i, team := range subtree {
+		teamIDs[i] = team.ID
+	}
 
-// GetUserAssets retrieves all assets owned by or shared with the user
-func (s *ManagerService) GetUserAssets(ctx context.Context, userID string) ([]*entity.Note, error) {
-	userUUID, err := uuid.Parse(userID)
+	// Get assets for the teams
+	assets, err := s.assetRepo.GetAssetsByTeamIDs(ctx, teamIDs)
 	if err != nil {
 		return nil, err
 	}
 
+	return assets, nil
+}
+
+// GetUserAssets retrieves all assets owned by or shared with the user.
+// Users may see their own assets; others must manage one of the teams the user belongs to.
+func (s *ManagerService) GetUserAssets(c *gin.Context, userUUID uuid.UUID) ([]*entity.Note, error) {
+	ctx := c.Request.Context()
+	callerID, _ := middleware.GetUserInfoFromGin(c)
+
 	// Verify user exists
 	exists, err := s.userRepo.ExistsByID(ctx, userUUID)
 	if err != nil {
@@ -68,6 +90,12 @@ func (s *ManagerService) GetUserAssets(ctx context.Context, userID string) ([]*e
 		return nil, ErrUserNotFound
 	}
 
+	if callerID != userUUID {
+		if err := s.checkManagesUser(ctx, callerID, userUUID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Get assets for the user
 	assets, err := s.assetRepo.GetAssetsByUserID(ctx, userUUID)
 	if err != nil {
@@ -76,3 +104,23 @@ func (s *ManagerService) GetUserAssets(ctx context.Context, userID string) ([]*e
 
 	return assets, nil
 }
+
+// checkManagesUser allows the caller when they manage at least one active team the user belongs to
+func (s *ManagerService) checkManagesUser(ctx context.Context, callerID uuid.UUID, userID uuid.UUID) error {
+	teams, err := s.teamRepo.GetAllByUserID(ctx, userID, false)
+	if err != nil {
+		return err
+	}
+
+	for _, team := range teams {
+		role, err := s.teamRepo.GetRole(ctx, team.ID, callerID)
+		if err != nil {
+			return err
+		}
+		if authz.Allowed(authz.InTeam(callerID, role), authz.ManageTeam, entity.ResourceTeam) {
+			return nil
+		}
+	}
+
+	return NewForbiddenError(fmt.Sprintf("only managers of a team user %s belongs to can view their assets", userID))
+}
@@ -0,0 +1,115 @@
+package application
+
+import (
+	"collab-service/internal/domain/authz"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/external/event"
+	"collab-service/internal/infrastructure/logger"
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxMentionsPerSave giới hạn số username được resolve cho một lần lưu, tránh một note dài gọi user-service quá nặng
+const maxMentionsPerSave = 50
+
+// @username phải đứng đầu hoặc sau ký tự không thuộc username, để "alice@example.com" không bị coi là mention
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.@-])@([A-Za-z0-9_][A-Za-z0-9_.-]{0,63})`)
+
+// ParseMentions trả về các username được @mention trong text (chữ thường, không trùng, theo thứ tự xuất hiện)
+func ParseMentions(text string) []string {
+	seen := make(map[string]bool)
+	var usernames []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		// Dấu câu ở cuối ("@alice." hoặc "@bob-") không thuộc username
+		username := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if username == "" || seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
+
+// MentionNotifier phát USER_MENTIONED cho những user mới được @mention trong note hoặc comment
+type MentionNotifier struct {
+	noteRepo      entity.NoteRepository
+	userRepo      entity.UserRepository
+	eventProducer *event.AssetChangeProducer
+}
+
+func NewMentionNotifier(noteRepo entity.NoteRepository, userRepo entity.UserRepository) *MentionNotifier {
+	return &MentionNotifier{
+		noteRepo:      noteRepo,
+		userRepo:      userRepo,
+		eventProducer: event.GetAssetChangeProducer(),
+	}
+}
+
+// Notify so sánh mention trong before và after rồi báo cho các user chỉ có trong after.
+// Chạy nền vì phải gọi user-service; user không đọc được note hoặc chính người viết sẽ không nhận thông báo.
+func (m *MentionNotifier) Notify(ctx context.Context, noteID, actorID uuid.UUID, commentID *uuid.UUID, before, after string) {
+	if m == nil {
+		return
+	}
+	usernames := newMentions(before, after)
+	if len(usernames) == 0 {
+		return
+	}
+	go m.notify(context.WithoutCancel(ctx), noteID, actorID, commentID, usernames)
+}
+
+func (m *MentionNotifier) notify(ctx context.Context, noteID, actorID uuid.UUID, commentID *uuid.UUID, usernames []string) {
+	users, err := m.userRepo.ListByUsernames(ctx, usernames)
+	if err != nil {
+		logger.Error("failed to resolve mentioned users", "noteId", noteID.String(), "error", err.Error())
+		return
+	}
+
+	ownerID, err := m.noteRepo.GetOwner(ctx, noteID)
+	if err != nil {
+		ownerID = actorID
+	}
+	comment := ""
+	if commentID != nil {
+		comment = commentID.String()
+	}
+
+	for _, user := range users {
+		if user.ID == actorID {
+			continue
+		}
+		// Chỉ báo cho người đọc được note
+		accessLevel, _ := m.noteRepo.GetAccessLevel(ctx, noteID, user.ID)
+		if !authz.Allowed(authz.OnAsset(user.ID, accessLevel), authz.View, entity.ResourceNote) {
+			continue
+		}
+		if err := m.eventProducer.Produce(event.NewMentionEvent(noteID.String(), ownerID.String(), actorID.String(), user.ID.String(), comment, time.Now().String())); err != nil {
+			logger.Error("failed to produce mention event", "noteId", noteID.String(), "userId", user.ID.String(), "error", err.Error())
+		}
+	}
+}
+
+// newMentions trả về các username có trong after nhưng chưa có trong before
+func newMentions(before, after string) []string {
+	existing := make(map[string]bool)
+	for _, username := range ParseMentions(before) {
+		existing[username] = true
+	}
+
+	var added []string
+	for _, username := range ParseMentions(after) {
+		if existing[username] {
+			continue
+		}
+		added = append(added, username)
+		if len(added) == maxMentionsPerSave {
+			break
+		}
+	}
+	return added
+}
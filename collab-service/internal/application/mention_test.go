@@ -0,0 +1,79 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"email address is not a mention", "mail alice@example.com", nil},
+		{"wrapped in punctuation", "(@Bob).", []string{"bob"}},
+		{"trailing dash and dot are trimmed", "@carol- and @dave.", []string{"carol", "dave"}},
+		{"dots inside the username are kept", "@eve.smith said hi", []string{"eve.smith"}},
+		{"case-folded and de-duplicated", "@Alice, @alice and @ALICE", []string{"alice"}},
+		{"order of first appearance", "@bob @carol @bob @alice", []string{"bob", "carol", "alice"}},
+		{"start of a line", "hi\n@erin_1", []string{"erin_1"}},
+		{"double at sign", "@@bob", nil},
+		{"username must start with a word character", "@-x @. @", nil},
+		{"email next to a mention", "bob@x.com cc @dan", []string{"dan"}},
+		{"no mentions", "plain text", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseMentions(tt.text))
+		})
+	}
+}
+
+func TestNewMentions(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   []string
+	}{
+		{"added mention", "hello @alice", "hello @alice and @bob", []string{"bob"}},
+		{"case change is not a new mention", "@Alice", "@alice", nil},
+		{"removed mention", "@alice @bob", "@bob", nil},
+		{"repeated mention notifies once", "", "@carol then @Carol", []string{"carol"}},
+		{"mention moved within the text", "@alice at the start", "at the end @alice", nil},
+		{"email added next to a mention", "@alice", "@alice alice@example.com @dan", []string{"dan"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newMentions(tt.before, tt.after))
+		})
+	}
+}
+
+func TestNewMentionsCap(t *testing.T) {
+	mentions := func(from, to int) string {
+		var b strings.Builder
+		for i := from; i < to; i++ {
+			fmt.Fprintf(&b, "@user%d ", i)
+		}
+		return b.String()
+	}
+
+	added := newMentions("", mentions(0, 60))
+	require.Len(t, added, maxMentionsPerSave)
+	assert.Equal(t, "user0", added[0])
+	assert.Equal(t, "user49", added[maxMentionsPerSave-1])
+
+	// Mention đã có từ trước không tính vào giới hạn
+	added = newMentions(mentions(0, 10), mentions(0, 70))
+	require.Len(t, added, maxMentionsPerSave)
+	assert.Equal(t, "user10", added[0])
+	assert.Equal(t, "user59", added[maxMentionsPerSave-1])
+}
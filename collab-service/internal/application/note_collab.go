@@ -0,0 +1,594 @@
+package application
+
+import (
+	"collab-service/internal/domain/authz"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/domain/ot"
+	"collab-service/internal/infrastructure/logger"
+	"collab-service/internal/interface/http/middleware"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// collabOutboundBuffer là số message chờ gửi tối đa của một phiên; client chậm hơn sẽ bị ngắt kết nối
+const collabOutboundBuffer = 256
+
+// Các loại message trong giao thức chỉnh sửa chung.
+// Client gửi "op" và "cursor"; server gửi "init", "op", "ack", "cursor", "presence" và "error".
+const (
+	CollabMsgInit     = "init"
+	CollabMsgOp       = "op"
+	CollabMsgAck      = "ack"
+	CollabMsgCursor   = "cursor"
+	CollabMsgPresence = "presence"
+	CollabMsgError    = "error"
+)
+
+// CollabMessage là message JSON trao đổi qua WebSocket và qua Redis pub/sub giữa các instance
+type CollabMessage struct {
+	Type         string                   `json:"type"`
+	Rev          int64                    `json:"rev,omitempty"`
+	BaseRev      int64                    `json:"baseRev,omitempty"`
+	Op           ot.Operation             `json:"op,omitempty"`
+	OpID         string                   `json:"opId,omitempty"`
+	SessionID    string                   `json:"sessionId,omitempty"`
+	UserID       *uuid.UUID               `json:"userId,omitempty"`
+	Doc          *string                  `json:"doc,omitempty"`
+	CanEdit      *bool                    `json:"canEdit,omitempty"`
+	Position     *int                     `json:"position,omitempty"`
+	SelectionEnd *int                     `json:"selectionEnd,omitempty"`
+	Users        []*entity.CollabPresence `json:"users,omitempty"`
+	Message      string                   `json:"message,omitempty"`
+}
+
+// CollabSession là một kết nối WebSocket đang chỉnh sửa note trên instance này
+type CollabSession struct {
+	ID      string
+	NoteID  uuid.UUID
+	UserID  uuid.UUID
+	CanEdit bool
+
+	c        *gin.Context
+	room     *collabRoom
+	outbound chan []byte
+	rev      int64 // revision cuối cùng đã gửi cho client
+	ready    bool  // đã nhận "init"; op tới trước đó đã nằm trong nội dung init
+	closed   bool
+	lastEdit time.Time
+}
+
+// Outbound là các message cần ghi xuống WebSocket; channel bị đóng khi phiên kết thúc
+func (s *CollabSession) Outbound() <-chan []byte {
+	return s.outbound
+}
+
+// collabRoom gom các phiên của cùng một note trên instance này
+type collabRoom struct {
+	noteID   uuid.UUID
+	mu       sync.Mutex
+	sessions map[string]*CollabSession
+
+	// persistMu đảm bảo không lưu snapshot bằng gin.Context của phiên đã kết thúc
+	persistMu   sync.Mutex
+	unsubscribe func()
+	stop        chan struct{}
+}
+
+// NoteCollabService điều phối chỉnh sửa note thời gian thực: áp dụng op qua NoteCollabStore,
+// fan-out qua Redis pub/sub và định kỳ lưu snapshot bằng NoteService.Update
+type NoteCollabService struct {
+	noteService      *NoteService
+	store            entity.NoteCollabStore
+	snapshotInterval time.Duration
+
+	mu    sync.Mutex
+	rooms map[uuid.UUID]*collabRoom
+}
+
+func NewNoteCollabService(noteService *NoteService, store entity.NoteCollabStore, snapshotInterval time.Duration) *NoteCollabService {
+	return &NoteCollabService{
+		noteService:      noteService,
+		store:            store,
+		snapshotInterval: snapshotInterval,
+		rooms:            make(map[uuid.UUID]*collabRoom),
+	}
+}
+
+// Join mở phiên chỉnh sửa cho user hiện tại. User cần ít nhất quyền đọc; chỉ WRITE trở lên mới được gửi op.
+func (s *NoteCollabService) Join(c *gin.Context, noteID uuid.UUID) (*CollabSession, error) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	accessLevel, _ := s.noteService.GetAccessLevel(c, noteID, userID)
+	principal := authz.OnAsset(userID, accessLevel)
+	if err := authorize(principal, authz.View, entity.ResourceNote, "You do not have permission to view this note"); err != nil {
+		return nil, err
+	}
+
+	session := &CollabSession{
+		ID:       uuid.NewString(),
+		NoteID:   noteID,
+		UserID:   userID,
+		CanEdit:  authz.Allowed(principal, authz.Edit, entity.ResourceNote),
+		c:        c,
+		outbound: make(chan []byte, collabOutboundBuffer),
+	}
+
+	room, err := s.acquireRoom(session)
+	if err != nil {
+		return nil, err
+	}
+
+	room.mu.Lock()
+	err = s.sendInit(ctx, session)
+	room.mu.Unlock()
+	if err != nil {
+		s.Leave(session)
+		return nil, err
+	}
+
+	s.touch(ctx, session, 0, 0)
+	s.broadcastPresence(ctx, noteID)
+	return session, nil
+}
+
+// Leave kết thúc phiên. Phiên cuối cùng của note trên instance này lưu snapshot và giải phóng room.
+func (s *NoteCollabService) Leave(session *CollabSession) {
+	ctx := context.Background()
+	room := session.room
+
+	room.mu.Lock()
+	delete(room.sessions, session.ID)
+	if !session.closed {
+		session.closed = true
+		close(session.outbound)
+	}
+	room.mu.Unlock()
+
+	if err := s.store.RemovePresence(ctx, session.NoteID, session.ID); err != nil {
+		logger.Error("failed to remove collab presence", "note_id", session.NoteID, "error", err)
+	}
+	s.broadcastPresence(ctx, session.NoteID)
+
+	// Chờ lần lưu snapshot đang chạy (có thể đang dùng context của phiên này) trước khi handler trả về
+	room.persistMu.Lock()
+	defer room.persistMu.Unlock()
+
+	s.mu.Lock()
+	room.mu.Lock()
+	last := len(room.sessions) == 0
+	room.mu.Unlock()
+	if last && s.rooms[room.noteID] == room {
+		delete(s.rooms, room.noteID)
+		close(room.stop)
+		room.unsubscribe()
+	}
+	s.mu.Unlock()
+
+	if last && session.CanEdit {
+		s.persistWith(room, session)
+	}
+}
+
+// Heartbeat giữ presence của phiên còn hiệu lực và kiểm tra lại quyền của user:
+// mất quyền đọc thì phiên bị đóng, quyền ghi thay đổi thì client nhận lại "init" với canEdit mới
+func (s *NoteCollabService) Heartbeat(session *CollabSession) {
+	ctx := session.c.Request.Context()
+	accessLevel, _ := s.noteService.GetAccessLevel(session.c, session.NoteID, session.UserID)
+	principal := authz.OnAsset(session.UserID, accessLevel)
+	canView := authz.Allowed(principal, authz.View, entity.ResourceNote)
+	canEdit := authz.Allowed(principal, authz.Edit, entity.ResourceNote)
+
+	room := session.room
+	room.mu.Lock()
+	closed := session.closed
+	changed := false
+	switch {
+	case closed:
+	case !canView:
+		data, _ := json.Marshal(&CollabMessage{Type: CollabMsgError, Message: "You no longer have permission to view this note"})
+		s.send(session, data)
+		if !session.closed {
+			session.closed = true
+			close(session.outbound)
+		}
+		closed = true
+	case canEdit != session.CanEdit:
+		session.CanEdit = canEdit
+		s.resync(ctx, session)
+		changed = true
+	}
+	room.mu.Unlock()
+
+	if closed {
+		return
+	}
+	s.touch(ctx, session, -1, -1)
+	if changed {
+		s.broadcastPresence(ctx, session.NoteID)
+	}
+}
+
+// HandleMessage xử lý một message client gửi lên
+func (s *NoteCollabService) HandleMessage(session *CollabSession, data []byte) {
+	ctx := session.c.Request.Context()
+
+	var msg CollabMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		s.sendError(session, "invalid message: "+err.Error())
+		return
+	}
+
+	switch msg.Type {
+	case CollabMsgOp:
+		if !session.CanEdit {
+			s.sendError(session, "You do not have permission to edit this note")
+			return
+		}
+		applied, err := s.store.Apply(ctx, session.NoteID, msg.BaseRev, &entity.CollabOp{
+			Op:        msg.Op,
+			OpID:      msg.OpID,
+			SessionID: session.ID,
+			UserID:    session.UserID,
+		})
+		if errors.Is(err, entity.ErrResyncRequired) {
+			session.room.mu.Lock()
+			s.resync(ctx, session)
+			session.room.mu.Unlock()
+			return
+		}
+		if err != nil {
+			s.sendError(session, err.Error())
+			return
+		}
+
+		session.room.mu.Lock()
+		session.lastEdit = time.Now()
+		session.room.mu.Unlock()
+
+		s.publish(ctx, session.NoteID, &CollabMessage{
+			Type:      CollabMsgOp,
+			Rev:       applied.Rev,
+			Op:        applied.Op,
+			OpID:      applied.OpID,
+			SessionID: applied.SessionID,
+			UserID:    &applied.UserID,
+		})
+
+	case CollabMsgCursor:
+		position, selectionEnd := 0, 0
+		if msg.Position != nil {
+			position = *msg.Position
+		}
+		selectionEnd = position
+		if msg.SelectionEnd != nil {
+			selectionEnd = *msg.SelectionEnd
+		}
+		if position < 0 || selectionEnd < 0 {
+			s.sendError(session, "cursor position must not be negative")
+			return
+		}
+		s.touch(ctx, session, position, selectionEnd)
+		s.publish(ctx, session.NoteID, &CollabMessage{
+			Type:         CollabMsgCursor,
+			SessionID:    session.ID,
+			UserID:       &session.UserID,
+			Position:     &position,
+			SelectionEnd: &selectionEnd,
+		})
+
+	default:
+		s.sendError(session, "unknown message type "+msg.Type)
+	}
+}
+
+// acquireRoom thêm phiên vào room của note, tạo room mới (subscribe Redis và chạy job lưu snapshot) nếu chưa có.
+// Phiên được thêm khi đang giữ s.mu để room không bị giải phóng giữa chừng.
+func (s *NoteCollabService) acquireRoom(session *CollabSession) (*collabRoom, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	noteID := session.NoteID
+	if room, ok := s.rooms[noteID]; ok {
+		room.mu.Lock()
+		session.room = room
+		room.sessions[session.ID] = session
+		room.mu.Unlock()
+		return room, nil
+	}
+
+	messages, unsubscribe, err := s.store.Subscribe(context.Background(), noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	room := &collabRoom{
+		noteID:      noteID,
+		sessions:    make(map[string]*CollabSession),
+		unsubscribe: unsubscribe,
+		stop:        make(chan struct{}),
+	}
+	s.rooms[noteID] = room
+	session.room = room
+	room.sessions[session.ID] = session
+
+	go s.dispatch(room, messages)
+	go s.persistLoop(room)
+	return room, nil
+}
+
+// dispatch chuyển message từ Redis tới các phiên trên instance này
+func (s *NoteCollabService) dispatch(room *collabRoom, messages <-chan []byte) {
+	for data := range messages {
+		var msg CollabMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+
+		room.mu.Lock()
+		switch msg.Type {
+		case CollabMsgOp:
+			for _, session := range room.sessions {
+				s.deliverOp(session, &msg)
+			}
+		case CollabMsgCursor:
+			for _, session := range room.sessions {
+				if session.ID != msg.SessionID {
+					s.send(session, data)
+				}
+			}
+		case CollabMsgPresence:
+			for _, session := range room.sessions {
+				s.send(session, data)
+			}
+		case CollabMsgInit:
+			// Bản nháp đã được nạp lại từ note, op log cũ không còn dùng được
+			for _, session := range room.sessions {
+				if session.ready {
+					s.resync(context.Background(), session)
+				}
+			}
+		}
+		room.mu.Unlock()
+	}
+}
+
+// deliverOp gửi op cho phiên theo đúng thứ tự revision, bù các op bị thiếu từ op log.
+// Phiên đã gửi op nhận "ack" thay vì chính op đó. Gọi khi đang giữ room.mu.
+func (s *NoteCollabService) deliverOp(session *CollabSession, msg *CollabMessage) {
+	if !session.ready || msg.Rev <= session.rev {
+		return
+	}
+
+	pending := []*CollabMessage{msg}
+	if msg.Rev > session.rev+1 {
+		// Message từ các instance khác có thể tới không theo thứ tự
+		missing, err := s.store.Ops(context.Background(), session.NoteID, session.rev, msg.Rev-1)
+		if err != nil {
+			s.resync(context.Background(), session)
+			return
+		}
+		pending = pending[:0]
+		for _, op := range missing {
+			userID := op.UserID
+			pending = append(pending, &CollabMessage{Type: CollabMsgOp, Rev: op.Rev, Op: op.Op, OpID: op.OpID, SessionID: op.SessionID, UserID: &userID})
+		}
+		pending = append(pending, msg)
+	}
+
+	for _, m := range pending {
+		out := m
+		if m.SessionID == session.ID {
+			out = &CollabMessage{Type: CollabMsgAck, Rev: m.Rev, OpID: m.OpID}
+		}
+		data, err := json.Marshal(out)
+		if err != nil {
+			continue
+		}
+		s.send(session, data)
+		session.rev = m.Rev
+	}
+}
+
+// persistLoop định kỳ lưu bản nháp chung vào note khi có thay đổi
+func (s *NoteCollabService) persistLoop(room *collabRoom) {
+	ticker := time.NewTicker(s.snapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-room.stop:
+			return
+		case <-ticker.C:
+			room.persistMu.Lock()
+			if editor := room.editor(); editor != nil {
+				s.persistWith(room, editor)
+			}
+			room.persistMu.Unlock()
+		}
+	}
+}
+
+// editor chọn phiên có quyền ghi, ưu tiên người sửa gần nhất, để làm tác giả của snapshot
+func (r *collabRoom) editor() *CollabSession {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var editor *CollabSession
+	for _, session := range r.sessions {
+		if session.CanEdit && (editor == nil || session.lastEdit.After(editor.lastEdit)) {
+			editor = session
+		}
+	}
+	return editor
+}
+
+// persistWith lưu snapshot qua NoteService.Update bằng quyền của phiên editor. Gọi khi đang giữ room.persistMu.
+func (s *NoteCollabService) persistWith(room *collabRoom, editor *CollabSession) {
+	ctx := editor.c.Request.Context()
+	if ctx.Err() != nil {
+		ctx = context.Background()
+	}
+
+	doc, rev, ok, err := s.store.Snapshot(ctx, room.noteID)
+	if err != nil || !ok {
+		if err != nil {
+			logger.Error("failed to read collab snapshot", "note_id", room.noteID, "error", err)
+		}
+		return
+	}
+
+	note, err := s.noteService.repo.GetByID(ctx, room.noteID)
+	if err != nil {
+		logger.Error("failed to load note for collab snapshot", "note_id", room.noteID, "error", err)
+		return
+	}
+
+	if note.Body != doc {
+		// Ghi có điều kiện theo version vừa đọc để không đè lên thay đổi được lưu ngoài phiên chỉnh sửa chung
+		err := s.noteService.Update(editor.c, &entity.Note{ID: note.ID, Title: note.Title, Body: doc, Version: note.Version})
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) && httpErr.Code == http.StatusPreconditionFailed {
+			s.reload(ctx, room.noteID)
+			return
+		}
+		if err != nil {
+			logger.Error("failed to persist collab snapshot", "note_id", room.noteID, "rev", rev, "error", err)
+			return
+		}
+	}
+
+	if err := s.store.MarkPersisted(ctx, room.noteID, rev); err != nil {
+		logger.Error("failed to mark collab snapshot persisted", "note_id", room.noteID, "error", err)
+	}
+}
+
+// reload thay bản nháp bằng nội dung note đã lưu khi note bị sửa ngoài phiên chỉnh sửa chung,
+// rồi yêu cầu mọi phiên trên mọi instance nạp lại bản nháp
+func (s *NoteCollabService) reload(ctx context.Context, noteID uuid.UUID) {
+	note, err := s.noteService.repo.GetByID(ctx, noteID)
+	if err != nil {
+		logger.Error("failed to load note for collab reload", "note_id", noteID, "error", err)
+		return
+	}
+
+	rev, err := s.store.Reset(ctx, noteID, note.Body)
+	if err != nil {
+		logger.Error("failed to reset collab draft", "note_id", noteID, "error", err)
+		return
+	}
+	logger.Info("collab draft reloaded after concurrent note update", "note_id", noteID, "rev", rev)
+
+	s.publish(ctx, noteID, &CollabMessage{Type: CollabMsgInit, Rev: rev})
+}
+
+// sendInit gửi nội dung bản nháp hiện tại cho phiên. Gọi khi đang giữ room.mu.
+func (s *NoteCollabService) sendInit(ctx context.Context, session *CollabSession) error {
+	doc, rev, err := s.store.Load(ctx, session.NoteID, func() (string, error) {
+		note, err := s.noteService.repo.GetByID(ctx, session.NoteID)
+		if err != nil {
+			return "", NewNotFoundError(err.Error())
+		}
+		return note.Body, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	session.rev = rev
+	session.ready = true
+	canEdit := session.CanEdit
+	data, err := json.Marshal(&CollabMessage{
+		Type:      CollabMsgInit,
+		Rev:       rev,
+		Doc:       &doc,
+		SessionID: session.ID,
+		UserID:    &session.UserID,
+		CanEdit:   &canEdit,
+	})
+	if err != nil {
+		return err
+	}
+	s.send(session, data)
+	return nil
+}
+
+// resync gửi lại toàn bộ bản nháp khi phiên không thể bắt kịp bằng op log. Gọi khi đang giữ room.mu.
+func (s *NoteCollabService) resync(ctx context.Context, session *CollabSession) {
+	if err := s.sendInit(ctx, session); err != nil {
+		data, _ := json.Marshal(&CollabMessage{Type: CollabMsgError, Message: err.Error()})
+		s.send(session, data)
+	}
+}
+
+func (s *NoteCollabService) sendError(session *CollabSession, message string) {
+	data, _ := json.Marshal(&CollabMessage{Type: CollabMsgError, Message: message})
+	session.room.mu.Lock()
+	s.send(session, data)
+	session.room.mu.Unlock()
+}
+
+// send đưa message vào hàng đợi của phiên; phiên không đọc kịp bị đóng. Gọi khi đang giữ room.mu.
+func (s *NoteCollabService) send(session *CollabSession, data []byte) {
+	if session.closed {
+		return
+	}
+	select {
+	case session.outbound <- data:
+	default:
+		session.closed = true
+		close(session.outbound)
+	}
+}
+
+// touch cập nhật presence; position < 0 giữ nguyên vị trí con trỏ đã lưu
+func (s *NoteCollabService) touch(ctx context.Context, session *CollabSession, position, selectionEnd int) {
+	presence := &entity.CollabPresence{
+		SessionID:    session.ID,
+		UserID:       session.UserID,
+		CanEdit:      session.CanEdit,
+		Position:     position,
+		SelectionEnd: selectionEnd,
+		SeenAt:       time.Now(),
+	}
+	if position < 0 {
+		presence.Position, presence.SelectionEnd = 0, 0
+		if current, err := s.store.ListPresence(ctx, session.NoteID); err == nil {
+			for _, p := range current {
+				if p.SessionID == session.ID {
+					presence.Position, presence.SelectionEnd = p.Position, p.SelectionEnd
+				}
+			}
+		}
+	}
+	if err := s.store.SetPresence(ctx, session.NoteID, presence); err != nil {
+		logger.Error("failed to update collab presence", "note_id", session.NoteID, "error", err)
+	}
+}
+
+// broadcastPresence gửi danh sách phiên đang kết nối tới mọi instance
+func (s *NoteCollabService) broadcastPresence(ctx context.Context, noteID uuid.UUID) {
+	users, err := s.store.ListPresence(ctx, noteID)
+	if err != nil {
+		logger.Error("failed to list collab presence", "note_id", noteID, "error", err)
+		return
+	}
+	s.publish(ctx, noteID, &CollabMessage{Type: CollabMsgPresence, Users: users})
+}
+
+func (s *NoteCollabService) publish(ctx context.Context, noteID uuid.UUID, msg *CollabMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	if err := s.store.Publish(ctx, noteID, data); err != nil {
+		logger.Error("failed to publish collab message", "note_id", noteID, "type", msg.Type, "error", err)
+	}
+}
@@ -0,0 +1,122 @@
+package application
+
+import "strings"
+
+// DiffOp là loại thay đổi của một dòng trong diff
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// maxDiffCells giới hạn kích thước bảng LCS (số dòng cũ x số dòng mới) để diff không tốn quá nhiều bộ nhớ.
+// Vượt quá giới hạn thì phần khác nhau được trả về như xoá toàn bộ rồi thêm toàn bộ.
+const maxDiffCells = 4_000_000
+
+// DiffLine là một dòng trong diff. OldLine/NewLine là số dòng (từ 1) ở bản cũ/mới, 0 nếu không có.
+type DiffLine struct {
+	Op      DiffOp `json:"op"`
+	OldLine int    `json:"oldLine,omitempty"`
+	NewLine int    `json:"newLine,omitempty"`
+	Text    string `json:"text"`
+}
+
+// DiffLines so sánh hai văn bản theo từng dòng bằng longest common subsequence
+func DiffLines(oldText, newText string) []DiffLine {
+	a := splitLines(oldText)
+	b := splitLines(newText)
+
+	// Bỏ phần đầu và phần cuối giống nhau trước khi dựng bảng LCS
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	diff := make([]DiffLine, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		diff = append(diff, DiffLine{Op: DiffEqual, OldLine: i + 1, NewLine: i + 1, Text: a[i]})
+	}
+
+	midA := a[prefix : len(a)-suffix]
+	midB := b[prefix : len(b)-suffix]
+	diff = append(diff, diffMiddle(midA, midB, prefix, prefix)...)
+
+	for i := 0; i < suffix; i++ {
+		oldIdx := len(a) - suffix + i
+		newIdx := len(b) - suffix + i
+		diff = append(diff, DiffLine{Op: DiffEqual, OldLine: oldIdx + 1, NewLine: newIdx + 1, Text: a[oldIdx]})
+	}
+
+	return diff
+}
+
+// diffMiddle diff phần ở giữa; oldOffset/newOffset là số dòng đã bỏ qua ở đầu mỗi bản
+func diffMiddle(a, b []string, oldOffset, newOffset int) []DiffLine {
+	n, m := len(a), len(b)
+	var diff []DiffLine
+
+	if n*m > maxDiffCells {
+		for i, line := range a {
+			diff = append(diff, DiffLine{Op: DiffDelete, OldLine: oldOffset + i + 1, Text: line})
+		}
+		for j, line := range b {
+			diff = append(diff, DiffLine{Op: DiffInsert, NewLine: newOffset + j + 1, Text: line})
+		}
+		return diff
+	}
+
+	// lcs[i][j] là độ dài LCS của a[i:] và b[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, DiffLine{Op: DiffEqual, OldLine: oldOffset + i + 1, NewLine: newOffset + j + 1, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, DiffLine{Op: DiffDelete, OldLine: oldOffset + i + 1, Text: a[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: DiffInsert, NewLine: newOffset + j + 1, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		diff = append(diff, DiffLine{Op: DiffDelete, OldLine: oldOffset + i + 1, Text: a[i]})
+	}
+	for ; j < m; j++ {
+		diff = append(diff, DiffLine{Op: DiffInsert, NewLine: newOffset + j + 1, Text: b[j]})
+	}
+
+	return diff
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
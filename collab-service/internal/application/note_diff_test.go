@@ -0,0 +1,121 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		want    []DiffLine
+	}{
+		{
+			name:    "identical",
+			oldText: "a\nb",
+			newText: "a\nb",
+			want: []DiffLine{
+				{Op: DiffEqual, OldLine: 1, NewLine: 1, Text: "a"},
+				{Op: DiffEqual, OldLine: 2, NewLine: 2, Text: "b"},
+			},
+		},
+		{
+			name:    "insert in the middle shifts new line numbers",
+			oldText: "a\nb\nc",
+			newText: "a\nb\nX\nc",
+			want: []DiffLine{
+				{Op: DiffEqual, OldLine: 1, NewLine: 1, Text: "a"},
+				{Op: DiffEqual, OldLine: 2, NewLine: 2, Text: "b"},
+				{Op: DiffInsert, NewLine: 3, Text: "X"},
+				{Op: DiffEqual, OldLine: 3, NewLine: 4, Text: "c"},
+			},
+		},
+		{
+			name:    "delete at the end",
+			oldText: "a\nb\nc",
+			newText: "a\nb",
+			want: []DiffLine{
+				{Op: DiffEqual, OldLine: 1, NewLine: 1, Text: "a"},
+				{Op: DiffEqual, OldLine: 2, NewLine: 2, Text: "b"},
+				{Op: DiffDelete, OldLine: 3, Text: "c"},
+			},
+		},
+		{
+			name:    "replacement between a common prefix and suffix",
+			oldText: "a\nb\nc\nd",
+			newText: "a\nX\nY\nd",
+			want: []DiffLine{
+				{Op: DiffEqual, OldLine: 1, NewLine: 1, Text: "a"},
+				{Op: DiffDelete, OldLine: 2, Text: "b"},
+				{Op: DiffDelete, OldLine: 3, Text: "c"},
+				{Op: DiffInsert, NewLine: 2, Text: "X"},
+				{Op: DiffInsert, NewLine: 3, Text: "Y"},
+				{Op: DiffEqual, OldLine: 4, NewLine: 4, Text: "d"},
+			},
+		},
+		{
+			name:    "common line inside the changed part",
+			oldText: "a\nb\nc",
+			newText: "X\nb\nY",
+			want: []DiffLine{
+				{Op: DiffDelete, OldLine: 1, Text: "a"},
+				{Op: DiffInsert, NewLine: 1, Text: "X"},
+				{Op: DiffEqual, OldLine: 2, NewLine: 2, Text: "b"},
+				{Op: DiffDelete, OldLine: 3, Text: "c"},
+				{Op: DiffInsert, NewLine: 3, Text: "Y"},
+			},
+		},
+		{
+			name:    "from empty",
+			oldText: "",
+			newText: "a\nb",
+			want: []DiffLine{
+				{Op: DiffInsert, NewLine: 1, Text: "a"},
+				{Op: DiffInsert, NewLine: 2, Text: "b"},
+			},
+		},
+		{
+			name:    "line endings and trailing newline are ignored",
+			oldText: "a\r\nb\r\n",
+			newText: "a\nb",
+			want: []DiffLine{
+				{Op: DiffEqual, OldLine: 1, NewLine: 1, Text: "a"},
+				{Op: DiffEqual, OldLine: 2, NewLine: 2, Text: "b"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DiffLines(tt.oldText, tt.newText))
+		})
+	}
+}
+
+func TestDiffLinesFallbackOverMaxCells(t *testing.T) {
+	// Phần ở giữa có 2001 x 2001 ô, vượt maxDiffCells
+	const changed = 2001
+	oldLines, newLines := []string{"head"}, []string{"head"}
+	for i := 0; i < changed; i++ {
+		oldLines = append(oldLines, fmt.Sprintf("old %d", i))
+		newLines = append(newLines, fmt.Sprintf("new %d", i))
+	}
+	oldLines, newLines = append(oldLines, "tail"), append(newLines, "tail")
+	require.Greater(t, changed*changed, maxDiffCells)
+
+	diff := DiffLines(strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"))
+	require.Len(t, diff, 2*changed+2)
+
+	assert.Equal(t, DiffLine{Op: DiffEqual, OldLine: 1, NewLine: 1, Text: "head"}, diff[0])
+	for i := 0; i < changed; i++ {
+		assert.Equal(t, DiffLine{Op: DiffDelete, OldLine: i + 2, Text: oldLines[i+1]}, diff[1+i])
+		assert.Equal(t, DiffLine{Op: DiffInsert, NewLine: i + 2, Text: newLines[i+1]}, diff[1+changed+i])
+	}
+	assert.Equal(t, DiffLine{Op: DiffEqual, OldLine: changed + 2, NewLine: changed + 2, Text: "tail"}, diff[len(diff)-1])
+}
@@ -0,0 +1,232 @@
+package application
+
+import (
+	"collab-service/config"
+	"collab-service/internal/domain/authz"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/external/event"
+	"collab-service/internal/infrastructure/logger"
+	"collab-service/internal/interface/http/middleware"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultRevisionPageSize = 20
+	MaxRevisionPageSize     = 100
+)
+
+// NoteRevisionSummary là một revision trong danh sách lịch sử, không kèm nội dung
+type NoteRevisionSummary struct {
+	Number       int       `json:"number"`
+	Title        string    `json:"title"`
+	ContentHash  string    `json:"contentHash"`
+	AuthorID     uuid.UUID `json:"authorId"`
+	RestoredFrom *int      `json:"restoredFrom,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// NoteRevisionPage là một trang lịch sử revision, mới nhất trước
+type NoteRevisionPage struct {
+	Revisions []NoteRevisionSummary `json:"revisions"`
+	Page      int                   `json:"page"`
+	PageSize  int                   `json:"pageSize"`
+	Total     int64                 `json:"total"`
+}
+
+// NoteRevisionDiff là diff theo dòng giữa hai revision của note
+type NoteRevisionDiff struct {
+	From     int        `json:"from"`
+	To       int        `json:"to"`
+	OldTitle string     `json:"oldTitle"`
+	NewTitle string     `json:"newTitle"`
+	Lines    []DiffLine `json:"lines"`
+}
+
+func toRevisionSummary(r *entity.NoteRevision) NoteRevisionSummary {
+	return NoteRevisionSummary{
+		Number:       r.Number,
+		Title:        r.Title,
+		ContentHash:  r.ContentHash,
+		AuthorID:     r.AuthorID,
+		RestoredFrom: r.RestoredFrom,
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+// recordRevision lưu nội dung hiện tại của note thành revision mới; bỏ qua nếu nội dung không đổi so với revision gần nhất
+func (s *NoteService) recordRevision(ctx context.Context, noteID uuid.UUID, title, body string, authorID uuid.UUID, restoredFrom *int) (*entity.NoteRevision, error) {
+	hash := entity.NoteContentHash(title, body)
+
+	latest, err := s.revisionRepo.Latest(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.ContentHash == hash && restoredFrom == nil {
+		return latest, nil
+	}
+
+	return s.revisionRepo.Append(ctx, &entity.NoteRevision{
+		NoteID:       noteID,
+		Title:        title,
+		Body:         body,
+		ContentHash:  hash,
+		AuthorID:     authorID,
+		RestoredFrom: restoredFrom,
+	}, config.GetConfig().NoteRevisionLimit)
+}
+
+// ensureBaseline lưu nội dung hiện tại của note được tạo trước khi có lịch sử revision,
+// để lần cập nhật đầu tiên vẫn khôi phục được nội dung cũ
+func (s *NoteService) ensureBaseline(ctx context.Context, note *entity.Note) error {
+	latest, err := s.revisionRepo.Latest(ctx, note.ID)
+	if err != nil || latest != nil {
+		return err
+	}
+
+	ownerID, err := s.repo.GetOwner(ctx, note.ID)
+	if err != nil {
+		return err
+	}
+	_, err = s.recordRevision(ctx, note.ID, note.Title, note.Body, ownerID, nil)
+	return err
+}
+
+// checkReadAccess trả về lỗi nếu user không có quyền đọc note
+func (s *NoteService) checkReadAccess(c *gin.Context, noteID uuid.UUID) error {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	accessLevel, err := s.GetAccessLevel(c, noteID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return NewNotFoundError(fmt.Sprintf("note %s not found", noteID))
+	}
+	if err != nil {
+		return err
+	}
+	return authorize(authz.OnAsset(userID, accessLevel), authz.View, entity.ResourceNote, "You do not have permission to view this note")
+}
+
+// getRevision trả về revision theo số, NotFound nếu revision không tồn tại hoặc đã bị xoá theo giới hạn lưu trữ
+func (s *NoteService) getRevision(ctx context.Context, noteID uuid.UUID, number int) (*entity.NoteRevision, error) {
+	revision, err := s.revisionRepo.GetByNumber(ctx, noteID, number)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewNotFoundError(fmt.Sprintf("revision %d of note %s not found", number, noteID))
+	}
+	return revision, err
+}
+
+// ListRevisions returns the note's revision history, newest first
+func (s *NoteService) ListRevisions(c *gin.Context, noteID uuid.UUID, page, pageSize int) (*NoteRevisionPage, error) {
+	if err := s.checkReadAccess(c, noteID); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultRevisionPageSize
+	}
+	if pageSize > MaxRevisionPageSize {
+		pageSize = MaxRevisionPageSize
+	}
+
+	revisions, total, err := s.revisionRepo.List(c.Request.Context(), noteID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NoteRevisionPage{
+		Revisions: make([]NoteRevisionSummary, len(revisions)),
+		Page:      page,
+		PageSize:  pageSize,
+		Total:     total,
+	}
+	for i, r := range revisions {
+		result.Revisions[i] = toRevisionSummary(r)
+	}
+	return result, nil
+}
+
+// GetRevision returns a single revision with its full content
+func (s *NoteService) GetRevision(c *gin.Context, noteID uuid.UUID, number int) (*entity.NoteRevision, error) {
+	if err := s.checkReadAccess(c, noteID); err != nil {
+		return nil, err
+	}
+	return s.getRevision(c.Request.Context(), noteID, number)
+}
+
+// DiffRevisions returns a line-level diff of the body between two revisions.
+// to = 0 means the latest revision.
+func (s *NoteService) DiffRevisions(c *gin.Context, noteID uuid.UUID, from, to int) (*NoteRevisionDiff, error) {
+	if err := s.checkReadAccess(c, noteID); err != nil {
+		return nil, err
+	}
+
+	ctx := c.Request.Context()
+	if to == 0 {
+		latest, err := s.revisionRepo.Latest(ctx, noteID)
+		if err != nil {
+			return nil, err
+		}
+		if latest == nil {
+			return nil, NewNotFoundError(fmt.Sprintf("note %s has no revisions", noteID))
+		}
+		to = latest.Number
+	}
+
+	oldRevision, err := s.getRevision(ctx, noteID, from)
+	if err != nil {
+		return nil, err
+	}
+	newRevision, err := s.getRevision(ctx, noteID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NoteRevisionDiff{
+		From:     oldRevision.Number,
+		To:       newRevision.Number,
+		OldTitle: oldRevision.Title,
+		NewTitle: newRevision.Title,
+		Lines:    DiffLines(oldRevision.Body, newRevision.Body),
+	}, nil
+}
+
+// RestoreRevision ghi lại nội dung của một revision cũ thành nội dung hiện tại, tạo revision mới thay vì xoá lịch sử
+func (s *NoteService) RestoreRevision(c *gin.Context, noteID uuid.UUID, number int) (*entity.NoteRevision, error) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	accessLevel, _ := s.GetAccessLevel(c, noteID, userID)
+	if err := authorize(authz.OnAsset(userID, accessLevel), authz.Edit, entity.ResourceNote, "You do not have permission to update this note"); err != nil {
+		return nil, err
+	}
+
+	revision, err := s.getRevision(ctx, noteID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, &entity.Note{ID: noteID, Title: revision.Title, Body: revision.Body}); err != nil {
+		return nil, err
+	}
+
+	restored, err := s.recordRevision(ctx, noteID, revision.Title, revision.Body, userID, &revision.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		if err := s.eventProducer.Produce(event.NewAssetEvent(event.NoteUpdated, event.Note, noteID.String(), userID.String(), userID.String(), time.Now().String(), entity.AccessLevelNone)); err != nil {
+			logger.Error("failed to produce event", "error", err)
+		}
+	}()
+
+	return restored, nil
+}
@@ -1,24 +1,36 @@
 package application
 
 import (
+	"collab-service/internal/domain/authz"
 	"collab-service/internal/domain/entity"
 	"collab-service/internal/infrastructure/external/event"
 	"collab-service/internal/interface/http/middleware"
+	"errors"
+	"fmt"
 	"log"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type NoteService struct {
 	repo          entity.NoteRepository
+	teamRepo      entity.TeamRepository
+	revisionRepo  entity.NoteRevisionRepository
+	tagRepo       entity.TagRepository
+	mentions      *MentionNotifier
 	eventProducer *event.AssetChangeProducer
 }
 
-func NewNoteService(repo entity.NoteRepository) *NoteService {
+func NewNoteService(repo entity.NoteRepository, teamRepo entity.TeamRepository, revisionRepo entity.NoteRevisionRepository, tagRepo entity.TagRepository, mentions *MentionNotifier) *NoteService {
 	return &NoteService{
 		repo:          repo,
+		teamRepo:      teamRepo,
+		revisionRepo:  revisionRepo,
+		tagRepo:       tagRepo,
+		mentions:      mentions,
 		eventProducer: event.GetAssetChangeProducer(),
 	}
 }
@@ -27,27 +39,35 @@ func (s *NoteService) Create(c *gin.Context, note *entity.Note) (*entity.Note, e
 	// Get user ID from the context
 	userID, _ := middleware.GetUserInfoFromGin(c)
 
-	ownerID, err := s.repo.GetOwner(c.Request.Context(), note.FolderID)
-	if err != nil {
-		return nil, err
-	}
-
 	// If the note has a folder, check folder access
 	if note.FolderID != uuid.Nil {
 		folderAccessLevel, _ := s.repo.GetFolderAccessLevel(c.Request.Context(), note.FolderID, userID)
-		if !folderAccessLevel.GreaterThan(entity.AccessLevelWrite) {
-			return nil, NewForbiddenError("You do not have write permission for this folder")
+		if err := authorize(authz.OnAsset(userID, folderAccessLevel), authz.Edit, entity.ResourceFolder, "You do not have write permission for this folder"); err != nil {
+			return nil, err
 		}
 	}
 
 	savedNote, err := s.repo.Create(c.Request.Context(), note, userID)
+	if err != nil {
+		return nil, err
+	}
 
-	go s.eventProducer.Produce(event.NewAssetEvent(event.NoteCreated, event.Note, savedNote.ID.String(), ownerID.String(), userID.String(), time.Now().String(), entity.AccessLevelOwner))
+	// Revision đầu tiên là nội dung lúc tạo note
+	if _, err := s.recordRevision(c.Request.Context(), savedNote.ID, savedNote.Title, savedNote.Body, userID, nil); err != nil {
+		return nil, err
+	}
+
+	// Người tạo là owner của note mới
+	go s.eventProducer.Produce(event.NewAssetEvent(event.NoteCreated, event.Note, savedNote.ID.String(), userID.String(), userID.String(), time.Now().String(), entity.AccessLevelOwner))
+	s.mentions.Notify(c.Request.Context(), savedNote.ID, userID, nil, "", savedNote.Body)
 
-	return savedNote, err
+	return savedNote, nil
 }
 
 func (s *NoteService) GetByID(c *gin.Context, id uuid.UUID) (*entity.Note, error) {
+	if err := s.checkReadAccess(c, id); err != nil {
+		return nil, err
+	}
 	return s.repo.GetByID(c.Request.Context(), id)
 }
 
@@ -63,43 +83,108 @@ func (s *NoteService) GetAccessLevel(c *gin.Context, noteID, userID uuid.UUID) (
 	return s.repo.GetAccessLevel(c.Request.Context(), noteID, userID)
 }
 
-func (s *NoteService) ShareNote(c *gin.Context, noteID, userID uuid.UUID, accessLevel entity.AccessLevel) error {
-	// Check if the user has permission to share the note
+// ExplainAccess giải thích vì sao user có quyền hiện tại trên note. userID nil là chính caller;
+// xem quyền của người khác chỉ dành cho owner.
+func (s *NoteService) ExplainAccess(c *gin.Context, noteID uuid.UUID, userID *uuid.UUID) (*entity.AccessExplanation, error) {
 	currentUserID, _ := middleware.GetUserInfoFromGin(c)
 
-	currentAccessLevel, _ := s.GetAccessLevel(c, noteID, currentUserID)
-	if !currentAccessLevel.GreaterThan(entity.AccessLevelOwner) {
-		return NewForbiddenError("You do not have permission to share this note")
+	explanation, err := s.repo.ExplainAccess(c.Request.Context(), noteID, currentUserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewNotFoundError(fmt.Sprintf("note %s not found", noteID))
+	}
+	if err != nil || userID == nil || *userID == currentUserID {
+		return explanation, err
 	}
 
-	userAccessLevel, _ := s.GetAccessLevel(c, noteID, userID)
+	if err := authorize(authz.OnAsset(currentUserID, explanation.AccessLevel), authz.Share, entity.ResourceNote, "Only the owner can explain another user's access to this note"); err != nil {
+		return nil, err
+	}
+	return s.repo.ExplainAccess(c.Request.Context(), noteID, *userID)
+}
 
-	if userAccessLevel != entity.AccessLevelNone {
-		return s.repo.ChangeAccessLevel(c.Request.Context(), noteID, userID, accessLevel)
+// ShareNote cấp quyền trên note cho user; expiresAt khác nil thì share tự hết hạn sau thời điểm đó
+func (s *NoteService) ShareNote(c *gin.Context, noteID, userID uuid.UUID, accessLevel entity.AccessLevel, expiresAt *time.Time) error {
+	// Check if the user has permission to share the note
+	currentUserID, _ := middleware.GetUserInfoFromGin(c)
+
+	currentAccessLevel, _ := s.GetAccessLevel(c, noteID, currentUserID)
+	if err := authorize(authz.OnAsset(currentUserID, currentAccessLevel), authz.Share, entity.ResourceNote, "You do not have permission to share this note"); err != nil {
+		return err
+	}
+	if err := checkShareExpiry(accessLevel, expiresAt); err != nil {
+		return err
 	}
 
-	err := s.repo.ShareNote(c.Request.Context(), noteID, userID, accessLevel)
+	// ShareNote cập nhật share trực tiếp nếu đã có; không dựa vào quyền hiệu lực vì quyền đó có thể đến từ team
+	err := s.repo.ShareNote(c.Request.Context(), noteID, userID, accessLevel, expiresAt)
+	if errors.Is(err, entity.ErrShareTargetIsOwner) {
+		return NewConflictError(fmt.Sprintf("user %s already owns this note", userID))
+	}
+	if err != nil {
+		return err
+	}
 
 	go s.eventProducer.Produce(event.NewAssetEvent(event.NoteShared, event.Note, noteID.String(), userID.String(), currentUserID.String(), time.Now().String(), accessLevel))
 
-	return err
+	return nil
 }
 
 func (s *NoteService) RevokeAccess(c *gin.Context, noteID, userID uuid.UUID) error {
 	// Check if the user has permission to revoke access
 	currentUserID, _ := middleware.GetUserInfoFromGin(c)
 	currentAccessLevel, _ := s.GetAccessLevel(c, noteID, currentUserID)
-	if !currentAccessLevel.GreaterThan(entity.AccessLevelOwner) {
-		return NewForbiddenError("You do not have permission to revoke access for this note")
+	if err := authorize(authz.OnAsset(currentUserID, currentAccessLevel), authz.Share, entity.ResourceNote, "You do not have permission to revoke access for this note"); err != nil {
+		return err
 	}
 
 	err := s.repo.RevokeAccess(c.Request.Context(), noteID, userID)
 
-	go s.eventProducer.Produce(event.NewAssetEvent(event.NoteUnshared, event.Note, noteID.String(), userID.String(), currentUserID.String(), time.Now().String(), entity.AccessLevelNone))
+	go s.eventProducer.Produce(event.NewUnshareEvent(event.NoteUnshared, event.Note, noteID.String(), userID.String(), currentUserID.String(), time.Now().String()))
 
 	return err
 }
 
+// ShareNoteWithTeam cấp quyền trên note cho mọi thành viên hiện tại và tương lai của team
+func (s *NoteService) ShareNoteWithTeam(c *gin.Context, noteID, teamID uuid.UUID, accessLevel entity.AccessLevel, expiresAt *time.Time) error {
+	currentUserID, _ := middleware.GetUserInfoFromGin(c)
+
+	currentAccessLevel, _ := s.GetAccessLevel(c, noteID, currentUserID)
+	if err := authorize(authz.OnAsset(currentUserID, currentAccessLevel), authz.Share, entity.ResourceNote, "You do not have permission to share this note"); err != nil {
+		return err
+	}
+
+	if err := checkTeamShareTarget(c.Request.Context(), s.teamRepo, teamID, accessLevel); err != nil {
+		return err
+	}
+	if err := checkShareExpiry(accessLevel, expiresAt); err != nil {
+		return err
+	}
+
+	if err := s.repo.ShareNoteWithTeam(c.Request.Context(), noteID, teamID, accessLevel, expiresAt); err != nil {
+		return err
+	}
+
+	go s.eventProducer.Produce(event.NewTeamShareEvent(event.NoteTeamShared, event.Note, noteID.String(), teamID.String(), currentUserID.String(), time.Now().String(), accessLevel))
+
+	return nil
+}
+
+func (s *NoteService) RevokeTeamAccess(c *gin.Context, noteID, teamID uuid.UUID) error {
+	currentUserID, _ := middleware.GetUserInfoFromGin(c)
+	currentAccessLevel, _ := s.GetAccessLevel(c, noteID, currentUserID)
+	if err := authorize(authz.OnAsset(currentUserID, currentAccessLevel), authz.Share, entity.ResourceNote, "You do not have permission to revoke access for this note"); err != nil {
+		return err
+	}
+
+	if err := s.repo.RevokeTeamAccess(c.Request.Context(), noteID, teamID); err != nil {
+		return err
+	}
+
+	go s.eventProducer.Produce(event.NewTeamShareEvent(event.NoteTeamUnshared, event.Note, noteID.String(), teamID.String(), currentUserID.String(), time.Now().String(), entity.AccessLevelNone))
+
+	return nil
+}
+
 func (s *NoteService) Update(c *gin.Context, note *entity.Note) error {
 	userID, _ := middleware.GetUserInfoFromGin(c)
 
@@ -108,13 +193,37 @@ func (s *NoteService) Update(c *gin.Context, note *entity.Note) error {
 
 	log.Println(accessLevel)
 
-	if !accessLevel.GreaterThan(entity.AccessLevelWrite) {
-		return NewForbiddenError("You do not have permission to update this note")
+	if err := authorize(authz.OnAsset(userID, accessLevel), authz.Edit, entity.ResourceNote, "You do not have permission to update this note"); err != nil {
+		return err
+	}
+
+	existing, err := s.repo.GetByID(c.Request.Context(), note.ID)
+	if err != nil {
+		return NewNotFoundError(err.Error())
+	}
+	if note.Version > 0 && note.Version != existing.Version {
+		return NewPreconditionFailedError("note has been modified by someone else, reload it and try again")
+	}
+	if err := s.ensureBaseline(c.Request.Context(), existing); err != nil {
+		return err
+	}
+
+	if err := s.repo.Update(c.Request.Context(), note); err != nil {
+		if errors.Is(err, entity.ErrStaleVersion) {
+			return NewPreconditionFailedError("note has been modified by someone else, reload it and try again")
+		}
+		return err
+	}
+
+	// Mỗi lần cập nhật tạo một revision bất biến để có thể xem lại và khôi phục
+	if _, err := s.recordRevision(c.Request.Context(), note.ID, note.Title, note.Body, userID, nil); err != nil {
+		return err
 	}
 
-	err := s.repo.Update(c.Request.Context(), note)
 	go s.eventProducer.Produce(event.NewAssetEvent(event.NoteUpdated, event.Note, note.ID.String(), userID.String(), userID.String(), time.Now().String(), entity.AccessLevelNone))
-	return err
+	// Chỉ báo cho người mới được mention, người đã có trong bản trước không bị báo lại
+	s.mentions.Notify(c.Request.Context(), note.ID, userID, nil, existing.Body, note.Body)
+	return nil
 }
 
 func (s *NoteService) Delete(c *gin.Context, id uuid.UUID) error {
@@ -123,13 +232,94 @@ func (s *NoteService) Delete(c *gin.Context, id uuid.UUID) error {
 	var accessLevel entity.AccessLevel
 	accessLevel, _ = s.GetAccessLevel(c, id, userID)
 
-	if !accessLevel.GreaterThan(entity.AccessLevelOwner) {
-		return NewForbiddenError("You do not have permission to delete this note")
+	if err := authorize(authz.OnAsset(userID, accessLevel), authz.Delete, entity.ResourceNote, "You do not have permission to delete this note"); err != nil {
+		return err
 	}
 
-	err := s.repo.Delete(c.Request.Context(), id)
+	// Note vào thùng rác, có thể khôi phục cho tới khi bị purge
+	err := s.repo.Trash(c.Request.Context(), id, userID)
 
 	go s.eventProducer.Produce(event.NewAssetEvent(event.NoteDeleted, event.Note, id.String(), userID.String(), userID.String(), time.Now().String(), entity.AccessLevelNone))
 
 	return err
 }
+
+// checkMoveAccess kiểm tra user có quyền ghi trên folder nguồn của note và trên folder đích
+func (s *NoteService) checkMoveAccess(c *gin.Context, noteID, folderID uuid.UUID, policy entity.NoteSharePolicy) (*entity.Note, error) {
+	if !policy.IsValid() {
+		return nil, NewBadRequestError(fmt.Sprintf("unknown share policy %q", policy))
+	}
+
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	note, err := s.repo.GetByID(c.Request.Context(), noteID)
+	if err != nil {
+		return nil, NewNotFoundError(fmt.Sprintf("note %s not found", noteID))
+	}
+
+	sourceAccess, _ := s.repo.GetFolderAccessLevel(c.Request.Context(), note.FolderID, userID)
+	if err := authorize(authz.OnAsset(userID, sourceAccess), authz.Edit, entity.ResourceFolder, "You do not have write permission for the source folder"); err != nil {
+		return nil, err
+	}
+
+	destinationAccess, _ := s.repo.GetFolderAccessLevel(c.Request.Context(), folderID, userID)
+	if err := authorize(authz.OnAsset(userID, destinationAccess), authz.Edit, entity.ResourceFolder, "You do not have write permission for the destination folder"); err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// Move chuyển note sang folder khác, giữ hoặc xoá các share của note theo policy
+func (s *NoteService) Move(c *gin.Context, noteID, folderID uuid.UUID, policy entity.NoteSharePolicy) error {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	note, err := s.checkMoveAccess(c, noteID, folderID, policy)
+	if err != nil {
+		return err
+	}
+
+	// RESET xoá share của người khác trên note nên cần quyền share, không chỉ quyền ghi trên folder
+	if policy == entity.NoteSharesReset {
+		accessLevel, _ := s.GetAccessLevel(c, noteID, userID)
+		if err := authorize(authz.OnAsset(userID, accessLevel), authz.Share, entity.ResourceNote, "Only the owner can reset the shares of this note"); err != nil {
+			return err
+		}
+	}
+
+	if note.FolderID == folderID {
+		return nil
+	}
+
+	if err := s.repo.Move(c.Request.Context(), noteID, folderID, policy); err != nil {
+		return err
+	}
+
+	go s.eventProducer.Produce(event.NewMoveEvent(event.NoteMoved, event.Note, noteID.String(), folderID.String(), userID.String(), time.Now().String()))
+
+	return nil
+}
+
+// Copy tạo bản sao của note trong folder đích; user thực hiện trở thành owner của bản sao
+func (s *NoteService) Copy(c *gin.Context, noteID, folderID uuid.UUID, policy entity.NoteSharePolicy) (*entity.Note, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	if _, err := s.checkMoveAccess(c, noteID, folderID, policy); err != nil {
+		return nil, err
+	}
+
+	copied, err := s.repo.Copy(c.Request.Context(), noteID, folderID, userID, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bản sao bắt đầu lịch sử revision riêng
+	if _, err := s.recordRevision(c.Request.Context(), copied.ID, copied.Title, copied.Body, userID, nil); err != nil {
+		return nil, err
+	}
+
+	copiedEvent := event.NewAssetEvent(event.NoteCopied, event.Note, copied.ID.String(), userID.String(), userID.String(), time.Now().String(), entity.AccessLevelOwner)
+	copiedEvent.ParentId = folderID.String()
+	go s.eventProducer.Produce(copiedEvent)
+
+	return copied, nil
+}
@@ -0,0 +1,133 @@
+package application
+
+import (
+	"collab-service/internal/domain/authz"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/external/event"
+	"collab-service/internal/interface/http/middleware"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ListNoteTags returns the tags on the note that the caller can see
+func (s *NoteService) ListNoteTags(c *gin.Context, noteID uuid.UUID) ([]*entity.Tag, error) {
+	if err := s.checkReadAccess(c, noteID); err != nil {
+		return nil, err
+	}
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	return s.tagRepo.ListByNote(c.Request.Context(), noteID, userID)
+}
+
+// AddNoteTag gắn tag lên note; cần quyền ghi trên note và tag phải là tag user được dùng
+func (s *NoteService) AddNoteTag(c *gin.Context, noteID, tagID uuid.UUID) error {
+	userID, tag, err := s.checkTagWriteAccess(c, noteID, tagID)
+	if err != nil {
+		return err
+	}
+
+	added, err := s.tagRepo.AddToNote(c.Request.Context(), noteID, tag.ID, userID)
+	if err != nil || !added {
+		return err
+	}
+
+	s.produceTagsEvent(c, noteID, userID, []string{tag.ID.String()}, nil)
+	return nil
+}
+
+// RemoveNoteTag gỡ tag khỏi note
+func (s *NoteService) RemoveNoteTag(c *gin.Context, noteID, tagID uuid.UUID) error {
+	userID, tag, err := s.checkTagWriteAccess(c, noteID, tagID)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.tagRepo.RemoveFromNote(c.Request.Context(), noteID, tag.ID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return NewNotFoundError(fmt.Sprintf("note %s is not tagged with %s", noteID, tagID))
+	}
+
+	s.produceTagsEvent(c, noteID, userID, nil, []string{tag.ID.String()})
+	return nil
+}
+
+// GetAllCanAccessByTags lọc các note user truy cập được theo tag; mỗi ref là ID hoặc tên tag (không phân biệt hoa thường).
+// Một tên có thể khớp nhiều tag (ví dụ tag cá nhân và tag team cùng tên), note chỉ cần có một trong số đó.
+func (s *NoteService) GetAllCanAccessByTags(c *gin.Context, refs []string) ([]*entity.Note, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	visible, err := s.tagRepo.ListVisible(c.Request.Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([][]uuid.UUID, 0, len(refs))
+	for _, ref := range refs {
+		ids := matchTags(visible, strings.TrimSpace(ref))
+		if len(ids) == 0 {
+			// Tag không tồn tại hoặc user không nhìn thấy: không note nào khớp
+			return []*entity.Note{}, nil
+		}
+		groups = append(groups, ids)
+	}
+
+	return s.repo.GetAllCanAccessByTags(c.Request.Context(), userID, groups)
+}
+
+func matchTags(tags []*entity.Tag, ref string) []uuid.UUID {
+	id, parseErr := uuid.Parse(ref)
+
+	var ids []uuid.UUID
+	for _, tag := range tags {
+		if (parseErr == nil && tag.ID == id) || strings.EqualFold(tag.Name, ref) {
+			ids = append(ids, tag.ID)
+		}
+	}
+	return ids
+}
+
+func (s *NoteService) checkTagWriteAccess(c *gin.Context, noteID, tagID uuid.UUID) (uuid.UUID, *entity.Tag, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	accessLevel, _ := s.GetAccessLevel(c, noteID, userID)
+	if err := authorize(authz.OnAsset(userID, accessLevel), authz.Edit, entity.ResourceNote, "You do not have write permission for this note"); err != nil {
+		return userID, nil, err
+	}
+
+	tag, err := s.tagRepo.GetByID(c.Request.Context(), tagID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return userID, nil, NewNotFoundError(fmt.Sprintf("tag %s not found", tagID))
+	}
+	if err != nil {
+		return userID, nil, err
+	}
+
+	if tag.IsTeamTag() {
+		role, err := s.teamRepo.GetRole(c.Request.Context(), *tag.TeamID, userID)
+		if err != nil {
+			return userID, nil, err
+		}
+		if !authz.Allowed(authz.InTeam(userID, role), authz.View, entity.ResourceTeam) {
+			return userID, nil, NewNotFoundError(fmt.Sprintf("tag %s not found", tagID))
+		}
+	} else if tag.OwnerID == nil || *tag.OwnerID != userID {
+		return userID, nil, NewNotFoundError(fmt.Sprintf("tag %s not found", tagID))
+	}
+	return userID, tag, nil
+}
+
+func (s *NoteService) produceTagsEvent(c *gin.Context, noteID, userID uuid.UUID, added, removed []string) {
+	ownerID, err := s.repo.GetOwner(c.Request.Context(), noteID)
+	if err != nil {
+		ownerID = userID
+	}
+	go s.eventProducer.Produce(event.NewNoteTagsEvent(noteID.String(), ownerID.String(), userID.String(), time.Now().String(), added, removed))
+}
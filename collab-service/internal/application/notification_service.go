@@ -0,0 +1,133 @@
+package application
+
+import (
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/interface/http/middleware"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultNotificationPageSize = 20
+	MaxNotificationPageSize     = 100
+)
+
+// NotificationPage là một trang của inbox, mới nhất trước
+type NotificationPage struct {
+	Notifications []*entity.Notification `json:"notifications"`
+	Page          int                    `json:"page"`
+	PageSize      int                    `json:"pageSize"`
+	Total         int64                  `json:"total"`
+	Unread        int64                  `json:"unread"`
+}
+
+// NotificationService đọc và cập nhật trạng thái inbox của user hiện tại.
+// Thông báo được collab-consumer ghi vào từ các topic asset.changes và team.activity.
+type NotificationService struct {
+	store entity.NotificationStore
+}
+
+func NewNotificationService(store entity.NotificationStore) *NotificationService {
+	return &NotificationService{
+		store: store,
+	}
+}
+
+// List returns a page of the caller's notifications, optionally only the unread ones
+func (s *NotificationService) List(c *gin.Context, unreadOnly bool, page, pageSize int) (*NotificationPage, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultNotificationPageSize
+	}
+	if pageSize > MaxNotificationPageSize {
+		pageSize = MaxNotificationPageSize
+	}
+
+	notifications, total, err := s.store.List(c.Request.Context(), userID, unreadOnly, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.store.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NotificationPage{
+		Notifications: notifications,
+		Page:          page,
+		PageSize:      pageSize,
+		Total:         total,
+		Unread:        unread,
+	}, nil
+}
+
+// UnreadCount returns the number of unread notifications of the caller
+func (s *NotificationService) UnreadCount(c *gin.Context) (int64, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	return s.store.UnreadCount(c.Request.Context(), userID)
+}
+
+// SetRead đánh dấu một thông báo của caller là đã đọc hoặc chưa đọc
+func (s *NotificationService) SetRead(c *gin.Context, notificationID string, read bool) error {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	found, err := s.store.SetRead(c.Request.Context(), userID, notificationID, read)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return NewNotFoundError(fmt.Sprintf("notification %s not found", notificationID))
+	}
+	return nil
+}
+
+// MarkAllRead đánh dấu toàn bộ inbox của caller là đã đọc
+func (s *NotificationService) MarkAllRead(c *gin.Context) error {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	return s.store.MarkAllRead(c.Request.Context(), userID)
+}
+
+// GetPreferences trả về cách gửi email của caller cho mọi loại thông báo, loại chưa chọn dùng mặc định
+func (s *NotificationService) GetPreferences(c *gin.Context) (map[string]entity.NotificationDelivery, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	saved, err := s.store.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := make(map[string]entity.NotificationDelivery, len(entity.NotificationTypes))
+	for _, eventType := range entity.NotificationTypes {
+		preferences[eventType] = entity.DefaultNotificationDelivery
+		if delivery, ok := saved[eventType]; ok && delivery.IsValid() {
+			preferences[eventType] = delivery
+		}
+	}
+	return preferences, nil
+}
+
+// UpdatePreferences đổi cách gửi email cho các loại thông báo được truyền, các loại khác giữ nguyên
+func (s *NotificationService) UpdatePreferences(c *gin.Context, changes map[string]entity.NotificationDelivery) (map[string]entity.NotificationDelivery, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	for eventType, delivery := range changes {
+		if !slices.Contains(entity.NotificationTypes, eventType) {
+			return nil, NewBadRequestError(fmt.Sprintf("unknown notification type %q, expected one of %s", eventType, strings.Join(entity.NotificationTypes, ", ")))
+		}
+		if !delivery.IsValid() {
+			return nil, NewBadRequestError(fmt.Sprintf("invalid delivery %q for %s, expected instant, daily, weekly or off", delivery, eventType))
+		}
+	}
+
+	if err := s.store.SetPreferences(c.Request.Context(), userID, changes); err != nil {
+		return nil, err
+	}
+	return s.GetPreferences(c)
+}
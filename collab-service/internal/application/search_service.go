@@ -0,0 +1,112 @@
+package application
+
+import (
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/interface/http/middleware"
+	"html"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultSearchPageSize = 20
+	MaxSearchPageSize     = 100
+	// MaxSearchQueryLength giới hạn độ dài truy vấn (rune) để tránh tsquery quá lớn
+	MaxSearchQueryLength = 256
+)
+
+// SearchResult là một kết quả tìm kiếm. Highlight và Snippet đã được escape HTML,
+// từ khớp được bọc trong <mark></mark>.
+type SearchResult struct {
+	Type      entity.SearchHitType `json:"type"`
+	ID        uuid.UUID            `json:"id"`
+	Title     string               `json:"title"`
+	Highlight string               `json:"highlight"`
+	Snippet   string               `json:"snippet,omitempty"`
+	ParentID  *uuid.UUID           `json:"parentId,omitempty"`
+	Rank      float64              `json:"rank"`
+	UpdatedAt time.Time            `json:"updatedAt"`
+}
+
+// SearchPage là một trang kết quả, liên quan nhất trước
+type SearchPage struct {
+	Results  []SearchResult `json:"results"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"pageSize"`
+	Total    int64          `json:"total"`
+}
+
+type SearchService struct {
+	searchRepo entity.SearchRepository
+}
+
+func NewSearchService(searchRepo entity.SearchRepository) *SearchService {
+	return &SearchService{
+		searchRepo: searchRepo,
+	}
+}
+
+// Search tìm note và folder theo nội dung; chỉ trả về asset user hiện tại truy cập được
+func (s *SearchService) Search(c *gin.Context, query *entity.SearchQuery, page, pageSize int) (*SearchPage, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Text == "" {
+		return nil, NewBadRequestError("search query must not be empty")
+	}
+	if utf8.RuneCountInString(query.Text) > MaxSearchQueryLength {
+		return nil, NewBadRequestError("search query is too long")
+	}
+	if query.UpdatedFrom != nil && query.UpdatedTo != nil && !query.UpdatedFrom.Before(*query.UpdatedTo) {
+		return nil, NewBadRequestError("from must be before to")
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultSearchPageSize
+	}
+	if pageSize > MaxSearchPageSize {
+		pageSize = MaxSearchPageSize
+	}
+	query.Offset = (page - 1) * pageSize
+	query.Limit = pageSize
+
+	hits, total, err := s.searchRepo.Search(c.Request.Context(), userID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SearchPage{
+		Results:  make([]SearchResult, len(hits)),
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}
+	for i, hit := range hits {
+		result.Results[i] = SearchResult{
+			Type:      hit.Type,
+			ID:        hit.ID,
+			Title:     hit.Title,
+			Highlight: markHighlights(hit.Highlight),
+			Snippet:   markHighlights(hit.Snippet),
+			ParentID:  hit.ParentID,
+			Rank:      hit.Rank,
+			UpdatedAt: hit.UpdatedAt,
+		}
+	}
+	return result, nil
+}
+
+// markHighlights escape nội dung của user rồi mới thay dấu đánh dấu từ khớp bằng thẻ <mark>,
+// để snippet có thể hiển thị dạng HTML mà không bị chèn mã
+func markHighlights(text string) string {
+	text = html.EscapeString(text)
+	text = strings.ReplaceAll(text, entity.SearchMarkStart, "<mark>")
+	return strings.ReplaceAll(text, entity.SearchMarkStop, "</mark>")
+}
@@ -0,0 +1,146 @@
+package application
+
+import (
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/external/event"
+	"collab-service/internal/infrastructure/logger"
+	"context"
+	"time"
+)
+
+// shareExpiryBatchSize giới hạn số share mỗi lần quét; phần còn lại được xử lý ở lần chạy sau
+const shareExpiryBatchSize = 200
+
+// checkShareExpiry kiểm tra hạn của share mới; owner không thể bị hết hạn
+func checkShareExpiry(accessLevel entity.AccessLevel, expiresAt *time.Time) error {
+	if expiresAt == nil {
+		return nil
+	}
+	if accessLevel == entity.AccessLevelOwner {
+		return NewBadRequestError("OWNER shares cannot expire")
+	}
+	if !expiresAt.After(time.Now()) {
+		return NewBadRequestError("expires_at must be in the future")
+	}
+	return nil
+}
+
+// ShareExpiryService xoá share đã hết hạn trên note/folder và nhắc user trước khi share của họ hết hạn
+type ShareExpiryService struct {
+	noteRepo       entity.NoteRepository
+	folderRepo     entity.FolderRepository
+	eventProducer  *event.AssetChangeProducer
+	reminderWindow time.Duration
+}
+
+func NewShareExpiryService(noteRepo entity.NoteRepository, folderRepo entity.FolderRepository, reminderWindow time.Duration) *ShareExpiryService {
+	return &ShareExpiryService{
+		noteRepo:       noteRepo,
+		folderRepo:     folderRepo,
+		eventProducer:  event.GetAssetChangeProducer(),
+		reminderWindow: reminderWindow,
+	}
+}
+
+// RemoveExpiredShares xoá share đã hết hạn và phát event unshare tương ứng.
+// It is run by the background scheduler and returns the number of removed shares.
+func (s *ShareExpiryService) RemoveExpiredShares(ctx context.Context) (int, error) {
+	now := time.Now()
+	removed := 0
+
+	noteShares, err := s.noteRepo.ListExpiredShares(ctx, now, shareExpiryBatchSize)
+	if err != nil {
+		return removed, err
+	}
+	for _, share := range noteShares {
+		// Share có thể vừa được gia hạn hoặc đã bị instance khác xoá
+		deleted, err := s.noteRepo.DeleteExpiredShare(ctx, share.ID, now)
+		if err != nil {
+			logger.Error("failed to remove expired note share", "shareId", share.ID.String(), "noteId", share.NoteID.String(), "error", err.Error())
+			continue
+		}
+		if !deleted {
+			continue
+		}
+		removed++
+
+		eventType, userID, teamID := event.NoteUnshared, share.UserID.String(), ""
+		if share.TeamID != nil {
+			eventType, userID, teamID = event.NoteTeamUnshared, "", share.TeamID.String()
+		}
+		go s.eventProducer.Produce(event.NewShareExpiredEvent(eventType, event.Note, share.NoteID.String(), userID, teamID, now.String()))
+	}
+
+	folderShares, err := s.folderRepo.ListExpiredShares(ctx, now, shareExpiryBatchSize)
+	if err != nil {
+		return removed, err
+	}
+	for _, share := range folderShares {
+		deleted, err := s.folderRepo.DeleteExpiredShare(ctx, share.ID, now)
+		if err != nil {
+			logger.Error("failed to remove expired folder share", "shareId", share.ID.String(), "folderId", share.FolderID.String(), "error", err.Error())
+			continue
+		}
+		if !deleted {
+			continue
+		}
+		removed++
+
+		eventType, userID, teamID := event.FolderUnshared, share.UserID.String(), ""
+		if share.TeamID != nil {
+			eventType, userID, teamID = event.FolderTeamUnshared, "", share.TeamID.String()
+		}
+		go s.eventProducer.Produce(event.NewShareExpiredEvent(eventType, event.Folder, share.FolderID.String(), userID, teamID, now.String()))
+	}
+
+	return removed, nil
+}
+
+// RemindExpiringShares phát SHARE_EXPIRING cho user có share sắp hết hạn trong reminderWindow.
+// Mỗi share chỉ được nhắc một lần; share lại với hạn mới sẽ được nhắc lại.
+func (s *ShareExpiryService) RemindExpiringShares(ctx context.Context) (int, error) {
+	now := time.Now()
+	before := now.Add(s.reminderWindow)
+	reminded := 0
+
+	noteShares, err := s.noteRepo.ListSharesExpiringBefore(ctx, now, before, shareExpiryBatchSize)
+	if err != nil {
+		return reminded, err
+	}
+	for _, share := range noteShares {
+		// Đánh dấu trước khi gửi để nhiều instance chạy cùng lúc không nhắc trùng
+		marked, err := s.noteRepo.MarkShareReminded(ctx, share.ID, now)
+		if err != nil {
+			logger.Error("failed to mark note share reminded", "shareId", share.ID.String(), "error", err.Error())
+			continue
+		}
+		if !marked {
+			continue
+		}
+		reminded++
+
+		go s.eventProducer.Produce(event.NewShareExpiringEvent(event.Note, share.NoteID.String(), share.UserID.String(),
+			share.ExpiresAt.UTC().Format(time.RFC3339), now.String(), share.AccessLevel))
+	}
+
+	folderShares, err := s.folderRepo.ListSharesExpiringBefore(ctx, now, before, shareExpiryBatchSize)
+	if err != nil {
+		return reminded, err
+	}
+	for _, share := range folderShares {
+		marked, err := s.folderRepo.MarkShareReminded(ctx, share.ID, now)
+		if err != nil {
+			logger.Error("failed to mark folder share reminded", "shareId", share.ID.String(), "error", err.Error())
+			continue
+		}
+		if !marked {
+			continue
+		}
+		reminded++
+
+		go s.eventProducer.Produce(event.NewShareExpiringEvent(event.Folder, share.FolderID.String(), share.UserID.String(),
+			share.ExpiresAt.UTC().Format(time.RFC3339), now.String(), share.AccessLevel))
+	}
+
+	return reminded, nil
+}
@@ -0,0 +1,376 @@
+package application
+
+import (
+	"collab-service/internal/domain/authz"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/logger"
+	"collab-service/internal/infrastructure/sercurity"
+	"collab-service/internal/interface/http/middleware"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	maxActiveShareLinks = 20
+	minShareLinkPassLen = 6
+	// bcrypt chỉ dùng 72 byte đầu của mật khẩu
+	maxShareLinkPassLen = 72
+	maxShareLinkUALen   = 512
+	// Quá shareLinkMaxFailures lần sai mật khẩu từ một IP trong shareLinkFailureWindow thì tạm khoá IP đó
+	shareLinkMaxFailures   = 10
+	shareLinkFailureWindow = 15 * time.Minute
+
+	DefaultShareLinkAccessPageSize = 50
+	MaxShareLinkAccessPageSize     = 200
+)
+
+// SharedContent là nội dung một link công khai trả về: một note, hoặc một folder cùng các folder con và note trực tiếp bên trong
+type SharedContent struct {
+	Link       *entity.ShareLink
+	Note       *entity.Note
+	Folder     *entity.Folder
+	SubFolders []*entity.Folder
+	Notes      []*entity.Note
+}
+
+// ShareLinkAccessPage là một trang access log của link, mới nhất trước
+type ShareLinkAccessPage struct {
+	Accesses []*entity.ShareLinkAccess
+	Page     int
+	PageSize int
+	Total    int64
+}
+
+// ShareLinkService quản lý link công khai chỉ đọc cho note và folder.
+// Owner tạo, liệt kê và thu hồi link; người có link mở nội dung qua route /s/:token không cần đăng nhập.
+type ShareLinkService struct {
+	noteRepo   entity.NoteRepository
+	folderRepo entity.FolderRepository
+	linkRepo   entity.ShareLinkRepository
+}
+
+func NewShareLinkService(noteRepo entity.NoteRepository, folderRepo entity.FolderRepository, linkRepo entity.ShareLinkRepository) *ShareLinkService {
+	return &ShareLinkService{
+		noteRepo:   noteRepo,
+		folderRepo: folderRepo,
+		linkRepo:   linkRepo,
+	}
+}
+
+// Create tạo link mới cho asset; chỉ owner được tạo. Token trong kết quả không thể lấy lại sau đó.
+func (s *ShareLinkService) Create(c *gin.Context, assetType entity.ShareLinkAssetType, assetID uuid.UUID, password string, expiresAt *time.Time) (*entity.ShareLink, error) {
+	if err := s.requireOwner(c, assetType, assetID); err != nil {
+		return nil, err
+	}
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	ctx := c.Request.Context()
+	now := time.Now()
+
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, NewBadRequestError("expires_at must be in the future")
+	}
+
+	link := &entity.ShareLink{
+		ID:        uuid.New(),
+		AssetType: assetType,
+		AssetID:   assetID,
+		ExpiresAt: expiresAt,
+		CreatedBy: userID,
+	}
+	if password != "" {
+		if len(password) < minShareLinkPassLen || len(password) > maxShareLinkPassLen {
+			return nil, NewBadRequestError(fmt.Sprintf("password must be between %d and %d characters", minShareLinkPassLen, maxShareLinkPassLen))
+		}
+		hash, err := sercurity.HashPassword(password)
+		if err != nil {
+			return nil, err
+		}
+		link.PasswordHash = hash
+	}
+
+	active, err := s.linkRepo.CountActive(ctx, assetType, assetID, now)
+	if err != nil {
+		return nil, err
+	}
+	if active >= maxActiveShareLinks {
+		return nil, NewConflictError(fmt.Sprintf("a %s can have at most %d active share links", assetTypeName(assetType), maxActiveShareLinks))
+	}
+
+	token, err := generateShareLinkToken()
+	if err != nil {
+		return nil, err
+	}
+	link.Token = token
+	link.TokenHash = hashShareLinkToken(token)
+
+	return s.linkRepo.Create(ctx, link)
+}
+
+// List returns every link of an asset, including expired and revoked ones
+func (s *ShareLinkService) List(c *gin.Context, assetType entity.ShareLinkAssetType, assetID uuid.UUID) ([]*entity.ShareLink, error) {
+	if err := s.requireOwner(c, assetType, assetID); err != nil {
+		return nil, err
+	}
+	return s.linkRepo.ListByAsset(c.Request.Context(), assetType, assetID)
+}
+
+// Revoke vô hiệu hoá link ngay lập tức; link đã thu hồi vẫn được giữ lại cùng access log
+func (s *ShareLinkService) Revoke(c *gin.Context, assetType entity.ShareLinkAssetType, assetID, linkID uuid.UUID) error {
+	if err := s.requireOwner(c, assetType, assetID); err != nil {
+		return err
+	}
+	if _, err := s.getOfAsset(c, assetType, assetID, linkID); err != nil {
+		return err
+	}
+	return s.linkRepo.Revoke(c.Request.Context(), linkID, time.Now())
+}
+
+// ListAccesses returns the access log of a link, newest first
+func (s *ShareLinkService) ListAccesses(c *gin.Context, assetType entity.ShareLinkAssetType, assetID, linkID uuid.UUID, page, pageSize int) (*ShareLinkAccessPage, error) {
+	if err := s.requireOwner(c, assetType, assetID); err != nil {
+		return nil, err
+	}
+	if _, err := s.getOfAsset(c, assetType, assetID, linkID); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultShareLinkAccessPageSize
+	}
+	if pageSize > MaxShareLinkAccessPageSize {
+		pageSize = MaxShareLinkAccessPageSize
+	}
+
+	accesses, total, err := s.linkRepo.ListAccesses(c.Request.Context(), linkID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &ShareLinkAccessPage{Accesses: accesses, Page: page, PageSize: pageSize, Total: total}, nil
+}
+
+// Open trả về asset của link. Không cần đăng nhập; mọi lần mở, kể cả thất bại, đều được ghi vào access log.
+func (s *ShareLinkService) Open(c *gin.Context, token, password string) (*SharedContent, error) {
+	link, err := s.authorize(c, token, password)
+	if err != nil {
+		return nil, err
+	}
+	if link.AssetType == entity.ShareLinkNote {
+		return s.openNote(c, link, link.AssetID)
+	}
+	return s.openFolder(c, link, link.AssetID)
+}
+
+// OpenNote trả về một note bên trong folder được share qua link (hoặc chính note của link)
+func (s *ShareLinkService) OpenNote(c *gin.Context, token, password string, noteID uuid.UUID) (*SharedContent, error) {
+	link, err := s.authorize(c, token, password)
+	if err != nil {
+		return nil, err
+	}
+	return s.openNote(c, link, noteID)
+}
+
+// OpenFolder trả về một folder con bên trong folder được share qua link
+func (s *ShareLinkService) OpenFolder(c *gin.Context, token, password string, folderID uuid.UUID) (*SharedContent, error) {
+	link, err := s.authorize(c, token, password)
+	if err != nil {
+		return nil, err
+	}
+	if link.AssetType != entity.ShareLinkFolder {
+		s.record(c, link, entity.ShareLinkAccessUnavailable, entity.ShareLinkFolder, folderID)
+		return nil, NewNotFoundError("folder not found")
+	}
+	return s.openFolder(c, link, folderID)
+}
+
+// authorize kiểm tra token, hạn, trạng thái thu hồi và mật khẩu của link.
+// Link không tồn tại và link đã thu hồi đều trả về 404 để không lộ link nào từng tồn tại.
+func (s *ShareLinkService) authorize(c *gin.Context, token, password string) (*entity.ShareLink, error) {
+	ctx := c.Request.Context()
+
+	link, err := s.linkRepo.GetByTokenHash(ctx, hashShareLinkToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewNotFoundError("share link not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	switch {
+	case link.IsRevoked():
+		s.record(c, link, entity.ShareLinkAccessRevoked, link.AssetType, link.AssetID)
+		return nil, NewNotFoundError("share link not found")
+	case link.IsExpired(now):
+		s.record(c, link, entity.ShareLinkAccessExpired, link.AssetType, link.AssetID)
+		return nil, NewGoneError("share link has expired")
+	}
+
+	if !link.HasPassword() {
+		return link, nil
+	}
+	if password == "" {
+		s.record(c, link, entity.ShareLinkAccessPasswordRequired, link.AssetType, link.AssetID)
+		return nil, NewUnauthorizedError("share link requires a password")
+	}
+
+	failures, err := s.linkRepo.CountFailedAttempts(ctx, link.ID, c.ClientIP(), now.Add(-shareLinkFailureWindow))
+	if err != nil {
+		return nil, err
+	}
+	if failures >= shareLinkMaxFailures {
+		s.record(c, link, entity.ShareLinkAccessThrottled, link.AssetType, link.AssetID)
+		return nil, NewTooManyRequestsError("too many wrong passwords, try again later")
+	}
+	if sercurity.CheckPasswordHash(password, link.PasswordHash) != nil {
+		s.record(c, link, entity.ShareLinkAccessWrongPassword, link.AssetType, link.AssetID)
+		return nil, NewUnauthorizedError("invalid share link password")
+	}
+	return link, nil
+}
+
+func (s *ShareLinkService) openNote(c *gin.Context, link *entity.ShareLink, noteID uuid.UUID) (*SharedContent, error) {
+	note, err := s.noteRepo.GetByID(c.Request.Context(), noteID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	visible := note != nil
+	if visible && link.AssetType == entity.ShareLinkNote {
+		visible = note.ID == link.AssetID
+	} else if visible {
+		visible, err = s.inSharedFolder(c.Request.Context(), link.AssetID, note.FolderID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !visible {
+		s.record(c, link, entity.ShareLinkAccessUnavailable, entity.ShareLinkNote, noteID)
+		return nil, NewNotFoundError("note not found")
+	}
+
+	s.record(c, link, entity.ShareLinkAccessGranted, entity.ShareLinkNote, noteID)
+	return &SharedContent{Link: link, Note: note}, nil
+}
+
+func (s *ShareLinkService) openFolder(c *gin.Context, link *entity.ShareLink, folderID uuid.UUID) (*SharedContent, error) {
+	ctx := c.Request.Context()
+
+	// Folder trong thùng rác không tìm thấy được; folder con bị xoá cùng folder của link cũng vậy
+	folder, err := s.folderRepo.GetByID(ctx, folderID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	visible := folder != nil
+	if visible && folderID != link.AssetID {
+		if visible, err = s.inSharedFolder(ctx, link.AssetID, folderID); err != nil {
+			return nil, err
+		}
+	}
+	if !visible {
+		s.record(c, link, entity.ShareLinkAccessUnavailable, entity.ShareLinkFolder, folderID)
+		return nil, NewNotFoundError("folder not found")
+	}
+
+	subFolders, err := s.folderRepo.GetChildren(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.noteRepo.GetByFolderID(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.record(c, link, entity.ShareLinkAccessGranted, entity.ShareLinkFolder, folderID)
+	return &SharedContent{Link: link, Folder: folder, SubFolders: subFolders, Notes: notes}, nil
+}
+
+// inSharedFolder kiểm tra folderID là folder của link hoặc nằm bên dưới nó
+func (s *ShareLinkService) inSharedFolder(ctx context.Context, rootID, folderID uuid.UUID) (bool, error) {
+	if rootID == folderID {
+		return true, nil
+	}
+	subtree, err := s.folderRepo.GetSubtreeIDs(ctx, rootID)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(subtree, folderID), nil
+}
+
+// record ghi access log; lỗi ghi log không chặn người xem
+func (s *ShareLinkService) record(c *gin.Context, link *entity.ShareLink, outcome entity.ShareLinkAccessOutcome, assetType entity.ShareLinkAssetType, assetID uuid.UUID) {
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > maxShareLinkUALen {
+		userAgent = userAgent[:maxShareLinkUALen]
+	}
+	access := &entity.ShareLinkAccess{
+		ID:        uuid.New(),
+		LinkID:    link.ID,
+		Outcome:   outcome,
+		AssetType: assetType,
+		AssetID:   assetID,
+		IP:        c.ClientIP(),
+		UserAgent: userAgent,
+		CreatedAt: time.Now(),
+	}
+	if err := s.linkRepo.RecordAccess(c.Request.Context(), access); err != nil {
+		logger.Error("failed to record share link access", "linkId", link.ID.String(), "outcome", string(outcome), "error", err.Error())
+	}
+}
+
+func (s *ShareLinkService) requireOwner(c *gin.Context, assetType entity.ShareLinkAssetType, assetID uuid.UUID) error {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	ctx := c.Request.Context()
+
+	accessLevel, resource := entity.AccessLevelNone, entity.ResourceFolder
+	if assetType == entity.ShareLinkNote {
+		accessLevel, _ = s.noteRepo.GetAccessLevel(ctx, assetID, userID)
+		resource = entity.ResourceNote
+	} else {
+		accessLevel, _ = s.folderRepo.GetAccessLevel(ctx, assetID, userID)
+	}
+	return authorize(authz.OnAsset(userID, accessLevel), authz.Share, resource,
+		fmt.Sprintf("only the owner can manage share links of this %s", assetTypeName(assetType)))
+}
+
+func (s *ShareLinkService) getOfAsset(c *gin.Context, assetType entity.ShareLinkAssetType, assetID, linkID uuid.UUID) (*entity.ShareLink, error) {
+	link, err := s.linkRepo.GetByID(c.Request.Context(), linkID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && (link.AssetType != assetType || link.AssetID != assetID)) {
+		return nil, NewNotFoundError(fmt.Sprintf("share link %s not found", linkID))
+	}
+	return link, err
+}
+
+func assetTypeName(assetType entity.ShareLinkAssetType) string {
+	if assetType == entity.ShareLinkNote {
+		return "note"
+	}
+	return "folder"
+}
+
+// generateShareLinkToken sinh token 256 bit, mã hoá base64url để dùng trực tiếp trong URL
+func generateShareLinkToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashShareLinkToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
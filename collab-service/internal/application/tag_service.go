@@ -0,0 +1,192 @@
+package application
+
+import (
+	"collab-service/internal/domain/authz"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/interface/http/middleware"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const MaxTagNameLength = 64
+
+// TagService quản lý tag cá nhân và tag của team
+type TagService struct {
+	tagRepo  entity.TagRepository
+	teamRepo entity.TeamRepository
+}
+
+func NewTagService(tagRepo entity.TagRepository, teamRepo entity.TeamRepository) *TagService {
+	return &TagService{
+		tagRepo:  tagRepo,
+		teamRepo: teamRepo,
+	}
+}
+
+// ListTags returns the caller's personal tags and the tags of their teams, optionally limited to one team
+func (s *TagService) ListTags(c *gin.Context, teamID *uuid.UUID) ([]*entity.Tag, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	tags, err := s.tagRepo.ListVisible(c.Request.Context(), userID)
+	if err != nil || teamID == nil {
+		return tags, err
+	}
+
+	filtered := make([]*entity.Tag, 0, len(tags))
+	for _, tag := range tags {
+		if tag.TeamID != nil && *tag.TeamID == *teamID {
+			filtered = append(filtered, tag)
+		}
+	}
+	return filtered, nil
+}
+
+// CreateTag tạo tag cá nhân, hoặc tag của team nếu teamID được truyền (cần quyền manager của team)
+func (s *TagService) CreateTag(c *gin.Context, name, color string, teamID *uuid.UUID) (*entity.Tag, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	name, color, err := normalizeTag(name, color)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &entity.Tag{Name: name, Color: color, TeamID: teamID, CreatedBy: userID}
+	if teamID != nil {
+		if err := s.requireTeamManager(c, *teamID, userID); err != nil {
+			return nil, err
+		}
+	} else {
+		tag.OwnerID = &userID
+	}
+
+	if err := s.ensureUniqueName(c, tag, uuid.Nil); err != nil {
+		return nil, err
+	}
+	return s.tagRepo.Create(c.Request.Context(), tag)
+}
+
+// UpdateTag đổi tên và/hoặc màu của tag; trường nil được giữ nguyên
+func (s *TagService) UpdateTag(c *gin.Context, tagID uuid.UUID, name, color *string) (*entity.Tag, error) {
+	tag, err := s.getManageableTag(c, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	newName, newColor := tag.Name, tag.Color
+	if name != nil {
+		newName = *name
+	}
+	if color != nil {
+		newColor = *color
+	}
+	if tag.Name, tag.Color, err = normalizeTag(newName, newColor); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureUniqueName(c, tag, tag.ID); err != nil {
+		return nil, err
+	}
+	if err := s.tagRepo.Update(c.Request.Context(), tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// DeleteTag xoá tag và gỡ nó khỏi mọi note
+func (s *TagService) DeleteTag(c *gin.Context, tagID uuid.UUID) error {
+	if _, err := s.getManageableTag(c, tagID); err != nil {
+		return err
+	}
+	return s.tagRepo.Delete(c.Request.Context(), tagID)
+}
+
+// getManageableTag trả về tag nếu caller được sửa/xoá: chủ của tag cá nhân hoặc manager của team sở hữu tag
+func (s *TagService) getManageableTag(c *gin.Context, tagID uuid.UUID) (*entity.Tag, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	tag, err := s.tagRepo.GetByID(c.Request.Context(), tagID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewNotFoundError(fmt.Sprintf("tag %s not found", tagID))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if tag.IsTeamTag() {
+		role, err := s.teamRepo.GetRole(c.Request.Context(), *tag.TeamID, userID)
+		if err != nil {
+			return nil, err
+		}
+		principal := authz.InTeam(userID, role)
+		if !authz.Allowed(principal, authz.View, entity.ResourceTeam) {
+			// Không để lộ tag của team mà user không thuộc về
+			return nil, NewNotFoundError(fmt.Sprintf("tag %s not found", tagID))
+		}
+		if err := authorize(principal, authz.ManageTeam, entity.ResourceTeam, "only team managers can change team tags"); err != nil {
+			return nil, err
+		}
+		return tag, nil
+	}
+
+	if tag.OwnerID == nil || *tag.OwnerID != userID {
+		return nil, NewNotFoundError(fmt.Sprintf("tag %s not found", tagID))
+	}
+	return tag, nil
+}
+
+func (s *TagService) requireTeamManager(c *gin.Context, teamID, userID uuid.UUID) error {
+	team, err := s.teamRepo.GetByID(c.Request.Context(), teamID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && team == nil) {
+		return NewNotFoundError(fmt.Sprintf("team %s not found", teamID))
+	}
+	if err != nil {
+		return err
+	}
+	if err := ensureNotArchived(team); err != nil {
+		return err
+	}
+
+	role, err := s.teamRepo.GetRole(c.Request.Context(), teamID, userID)
+	if err != nil {
+		return err
+	}
+	return authorize(authz.InTeam(userID, role), authz.ManageTeam, entity.ResourceTeam,
+		fmt.Sprintf("only managers of team %s can create team tags", teamID))
+}
+
+// ensureUniqueName từ chối tên trùng (không phân biệt hoa thường) trong cùng phạm vi user/team, bỏ qua chính tag đang sửa
+func (s *TagService) ensureUniqueName(c *gin.Context, tag *entity.Tag, exceptID uuid.UUID) error {
+	existing, err := s.tagRepo.FindByName(c.Request.Context(), tag.OwnerID, tag.TeamID, tag.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != exceptID {
+		return NewConflictError(fmt.Sprintf("tag %q already exists", tag.Name))
+	}
+	return nil
+}
+
+func normalizeTag(name, color string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", "", NewBadRequestError("tag name is required")
+	}
+	if utf8.RuneCountInString(name) > MaxTagNameLength {
+		return "", "", NewBadRequestError(fmt.Sprintf("tag name must be at most %d characters", MaxTagNameLength))
+	}
+
+	color = strings.TrimSpace(color)
+	if color == "" {
+		color = entity.DefaultTagColor
+	}
+	if !entity.IsValidTagColor(color) {
+		return "", "", NewBadRequestError(fmt.Sprintf("invalid tag color %q, expected #RRGGBB", color))
+	}
+	return name, strings.ToUpper(color), nil
+}
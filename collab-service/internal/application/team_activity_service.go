@@ -0,0 +1,220 @@
+package application
+
+import (
+	"collab-service/internal/domain/authz"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/external/event"
+	"collab-service/internal/interface/http/middleware"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultActivityPageSize = 20
+	MaxActivityPageSize     = 100
+)
+
+// activityMessages là mẫu câu hiển thị cho từng loại event: actor rồi target
+var activityMessages = map[event.TeamEventType]string{
+	event.TeamCreated:    "%s created the team",
+	event.TeamArchived:   "%s archived the team",
+	event.TeamRestored:   "%s restored the team",
+	event.MemberAdded:    "%s added %s as a member",
+	event.MemberRemoved:  "%s removed member %s",
+	event.ManagerAdded:   "%s added %s as a manager",
+	event.ManagerRemoved: "%s removed manager %s",
+}
+
+// ActivityUser là actor hoặc target của một activity kèm tên hiển thị
+type ActivityUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// TeamActivityItem là một activity đã được render để hiển thị
+type TeamActivityItem struct {
+	ID         uuid.UUID     `json:"id"`
+	Type       string        `json:"type"`
+	Actor      ActivityUser  `json:"actor"`
+	Target     *ActivityUser `json:"target,omitempty"`
+	Message    string        `json:"message"`
+	OccurredAt time.Time     `json:"occurredAt"`
+}
+
+// TeamActivityPage là một trang của activity feed
+type TeamActivityPage struct {
+	Activities []TeamActivityItem `json:"activities"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"pageSize"`
+	Total      int64              `json:"total"`
+}
+
+// TeamActivityService lưu các TeamEvent từ stream và phục vụ activity feed của team
+type TeamActivityService struct {
+	activityRepository entity.TeamActivityRepository
+	teamRepository     entity.TeamRepository
+	userRepository     entity.UserRepository
+}
+
+func NewTeamActivityService(activityRepo entity.TeamActivityRepository, teamRepo entity.TeamRepository, userRepo entity.UserRepository) *TeamActivityService {
+	return &TeamActivityService{
+		activityRepository: activityRepo,
+		teamRepository:     teamRepo,
+		userRepository:     userRepo,
+	}
+}
+
+// RecordEvent persists a team event consumed from the team activity stream
+func (s *TeamActivityService) RecordEvent(ctx context.Context, e *event.TeamEvent) error {
+	teamID, err := uuid.Parse(e.TeamID)
+	if err != nil {
+		return nil // event không gắn với team hợp lệ, không có gì để lưu
+	}
+
+	occurredAt, err := time.Parse(time.RFC3339, e.Timestamp)
+	if err != nil {
+		occurredAt = time.Now()
+	}
+
+	eventID := e.EventID
+	if eventID == "" {
+		// Event cũ chưa có EventID: lấy hash nội dung để xử lý lại vẫn không bị trùng
+		sum := sha256.Sum256([]byte(e.TeamID + string(e.EventType) + e.PerformedBy + e.TargetUserID + e.Timestamp))
+		eventID = hex.EncodeToString(sum[:16])
+	}
+
+	activity := &entity.TeamActivity{
+		EventID:    eventID,
+		TeamID:     teamID,
+		EventType:  string(e.EventType),
+		ActorID:    e.PerformedBy,
+		OccurredAt: occurredAt,
+	}
+	if target, err := uuid.Parse(e.TargetUserID); err == nil {
+		activity.TargetUserID = &target
+	}
+
+	return s.activityRepository.Record(ctx, activity)
+}
+
+// ListActivity returns the team's activity feed, newest first, for team managers
+func (s *TeamActivityService) ListActivity(c *gin.Context, teamID uuid.UUID, eventTypes []string, page, pageSize int) (*TeamActivityPage, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	role, err := s.teamRepository.GetRole(c.Request.Context(), teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(authz.InTeam(userID, role), authz.ManageTeam, entity.ResourceTeam, "only team managers can view the activity feed"); err != nil {
+		return nil, err
+	}
+
+	for _, t := range eventTypes {
+		if _, ok := activityMessages[event.TeamEventType(t)]; !ok {
+			return nil, NewBadRequestError(fmt.Sprintf("unknown activity type %q", t))
+		}
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultActivityPageSize
+	}
+	if pageSize > MaxActivityPageSize {
+		pageSize = MaxActivityPageSize
+	}
+
+	activities, total, err := s.activityRepository.List(c.Request.Context(), teamID, eventTypes, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := s.resolveNames(c.Request.Context(), activities)
+	if err != nil {
+		return nil, err
+	}
+	name := func(id string) string {
+		if id == event.SystemActor {
+			return "System"
+		}
+		if n, ok := names[id]; ok {
+			return n
+		}
+		return "Unknown user"
+	}
+
+	result := &TeamActivityPage{
+		Activities: make([]TeamActivityItem, len(activities)),
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+	}
+	for i, a := range activities {
+		item := TeamActivityItem{
+			ID:         a.ID,
+			Type:       a.EventType,
+			Actor:      ActivityUser{ID: a.ActorID, Name: name(a.ActorID)},
+			OccurredAt: a.OccurredAt,
+		}
+		format, ok := activityMessages[event.TeamEventType(a.EventType)]
+		if !ok {
+			format = "%s updated the team"
+		}
+		args := []any{item.Actor.Name}
+		if a.TargetUserID != nil {
+			item.Target = &ActivityUser{ID: a.TargetUserID.String(), Name: name(a.TargetUserID.String())}
+		}
+		if strings.Count(format, "%s") > 1 {
+			if item.Target != nil {
+				args = append(args, item.Target.Name)
+			} else {
+				args = append(args, "Unknown user")
+			}
+		}
+		item.Message = fmt.Sprintf(format, args...)
+		result.Activities[i] = item
+	}
+
+	return result, nil
+}
+
+// resolveNames looks up the usernames of every actor and target on the page in one user-service call
+func (s *TeamActivityService) resolveNames(ctx context.Context, activities []*entity.TeamActivity) (map[string]string, error) {
+	seen := make(map[uuid.UUID]bool)
+	var ids []uuid.UUID
+	add := func(id uuid.UUID) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, a := range activities {
+		if actor, err := uuid.Parse(a.ActorID); err == nil {
+			add(actor)
+		}
+		if a.TargetUserID != nil {
+			add(*a.TargetUserID)
+		}
+	}
+
+	names := make(map[string]string, len(ids))
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	users, err := s.userRepository.List(ctx, nil, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		names[user.ID.String()] = user.Username
+	}
+	return names, nil
+}
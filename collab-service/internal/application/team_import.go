@@ -0,0 +1,303 @@
+package application
+
+import (
+	"collab-service/internal/domain/authz"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/external/event"
+	"collab-service/internal/infrastructure/logger"
+	"collab-service/internal/interface/http/middleware"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// MaxMemberImportRows giới hạn số dòng trong một lần import roster
+const MaxMemberImportRows = 1000
+
+// MemberImportRow là một dòng trong file import, định danh user bằng email hoặc user ID
+type MemberImportRow struct {
+	Row       int
+	UserID    *uuid.UUID
+	Email     string
+	Role      entity.TeamAccessRole
+	ExpiresAt *time.Time
+}
+
+func (r MemberImportRow) identifier() string {
+	if r.UserID != nil {
+		return r.UserID.String()
+	}
+	return r.Email
+}
+
+// ImportedMember là một user được (hoặc sẽ được, với dry-run) thêm vào team
+type ImportedMember struct {
+	Row    int                   `json:"row"`
+	UserID uuid.UUID             `json:"userId"`
+	Email  string                `json:"email"`
+	Role   entity.TeamAccessRole `json:"role"`
+}
+
+// ImportIssue mô tả một dòng không được áp dụng
+type ImportIssue struct {
+	Row    int    `json:"row"`
+	User   string `json:"user"`
+	Reason string `json:"reason"`
+}
+
+// RoleConflict là user đã có trong team (hoặc lặp lại trong file) với role khác role được yêu cầu
+type RoleConflict struct {
+	Row           int                   `json:"row"`
+	UserID        uuid.UUID             `json:"userId"`
+	Email         string                `json:"email"`
+	CurrentRole   entity.TeamAccessRole `json:"currentRole"`
+	RequestedRole entity.TeamAccessRole `json:"requestedRole"`
+}
+
+// MemberImportReport là kết quả của một lần import, giống nhau cho cả dry-run và import thật
+type MemberImportReport struct {
+	DryRun        bool             `json:"dryRun"`
+	Added         []ImportedMember `json:"added"`
+	Unchanged     []ImportedMember `json:"unchanged"`
+	UnknownUsers  []ImportIssue    `json:"unknownUsers"`
+	Duplicates    []ImportIssue    `json:"duplicates"`
+	RoleConflicts []RoleConflict   `json:"roleConflicts"`
+	Invalid       []ImportIssue    `json:"invalid"`
+}
+
+// ExportedMember là một dòng trong roster được export
+type ExportedMember struct {
+	UserID    uuid.UUID             `json:"userId"`
+	Username  string                `json:"username"`
+	Email     string                `json:"email"`
+	Role      entity.TeamAccessRole `json:"role"`
+	ExpiresAt *time.Time            `json:"expiresAt,omitempty"`
+}
+
+var importableRoles = map[entity.TeamAccessRole]bool{
+	entity.TeamManager: true,
+	entity.TeamMember:  true,
+	entity.TeamGuest:   true,
+}
+
+// ImportMembers adds users listed by email or ID to the team.
+// Unknown users, duplicate rows and role conflicts are reported and skipped; with dryRun nothing is written.
+func (s *TeamService) ImportMembers(c *gin.Context, teamID uuid.UUID, rows []MemberImportRow, dryRun bool) (*MemberImportReport, error) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	if len(rows) == 0 {
+		return nil, NewBadRequestError("import file contains no members")
+	}
+	if len(rows) > MaxMemberImportRows {
+		return nil, NewBadRequestError(fmt.Sprintf("import is limited to %d members", MaxMemberImportRows))
+	}
+
+	team, err := s.teamRepository.GetByID(ctx, teamID)
+	if err != nil || team == nil {
+		return nil, NewNotFoundError(fmt.Sprintf("team %s not found", teamID))
+	}
+	if err := ensureNotArchived(team); err != nil {
+		return nil, err
+	}
+
+	role, err := s.teamRepository.GetRole(ctx, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(authz.InTeam(userID, role), authz.ManageMembers, entity.ResourceTeam, "only team managers can import members"); err != nil {
+		return nil, err
+	}
+
+	report := &MemberImportReport{
+		DryRun:        dryRun,
+		Added:         []ImportedMember{},
+		Unchanged:     []ImportedMember{},
+		UnknownUsers:  []ImportIssue{},
+		Duplicates:    []ImportIssue{},
+		RoleConflicts: []RoleConflict{},
+		Invalid:       []ImportIssue{},
+	}
+
+	// Kiểm tra từng dòng và gom email/ID để resolve theo batch
+	var valid []MemberImportRow
+	var ids []uuid.UUID
+	var emails []string
+	for _, row := range rows {
+		row.Email = strings.ToLower(strings.TrimSpace(row.Email))
+		if row.Role == "" {
+			row.Role = entity.TeamMember
+		}
+		switch {
+		case row.UserID == nil && row.Email == "":
+			report.Invalid = append(report.Invalid, ImportIssue{Row: row.Row, Reason: "missing email or user ID"})
+			continue
+		case !importableRoles[row.Role]:
+			report.Invalid = append(report.Invalid, ImportIssue{Row: row.Row, User: row.identifier(), Reason: fmt.Sprintf("role %q cannot be imported", row.Role)})
+			continue
+		case row.ExpiresAt != nil && !row.ExpiresAt.After(time.Now()):
+			report.Invalid = append(report.Invalid, ImportIssue{Row: row.Row, User: row.identifier(), Reason: "expiresAt must be in the future"})
+			continue
+		}
+
+		if row.UserID != nil {
+			ids = append(ids, *row.UserID)
+		} else {
+			emails = append(emails, row.Email)
+		}
+		valid = append(valid, row)
+	}
+
+	usersByID, usersByEmail, err := s.resolveImportUsers(c, ids, emails)
+	if err != nil {
+		return nil, err
+	}
+
+	currentRoles := make(map[uuid.UUID]entity.TeamAccessRole, len(team.Rosters))
+	for _, roster := range team.Rosters {
+		currentRoles[roster.UserID] = roster.Role
+	}
+
+	seen := make(map[uuid.UUID]MemberImportRow)
+	var toAdd []MemberImportRow
+	var toAddUsers []*entity.User
+	for _, row := range valid {
+		var user *entity.User
+		if row.UserID != nil {
+			user = usersByID[*row.UserID]
+		} else {
+			user = usersByEmail[row.Email]
+		}
+		if user == nil {
+			report.UnknownUsers = append(report.UnknownUsers, ImportIssue{Row: row.Row, User: row.identifier(), Reason: "user not found"})
+			continue
+		}
+
+		if first, ok := seen[user.ID]; ok {
+			if first.Role != row.Role {
+				report.RoleConflicts = append(report.RoleConflicts, RoleConflict{Row: row.Row, UserID: user.ID, Email: user.Email, CurrentRole: first.Role, RequestedRole: row.Role})
+			} else {
+				report.Duplicates = append(report.Duplicates, ImportIssue{Row: row.Row, User: row.identifier(), Reason: fmt.Sprintf("already listed on row %d", first.Row)})
+			}
+			continue
+		}
+		seen[user.ID] = row
+
+		member := ImportedMember{Row: row.Row, UserID: user.ID, Email: user.Email, Role: row.Role}
+		if current, ok := currentRoles[user.ID]; ok {
+			if current == row.Role {
+				report.Unchanged = append(report.Unchanged, member)
+			} else {
+				report.RoleConflicts = append(report.RoleConflicts, RoleConflict{Row: row.Row, UserID: user.ID, Email: user.Email, CurrentRole: current, RequestedRole: row.Role})
+			}
+			continue
+		}
+
+		report.Added = append(report.Added, member)
+		toAdd = append(toAdd, row)
+		toAddUsers = append(toAddUsers, user)
+	}
+
+	if dryRun || len(toAdd) == 0 {
+		return report, nil
+	}
+
+	for i, row := range toAdd {
+		memberID := toAddUsers[i].ID
+		eventType := event.MemberAdded
+		if row.Role == entity.TeamManager {
+			eventType = event.ManagerAdded
+			err = s.teamRepository.AddManager(ctx, teamID, memberID)
+		} else {
+			err = s.teamRepository.AddMembers(ctx, teamID, []uuid.UUID{memberID}, row.Role, row.ExpiresAt)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		go func(eventType event.TeamEventType, memberID uuid.UUID) {
+			if err := s.eventProducer.Produce(event.NewTeamEvent(eventType, teamID.String(), userID.String(), memberID.String())); err != nil {
+				logger.Error("failed to produce event: %v", err)
+			}
+		}(eventType, memberID)
+	}
+
+	return report, nil
+}
+
+// resolveImportUsers looks up users by ID and by email with one user-service call each
+func (s *TeamService) resolveImportUsers(c *gin.Context, ids []uuid.UUID, emails []string) (map[uuid.UUID]*entity.User, map[string]*entity.User, error) {
+	usersByID := make(map[uuid.UUID]*entity.User)
+	usersByEmail := make(map[string]*entity.User)
+
+	if len(ids) > 0 {
+		users, err := s.userRepository.List(c.Request.Context(), nil, ids)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, user := range users {
+			usersByID[user.ID] = user
+		}
+	}
+
+	if len(emails) > 0 {
+		users, err := s.userRepository.ListByEmails(c.Request.Context(), emails)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, user := range users {
+			usersByEmail[strings.ToLower(user.Email)] = user
+		}
+	}
+
+	return usersByID, usersByEmail, nil
+}
+
+// ExportMembers returns the team roster with emails resolved from the user-service
+func (s *TeamService) ExportMembers(c *gin.Context, teamID uuid.UUID) ([]ExportedMember, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	role, err := s.teamRepository.GetRole(c.Request.Context(), teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(authz.InTeam(userID, role), authz.ListMembers, entity.ResourceTeam, "only team members can export the roster"); err != nil {
+		return nil, err
+	}
+
+	team, err := s.teamRepository.GetByID(c.Request.Context(), teamID)
+	if err != nil || team == nil {
+		return nil, NewNotFoundError(fmt.Sprintf("team %s not found", teamID))
+	}
+
+	members := make([]ExportedMember, 0, len(team.Rosters))
+	if len(team.Rosters) == 0 {
+		return members, nil
+	}
+
+	ids := make([]uuid.UUID, len(team.Rosters))
+	for i, roster := range team.Rosters {
+		ids[i] = roster.UserID
+	}
+	users, err := s.userRepository.List(c.Request.Context(), nil, ids)
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[uuid.UUID]*entity.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	for _, roster := range team.Rosters {
+		member := ExportedMember{UserID: roster.UserID, Role: roster.Role, ExpiresAt: roster.ExpiresAt}
+		if user, ok := usersByID[roster.UserID]; ok {
+			member.Username = user.Username
+			member.Email = user.Email
+		}
+		members = append(members, member)
+	}
+
+	return members, nil
+}
@@ -1,11 +1,18 @@
 package application
 
 import (
+	"collab-service/internal/domain/authz"
 	"collab-service/internal/domain/entity"
 	"collab-service/internal/infrastructure/external/event"
 	"collab-service/internal/infrastructure/logger"
 	"collab-service/internal/interface/http/middleware"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -28,13 +35,24 @@ func NewTeamService(repo entity.TeamRepository, userRepo entity.UserRepository)
 	}
 }
 
-// CreateTeam creates a new team
-func (s *TeamService) CreateTeam(c *gin.Context, name string, members []uuid.UUID, manager []uuid.UUID) (*entity.Team, error) {
+// CreateTeam creates a new team, optionally nested under parentID
+func (s *TeamService) CreateTeam(c *gin.Context, name string, members []uuid.UUID, manager []uuid.UUID, parentID *uuid.UUID) (*entity.Team, error) {
 	// Get the user ID from the context
 	userID, _ := middleware.GetUserInfoFromGin(c)
 
+	// Only managers of the parent (directly or through inheritance) may create sub-teams
+	if parentID != nil {
+		if err := s.requireManagerOf(c, *parentID, userID); err != nil {
+			return nil, err
+		}
+		if err := s.requireActive(c, *parentID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create a new team with the current user as creator
 	team := entity.NewTeam(name, []entity.Roster{})
+	team.ParentID = parentID
 
 	// Create a map to track all processed users to avoid duplicates
 	processedUsers := make(map[uuid.UUID]bool)
@@ -94,15 +112,118 @@ func (s *TeamService) CreateTeam(c *gin.Context, name string, members []uuid.UUI
 	return savedTeam, err
 }
 
-func (s *TeamService) GetAllTeamsOfUser(c *gin.Context) ([]*entity.Team, error) {
+// GetAllTeamsOfUser lists the teams of the current user. Archived teams are hidden unless includeArchived is set.
+func (s *TeamService) GetAllTeamsOfUser(c *gin.Context, includeArchived bool) ([]*entity.Team, error) {
 	userID, _ := middleware.GetUserInfoFromGin(c)
-	teams, err := s.teamRepository.GetAllByUserID(c.Request.Context(), userID)
+	teams, err := s.teamRepository.GetAllByUserID(c.Request.Context(), userID, includeArchived)
 	if err != nil {
 		return nil, err
 	}
 	return teams, nil
 }
 
+// GetTeamTree returns the team with its whole sub-team hierarchy linked through Children
+func (s *TeamService) GetTeamTree(c *gin.Context, id uuid.UUID) (*entity.Team, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	role, err := s.teamRepository.GetRole(c.Request.Context(), id, userID)
+	if err != nil {
+		return nil, err
+	}
+	principal := authz.InTeam(userID, role)
+	if err := authorize(principal, authz.View, entity.ResourceTeam, "you are not a member of this team"); err != nil {
+		return nil, err
+	}
+	if err := authorize(principal, authz.ListMembers, entity.ResourceTeam, "guests cannot view the team hierarchy"); err != nil {
+		return nil, err
+	}
+
+	teams, err := s.teamRepository.GetSubtree(c.Request.Context(), id)
+	if err != nil {
+		return nil, NewNotFoundError(fmt.Sprintf("team %s not found", id))
+	}
+
+	return entity.BuildTeamTree(id, teams), nil
+}
+
+// MoveTeam re-parents a team. A nil parentID turns the team into a top-level team.
+func (s *TeamService) MoveTeam(c *gin.Context, teamID uuid.UUID, parentID *uuid.UUID) (*entity.Team, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	if err := s.requireManagerOf(c, teamID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.requireActive(c, teamID); err != nil {
+		return nil, err
+	}
+
+	if parentID != nil {
+		if *parentID == teamID {
+			return nil, NewBadRequestError("a team cannot be its own parent")
+		}
+
+		if err := s.requireManagerOf(c, *parentID, userID); err != nil {
+			return nil, err
+		}
+		if err := s.requireActive(c, *parentID); err != nil {
+			return nil, err
+		}
+
+		// Prevent cycles: the new parent must not live inside the team's own subtree
+		subtree, err := s.teamRepository.GetSubtree(c.Request.Context(), teamID)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range subtree {
+			if t.ID == *parentID {
+				return nil, NewBadRequestError("cannot move a team beneath one of its own sub-teams")
+			}
+		}
+	}
+
+	if err := s.teamRepository.SetParent(c.Request.Context(), teamID, parentID); err != nil {
+		return nil, err
+	}
+
+	return s.teamRepository.GetByID(c.Request.Context(), teamID)
+}
+
+// requireManagerOf checks that the team exists and that userID manages it, directly or via a parent team
+func (s *TeamService) requireManagerOf(c *gin.Context, teamID, userID uuid.UUID) error {
+	exists, err := s.teamRepository.ExistsByID(c.Request.Context(), teamID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return NewNotFoundError(fmt.Sprintf("team %s not found", teamID))
+	}
+
+	role, err := s.teamRepository.GetRole(c.Request.Context(), teamID, userID)
+	if err != nil {
+		return err
+	}
+	return authorize(authz.InTeam(userID, role), authz.ManageTeam, entity.ResourceTeam,
+		fmt.Sprintf("only managers of team %s can change its hierarchy", teamID))
+}
+
+// requireActive rejects changes to archived teams, which are kept read-only until restored
+func (s *TeamService) requireActive(c *gin.Context, teamID uuid.UUID) error {
+	team, err := s.teamRepository.GetByID(c.Request.Context(), teamID)
+	if err != nil {
+		return err
+	}
+	if team == nil {
+		return NewNotFoundError(fmt.Sprintf("team %s not found", teamID))
+	}
+	return ensureNotArchived(team)
+}
+
+func ensureNotArchived(team *entity.Team) error {
+	if team.IsArchived() {
+		return NewConflictError(fmt.Sprintf("team %s is archived and read-only", team.ID))
+	}
+	return nil
+}
+
 func (s *TeamService) GetTeamByID(c *gin.Context, id uuid.UUID) (*entity.Team, error) {
 	team, err := s.teamRepository.GetByID(c.Request.Context(), id)
 	if err != nil {
@@ -114,8 +235,20 @@ func (s *TeamService) GetTeamByID(c *gin.Context, id uuid.UUID) (*entity.Team, e
 	return team, nil
 }
 
-func (s *TeamService) AddMembers(c *gin.Context, teamID uuid.UUID, members []uuid.UUID) (*entity.Team, error) {
+// AddMembers adds users to the team as MEMBER or GUEST.
+// When expiresAt is set the membership is time-bound and gets removed by the sweeper after that time.
+func (s *TeamService) AddMembers(c *gin.Context, teamID uuid.UUID, members []uuid.UUID, role entity.TeamAccessRole, expiresAt *time.Time) (*entity.Team, error) {
 	userID, _ := middleware.GetUserInfoFromGin(c)
+	if role == "" {
+		role = entity.TeamMember
+	}
+	if role != entity.TeamMember && role != entity.TeamGuest {
+		return nil, NewBadRequestError("role must be MEMBER or GUEST")
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, NewBadRequestError("expiresAt must be in the future")
+	}
+
 	team, err := s.teamRepository.GetByID(c.Request.Context(), teamID)
 	if err != nil {
 		return nil, err
@@ -123,6 +256,17 @@ func (s *TeamService) AddMembers(c *gin.Context, teamID uuid.UUID, members []uui
 	if team == nil {
 		return nil, fmt.Errorf("team %s not found", teamID)
 	}
+	if err := ensureNotArchived(team); err != nil {
+		return nil, err
+	}
+
+	callerRole, err := s.teamRepository.GetRole(c.Request.Context(), teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(authz.InTeam(userID, callerRole), authz.ManageMembers, entity.ResourceTeam, "only team managers can add members"); err != nil {
+		return nil, err
+	}
 
 	users, err := s.userRepository.List(c.Request.Context(), nil, members)
 	if err != nil {
@@ -138,7 +282,7 @@ func (s *TeamService) AddMembers(c *gin.Context, teamID uuid.UUID, members []uui
 		userIDs = append(userIDs, user.ID)
 	}
 
-	err = s.teamRepository.AddMembers(c.Request.Context(), team.ID, userIDs)
+	err = s.teamRepository.AddMembers(c.Request.Context(), team.ID, userIDs, role, expiresAt)
 	if err != nil {
 		return nil, err
 	}
@@ -166,8 +310,8 @@ func (s *TeamService) AddManager(c *gin.Context, teamID uuid.UUID, managerID uui
 		return nil, err
 	}
 
-	if !role.IsHigherOrEqualTo(entity.TeamManager) {
-		return nil, NewForbiddenError("only team managers can add other managers")
+	if err := authorize(authz.InTeam(userId, role), authz.ManageMembers, entity.ResourceTeam, "only team managers can add other managers"); err != nil {
+		return nil, err
 	}
 
 	team, err := s.teamRepository.GetByID(c.Request.Context(), teamID)
@@ -177,6 +321,9 @@ func (s *TeamService) AddManager(c *gin.Context, teamID uuid.UUID, managerID uui
 	if team == nil {
 		return nil, NewNotFoundError(fmt.Sprintf("team %s not found", teamID))
 	}
+	if err := ensureNotArchived(team); err != nil {
+		return nil, err
+	}
 
 	user, err := s.userRepository.GetByID(c.Request.Context(), managerID)
 	if err != nil {
@@ -206,11 +353,13 @@ func (s *TeamService) RemoveMember(c *gin.Context, teamID uuid.UUID, memberID uu
 		return err
 	}
 
-	if role == entity.TeamMember {
-		// If the user is a regular member, they can only remove themselves
-		if userId != memberID {
-			return NewForbiddenError("regular members can only remove themselves")
+	// Member và guest chỉ được tự rời team; xoá người khác cần quyền manager
+	if userId == memberID {
+		if err := authorize(authz.InTeam(userId, role), authz.Leave, entity.ResourceTeam, "you are not a member of this team"); err != nil {
+			return err
 		}
+	} else if err := authorize(authz.InTeam(userId, role), authz.ManageMembers, entity.ResourceTeam, "regular members can only remove themselves"); err != nil {
+		return err
 	}
 
 	team, err := s.teamRepository.GetByID(c.Request.Context(), teamID)
@@ -220,6 +369,9 @@ func (s *TeamService) RemoveMember(c *gin.Context, teamID uuid.UUID, memberID uu
 	if team == nil {
 		return NewNotFoundError(fmt.Sprintf("team %s not found", teamID))
 	}
+	if err := ensureNotArchived(team); err != nil {
+		return err
+	}
 
 	if err := s.teamRepository.RemoveMember(c.Request.Context(), teamID, memberID); err != nil {
 		return err
@@ -236,8 +388,8 @@ func (s *TeamService) RemoveManager(c *gin.Context, teamID uuid.UUID, managerID
 		return err
 	}
 
-	if role != entity.TeamOwner {
-		return NewForbiddenError("only team managers can remove other managers")
+	if err := authorize(authz.InTeam(userId, role), authz.RemoveManager, entity.ResourceTeam, "only team owners can remove managers"); err != nil {
+		return err
 	}
 
 	team, err := s.teamRepository.GetByID(c.Request.Context(), teamID)
@@ -247,6 +399,9 @@ func (s *TeamService) RemoveManager(c *gin.Context, teamID uuid.UUID, managerID
 	if team == nil {
 		return NewNotFoundError(fmt.Sprintf("team %s not found", teamID))
 	}
+	if err := ensureNotArchived(team); err != nil {
+		return err
+	}
 
 	targetRole, err := s.teamRepository.GetRole(c.Request.Context(), teamID, managerID)
 	switch targetRole {
@@ -270,14 +425,25 @@ func (s *TeamService) RemoveManager(c *gin.Context, teamID uuid.UUID, managerID
 }
 
 func (s *TeamService) UpdateTeam(c *gin.Context, team *entity.Team) (*entity.Team, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	role, err := s.teamRepository.GetRole(c.Request.Context(), team.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(authz.InTeam(userID, role), authz.ManageTeam, entity.ResourceTeam, "only team managers can update the team"); err != nil {
+		return nil, err
+	}
 
-	team, err := s.teamRepository.GetByID(c.Request.Context(), team.ID)
+	team, err = s.teamRepository.GetByID(c.Request.Context(), team.ID)
 	if err != nil {
 		return nil, err
 	}
 	if team == nil {
 		return nil, nil
 	}
+	if err := ensureNotArchived(team); err != nil {
+		return nil, err
+	}
 
 	// Update the team in the repository
 	updatedTeam, err := s.teamRepository.Update(c.Request.Context(), team)
@@ -290,7 +456,9 @@ func (s *TeamService) UpdateTeam(c *gin.Context, team *entity.Team) (*entity.Tea
 	return updatedTeam, nil
 }
 
-func (s *TeamService) DeleteTeam(c *gin.Context, id uuid.UUID) error {
+// ArchiveTeam archives a team instead of deleting it. The team and its rosters are kept
+// read-only until the owner restores it or the retention job purges it.
+func (s *TeamService) ArchiveTeam(c *gin.Context, id uuid.UUID) error {
 
 	userId, _ := middleware.GetUserInfoFromGin(c)
 	role, err := s.teamRepository.GetRole(c.Request.Context(), id, userId)
@@ -298,8 +466,8 @@ func (s *TeamService) DeleteTeam(c *gin.Context, id uuid.UUID) error {
 		return err
 	}
 
-	if role != entity.TeamOwner {
-		return NewForbiddenError("only team owners can delete a team")
+	if err := authorize(authz.InTeam(userId, role), authz.Archive, entity.ResourceTeam, "only team owners can archive a team"); err != nil {
+		return err
 	}
 
 	team, err := s.teamRepository.GetByID(c.Request.Context(), id)
@@ -310,10 +478,224 @@ func (s *TeamService) DeleteTeam(c *gin.Context, id uuid.UUID) error {
 	if team == nil {
 		return NewNotFoundError(fmt.Sprintf("team %s not found", id))
 	}
+	if err := ensureNotArchived(team); err != nil {
+		return err
+	}
 
-	if err := s.teamRepository.Delete(c.Request.Context(), id); err != nil {
+	if err := s.teamRepository.Archive(c.Request.Context(), id, userId); err != nil {
 		return err
 	}
 
+	go s.eventProducer.Produce(event.NewTeamMembersEvent(event.TeamArchived, team, userId.String()))
 	return nil
 }
+
+// RestoreTeam brings an archived team back to the active state
+func (s *TeamService) RestoreTeam(c *gin.Context, id uuid.UUID) (*entity.Team, error) {
+	userId, _ := middleware.GetUserInfoFromGin(c)
+	role, err := s.teamRepository.GetRole(c.Request.Context(), id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorize(authz.InTeam(userId, role), authz.Archive, entity.ResourceTeam, "only team owners can restore a team"); err != nil {
+		return nil, err
+	}
+
+	team, err := s.teamRepository.GetByID(c.Request.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, NewNotFoundError(fmt.Sprintf("team %s not found", id))
+	}
+	if !team.IsArchived() {
+		return nil, NewConflictError(fmt.Sprintf("team %s is not archived", id))
+	}
+
+	if err := s.teamRepository.Restore(c.Request.Context(), id); err != nil {
+		return nil, err
+	}
+
+	go s.eventProducer.Produce(event.NewTeamMembersEvent(event.TeamRestored, team, userId.String()))
+	return s.teamRepository.GetByID(c.Request.Context(), id)
+}
+
+// PurgeArchivedTeams permanently deletes teams that have been archived for longer than retention.
+// It is run by the background scheduler and returns the number of purged teams.
+func (s *TeamService) PurgeArchivedTeams(ctx context.Context, retention time.Duration) (int, error) {
+	ids, err := s.teamRepository.ListArchivedBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range ids {
+		if err := s.teamRepository.Delete(ctx, id); err != nil {
+			logger.Error("failed to purge archived team", "teamId", id.String(), "error", err.Error())
+			continue
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// RemoveExpiredMemberships removes time-bound memberships whose expiry has passed.
+// It is run by the background scheduler and returns the number of removed memberships.
+func (s *TeamService) RemoveExpiredMemberships(ctx context.Context) (int, error) {
+	now := time.Now()
+	rosters, err := s.teamRepository.ListExpiredRosters(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, roster := range rosters {
+		deleted, err := s.teamRepository.RemoveExpiredMember(ctx, roster.TeamID, roster.UserID, now)
+		if err != nil {
+			logger.Error("failed to remove expired membership", "teamId", roster.TeamID.String(), "userId", roster.UserID.String(), "error", err.Error())
+			continue
+		}
+		// Membership was extended or removed since it was listed
+		if !deleted {
+			continue
+		}
+		removed++
+
+		go s.eventProducer.Produce(event.NewTeamEvent(event.MemberRemoved, roster.TeamID.String(), event.SystemActor, roster.UserID.String()))
+	}
+
+	return removed, nil
+}
+
+// checkTeamShareTarget validates that a folder or note can be shared with the team at the given level
+func checkTeamShareTarget(ctx context.Context, teamRepo entity.TeamRepository, teamID uuid.UUID, accessLevel entity.AccessLevel) error {
+	if !accessLevel.IsTeamShareable() {
+		return NewBadRequestError("teams can only be granted READ or WRITE access")
+	}
+
+	team, err := teamRepo.GetByID(ctx, teamID)
+	if err != nil || team == nil {
+		return NewNotFoundError(fmt.Sprintf("team %s not found", teamID))
+	}
+	return ensureNotArchived(team)
+}
+
+const (
+	DefaultMemberPageSize = 20
+	MaxMemberPageSize     = 100
+)
+
+// TeamMember là một thành viên của team kèm thông tin user
+type TeamMember struct {
+	UserID    uuid.UUID
+	Username  string
+	Email     string
+	AvatarURL string
+	Role      entity.TeamAccessRole
+	JoinedAt  time.Time
+	ExpiresAt *time.Time
+}
+
+// TeamMemberPage là một trang trong danh sách member
+type TeamMemberPage struct {
+	Members  []TeamMember
+	Page     int
+	PageSize int
+	Total    int
+}
+
+// ListMembers returns a page of the team roster, optionally filtered by role, ordered by role then join date.
+// User details are fetched from the user-service in one batch for the requested page only.
+func (s *TeamService) ListMembers(c *gin.Context, teamID uuid.UUID, role entity.TeamAccessRole, page, pageSize int) (*TeamMemberPage, error) {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+	callerRole, err := s.teamRepository.GetRole(c.Request.Context(), teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(authz.InTeam(userID, callerRole), authz.ListMembers, entity.ResourceTeam, "only team members can list members"); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultMemberPageSize
+	}
+	if pageSize > MaxMemberPageSize {
+		pageSize = MaxMemberPageSize
+	}
+
+	team, err := s.teamRepository.GetByID(c.Request.Context(), teamID)
+	if err != nil || team == nil {
+		return nil, NewNotFoundError(fmt.Sprintf("team %s not found", teamID))
+	}
+
+	var rosters []entity.Roster
+	for _, roster := range team.Rosters {
+		if role == "" || roster.Role == role {
+			rosters = append(rosters, roster)
+		}
+	}
+	sort.SliceStable(rosters, func(i, j int) bool {
+		if rosters[i].Role != rosters[j].Role {
+			return rosters[i].Role.IsHigherOrEqualTo(rosters[j].Role)
+		}
+		return rosters[i].CreatedAt.Before(rosters[j].CreatedAt)
+	})
+
+	result := &TeamMemberPage{
+		Members:  []TeamMember{},
+		Page:     page,
+		PageSize: pageSize,
+		Total:    len(rosters),
+	}
+
+	start := (page - 1) * pageSize
+	if start >= len(rosters) {
+		return result, nil
+	}
+	end := start + pageSize
+	if end > len(rosters) {
+		end = len(rosters)
+	}
+	rosters = rosters[start:end]
+
+	ids := make([]uuid.UUID, len(rosters))
+	for i, roster := range rosters {
+		ids[i] = roster.UserID
+	}
+	users, err := s.userRepository.List(c.Request.Context(), nil, ids)
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[uuid.UUID]*entity.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	for _, roster := range rosters {
+		member := TeamMember{
+			UserID:    roster.UserID,
+			Role:      roster.Role,
+			JoinedAt:  roster.CreatedAt,
+			ExpiresAt: roster.ExpiresAt,
+		}
+		if user, ok := usersByID[roster.UserID]; ok {
+			member.Username = user.Username
+			member.Email = user.Email
+			member.AvatarURL = avatarURL(user.Email)
+		}
+		result.Members = append(result.Members, member)
+	}
+
+	return result, nil
+}
+
+// avatarURL trả về ảnh đại diện Gravatar theo email; user-service chưa lưu avatar riêng
+func avatarURL(email string) string {
+	hash := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return "https://www.gravatar.com/avatar/" + hex.EncodeToString(hash[:]) + "?d=identicon"
+}
@@ -0,0 +1,237 @@
+package application
+
+import (
+	"collab-service/internal/domain/authz"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/external/event"
+	"collab-service/internal/infrastructure/logger"
+	"collab-service/internal/interface/http/middleware"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TrashItem là một folder hoặc note trong thùng rác của user.
+// Folder con và note bị xoá cùng folder không được liệt kê riêng, chúng được khôi phục theo folder.
+type TrashItem struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      event.AssetType `json:"type"`
+	Name      string          `json:"name"`
+	ParentID  *uuid.UUID      `json:"parentId,omitempty"`
+	DeletedAt time.Time       `json:"deletedAt"`
+	DeletedBy *uuid.UUID      `json:"deletedBy,omitempty"`
+	// ExpiresAt là thời điểm mục bị xoá vĩnh viễn
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type TrashService struct {
+	noteRepo       entity.NoteRepository
+	folderRepo     entity.FolderRepository
+	attachmentRepo entity.AttachmentRepository
+	blobStore      entity.BlobStore
+	retention      time.Duration
+	eventProducer  *event.AssetChangeProducer
+}
+
+func NewTrashService(noteRepo entity.NoteRepository, folderRepo entity.FolderRepository, attachmentRepo entity.AttachmentRepository, blobStore entity.BlobStore, retention time.Duration) *TrashService {
+	return &TrashService{
+		noteRepo:       noteRepo,
+		folderRepo:     folderRepo,
+		attachmentRepo: attachmentRepo,
+		blobStore:      blobStore,
+		retention:      retention,
+		eventProducer:  event.GetAssetChangeProducer(),
+	}
+}
+
+// List trả về thùng rác của user hiện tại: những gì user đã xoá hoặc user là owner, mới xoá trước
+func (s *TrashService) List(c *gin.Context) ([]*TrashItem, error) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	folders, err := s.folderRepo.ListTrash(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.noteRepo.ListTrash(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*TrashItem, 0, len(folders)+len(notes))
+	for _, folder := range folders {
+		items = append(items, &TrashItem{
+			ID:        folder.ID,
+			Type:      event.Folder,
+			Name:      folder.Name,
+			ParentID:  folder.ParentID,
+			DeletedAt: *folder.DeletedAt,
+			DeletedBy: folder.DeletedBy,
+			ExpiresAt: folder.DeletedAt.Add(s.retention),
+		})
+	}
+	for _, note := range notes {
+		folderID := note.FolderID
+		items = append(items, &TrashItem{
+			ID:        note.ID,
+			Type:      event.Note,
+			Name:      note.Title,
+			ParentID:  &folderID,
+			DeletedAt: *note.DeletedAt,
+			DeletedBy: note.DeletedBy,
+			ExpiresAt: note.DeletedAt.Add(s.retention),
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+	return items, nil
+}
+
+// checkRestore chỉ cho người đã xoá hoặc owner khôi phục, và chỉ trong thời hạn lưu giữ.
+// Mục trong thùng rác không còn quyền hiệu lực nên principal được dựng từ owner và người đã xoá.
+func (s *TrashService) checkRestore(resource entity.ResourceType, deletedAt *time.Time, deletedBy *uuid.UUID, ownerID, userID uuid.UUID) error {
+	principal := authz.Principal{UserID: userID, Actor: deletedBy != nil && *deletedBy == userID}
+	if ownerID == userID {
+		principal.AccessLevel = entity.AccessLevelOwner
+	}
+	if err := authorize(principal, authz.Restore, resource, "Only the owner or the user who deleted it can restore this item"); err != nil {
+		return err
+	}
+	if time.Since(*deletedAt) > s.retention {
+		return NewNotFoundError("The item has expired and is being permanently deleted")
+	}
+	return nil
+}
+
+// RestoreNote đưa note ra khỏi thùng rác cùng toàn bộ share của nó.
+// Folder chứa note phải còn tồn tại; nếu folder cũng trong thùng rác thì cần khôi phục folder trước.
+func (s *TrashService) RestoreNote(c *gin.Context, noteID uuid.UUID) (*entity.Note, error) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	note, err := s.noteRepo.GetTrashed(ctx, noteID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError(fmt.Sprintf("note %s is not in the trash", noteID))
+		}
+		return nil, err
+	}
+
+	ownerID, err := s.noteRepo.GetOwner(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkRestore(entity.ResourceNote, note.DeletedAt, note.DeletedBy, ownerID, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.folderRepo.GetByID(ctx, note.FolderID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewConflictError("The folder containing this note is in the trash, restore the folder first")
+		}
+		return nil, err
+	}
+
+	if err := s.noteRepo.Restore(ctx, noteID); err != nil {
+		return nil, err
+	}
+
+	go s.eventProducer.Produce(event.NewAssetEvent(event.NoteRestored, event.Note, noteID.String(), ownerID.String(), userID.String(), time.Now().String(), entity.AccessLevelOwner))
+
+	return s.noteRepo.GetByID(ctx, noteID)
+}
+
+// RestoreFolder đưa folder ra khỏi thùng rác cùng những folder con, note và share bị xoá cùng lúc với nó.
+// Folder cha phải còn tồn tại; nếu folder cha cũng trong thùng rác thì cần khôi phục folder cha trước.
+func (s *TrashService) RestoreFolder(c *gin.Context, folderID uuid.UUID) (*entity.Folder, error) {
+	ctx := c.Request.Context()
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	folder, err := s.folderRepo.GetTrashed(ctx, folderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError(fmt.Sprintf("folder %s is not in the trash", folderID))
+		}
+		return nil, err
+	}
+
+	ownerID, err := s.folderRepo.GetOwner(ctx, folderID)
+	if err != nil {
+		ownerID = uuid.Nil
+	}
+	if err := s.checkRestore(entity.ResourceFolder, folder.DeletedAt, folder.DeletedBy, ownerID, userID); err != nil {
+		return nil, err
+	}
+
+	if folder.ParentID != nil {
+		if _, err := s.folderRepo.GetByID(ctx, *folder.ParentID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, NewConflictError("The parent folder is in the trash, restore the parent folder first")
+			}
+			return nil, err
+		}
+	}
+
+	if err := s.folderRepo.Restore(ctx, folderID); err != nil {
+		return nil, err
+	}
+
+	go s.eventProducer.Produce(event.NewAssetEvent(event.FolderRestored, event.Folder, folderID.String(), ownerID.String(), userID.String(), time.Now().String(), entity.AccessLevelOwner))
+
+	return s.folderRepo.GetByID(ctx, folderID)
+}
+
+// PurgeExpired xoá vĩnh viễn các folder và note đã nằm trong thùng rác lâu hơn thời hạn lưu giữ.
+// It is run by the background scheduler and returns the number of purged items.
+func (s *TrashService) PurgeExpired(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-s.retention)
+	purged := 0
+
+	// Folder trước: note bên trong bị xoá theo folder
+	folderIDs, err := s.folderRepo.ListTrashedBefore(ctx, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range folderIDs {
+		// Lấy key blob trước khi metadata file đính kèm bị xoá cùng folder
+		keys, err := s.attachmentRepo.StorageKeysInFolder(ctx, id)
+		if err != nil {
+			logger.Error("failed to list attachments of trashed folder", "folderId", id.String(), "error", err.Error())
+			continue
+		}
+		if err := s.folderRepo.Delete(ctx, id); err != nil {
+			logger.Error("failed to purge trashed folder", "folderId", id.String(), "error", err.Error())
+			continue
+		}
+		deleteBlobs(ctx, s.blobStore, keys)
+		purged++
+	}
+
+	noteIDs, err := s.noteRepo.ListTrashedBefore(ctx, cutoff)
+	if err != nil {
+		return purged, err
+	}
+	for _, id := range noteIDs {
+		keys, err := s.attachmentRepo.StorageKeysOfNote(ctx, id)
+		if err != nil {
+			logger.Error("failed to list attachments of trashed note", "noteId", id.String(), "error", err.Error())
+			continue
+		}
+		if err := s.noteRepo.Delete(ctx, id); err != nil {
+			logger.Error("failed to purge trashed note", "noteId", id.String(), "error", err.Error())
+			continue
+		}
+		deleteBlobs(ctx, s.blobStore, keys)
+		purged++
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,295 @@
+package application
+
+import (
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/external/event"
+	"collab-service/internal/infrastructure/logger"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	webhookDispatchBatch       = 50
+	webhookDispatchConcurrency = 8
+	webhookBaseBackoff         = 30 * time.Second
+	webhookMaxBackoff          = 6 * time.Hour
+)
+
+// WebhookPayload là body JSON được POST tới webhook; Data là event gốc trên stream
+type WebhookPayload struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	TeamID    uuid.UUID       `json:"teamId"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// WebhookDispatcher nhận event từ asset.changes và team.activity, tạo delivery cho các webhook khớp,
+// rồi gửi delivery tới hạn với chữ ký HMAC-SHA256, retry theo exponential backoff.
+// Webhook lỗi liên tiếp disableAfter lần sẽ bị tắt.
+type WebhookDispatcher struct {
+	webhookRepo  entity.WebhookRepository
+	sender       entity.WebhookSender
+	timeout      time.Duration
+	maxAttempts  int
+	disableAfter int
+}
+
+func NewWebhookDispatcher(webhookRepo entity.WebhookRepository, sender entity.WebhookSender, timeout time.Duration, maxAttempts, disableAfter int) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhookRepo:  webhookRepo,
+		sender:       sender,
+		timeout:      timeout,
+		maxAttempts:  maxAttempts,
+		disableAfter: disableAfter,
+	}
+}
+
+// HandleAssetEvent gửi event tới webhook của các team truy cập được asset, và của team được share/unshare
+func (d *WebhookDispatcher) HandleAssetEvent(ctx context.Context, e *event.AssetEvent, raw []byte) error {
+	var teamIDs []uuid.UUID
+	if assetID, err := uuid.Parse(e.AssetId); err == nil {
+		teamIDs, err = d.webhookRepo.TeamIDsForAsset(ctx, string(e.AssetType), assetID)
+		// Asset đã bị xoá vĩnh viễn thì không còn team nào truy cập
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+	if teamID, err := uuid.Parse(e.TeamId); err == nil && !containsUUID(teamIDs, teamID) {
+		teamIDs = append(teamIDs, teamID)
+	}
+
+	eventID := e.EventId
+	if eventID == "" {
+		eventID = contentEventID(raw)
+	}
+	return d.enqueue(ctx, teamIDs, eventID, string(e.EventType), raw)
+}
+
+// HandleTeamEvent gửi event tới webhook của team
+func (d *WebhookDispatcher) HandleTeamEvent(ctx context.Context, e *event.TeamEvent) error {
+	teamID, err := uuid.Parse(e.TeamID)
+	if err != nil {
+		return nil
+	}
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	eventID := e.EventID
+	if eventID == "" {
+		eventID = contentEventID(raw)
+	}
+	return d.enqueue(ctx, []uuid.UUID{teamID}, eventID, string(e.EventType), raw)
+}
+
+func (d *WebhookDispatcher) enqueue(ctx context.Context, teamIDs []uuid.UUID, eventID, eventType string, raw []byte) error {
+	webhooks, err := d.webhookRepo.ListActiveByTeams(ctx, teamIDs)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := make([]*entity.WebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		if !webhook.Accepts(eventType) {
+			continue
+		}
+		payload, err := json.Marshal(&WebhookPayload{
+			ID:        eventID,
+			Type:      eventType,
+			TeamID:    webhook.TeamID,
+			CreatedAt: now.UTC(),
+			Data:      raw,
+		})
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, &entity.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       eventID,
+			EventType:     eventType,
+			Payload:       string(payload),
+			Status:        entity.WebhookDeliveryPending,
+			NextAttemptAt: &now,
+		})
+	}
+	return d.webhookRepo.EnqueueDeliveries(ctx, deliveries)
+}
+
+// DispatchDue gửi các delivery tới hạn, trả về số delivery đã thử gửi.
+// Delivery được giữ chỗ (lease) trước khi gửi nên nhiều instance có thể chạy cùng lúc.
+func (d *WebhookDispatcher) DispatchDue(ctx context.Context) (int, error) {
+	deliveries, err := d.webhookRepo.ClaimDueDeliveries(ctx, time.Now(), webhookDispatchBatch, 2*d.timeout+time.Minute)
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, webhookDispatchConcurrency)
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(delivery *entity.WebhookDelivery) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := d.deliver(ctx, delivery); err != nil {
+				logger.Error("failed to record webhook delivery", "deliveryId", delivery.ID.String(), "error", err.Error())
+			}
+		}(delivery)
+	}
+	wg.Wait()
+	return len(deliveries), nil
+}
+
+// PurgeDeliveries xoá delivery log đã xong cũ hơn retention
+func (d *WebhookDispatcher) PurgeDeliveries(ctx context.Context, retention time.Duration) (int64, error) {
+	return d.webhookRepo.DeleteDeliveriesBefore(ctx, time.Now().Add(-retention))
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	webhook, err := d.webhookRepo.GetByID(ctx, delivery.WebhookID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil // webhook vừa bị xoá cùng các delivery của nó
+	}
+	if err != nil {
+		return err
+	}
+	if !webhook.Active {
+		d.finish(delivery, entity.WebhookDeliveryFailed, nil, "webhook is disabled")
+		return d.webhookRepo.SaveDelivery(ctx, delivery)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	headers := map[string]string{
+		"Content-Type":        "application/json",
+		"User-Agent":          "collab-service-webhooks/1.0",
+		"X-Webhook-Id":        delivery.ID.String(),
+		"X-Webhook-Event":     delivery.EventType,
+		"X-Webhook-Timestamp": timestamp,
+		"X-Webhook-Signature": "sha256=" + SignWebhookPayload(webhook.Secret, timestamp, []byte(delivery.Payload)),
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, d.timeout)
+	status, body, sendErr := d.sender.Send(sendCtx, webhook.URL, headers, []byte(delivery.Payload))
+	cancel()
+
+	delivery.Attempts++
+	var responseStatus *int
+	if status != 0 {
+		responseStatus = &status
+	}
+
+	if sendErr == nil && status >= 200 && status < 300 {
+		d.finish(delivery, entity.WebhookDeliverySucceeded, responseStatus, "")
+		if _, err := d.webhookRepo.RecordResult(ctx, webhook.ID, true); err != nil {
+			return err
+		}
+		return d.webhookRepo.SaveDelivery(ctx, delivery)
+	}
+
+	lastError := describeWebhookFailure(status, body, sendErr)
+	failures, err := d.webhookRepo.RecordResult(ctx, webhook.ID, false)
+	if err != nil {
+		return err
+	}
+
+	// 410 Gone: receiver báo endpoint không còn dùng nữa
+	disableReason := ""
+	switch {
+	case status == 410:
+		disableReason = "endpoint responded 410 Gone"
+	case d.disableAfter > 0 && failures >= d.disableAfter:
+		disableReason = fmt.Sprintf("disabled after %d consecutive failed deliveries", failures)
+	}
+
+	if disableReason != "" {
+		d.finish(delivery, entity.WebhookDeliveryFailed, responseStatus, lastError)
+		if err := d.webhookRepo.SaveDelivery(ctx, delivery); err != nil {
+			return err
+		}
+		logger.Warn("Disabling webhook", "webhookId", webhook.ID.String(), "teamId", webhook.TeamID.String(), "reason", disableReason)
+		if err := d.webhookRepo.Disable(ctx, webhook.ID, disableReason, time.Now()); err != nil {
+			return err
+		}
+		return d.webhookRepo.FailPendingDeliveries(ctx, webhook.ID, "webhook disabled: "+disableReason)
+	}
+
+	if delivery.Attempts >= d.maxAttempts {
+		d.finish(delivery, entity.WebhookDeliveryFailed, responseStatus, lastError)
+	} else {
+		next := time.Now().Add(webhookBackoff(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		delivery.ResponseStatus = responseStatus
+		delivery.LastError = lastError
+	}
+	return d.webhookRepo.SaveDelivery(ctx, delivery)
+}
+
+func (d *WebhookDispatcher) finish(delivery *entity.WebhookDelivery, status entity.WebhookDeliveryStatus, responseStatus *int, lastError string) {
+	now := time.Now()
+	delivery.Status = status
+	delivery.NextAttemptAt = nil
+	delivery.ResponseStatus = responseStatus
+	delivery.LastError = lastError
+	if status == entity.WebhookDeliverySucceeded {
+		delivery.DeliveredAt = &now
+	}
+}
+
+// SignWebhookPayload trả về HMAC-SHA256 (hex) của "timestamp.body" với secret của webhook.
+// Receiver tính lại chữ ký từ header X-Webhook-Timestamp và body để xác thực request.
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff là thời gian chờ trước lần thử tiếp theo: 30s, 1m, 2m... tối đa 6h, cộng thêm tới 10% ngẫu nhiên
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookMaxBackoff
+	if attempts < 20 {
+		backoff = min(webhookBaseBackoff<<(attempts-1), webhookMaxBackoff)
+	}
+	return backoff + rand.N(backoff/10+1)
+}
+
+func describeWebhookFailure(status int, body string, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if body == "" {
+		return fmt.Sprintf("HTTP %d", status)
+	}
+	return fmt.Sprintf("HTTP %d: %s", status, body)
+}
+
+// contentEventID sinh ID ổn định cho event cũ không có eventId, để đọc lại từ Kafka vẫn không gửi trùng
+func contentEventID(raw []byte) string {
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:16])
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, existing := range ids {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
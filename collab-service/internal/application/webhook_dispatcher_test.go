@@ -0,0 +1,166 @@
+package application
+
+import (
+	"collab-service/internal/domain/entity"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	// Tính độc lập: HMAC-SHA256(key="whsec_test", msg=`1700000000.{"id":"evt_1"}`)
+	signature := SignWebhookPayload("whsec_test", "1700000000", []byte(`{"id":"evt_1"}`))
+	assert.Equal(t, "c89214b5b5da833daed6f0b8c5bb6bd58cea9022bd80ccc78230f3942d632925", signature)
+
+	assert.NotEqual(t, signature, SignWebhookPayload("whsec_other", "1700000000", []byte(`{"id":"evt_1"}`)))
+	assert.NotEqual(t, signature, SignWebhookPayload("whsec_test", "1700000001", []byte(`{"id":"evt_1"}`)))
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		base     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{10, 4*time.Hour + 16*time.Minute},
+		{11, 6 * time.Hour},
+		{19, 6 * time.Hour},
+		{20, 6 * time.Hour},
+		{100, 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 200; i++ {
+			backoff := webhookBackoff(tt.attempts)
+			assert.GreaterOrEqual(t, backoff, tt.base, "attempt %d", tt.attempts)
+			assert.LessOrEqual(t, backoff, tt.base+tt.base/10, "attempt %d: jitter above 10%%", tt.attempts)
+		}
+	}
+}
+
+type fakeWebhookSender struct {
+	status  int
+	headers map[string]string
+	body    []byte
+}
+
+func (s *fakeWebhookSender) Send(_ context.Context, _ string, headers map[string]string, body []byte) (int, string, error) {
+	s.headers, s.body = headers, body
+	return s.status, "", nil
+}
+
+// fakeWebhookRepository chỉ cài các method deliver dùng tới
+type fakeWebhookRepository struct {
+	entity.WebhookRepository
+
+	webhook        *entity.Webhook
+	failures       int
+	saved          *entity.WebhookDelivery
+	disabledReason string
+	failedPending  bool
+}
+
+func (r *fakeWebhookRepository) GetByID(context.Context, uuid.UUID) (*entity.Webhook, error) {
+	return r.webhook, nil
+}
+
+func (r *fakeWebhookRepository) RecordResult(_ context.Context, _ uuid.UUID, succeeded bool) (int, error) {
+	if succeeded {
+		r.failures = 0
+	} else {
+		r.failures++
+	}
+	return r.failures, nil
+}
+
+func (r *fakeWebhookRepository) SaveDelivery(_ context.Context, delivery *entity.WebhookDelivery) error {
+	r.saved = delivery
+	return nil
+}
+
+func (r *fakeWebhookRepository) Disable(_ context.Context, _ uuid.UUID, reason string, _ time.Time) error {
+	r.disabledReason = reason
+	return nil
+}
+
+func (r *fakeWebhookRepository) FailPendingDeliveries(context.Context, uuid.UUID, string) error {
+	r.failedPending = true
+	return nil
+}
+
+func newTestDelivery(webhookID uuid.UUID) *entity.WebhookDelivery {
+	now := time.Now()
+	return &entity.WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     webhookID,
+		EventID:       "evt_1",
+		EventType:     "NOTE_CREATED",
+		Payload:       `{"id":"evt_1"}`,
+		Status:        entity.WebhookDeliveryPending,
+		NextAttemptAt: &now,
+	}
+}
+
+func TestWebhookDeliver(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		priorFailures  int
+		wantStatus     entity.WebhookDeliveryStatus
+		disabledReason string
+	}{
+		{name: "success", status: 204, priorFailures: 2, wantStatus: entity.WebhookDeliverySucceeded},
+		{name: "failure is retried", status: 500, wantStatus: entity.WebhookDeliveryPending},
+		{name: "410 disables the webhook", status: 410, wantStatus: entity.WebhookDeliveryFailed,
+			disabledReason: "endpoint responded 410 Gone"},
+		{name: "consecutive failures disable the webhook", status: 500, priorFailures: 2, wantStatus: entity.WebhookDeliveryFailed,
+			disabledReason: "disabled after 3 consecutive failed deliveries"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := &entity.Webhook{ID: uuid.New(), TeamID: uuid.New(), URL: "https://example.com/hook", Secret: "whsec_test", Active: true}
+			repo := &fakeWebhookRepository{webhook: webhook, failures: tt.priorFailures}
+			sender := &fakeWebhookSender{status: tt.status}
+			dispatcher := NewWebhookDispatcher(repo, sender, time.Second, 5, 3)
+
+			delivery := newTestDelivery(webhook.ID)
+			require.NoError(t, dispatcher.deliver(context.Background(), delivery))
+
+			assert.Equal(t, delivery, repo.saved)
+			assert.Equal(t, 1, delivery.Attempts)
+			assert.Equal(t, tt.wantStatus, delivery.Status)
+			assert.Equal(t, tt.disabledReason, repo.disabledReason)
+			assert.Equal(t, tt.disabledReason != "", repo.failedPending)
+			if tt.wantStatus == entity.WebhookDeliveryPending {
+				require.NotNil(t, delivery.NextAttemptAt)
+				assert.True(t, delivery.NextAttemptAt.After(time.Now().Add(29*time.Second)))
+			} else {
+				assert.Nil(t, delivery.NextAttemptAt)
+			}
+
+			timestamp := sender.headers["X-Webhook-Timestamp"]
+			assert.Equal(t, "sha256="+SignWebhookPayload(webhook.Secret, timestamp, sender.body), sender.headers["X-Webhook-Signature"])
+		})
+	}
+}
+
+func TestWebhookDeliverToDisabledWebhook(t *testing.T) {
+	webhook := &entity.Webhook{ID: uuid.New(), Active: false}
+	repo := &fakeWebhookRepository{webhook: webhook}
+	sender := &fakeWebhookSender{status: 200}
+	dispatcher := NewWebhookDispatcher(repo, sender, time.Second, 5, 3)
+
+	delivery := newTestDelivery(webhook.ID)
+	require.NoError(t, dispatcher.deliver(context.Background(), delivery))
+
+	assert.Nil(t, sender.headers, "disabled webhook must not be called")
+	assert.Equal(t, entity.WebhookDeliveryFailed, delivery.Status)
+	assert.Equal(t, "webhook is disabled", delivery.LastError)
+}
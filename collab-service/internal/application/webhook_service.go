@@ -0,0 +1,307 @@
+package application
+
+import (
+	"collab-service/internal/domain/authz"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/external/event"
+	"collab-service/internal/interface/http/middleware"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	maxWebhookURLLength            = 2048
+	minWebhookSecretLen            = 16
+	maxWebhookSecretLen            = 128
+	webhookSecretPrefix            = "whsec_"
+	maxWebhooksPerTeam             = 20
+	DefaultWebhookDeliveryPageSize = 20
+	MaxWebhookDeliveryPageSize     = 100
+)
+
+// WebhookEventTypes là các loại event có thể đăng ký, gồm event của asset.changes và team.activity
+var WebhookEventTypes = []string{
+	string(event.FolderCreated), string(event.FolderUpdated), string(event.FolderDeleted),
+	string(event.FolderShared), string(event.FolderUnshared), string(event.FolderMoved), string(event.FolderRestored),
+	string(event.FolderTeamShared), string(event.FolderTeamUnshared),
+	string(event.NoteCreated), string(event.NoteUpdated), string(event.NoteDeleted),
+	string(event.NoteShared), string(event.NoteUnshared), string(event.NoteMoved), string(event.NoteCopied), string(event.NoteRestored),
+	string(event.NoteTeamShared), string(event.NoteTeamUnshared),
+	string(event.CommentAdded), string(event.UserMentioned), string(event.ShareExpiring),
+	string(event.AccessRequested), string(event.AccessRequestApproved), string(event.AccessRequestDenied),
+	string(event.TeamCreated), string(event.TeamArchived), string(event.TeamRestored),
+	string(event.MemberAdded), string(event.MemberRemoved), string(event.ManagerAdded), string(event.ManagerRemoved),
+}
+
+// WebhookDeliveryPage là một trang lịch sử gửi của webhook, mới nhất trước
+type WebhookDeliveryPage struct {
+	Deliveries []*entity.WebhookDelivery
+	Page       int
+	PageSize   int
+	Total      int64
+}
+
+// WebhookService cho manager của team đăng ký và quản lý webhook của team
+type WebhookService struct {
+	webhookRepo entity.WebhookRepository
+	teamRepo    entity.TeamRepository
+}
+
+func NewWebhookService(webhookRepo entity.WebhookRepository, teamRepo entity.TeamRepository) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		teamRepo:    teamRepo,
+	}
+}
+
+// List returns the webhooks registered by a team
+func (s *WebhookService) List(c *gin.Context, teamID uuid.UUID) ([]*entity.Webhook, error) {
+	if err := s.requireTeamManager(c, teamID, false); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.ListByTeam(c.Request.Context(), teamID)
+}
+
+// Get returns one webhook of a team
+func (s *WebhookService) Get(c *gin.Context, teamID, webhookID uuid.UUID) (*entity.Webhook, error) {
+	if err := s.requireTeamManager(c, teamID, false); err != nil {
+		return nil, err
+	}
+	return s.getOfTeam(c, teamID, webhookID)
+}
+
+// Create đăng ký webhook mới cho team; secret rỗng thì hệ thống tự sinh
+func (s *WebhookService) Create(c *gin.Context, teamID uuid.UUID, rawURL string, eventTypes []string, secret string) (*entity.Webhook, error) {
+	if err := s.requireTeamManager(c, teamID, true); err != nil {
+		return nil, err
+	}
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	webhookURL, err := normalizeWebhookURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if eventTypes, err = normalizeWebhookEventTypes(eventTypes); err != nil {
+		return nil, err
+	}
+	if secret, err = normalizeWebhookSecret(secret); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.webhookRepo.ListByTeam(c.Request.Context(), teamID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxWebhooksPerTeam {
+		return nil, NewBadRequestError(fmt.Sprintf("a team can register at most %d webhooks", maxWebhooksPerTeam))
+	}
+
+	return s.webhookRepo.Create(c.Request.Context(), &entity.Webhook{
+		TeamID:     teamID,
+		URL:        webhookURL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		Active:     true,
+		CreatedBy:  userID,
+	})
+}
+
+// Update đổi URL, bộ lọc event hoặc trạng thái của webhook; trường nil được giữ nguyên.
+// Bật lại webhook đã bị tắt sẽ xoá bộ đếm lỗi.
+func (s *WebhookService) Update(c *gin.Context, teamID, webhookID uuid.UUID, rawURL *string, eventTypes *[]string, active *bool) (*entity.Webhook, error) {
+	if err := s.requireTeamManager(c, teamID, true); err != nil {
+		return nil, err
+	}
+	webhook, err := s.getOfTeam(c, teamID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if rawURL != nil {
+		if webhook.URL, err = normalizeWebhookURL(*rawURL); err != nil {
+			return nil, err
+		}
+	}
+	if eventTypes != nil {
+		if webhook.EventTypes, err = normalizeWebhookEventTypes(*eventTypes); err != nil {
+			return nil, err
+		}
+	}
+
+	deactivated := false
+	if active != nil && *active != webhook.Active {
+		webhook.Active = *active
+		if *active {
+			webhook.FailureCount = 0
+			webhook.DisabledAt = nil
+			webhook.DisabledReason = ""
+		} else {
+			now := time.Now()
+			webhook.DisabledAt = &now
+			webhook.DisabledReason = "disabled by a team manager"
+			deactivated = true
+		}
+	}
+
+	if err := s.webhookRepo.Update(c.Request.Context(), webhook); err != nil {
+		return nil, err
+	}
+	if deactivated {
+		if err := s.webhookRepo.FailPendingDeliveries(c.Request.Context(), webhook.ID, webhook.DisabledReason); err != nil {
+			return nil, err
+		}
+	}
+	return webhook, nil
+}
+
+// RotateSecret sinh secret mới cho webhook; các delivery sau đó được ký bằng secret mới
+func (s *WebhookService) RotateSecret(c *gin.Context, teamID, webhookID uuid.UUID) (*entity.Webhook, error) {
+	if err := s.requireTeamManager(c, teamID, true); err != nil {
+		return nil, err
+	}
+	webhook, err := s.getOfTeam(c, teamID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook.Secret, err = generateWebhookSecret(); err != nil {
+		return nil, err
+	}
+	if err := s.webhookRepo.Update(c.Request.Context(), webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// Delete xoá webhook cùng lịch sử gửi
+func (s *WebhookService) Delete(c *gin.Context, teamID, webhookID uuid.UUID) error {
+	if err := s.requireTeamManager(c, teamID, false); err != nil {
+		return err
+	}
+	if _, err := s.getOfTeam(c, teamID, webhookID); err != nil {
+		return err
+	}
+	return s.webhookRepo.Delete(c.Request.Context(), webhookID)
+}
+
+// ListDeliveries returns the delivery log of a webhook, newest first
+func (s *WebhookService) ListDeliveries(c *gin.Context, teamID, webhookID uuid.UUID, page, pageSize int) (*WebhookDeliveryPage, error) {
+	if err := s.requireTeamManager(c, teamID, false); err != nil {
+		return nil, err
+	}
+	if _, err := s.getOfTeam(c, teamID, webhookID); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultWebhookDeliveryPageSize
+	}
+	if pageSize > MaxWebhookDeliveryPageSize {
+		pageSize = MaxWebhookDeliveryPageSize
+	}
+
+	deliveries, total, err := s.webhookRepo.ListDeliveries(c.Request.Context(), webhookID, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookDeliveryPage{Deliveries: deliveries, Page: page, PageSize: pageSize, Total: total}, nil
+}
+
+// requireTeamManager kiểm tra caller là manager của team; team đã archive chỉ cho xem và xoá webhook
+func (s *WebhookService) requireTeamManager(c *gin.Context, teamID uuid.UUID, modifying bool) error {
+	userID, _ := middleware.GetUserInfoFromGin(c)
+
+	team, err := s.teamRepo.GetByID(c.Request.Context(), teamID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && team == nil) {
+		return NewNotFoundError(fmt.Sprintf("team %s not found", teamID))
+	}
+	if err != nil {
+		return err
+	}
+
+	role, err := s.teamRepo.GetRole(c.Request.Context(), teamID, userID)
+	if err != nil {
+		return err
+	}
+	if err := authorize(authz.InTeam(userID, role), authz.ManageTeam, entity.ResourceTeam, fmt.Sprintf("only managers of team %s can manage its webhooks", teamID)); err != nil {
+		return err
+	}
+	if modifying {
+		return ensureNotArchived(team)
+	}
+	return nil
+}
+
+func (s *WebhookService) getOfTeam(c *gin.Context, teamID, webhookID uuid.UUID) (*entity.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(c.Request.Context(), webhookID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && webhook.TeamID != teamID) {
+		return nil, NewNotFoundError(fmt.Sprintf("webhook %s not found in team %s", webhookID, teamID))
+	}
+	return webhook, err
+}
+
+func normalizeWebhookURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", NewBadRequestError("webhook url is required")
+	}
+	if len(raw) > maxWebhookURLLength {
+		return "", NewBadRequestError(fmt.Sprintf("webhook url must be at most %d characters", maxWebhookURLLength))
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return "", NewBadRequestError("webhook url must be an absolute http or https url")
+	}
+	if parsed.User != nil {
+		return "", NewBadRequestError("webhook url must not contain credentials, use the signing secret instead")
+	}
+	parsed.Fragment = ""
+	return parsed.String(), nil
+}
+
+// normalizeWebhookEventTypes kiểm tra và bỏ trùng bộ lọc event; danh sách rỗng nghĩa là mọi event
+func normalizeWebhookEventTypes(eventTypes []string) ([]string, error) {
+	normalized := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		eventType = strings.ToUpper(strings.TrimSpace(eventType))
+		if !slices.Contains(WebhookEventTypes, eventType) {
+			return nil, NewBadRequestError(fmt.Sprintf("unknown event type %q", eventType))
+		}
+		if !slices.Contains(normalized, eventType) {
+			normalized = append(normalized, eventType)
+		}
+	}
+	return normalized, nil
+}
+
+func normalizeWebhookSecret(secret string) (string, error) {
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return generateWebhookSecret()
+	}
+	if len(secret) < minWebhookSecretLen || len(secret) > maxWebhookSecretLen {
+		return "", NewBadRequestError(fmt.Sprintf("webhook secret must be %d to %d characters", minWebhookSecretLen, maxWebhookSecretLen))
+	}
+	return secret, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,45 @@
+package bootstrap
+
+import (
+	"collab-service/config"
+	"collab-service/internal/application"
+	"collab-service/internal/infrastructure/external/user_service"
+	"collab-service/internal/infrastructure/persistence/repository"
+	"collab-service/internal/interface/http/handler"
+	"collab-service/internal/interface/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func InitAccessRequestModule(r *gin.Engine, db *gorm.DB) {
+	noteRepo := repository.NewNoteRepository(db)
+	folderRepo := repository.NewFolderRepository(db)
+	teamRepo := repository.NewTeamRepository(db)
+	userRepo := user_service.NewUserRepository(user_service.NewGraphQLClient(config.GetConfig().UserServiceEndpoint))
+
+	// Duyệt yêu cầu đi qua NoteService/FolderService để dùng chung kiểm tra quyền và event share
+	noteService := application.NewNoteService(noteRepo, teamRepo, repository.NewNoteRevisionRepository(db), repository.NewTagRepository(db), application.NewMentionNotifier(noteRepo, userRepo))
+	folderService := application.NewFolderService(folderRepo, teamRepo)
+
+	service := application.NewAccessRequestService(noteService, folderService, noteRepo, folderRepo, repository.NewAccessRequestRepository(db))
+	h := handler.NewAccessRequestHandler(service)
+
+	noteRoutes := r.Group("/api/notes")
+	noteRoutes.Use(middleware.AuthMiddleware())
+	{
+		noteRoutes.POST("/:noteID/access-requests", h.CreateNoteRequest)
+		noteRoutes.GET("/:id/access-requests", h.ListNoteRequests)
+		noteRoutes.POST("/:noteID/access-requests/:requestID/approve", h.ApproveNoteRequest)
+		noteRoutes.POST("/:noteID/access-requests/:requestID/deny", h.DenyNoteRequest)
+	}
+
+	folderRoutes := r.Group("/api/folders")
+	folderRoutes.Use(middleware.AuthMiddleware())
+	{
+		folderRoutes.POST("/:folderID/access-requests", h.CreateFolderRequest)
+		folderRoutes.GET("/:folderID/access-requests", h.ListFolderRequests)
+		folderRoutes.POST("/:folderID/access-requests/:requestID/approve", h.ApproveFolderRequest)
+		folderRoutes.POST("/:folderID/access-requests/:requestID/deny", h.DenyFolderRequest)
+	}
+}
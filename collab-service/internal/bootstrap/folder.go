@@ -12,7 +12,8 @@ import (
 
 func InitFolderModule(r *gin.Engine, db *gorm.DB) {
 	folderRepo := repository.NewFolderRepository(db)
-	folderService := application.NewFolderService(folderRepo)
+	teamRepo := repository.NewTeamRepository(db)
+	folderService := application.NewFolderService(folderRepo, teamRepo)
 	folderHandler := handler.NewFolderHandler(folderService)
 
 	group := r.Group("/api/folders")
@@ -25,5 +26,9 @@ func InitFolderModule(r *gin.Engine, db *gorm.DB) {
 		group.DELETE("/:folderID", folderHandler.Delete)
 		group.POST("/:folderID/share", folderHandler.ShareFolder)
 		group.DELETE("/:folderID/share/:userID", folderHandler.RevokeAccess)
+		group.DELETE("/:folderID/team-shares/:teamID", folderHandler.RevokeTeamAccess)
+		group.GET("/:folderID/breadcrumbs", folderHandler.GetBreadcrumbs)
+		group.GET("/:folderID/permissions/explain", folderHandler.ExplainAccess)
+		group.PUT("/:folderID/parent", folderHandler.MoveFolder)
 	}
 }
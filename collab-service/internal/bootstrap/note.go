@@ -1,7 +1,12 @@
 package bootstrap
 
 import (
+	"collab-service/config"
 	"collab-service/internal/application"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/external/cache"
+	"collab-service/internal/infrastructure/external/storage"
+	"collab-service/internal/infrastructure/external/user_service"
 	"collab-service/internal/infrastructure/persistence/repository"
 	"collab-service/internal/interface/http/handler"
 	"collab-service/internal/interface/http/middleware"
@@ -12,9 +17,30 @@ import (
 
 func InitNoteModule(r *gin.Engine, db *gorm.DB) {
 	noteRepo := repository.NewNoteRepository(db)
-	noteService := application.NewNoteService(noteRepo)
+	teamRepo := repository.NewTeamRepository(db)
+	userRepo := user_service.NewUserRepository(user_service.NewGraphQLClient(config.GetConfig().UserServiceEndpoint))
+	mentions := application.NewMentionNotifier(noteRepo, userRepo)
+	noteService := application.NewNoteService(noteRepo, teamRepo, repository.NewNoteRevisionRepository(db), repository.NewTagRepository(db), mentions)
 	noteHandler := handler.NewNoteHandler(noteService)
 
+	attachmentService := application.NewAttachmentService(
+		noteRepo,
+		repository.NewAttachmentRepository(db),
+		storage.GetBlobStore(),
+		config.GetConfig().AttachmentMaxSize,
+		entity.AttachmentQuota{
+			Team: config.GetConfig().TeamAttachmentQuota,
+			User: config.GetConfig().UserAttachmentQuota,
+		},
+	)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService)
+
+	commentHandler := handler.NewCommentHandler(application.NewCommentService(noteRepo, repository.NewCommentRepository(db), mentions))
+
+	collabStore := cache.NewNoteCollabStore(cache.GetRedisClient())
+	collabService := application.NewNoteCollabService(noteService, collabStore, config.GetConfig().NoteCollabSnapshot)
+	collabHandler := handler.NewNoteCollabHandler(collabService)
+
 	noteRoutes := r.Group("/api/notes")
 	noteRoutes.Use(middleware.AuthMiddleware())
 	{
@@ -24,6 +50,29 @@ func InitNoteModule(r *gin.Engine, db *gorm.DB) {
 		noteRoutes.PUT("/:id", noteHandler.Update)
 		noteRoutes.DELETE("/:noteID", noteHandler.Delete)
 		noteRoutes.POST("/:noteID/shares", noteHandler.ShareNote)
+		noteRoutes.POST("/:noteID/move", noteHandler.Move)
+		noteRoutes.POST("/:noteID/copy", noteHandler.Copy)
+		noteRoutes.GET("/:id/revisions", noteHandler.ListRevisions)
+		noteRoutes.GET("/:id/revisions/:number", noteHandler.GetRevision)
+		noteRoutes.GET("/:id/diff", noteHandler.DiffRevisions)
+		noteRoutes.GET("/:id/live", collabHandler.Live)
+		noteRoutes.GET("/:id/tags", noteHandler.ListTags)
+		noteRoutes.GET("/:id/permissions/explain", noteHandler.ExplainAccess)
+		noteRoutes.POST("/:noteID/tags/:tagID", noteHandler.AddTag)
+		noteRoutes.DELETE("/:noteID/tags/:tagID", noteHandler.RemoveTag)
+		noteRoutes.POST("/:noteID/attachments", attachmentHandler.Upload)
+		noteRoutes.GET("/:id/attachments", attachmentHandler.List)
+		noteRoutes.GET("/:id/attachments/:attachmentID", attachmentHandler.Download)
+		noteRoutes.DELETE("/:noteID/attachments/:attachmentID", attachmentHandler.Delete)
+		noteRoutes.GET("/:id/comments", commentHandler.List)
+		noteRoutes.POST("/:noteID/comments", commentHandler.Create)
+		noteRoutes.POST("/:noteID/comments/:commentID/replies", commentHandler.Reply)
+		noteRoutes.PUT("/:id/comments/:commentID", commentHandler.Update)
+		noteRoutes.DELETE("/:noteID/comments/:commentID", commentHandler.Delete)
+		noteRoutes.POST("/:noteID/comments/:commentID/resolve", commentHandler.Resolve)
+		noteRoutes.POST("/:noteID/comments/:commentID/reopen", commentHandler.Reopen)
+		noteRoutes.POST("/:noteID/revisions/:number/restore", noteHandler.RestoreRevision)
 		noteRoutes.DELETE("/:noteID/shares/:userID", noteHandler.RevokeAccess)
+		noteRoutes.DELETE("/:noteID/team-shares/:teamID", noteHandler.RevokeTeamAccess)
 	}
 }
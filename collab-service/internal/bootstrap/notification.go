@@ -0,0 +1,27 @@
+package bootstrap
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/infrastructure/external/cache"
+	"collab-service/internal/interface/http/handler"
+	"collab-service/internal/interface/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func InitNotificationModule(r *gin.Engine) {
+	service := application.NewNotificationService(cache.NewNotificationStore(cache.GetRedisClient()))
+	h := handler.NewNotificationHandler(service)
+
+	group := r.Group("/api/notifications")
+	group.Use(middleware.AuthMiddleware())
+	{
+		group.GET("", h.List)
+		group.GET("/unread-count", h.UnreadCount)
+		group.GET("/preferences", h.GetPreferences)
+		group.PUT("/preferences", h.UpdatePreferences)
+		group.POST("/read-all", h.MarkAllRead)
+		group.POST("/:notificationID/read", h.MarkRead)
+		group.POST("/:notificationID/unread", h.MarkUnread)
+	}
+}
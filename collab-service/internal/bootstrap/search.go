@@ -0,0 +1,22 @@
+package bootstrap
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/infrastructure/persistence/repository"
+	"collab-service/internal/interface/http/handler"
+	"collab-service/internal/interface/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func InitSearchModule(r *gin.Engine, db *gorm.DB) {
+	service := application.NewSearchService(repository.NewSearchRepository(db))
+	h := handler.NewSearchHandler(service)
+
+	group := r.Group("/api/search")
+	group.Use(middleware.AuthMiddleware())
+	{
+		group.GET("", h.Search)
+	}
+}
@@ -0,0 +1,39 @@
+package bootstrap
+
+import (
+	"collab-service/config"
+	"collab-service/internal/application"
+	"collab-service/internal/infrastructure/logger"
+	"collab-service/internal/infrastructure/persistence/repository"
+	"collab-service/internal/infrastructure/scheduler"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// InitShareExpiryModule đăng ký các job dọn share hết hạn và nhắc user trước khi share hết hạn
+func InitShareExpiryModule(db *gorm.DB) {
+	service := application.NewShareExpiryService(
+		repository.NewNoteRepository(db),
+		repository.NewFolderRepository(db),
+		config.GetConfig().ShareExpiryReminder,
+	)
+
+	// Remove note and folder shares once their expiry has passed
+	scheduler.GetScheduler().Every("sweep-expired-shares", config.GetConfig().ShareExpirySweep, func(ctx context.Context) error {
+		removed, err := service.RemoveExpiredShares(ctx)
+		if removed > 0 {
+			logger.Info("Removed expired shares", "count", removed)
+		}
+		return err
+	})
+
+	// Warn users whose access is about to expire
+	scheduler.GetScheduler().Every("remind-expiring-shares", config.GetConfig().ShareExpirySweep, func(ctx context.Context) error {
+		reminded, err := service.RemindExpiringShares(ctx)
+		if reminded > 0 {
+			logger.Info("Sent share expiry reminders", "count", reminded)
+		}
+		return err
+	})
+}
@@ -0,0 +1,42 @@
+package bootstrap
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/infrastructure/persistence/repository"
+	"collab-service/internal/interface/http/handler"
+	"collab-service/internal/interface/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func InitShareLinkModule(r *gin.Engine, db *gorm.DB) {
+	service := application.NewShareLinkService(repository.NewNoteRepository(db), repository.NewFolderRepository(db), repository.NewShareLinkRepository(db))
+	h := handler.NewShareLinkHandler(service)
+
+	noteRoutes := r.Group("/api/notes")
+	noteRoutes.Use(middleware.AuthMiddleware())
+	{
+		noteRoutes.POST("/:noteID/links", h.CreateNoteLink)
+		noteRoutes.GET("/:id/links", h.ListNoteLinks)
+		noteRoutes.GET("/:id/links/:linkID/accesses", h.ListNoteLinkAccesses)
+		noteRoutes.DELETE("/:noteID/links/:linkID", h.RevokeNoteLink)
+	}
+
+	folderRoutes := r.Group("/api/folders")
+	folderRoutes.Use(middleware.AuthMiddleware())
+	{
+		folderRoutes.POST("/:folderID/links", h.CreateFolderLink)
+		folderRoutes.GET("/:folderID/links", h.ListFolderLinks)
+		folderRoutes.GET("/:folderID/links/:linkID/accesses", h.ListFolderLinkAccesses)
+		folderRoutes.DELETE("/:folderID/links/:linkID", h.RevokeFolderLink)
+	}
+
+	// Link công khai không qua AuthMiddleware; service tự kiểm tra token, hạn, mật khẩu và ghi access log
+	public := r.Group("/s")
+	{
+		public.GET("/:token", h.Open)
+		public.GET("/:token/notes/:noteID", h.OpenNote)
+		public.GET("/:token/folders/:folderID", h.OpenFolder)
+	}
+}
@@ -0,0 +1,25 @@
+package bootstrap
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/infrastructure/persistence/repository"
+	"collab-service/internal/interface/http/handler"
+	"collab-service/internal/interface/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func InitTagModule(r *gin.Engine, db *gorm.DB) {
+	service := application.NewTagService(repository.NewTagRepository(db), repository.NewTeamRepository(db))
+	h := handler.NewTagHandler(service)
+
+	group := r.Group("/api/tags")
+	group.Use(middleware.AuthMiddleware())
+	{
+		group.GET("", h.List)
+		group.POST("", h.Create)
+		group.PUT("/:tagID", h.Update)
+		group.DELETE("/:tagID", h.Delete)
+	}
+}
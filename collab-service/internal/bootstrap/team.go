@@ -4,10 +4,14 @@ import (
 	"collab-service/config"
 	"collab-service/internal/application"
 	"collab-service/internal/infrastructure/external/cache"
+	"collab-service/internal/infrastructure/external/event"
 	"collab-service/internal/infrastructure/external/user_service"
+	"collab-service/internal/infrastructure/logger"
 	"collab-service/internal/infrastructure/persistence/repository"
+	"collab-service/internal/infrastructure/scheduler"
 	"collab-service/internal/interface/http/handler"
 	"collab-service/internal/interface/http/middleware"
+	"context"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -25,18 +29,49 @@ func InitTeamModule(r *gin.Engine, db *gorm.DB) {
 	service := application.NewTeamService(teamRepoWithCache, userRepo)
 	h := handler.NewTeamHandler(service)
 
+	activityService := application.NewTeamActivityService(repository.NewTeamActivityRepository(db), teamRepoWithCache, userRepo)
+	activityHandler := handler.NewTeamActivityHandler(activityService)
+
+	// Lưu các event từ stream team activity vào activity feed
+	event.GetTeamActivityConsumer().Subscribe(activityService.RecordEvent)
+
 	group := r.Group("/api/teams")
 	group.Use(middleware.AuthMiddleware())
 	{
 		group.POST("", h.CreateTeam)
+		group.POST("/:teamId/restore", h.RestoreTeam)
+		group.POST("/:teamId/members/import", h.ImportMembers)
 		group.GET(":id", h.GetByID)
 		group.GET("", h.GetAllByUserID)
+		group.GET("/:id/tree", h.GetTree)
+		group.GET("/:id/members", h.ListMembers)
+		group.GET("/:id/members/export", h.ExportMembers)
+		group.GET("/:id/activity", activityHandler.GetActivity)
 		group.PUT("/:teamId/members", h.AddMembers)
 		group.PUT("/:teamId/managers/:managerId", h.AddManager)
 		group.DELETE("/:teamId/members/:memberId", h.RemoveMember)
 		group.DELETE("/:teamId/managers/:managerId", h.RemoveManager)
+		group.PUT("/:teamId/parent", h.MoveTeam)
 		group.PUT("/:teamId", h.UpdateTeam)
 		group.DELETE("/:teamId", h.DeleteTeam)
 	}
 
+	// Permanently remove teams once they have been archived for longer than the retention period
+	retention := config.GetConfig().TeamRetention
+	scheduler.GetScheduler().Every("purge-archived-teams", config.GetConfig().TeamPurgeInterval, func(ctx context.Context) error {
+		purged, err := service.PurgeArchivedTeams(ctx, retention)
+		if purged > 0 {
+			logger.Info("Purged archived teams", "count", purged)
+		}
+		return err
+	})
+
+	// Remove time-bound memberships (e.g. contractors) once they expire
+	scheduler.GetScheduler().Every("sweep-expired-memberships", config.GetConfig().MembershipSweep, func(ctx context.Context) error {
+		removed, err := service.RemoveExpiredMemberships(ctx)
+		if removed > 0 {
+			logger.Info("Removed expired memberships", "count", removed)
+		}
+		return err
+	})
 }
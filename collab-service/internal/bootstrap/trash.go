@@ -0,0 +1,44 @@
+package bootstrap
+
+import (
+	"collab-service/config"
+	"collab-service/internal/application"
+	"collab-service/internal/infrastructure/external/storage"
+	"collab-service/internal/infrastructure/logger"
+	"collab-service/internal/infrastructure/persistence/repository"
+	"collab-service/internal/infrastructure/scheduler"
+	"collab-service/internal/interface/http/handler"
+	"collab-service/internal/interface/http/middleware"
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func InitTrashModule(r *gin.Engine, db *gorm.DB) {
+	service := application.NewTrashService(
+		repository.NewNoteRepository(db),
+		repository.NewFolderRepository(db),
+		repository.NewAttachmentRepository(db),
+		storage.GetBlobStore(),
+		config.GetConfig().TrashRetention,
+	)
+	h := handler.NewTrashHandler(service)
+
+	group := r.Group("/api/trash")
+	group.Use(middleware.AuthMiddleware())
+	{
+		group.GET("", h.List)
+		group.POST("/notes/:noteID/restore", h.RestoreNote)
+		group.POST("/folders/:folderID/restore", h.RestoreFolder)
+	}
+
+	// Permanently remove folders and notes once they have been in the trash for longer than the retention period
+	scheduler.GetScheduler().Every("purge-trash", config.GetConfig().TrashPurgeInterval, func(ctx context.Context) error {
+		purged, err := service.PurgeExpired(ctx)
+		if purged > 0 {
+			logger.Info("Purged trashed items", "count", purged)
+		}
+		return err
+	})
+}
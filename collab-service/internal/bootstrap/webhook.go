@@ -0,0 +1,61 @@
+package bootstrap
+
+import (
+	"collab-service/config"
+	"collab-service/internal/application"
+	"collab-service/internal/infrastructure/external/cache"
+	"collab-service/internal/infrastructure/external/event"
+	"collab-service/internal/infrastructure/external/webhook"
+	"collab-service/internal/infrastructure/logger"
+	"collab-service/internal/infrastructure/persistence/repository"
+	"collab-service/internal/infrastructure/scheduler"
+	"collab-service/internal/interface/http/handler"
+	"collab-service/internal/interface/http/middleware"
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func InitWebhookModule(r *gin.Engine, db *gorm.DB) {
+	cfg := config.GetConfig()
+
+	webhookRepo := repository.NewWebhookRepository(db)
+	teamRepo := cache.NewTeamRepositoryWithCache(repository.NewTeamRepository(db), cache.GetRedisClient(), time.Hour)
+
+	service := application.NewWebhookService(webhookRepo, teamRepo)
+	h := handler.NewWebhookHandler(service)
+
+	dispatcher := application.NewWebhookDispatcher(webhookRepo, webhook.NewHTTPSender(cfg.WebhookTimeout, cfg.WebhookAllowPrivate), cfg.WebhookTimeout, cfg.WebhookMaxAttempts, cfg.WebhookDisableAfter)
+
+	// Event được ghi thành delivery trong DB trước, việc gửi HTTP do job dispatch-webhooks đảm nhận
+	event.GetAssetChangeConsumer().Subscribe(dispatcher.HandleAssetEvent)
+	event.GetTeamActivityConsumer().Subscribe(dispatcher.HandleTeamEvent)
+
+	group := r.Group("/api/teams")
+	group.Use(middleware.AuthMiddleware())
+	{
+		group.GET("/:id/webhooks", h.List)
+		group.GET("/:id/webhooks/:webhookId", h.Get)
+		group.GET("/:id/webhooks/:webhookId/deliveries", h.ListDeliveries)
+		group.POST("/:teamId/webhooks", h.Create)
+		group.POST("/:teamId/webhooks/:webhookId/rotate-secret", h.RotateSecret)
+		group.PUT("/:teamId/webhooks/:webhookId", h.Update)
+		group.DELETE("/:teamId/webhooks/:webhookId", h.Delete)
+	}
+
+	scheduler.GetScheduler().Every("dispatch-webhooks", cfg.WebhookDispatch, func(ctx context.Context) error {
+		_, err := dispatcher.DispatchDue(ctx)
+		return err
+	})
+
+	// Delivery log chỉ giữ trong thời gian retention
+	scheduler.GetScheduler().Every("purge-webhook-deliveries", cfg.WebhookPurge, func(ctx context.Context) error {
+		purged, err := dispatcher.PurgeDeliveries(ctx, cfg.WebhookRetention)
+		if purged > 0 {
+			logger.Info("Purged webhook deliveries", "count", purged)
+		}
+		return err
+	})
+}
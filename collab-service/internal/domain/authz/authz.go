@@ -0,0 +1,136 @@
+// Package authz là lớp phân quyền tập trung của collab-service.
+// Application service không tự so sánh AccessLevel hay TeamAccessRole mà hỏi Allowed(principal, action, resource);
+// mọi quy tắc nằm trong bảng rules bên dưới.
+package authz
+
+import (
+	"collab-service/internal/domain/entity"
+
+	"github.com/google/uuid"
+)
+
+// Action là hành động cần phân quyền
+type Action string
+
+const (
+	// View đọc note/folder cùng revision, comment, attachment; với team là dùng tài nguyên của team như tag
+	View Action = "VIEW"
+	// Comment viết comment hoặc trả lời trên note
+	Comment Action = "COMMENT"
+	// Edit sửa note/folder: nội dung, tag, attachment, resolve comment, tạo hoặc chuyển mục vào/ra folder; với comment là sửa/xoá comment
+	Edit Action = "EDIT"
+	// Share cấp hoặc thu hồi quyền: share, share link, duyệt yêu cầu truy cập, xem quyền của người khác
+	Share Action = "SHARE"
+	// Move chuyển folder cùng cây con sang folder cha khác
+	Move Action = "MOVE"
+	// Delete đưa note/folder vào thùng rác
+	Delete Action = "DELETE"
+	// Restore khôi phục note/folder từ thùng rác
+	Restore Action = "RESTORE"
+
+	// ListMembers xem roster, cây team và export danh sách thành viên
+	ListMembers Action = "LIST_MEMBERS"
+	// ManageMembers thêm member/manager, xoá người khác khỏi team và import roster
+	ManageMembers Action = "MANAGE_MEMBERS"
+	// Leave tự rời team
+	Leave Action = "LEAVE"
+	// ManageTeam sửa team, đổi cây team, quản lý webhook, tag của team và xem activity feed
+	ManageTeam Action = "MANAGE_TEAM"
+	// RemoveManager xoá manager khỏi team
+	RemoveManager Action = "REMOVE_MANAGER"
+	// Archive lưu trữ hoặc khôi phục team
+	Archive Action = "ARCHIVE"
+)
+
+// Principal là user thực hiện hành động cùng quan hệ của họ với tài nguyên đang xét:
+// AccessLevel với note/folder, TeamRole với team, Actor khi user là người đã tạo comment hoặc đưa mục vào thùng rác.
+type Principal struct {
+	UserID      uuid.UUID
+	AccessLevel entity.AccessLevel
+	TeamRole    entity.TeamAccessRole
+	Actor       bool
+}
+
+// OnAsset là principal với quyền hiệu lực trên một note hoặc folder
+func OnAsset(userID uuid.UUID, accessLevel entity.AccessLevel) Principal {
+	return Principal{UserID: userID, AccessLevel: accessLevel}
+}
+
+// InTeam là principal với role của user trong một team
+func InTeam(userID uuid.UUID, role entity.TeamAccessRole) Principal {
+	return Principal{UserID: userID, TeamRole: role}
+}
+
+// Rule là yêu cầu tối thiểu để thực hiện action trên một loại tài nguyên.
+// Principal được phép nếu thoả một trong các điều kiện được đặt; rule không đặt điều kiện nào thì không ai được phép.
+type Rule struct {
+	Resource entity.ResourceType
+	Action   Action
+	// AccessLevel là quyền tối thiểu trên note/folder, rỗng là không xét
+	AccessLevel entity.AccessLevel
+	// TeamRole là role tối thiểu trong team, rỗng là không xét
+	TeamRole entity.TeamAccessRole
+	// Actor cho phép người đã tạo hoặc thực hiện thay đổi trên tài nguyên
+	Actor bool
+}
+
+var rules = []Rule{
+	{Resource: entity.ResourceNote, Action: View, AccessLevel: entity.AccessLevelRead},
+	{Resource: entity.ResourceNote, Action: Comment, AccessLevel: entity.AccessLevelRead},
+	{Resource: entity.ResourceNote, Action: Edit, AccessLevel: entity.AccessLevelWrite},
+	{Resource: entity.ResourceNote, Action: Share, AccessLevel: entity.AccessLevelOwner},
+	{Resource: entity.ResourceNote, Action: Delete, AccessLevel: entity.AccessLevelOwner},
+	{Resource: entity.ResourceNote, Action: Restore, AccessLevel: entity.AccessLevelOwner, Actor: true},
+
+	{Resource: entity.ResourceFolder, Action: View, AccessLevel: entity.AccessLevelRead},
+	{Resource: entity.ResourceFolder, Action: Edit, AccessLevel: entity.AccessLevelWrite},
+	{Resource: entity.ResourceFolder, Action: Share, AccessLevel: entity.AccessLevelOwner},
+	{Resource: entity.ResourceFolder, Action: Move, AccessLevel: entity.AccessLevelOwner},
+	{Resource: entity.ResourceFolder, Action: Delete, AccessLevel: entity.AccessLevelOwner},
+	{Resource: entity.ResourceFolder, Action: Restore, AccessLevel: entity.AccessLevelOwner, Actor: true},
+
+	// Chỉ tác giả được sửa hoặc xoá comment của mình
+	{Resource: entity.ResourceComment, Action: Edit, Actor: true},
+
+	// Guest chỉ dùng được tài nguyên của team, không xem được roster hay cây team
+	{Resource: entity.ResourceTeam, Action: View, TeamRole: entity.TeamGuest},
+	{Resource: entity.ResourceTeam, Action: Leave, TeamRole: entity.TeamGuest},
+	{Resource: entity.ResourceTeam, Action: ListMembers, TeamRole: entity.TeamMember},
+	{Resource: entity.ResourceTeam, Action: ManageMembers, TeamRole: entity.TeamManager},
+	{Resource: entity.ResourceTeam, Action: ManageTeam, TeamRole: entity.TeamManager},
+	{Resource: entity.ResourceTeam, Action: RemoveManager, TeamRole: entity.TeamOwner},
+	{Resource: entity.ResourceTeam, Action: Archive, TeamRole: entity.TeamOwner},
+}
+
+type ruleKey struct {
+	resource entity.ResourceType
+	action   Action
+}
+
+var ruleIndex = func() map[ruleKey]Rule {
+	index := make(map[ruleKey]Rule, len(rules))
+	for _, r := range rules {
+		index[ruleKey{r.Resource, r.Action}] = r
+	}
+	return index
+}()
+
+// Allowed trả true nếu principal được thực hiện action trên loại tài nguyên.
+// Cặp action/resource không có rule luôn bị từ chối.
+func Allowed(p Principal, action Action, resource entity.ResourceType) bool {
+	rule, ok := ruleIndex[ruleKey{resource, action}]
+	if !ok {
+		return false
+	}
+	return rule.allows(p)
+}
+
+func (r Rule) allows(p Principal) bool {
+	if r.AccessLevel != "" && p.AccessLevel != entity.AccessLevelNone && p.AccessLevel.AtLeast(r.AccessLevel) {
+		return true
+	}
+	if r.TeamRole != "" && p.TeamRole != entity.TeamNone && p.TeamRole.IsHigherOrEqualTo(r.TeamRole) {
+		return true
+	}
+	return r.Actor && p.Actor
+}
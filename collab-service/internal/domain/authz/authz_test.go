@@ -0,0 +1,166 @@
+package authz
+
+import (
+	"collab-service/internal/domain/entity"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	allAccessLevels = []entity.AccessLevel{entity.AccessLevelNone, entity.AccessLevelRead, entity.AccessLevelWrite, entity.AccessLevelOwner}
+	allTeamRoles    = []entity.TeamAccessRole{entity.TeamNone, entity.TeamGuest, entity.TeamMember, entity.TeamManager, entity.TeamOwner}
+)
+
+func accessLevels(levels ...entity.AccessLevel) map[entity.AccessLevel]bool {
+	allowed := make(map[entity.AccessLevel]bool, len(levels))
+	for _, l := range levels {
+		allowed[l] = true
+	}
+	return allowed
+}
+
+func teamRoles(roles ...entity.TeamAccessRole) map[entity.TeamAccessRole]bool {
+	allowed := make(map[entity.TeamAccessRole]bool, len(roles))
+	for _, r := range roles {
+		allowed[r] = true
+	}
+	return allowed
+}
+
+func TestAllowedOnAssets(t *testing.T) {
+	readers := accessLevels(entity.AccessLevelRead, entity.AccessLevelWrite, entity.AccessLevelOwner)
+	writers := accessLevels(entity.AccessLevelWrite, entity.AccessLevelOwner)
+	owners := accessLevels(entity.AccessLevelOwner)
+
+	tests := []struct {
+		resource entity.ResourceType
+		action   Action
+		allowed  map[entity.AccessLevel]bool
+	}{
+		{entity.ResourceNote, View, readers},
+		{entity.ResourceNote, Comment, readers},
+		{entity.ResourceNote, Edit, writers},
+		{entity.ResourceNote, Share, owners},
+		{entity.ResourceNote, Delete, owners},
+		{entity.ResourceNote, Restore, owners},
+		{entity.ResourceNote, Move, accessLevels()},
+		{entity.ResourceFolder, View, readers},
+		{entity.ResourceFolder, Edit, writers},
+		{entity.ResourceFolder, Share, owners},
+		{entity.ResourceFolder, Move, owners},
+		{entity.ResourceFolder, Delete, owners},
+		{entity.ResourceFolder, Restore, owners},
+		{entity.ResourceFolder, Comment, accessLevels()},
+		// Quyền trên note/folder không cho phép gì trên comment hay team
+		{entity.ResourceComment, Edit, accessLevels()},
+		{entity.ResourceTeam, View, accessLevels()},
+		{entity.ResourceTeam, ManageTeam, accessLevels()},
+	}
+
+	for _, tt := range tests {
+		for _, level := range allAccessLevels {
+			t.Run(fmt.Sprintf("%s/%s/%s", tt.resource, tt.action, level), func(t *testing.T) {
+				got := Allowed(OnAsset(uuid.New(), level), tt.action, tt.resource)
+				assert.Equal(t, tt.allowed[level], got)
+			})
+		}
+	}
+}
+
+func TestAllowedOnTeams(t *testing.T) {
+	anyMember := teamRoles(entity.TeamGuest, entity.TeamMember, entity.TeamManager, entity.TeamOwner)
+	members := teamRoles(entity.TeamMember, entity.TeamManager, entity.TeamOwner)
+	managers := teamRoles(entity.TeamManager, entity.TeamOwner)
+	owners := teamRoles(entity.TeamOwner)
+
+	tests := []struct {
+		action  Action
+		allowed map[entity.TeamAccessRole]bool
+	}{
+		{View, anyMember},
+		{Leave, anyMember},
+		{ListMembers, members},
+		{ManageMembers, managers},
+		{ManageTeam, managers},
+		{RemoveManager, owners},
+		{Archive, owners},
+		{Share, teamRoles()},
+		{Delete, teamRoles()},
+	}
+
+	for _, tt := range tests {
+		for _, role := range allTeamRoles {
+			t.Run(fmt.Sprintf("%s/%s", tt.action, role), func(t *testing.T) {
+				got := Allowed(InTeam(uuid.New(), role), tt.action, entity.ResourceTeam)
+				assert.Equal(t, tt.allowed[role], got)
+			})
+		}
+	}
+}
+
+func TestAllowedForActor(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal Principal
+		action    Action
+		resource  entity.ResourceType
+		want      bool
+	}{
+		{"comment author edits", Principal{Actor: true}, Edit, entity.ResourceComment, true},
+		{"other user edits comment", Principal{}, Edit, entity.ResourceComment, false},
+		{"note owner cannot edit others' comment", Principal{AccessLevel: entity.AccessLevelOwner}, Edit, entity.ResourceComment, false},
+		{"deleter restores note", Principal{Actor: true}, Restore, entity.ResourceNote, true},
+		{"deleter restores folder", Principal{Actor: true}, Restore, entity.ResourceFolder, true},
+		{"writer who did not delete", Principal{AccessLevel: entity.AccessLevelWrite}, Restore, entity.ResourceNote, false},
+		{"actor cannot share", Principal{Actor: true}, Share, entity.ResourceNote, false},
+		{"actor cannot delete", Principal{Actor: true}, Delete, entity.ResourceFolder, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Allowed(tt.principal, tt.action, tt.resource))
+		})
+	}
+}
+
+func TestAllowedDeniesUnknownRules(t *testing.T) {
+	owner := Principal{AccessLevel: entity.AccessLevelOwner, TeamRole: entity.TeamOwner, Actor: true}
+
+	assert.False(t, Allowed(owner, Action("UNKNOWN"), entity.ResourceNote))
+	assert.False(t, Allowed(owner, View, entity.ResourceType("UNKNOWN")))
+	assert.False(t, Allowed(Principal{AccessLevel: "", TeamRole: ""}, View, entity.ResourceNote))
+}
+
+func TestRulesAreUnique(t *testing.T) {
+	seen := make(map[ruleKey]bool, len(rules))
+	for _, r := range rules {
+		key := ruleKey{r.Resource, r.Action}
+		assert.False(t, seen[key], "duplicate rule for %s/%s", r.Resource, r.Action)
+		seen[key] = true
+		assert.True(t, r.AccessLevel != "" || r.TeamRole != "" || r.Actor, "rule %s/%s allows nobody", r.Resource, r.Action)
+	}
+}
+
+func TestAccessLevelComparison(t *testing.T) {
+	tests := []struct {
+		a, b        entity.AccessLevel
+		greaterThan bool
+		atLeast     bool
+	}{
+		{entity.AccessLevelOwner, entity.AccessLevelWrite, true, true},
+		{entity.AccessLevelWrite, entity.AccessLevelWrite, false, true},
+		{entity.AccessLevelRead, entity.AccessLevelWrite, false, false},
+		{entity.AccessLevelNone, entity.AccessLevelNone, false, true},
+		{entity.AccessLevelRead, entity.AccessLevelNone, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s-%s", tt.a, tt.b), func(t *testing.T) {
+			assert.Equal(t, tt.greaterThan, tt.a.GreaterThan(tt.b))
+			assert.Equal(t, tt.atLeast, tt.a.AtLeast(tt.b))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccessRequestAssetType string
+
+const (
+	AccessRequestNote   AccessRequestAssetType = "NOTE"
+	AccessRequestFolder AccessRequestAssetType = "FOLDER"
+)
+
+type AccessRequestStatus string
+
+const (
+	AccessRequestPending  AccessRequestStatus = "PENDING"
+	AccessRequestApproved AccessRequestStatus = "APPROVED"
+	AccessRequestDenied   AccessRequestStatus = "DENIED"
+)
+
+func (s AccessRequestStatus) IsValid() bool {
+	return s == AccessRequestPending || s == AccessRequestApproved || s == AccessRequestDenied
+}
+
+// AccessRequest là yêu cầu của user xin quyền READ hoặc WRITE trên note/folder mà họ chưa truy cập được.
+// Owner duyệt (tạo share qua luồng share thông thường) hoặc từ chối; mỗi user chỉ có một yêu cầu PENDING trên một asset.
+type AccessRequest struct {
+	ID          uuid.UUID
+	AssetType   AccessRequestAssetType
+	AssetID     uuid.UUID
+	RequesterID uuid.UUID
+	AccessLevel AccessLevel
+	Message     string
+	Status      AccessRequestStatus
+	DecidedBy   *uuid.UUID
+	DecidedAt   *time.Time
+	CreatedAt   time.Time
+}
+
+type AccessRequestRepository interface {
+	Create(ctx context.Context, request *AccessRequest) (*AccessRequest, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*AccessRequest, error)
+	// GetPending trả về yêu cầu đang chờ của user trên asset, gorm.ErrRecordNotFound nếu không có
+	GetPending(ctx context.Context, assetType AccessRequestAssetType, assetID, requesterID uuid.UUID) (*AccessRequest, error)
+	// ListByAsset trả về yêu cầu trên asset, mới nhất trước; status nil là mọi trạng thái
+	ListByAsset(ctx context.Context, assetType AccessRequestAssetType, assetID uuid.UUID, status *AccessRequestStatus) ([]*AccessRequest, error)
+	// Decide chuyển yêu cầu PENDING sang status; false nếu yêu cầu đã được xử lý trước đó
+	Decide(ctx context.Context, id uuid.UUID, status AccessRequestStatus, decidedBy uuid.UUID, at time.Time) (bool, error)
+	// Reopen đưa yêu cầu decidedBy vừa duyệt về PENDING khi không cấp được quyền;
+	// bỏ qua nếu người yêu cầu đã tạo yêu cầu PENDING khác cho asset
+	Reopen(ctx context.Context, id uuid.UUID, decidedBy uuid.UUID) error
+}
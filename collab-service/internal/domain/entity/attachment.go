@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrBlobNotFound được trả về khi blob store không có object với key đã cho
+var ErrBlobNotFound = errors.New("blob not found")
+
+// Attachment là metadata của một file đính kèm note; nội dung file nằm trong BlobStore theo StorageKey
+type Attachment struct {
+	ID         uuid.UUID
+	NoteID     uuid.UUID
+	FileName   string
+	MimeType   string
+	Size       int64
+	Checksum   string // SHA-256 hex của nội dung
+	StorageKey string
+	UploadedBy uuid.UUID
+	CreatedAt  time.Time
+}
+
+// AttachmentQuota giới hạn tổng dung lượng file đính kèm. Dung lượng của note được tính cho mọi team
+// truy cập note qua team share; note không share cho team nào được tính vào quota của owner note.
+type AttachmentQuota struct {
+	Team int64
+	User int64
+}
+
+// AttachmentQuotaError được trả về khi thêm file đính kèm sẽ vượt quota của một team hoặc của owner note
+type AttachmentQuotaError struct {
+	TeamID  uuid.UUID // uuid.Nil khi quota tính theo owner
+	OwnerID uuid.UUID
+	Used    int64
+	Quota   int64
+}
+
+func (e *AttachmentQuotaError) Error() string {
+	if e.TeamID != uuid.Nil {
+		return fmt.Sprintf("attachment quota of team %s exceeded (%d of %d bytes used)", e.TeamID, e.Used, e.Quota)
+	}
+	return fmt.Sprintf("attachment quota of user %s exceeded (%d of %d bytes used)", e.OwnerID, e.Used, e.Quota)
+}
+
+type AttachmentRepository interface {
+	// Create lưu metadata, kiểm tra lại quota trong cùng transaction để các upload đồng thời không cùng vượt quota
+	Create(ctx context.Context, attachment *Attachment, quota AttachmentQuota) (*Attachment, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Attachment, error)
+	ListByNote(ctx context.Context, noteID uuid.UUID) ([]*Attachment, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// CheckQuota trả về *AttachmentQuotaError nếu thêm attachment sẽ vượt quota; không giữ lock nên chỉ dùng để từ chối sớm
+	CheckQuota(ctx context.Context, attachment *Attachment, quota AttachmentQuota) error
+
+	// StorageKeysOfNote và StorageKeysInFolder trả về key blob cần dọn khi note/folder bị xoá vĩnh viễn
+	StorageKeysOfNote(ctx context.Context, noteID uuid.UUID) ([]string, error)
+	StorageKeysInFolder(ctx context.Context, folderID uuid.UUID) ([]string, error)
+}
+
+// BlobStore lưu nội dung file đính kèm. Key do service sinh ra, không chứa dữ liệu của user.
+type BlobStore interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+	// Get mở nội dung từ offset, đọc length byte; length < 0 nghĩa là đọc tới hết
+	Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	// Delete không trả lỗi nếu key không tồn tại
+	Delete(ctx context.Context, key string) error
+}
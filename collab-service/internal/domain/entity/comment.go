@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommentAnchor gắn comment vào một đoạn trong body của note, Start/End là vị trí rune [Start, End).
+// Quote là đoạn văn bản lúc comment, giúp client định vị lại khi body đã thay đổi.
+type CommentAnchor struct {
+	Start int
+	End   int
+	Quote string
+}
+
+// Comment là một comment trên note. Comment gốc (ParentID nil) mở một thread, reply trỏ tới comment gốc.
+// Resolve/reopen áp dụng cho cả thread qua comment gốc.
+type Comment struct {
+	ID       uuid.UUID
+	NoteID   uuid.UUID
+	ParentID *uuid.UUID
+	AuthorID uuid.UUID
+	Body     string
+	Anchor   *CommentAnchor
+
+	ResolvedAt *time.Time
+	ResolvedBy *uuid.UUID
+	EditedAt   *time.Time
+	// DeletedAt đánh dấu comment gốc đã bị xoá nhưng được giữ lại vì thread còn reply
+	DeletedAt *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (c *Comment) IsReply() bool {
+	return c.ParentID != nil
+}
+
+func (c *Comment) IsResolved() bool {
+	return c.ResolvedAt != nil
+}
+
+func (c *Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+type CommentRepository interface {
+	Create(ctx context.Context, comment *Comment) (*Comment, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Comment, error)
+	// ListByNote trả về mọi comment của note (gốc và reply), cũ trước
+	ListByNote(ctx context.Context, noteID uuid.UUID) ([]*Comment, error)
+	UpdateBody(ctx context.Context, id uuid.UUID, body string, editedAt time.Time) error
+	// SetResolved đặt hoặc xoá (resolvedBy nil) trạng thái resolved của thread
+	SetResolved(ctx context.Context, id uuid.UUID, resolvedBy *uuid.UUID, resolvedAt *time.Time) error
+	CountReplies(ctx context.Context, id uuid.UUID) (int64, error)
+	// MarkDeleted xoá nội dung comment nhưng giữ lại dòng để thread vẫn hiển thị các reply
+	MarkDeleted(ctx context.Context, id uuid.UUID, deletedAt time.Time) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -11,13 +11,29 @@ type Folder struct {
 	ID   uuid.UUID
 	Name string
 
+	// ParentID là folder cha; nil với folder gốc. Quyền trên folder cha được kế thừa xuống các folder con.
+	ParentID *uuid.UUID
+
+	// Version tăng mỗi lần folder được cập nhật, dùng cho ETag/If-Match
+	Version int64
+
 	Notes  []Note
 	Shared []FolderShare
 
+	// DeletedAt khác nil khi folder đang nằm trong thùng rác. Folder con và note bên dưới
+	// bị xoá cùng lúc mang cùng DeletedAt và được khôi phục cùng folder.
+	DeletedAt *time.Time
+	DeletedBy *uuid.UUID
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// IsTrashed reports whether the folder is in the trash
+func (f *Folder) IsTrashed() bool {
+	return f.DeletedAt != nil
+}
+
 func NewFolder(name string) *Folder {
 	return &Folder{
 		Name:      name,
@@ -34,9 +50,36 @@ type FolderRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*Folder, error)
 	GetAllForCanAccess(ctx context.Context, userID uuid.UUID) ([]*Folder, error)
 	GetAccessLevel(ctx context.Context, folderID uuid.UUID, userID uuid.UUID) (AccessLevel, error)
-	ShareFolder(ctx context.Context, folderID, userID uuid.UUID, accessLevel AccessLevel) error
+	// ExplainAccess trả về quyền hiệu lực cùng mọi grant tạo nên nó, tính theo đúng logic của GetAccessLevel
+	ExplainAccess(ctx context.Context, folderID, userID uuid.UUID) (*AccessExplanation, error)
+	// ShareFolder tạo hoặc cập nhật share cho user; expiresAt nil là share vĩnh viễn
+	ShareFolder(ctx context.Context, folderID, userID uuid.UUID, accessLevel AccessLevel, expiresAt *time.Time) error
 	RevokeAccess(ctx context.Context, folderID, userID uuid.UUID) error
+	ShareFolderWithTeam(ctx context.Context, folderID, teamID uuid.UUID, accessLevel AccessLevel, expiresAt *time.Time) error
+	RevokeTeamAccess(ctx context.Context, folderID, teamID uuid.UUID) error
 	ChangeAccessLevel(ctx context.Context, folderID, userID uuid.UUID, accessLevel AccessLevel) error
 	Update(ctx context.Context, folder *Folder) error
+	GetChildren(ctx context.Context, folderID uuid.UUID) ([]*Folder, error)
+	GetSubtreeIDs(ctx context.Context, folderID uuid.UUID) ([]uuid.UUID, error)
+	GetBreadcrumbs(ctx context.Context, folderID, userID uuid.UUID) ([]*Folder, error)
+	SetParent(ctx context.Context, folderID uuid.UUID, parentID *uuid.UUID) error
+
+	// ListExpiredShares, DeleteExpiredShare, ListSharesExpiringBefore và MarkShareReminded
+	// giống các method cùng tên của NoteRepository, áp dụng cho folder_shares
+	ListExpiredShares(ctx context.Context, now time.Time, limit int) ([]*FolderShare, error)
+	DeleteExpiredShare(ctx context.Context, id uuid.UUID, now time.Time) (bool, error)
+	ListSharesExpiringBefore(ctx context.Context, now, before time.Time, limit int) ([]*FolderShare, error)
+	MarkShareReminded(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+
+	// Trash chuyển folder cùng toàn bộ folder con và note bên dưới vào thùng rác
+	Trash(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error
+	// Restore khôi phục folder và những gì bị xoá cùng lúc với nó
+	Restore(ctx context.Context, id uuid.UUID) error
+	GetTrashed(ctx context.Context, id uuid.UUID) (*Folder, error)
+	// ListTrash trả về các folder trong thùng rác do user xoá hoặc user là owner,
+	// không gồm folder con bị xoá cùng lúc với folder cha
+	ListTrash(ctx context.Context, userID uuid.UUID) ([]*Folder, error)
+	ListTrashedBefore(ctx context.Context, cutoff time.Time) ([]uuid.UUID, error)
+	// Delete xoá vĩnh viễn folder cùng toàn bộ folder con, note và share bên dưới
 	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,12 +16,20 @@ const (
 	AccessLevelNone  AccessLevel = "NONE"
 )
 
+// ErrShareTargetIsOwner được repository trả về khi share trực tiếp cho user đang giữ quyền OWNER;
+// share không được ghi đè (hạ quyền) owner
+var ErrShareTargetIsOwner = errors.New("user already owns this asset")
+
+// FolderShare cấp quyền trên folder cho một user (UserID) hoặc cả một team (TeamID).
+// Với team share, UserID là uuid.Nil và quyền được resolve qua rosters tại thời điểm truy vấn.
 type FolderShare struct {
 	ID       uuid.UUID
 	FolderID uuid.UUID
 	UserID   uuid.UUID
+	TeamID   *uuid.UUID
 
 	AccessLevel AccessLevel
+	ExpiresAt   *time.Time
 
 	CreatedAt time.Time
 }
@@ -39,11 +48,21 @@ func (a AccessLevel) Priority() int {
 	}
 }
 
-// GreaterThan trả true nếu a có quyền cao hơn b
+// GreaterThan trả true nếu a có quyền cao hơn hẳn b
 func (a AccessLevel) GreaterThan(b AccessLevel) bool {
+	return a.Priority() > b.Priority()
+}
+
+// AtLeast trả true nếu a có quyền bằng hoặc cao hơn b
+func (a AccessLevel) AtLeast(b AccessLevel) bool {
 	return a.Priority() >= b.Priority()
 }
 
+// IsTeamShareable trả true nếu mức quyền có thể cấp cho cả team (chỉ READ hoặc WRITE)
+func (a AccessLevel) IsTeamShareable() bool {
+	return a == AccessLevelRead || a == AccessLevelWrite
+}
+
 // MaxAccessLevel trả về quyền cao nhất giữa hai mức
 func MaxAccessLevel(a, b AccessLevel) AccessLevel {
 	if a.Priority() >= b.Priority() {
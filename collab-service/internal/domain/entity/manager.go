@@ -7,6 +7,6 @@ import (
 )
 
 type ManagerRepository interface {
-	GetAssetsByTeamID(ctx context.Context, teamID uuid.UUID) ([]*Note, error)
+	GetAssetsByTeamIDs(ctx context.Context, teamIDs []uuid.UUID) ([]*Note, error)
 	GetAssetsByUserID(ctx context.Context, userID uuid.UUID) ([]*Note, error)
 }
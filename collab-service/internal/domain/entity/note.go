@@ -18,21 +18,75 @@ type Note struct {
 
 	Shared []*NoteShare
 
+	// Version tăng mỗi lần note được cập nhật, dùng cho ETag/If-Match
+	Version int64
+
+	// DeletedAt khác nil khi note đang nằm trong thùng rác, có thể khôi phục cho tới khi bị purge
+	DeletedAt *time.Time
+	DeletedBy *uuid.UUID
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// IsTrashed reports whether the note is in the trash
+func (n *Note) IsTrashed() bool {
+	return n.DeletedAt != nil
+}
+
+// NoteSharePolicy quyết định share của note được giữ lại hay xoá khi di chuyển/sao chép note
+type NoteSharePolicy string
+
+const (
+	// NoteSharesKeep giữ nguyên (hoặc sao chép) mọi share trực tiếp và share cho team của note
+	NoteSharesKeep NoteSharePolicy = "KEEP"
+	// NoteSharesReset chỉ giữ lại owner; các quyền khác đến từ folder đích
+	NoteSharesReset NoteSharePolicy = "RESET"
+)
+
+func (p NoteSharePolicy) IsValid() bool {
+	return p == NoteSharesKeep || p == NoteSharesReset
+}
+
 type NoteRepository interface {
 	Create(ctx context.Context, note *Note, userID uuid.UUID) (*Note, error)
 	GetOwner(ctx context.Context, noteID uuid.UUID) (uuid.UUID, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*Note, error)
 	GetByFolderID(ctx context.Context, folderID uuid.UUID) ([]*Note, error)
 	GetAllCanAccess(ctx context.Context, userID uuid.UUID) ([]*Note, error)
+	// GetAllCanAccessByTags như GetAllCanAccess nhưng chỉ lấy note khớp mọi nhóm tag (mỗi nhóm khớp một tag bất kỳ)
+	GetAllCanAccessByTags(ctx context.Context, userID uuid.UUID, tagGroups [][]uuid.UUID) ([]*Note, error)
 	GetFolderAccessLevel(ctx context.Context, folderID, userID uuid.UUID) (AccessLevel, error)
 	GetAccessLevel(ctx context.Context, noteID, userID uuid.UUID) (AccessLevel, error)
-	ShareNote(ctx context.Context, noteID, userID uuid.UUID, accessLevel AccessLevel) error
+	// ExplainAccess trả về quyền hiệu lực cùng mọi grant tạo nên nó, tính theo đúng logic của GetAccessLevel
+	ExplainAccess(ctx context.Context, noteID, userID uuid.UUID) (*AccessExplanation, error)
+	// ShareNote tạo hoặc cập nhật share cho user; expiresAt nil là share vĩnh viễn
+	ShareNote(ctx context.Context, noteID, userID uuid.UUID, accessLevel AccessLevel, expiresAt *time.Time) error
 	RevokeAccess(ctx context.Context, noteID, userID uuid.UUID) error
+	ShareNoteWithTeam(ctx context.Context, noteID, teamID uuid.UUID, accessLevel AccessLevel, expiresAt *time.Time) error
+	RevokeTeamAccess(ctx context.Context, noteID, teamID uuid.UUID) error
 	ChangeAccessLevel(ctx context.Context, userID, folderID uuid.UUID, accessLevel AccessLevel) error
 	Update(ctx context.Context, note *Note) error
+	Move(ctx context.Context, noteID, folderID uuid.UUID, policy NoteSharePolicy) error
+	Copy(ctx context.Context, noteID, folderID, userID uuid.UUID, policy NoteSharePolicy) (*Note, error)
+
+	// ListExpiredShares trả về tối đa limit share (user hoặc team) đã hết hạn tại thời điểm now
+	ListExpiredShares(ctx context.Context, now time.Time, limit int) ([]*NoteShare, error)
+	// DeleteExpiredShare xoá share nếu nó vẫn còn hết hạn; false nếu share đã bị xoá hoặc được gia hạn
+	DeleteExpiredShare(ctx context.Context, id uuid.UUID, now time.Time) (bool, error)
+	// ListSharesExpiringBefore trả về share cho user sẽ hết hạn trong khoảng (now, before] và chưa được nhắc
+	ListSharesExpiringBefore(ctx context.Context, now, before time.Time, limit int) ([]*NoteShare, error)
+	// MarkShareReminded đánh dấu đã nhắc; false nếu share đã được đánh dấu trước đó
+	MarkShareReminded(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+
+	// Trash chuyển note vào thùng rác; share và revision được giữ nguyên để khôi phục
+	Trash(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	GetTrashed(ctx context.Context, id uuid.UUID) (*Note, error)
+	// ListTrash trả về các note trong thùng rác do user xoá hoặc user là owner,
+	// không gồm note bị xoá cùng lúc với folder chứa nó (chúng đi theo folder)
+	ListTrash(ctx context.Context, userID uuid.UUID) ([]*Note, error)
+	ListTrashedBefore(ctx context.Context, cutoff time.Time) ([]uuid.UUID, error)
+	// Delete xoá vĩnh viễn note cùng share và revision
 	Delete(ctx context.Context, id uuid.UUID) error
 }
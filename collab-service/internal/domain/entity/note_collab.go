@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"collab-service/internal/domain/ot"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrResyncRequired được trả về khi operation dựa trên revision quá cũ, không còn trong op log để transform
+var ErrResyncRequired = errors.New("operation is based on a revision that is no longer available, resync required")
+
+// CollabOp là một operation đã được áp dụng lên bản nháp chung của note
+type CollabOp struct {
+	Rev       int64        `json:"rev"`
+	Op        ot.Operation `json:"op"`
+	OpID      string       `json:"opId,omitempty"`
+	SessionID string       `json:"sessionId"`
+	UserID    uuid.UUID    `json:"userId"`
+}
+
+// CollabPresence là một phiên chỉnh sửa đang kết nối tới note
+type CollabPresence struct {
+	SessionID    string    `json:"sessionId"`
+	UserID       uuid.UUID `json:"userId"`
+	CanEdit      bool      `json:"canEdit"`
+	Position     int       `json:"position"`
+	SelectionEnd int       `json:"selectionEnd"`
+	SeenAt       time.Time `json:"seenAt"`
+}
+
+// NoteCollabStore giữ bản nháp đang chỉnh sửa chung của note, op log và presence,
+// dùng chung giữa các instance collab-service
+type NoteCollabStore interface {
+	// Load trả về bản nháp và revision hiện tại; nếu chưa có bản nháp thì khởi tạo từ initial
+	Load(ctx context.Context, noteID uuid.UUID, initial func() (string, error)) (string, int64, error)
+	// Apply transform op (dựa trên baseRev) qua các op đồng thời rồi áp dụng, trả về op đã áp dụng với revision mới
+	Apply(ctx context.Context, noteID uuid.UUID, baseRev int64, op *CollabOp) (*CollabOp, error)
+	// Ops trả về các op có revision trong khoảng (fromRev, toRev]
+	Ops(ctx context.Context, noteID uuid.UUID, fromRev, toRev int64) ([]*CollabOp, error)
+	// Snapshot trả về bản nháp hiện tại nếu có thay đổi chưa được lưu, và đánh dấu đang lưu để instance khác không lưu trùng
+	Snapshot(ctx context.Context, noteID uuid.UUID) (doc string, rev int64, ok bool, err error)
+	MarkPersisted(ctx context.Context, noteID uuid.UUID, rev int64) error
+	// Reset thay bản nháp bằng doc (đã được lưu) và xoá op log; trả về revision mới mà mọi phiên phải nạp lại
+	Reset(ctx context.Context, noteID uuid.UUID, doc string) (int64, error)
+
+	Publish(ctx context.Context, noteID uuid.UUID, message []byte) error
+	Subscribe(ctx context.Context, noteID uuid.UUID) (<-chan []byte, func(), error)
+
+	SetPresence(ctx context.Context, noteID uuid.UUID, presence *CollabPresence) error
+	RemovePresence(ctx context.Context, noteID uuid.UUID, sessionID string) error
+	ListPresence(ctx context.Context, noteID uuid.UUID) ([]*CollabPresence, error)
+}
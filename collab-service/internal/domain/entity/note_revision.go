@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NoteRevision là một phiên bản bất biến của note, được tạo mỗi lần nội dung note thay đổi
+type NoteRevision struct {
+	ID           uuid.UUID
+	NoteID       uuid.UUID
+	Number       int // Tăng dần theo từng note, bắt đầu từ 1
+	Title        string
+	Body         string
+	ContentHash  string
+	AuthorID     uuid.UUID
+	RestoredFrom *int // Số revision được khôi phục, nil với chỉnh sửa thông thường
+	CreatedAt    time.Time
+}
+
+// NoteContentHash là sha256 của title và body, dùng để nhận biết nội dung không đổi
+func NoteContentHash(title, body string) string {
+	sum := sha256.Sum256([]byte(title + "\x00" + body))
+	return hex.EncodeToString(sum[:])
+}
+
+type NoteRevisionRepository interface {
+	// Append gán số revision tiếp theo, lưu revision và chỉ giữ lại keep revision mới nhất của note (keep <= 0 là không giới hạn)
+	Append(ctx context.Context, revision *NoteRevision, keep int) (*NoteRevision, error)
+	Latest(ctx context.Context, noteID uuid.UUID) (*NoteRevision, error)
+	GetByNumber(ctx context.Context, noteID uuid.UUID, number int) (*NoteRevision, error)
+	List(ctx context.Context, noteID uuid.UUID, offset, limit int) ([]*NoteRevision, int64, error)
+}
@@ -6,12 +6,16 @@ import (
 	"github.com/google/uuid"
 )
 
+// NoteShare cấp quyền trên note cho một user (UserID) hoặc cả một team (TeamID)
 type NoteShare struct {
 	ID     uuid.UUID
 	NoteID uuid.UUID
 	UserID uuid.UUID
+	TeamID *uuid.UUID
 
 	AccessLevel AccessLevel
+	// ExpiresAt nil là share vĩnh viễn; sau thời điểm này share không còn cấp quyền và bị sweeper xoá
+	ExpiresAt *time.Time
 
 	CreatedAt time.Time
 }
@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification là một thông báo trong inbox của user, được collab-consumer tạo từ event
+// (USER_MENTIONED, COMMENT_ADDED, NOTE_SHARED, FOLDER_SHARED, SHARE_EXPIRING, ACCESS_REQUESTED,
+// ACCESS_REQUEST_APPROVED, ACCESS_REQUEST_DENIED, NOTE_UNSHARED, FOLDER_UNSHARED, MEMBER_ADDED, MEMBER_REMOVED,
+// MANAGER_ADDED, TEAM_ARCHIVED, TEAM_RESTORED)
+type Notification struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	ActorID   string `json:"actorId,omitempty"`
+	AssetType string `json:"assetType,omitempty"`
+	AssetID   string `json:"assetId,omitempty"`
+	CommentID string `json:"commentId,omitempty"`
+	TeamID    string `json:"teamId,omitempty"`
+	// ExpiresAt là thời điểm share hết hạn, chỉ có với SHARE_EXPIRING
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Read      bool       `json:"read"`
+}
+
+// NotificationDelivery là cách thông báo một loại event được gửi qua email
+type NotificationDelivery string
+
+const (
+	DeliveryInstant NotificationDelivery = "instant"
+	DeliveryDaily   NotificationDelivery = "daily"
+	DeliveryWeekly  NotificationDelivery = "weekly"
+	DeliveryOff     NotificationDelivery = "off"
+
+	// DefaultNotificationDelivery áp dụng cho loại event user chưa chọn, khớp với collab-consumer
+	DefaultNotificationDelivery = DeliveryDaily
+)
+
+func (d NotificationDelivery) IsValid() bool {
+	switch d {
+	case DeliveryInstant, DeliveryDaily, DeliveryWeekly, DeliveryOff:
+		return true
+	}
+	return false
+}
+
+// NotificationTypes là các loại event tạo thông báo, user chọn cách gửi email cho từng loại
+var NotificationTypes = []string{"USER_MENTIONED", "COMMENT_ADDED", "NOTE_SHARED", "FOLDER_SHARED", "SHARE_EXPIRING",
+	"ACCESS_REQUESTED", "ACCESS_REQUEST_APPROVED", "ACCESS_REQUEST_DENIED", "NOTE_UNSHARED", "FOLDER_UNSHARED",
+	"MEMBER_ADDED", "MEMBER_REMOVED", "MANAGER_ADDED", "TEAM_ARCHIVED", "TEAM_RESTORED"}
+
+type NotificationStore interface {
+	// List trả về thông báo của user, mới nhất trước, cùng tổng số thông báo khớp bộ lọc
+	List(ctx context.Context, userID uuid.UUID, unreadOnly bool, offset, limit int) ([]*Notification, int64, error)
+	UnreadCount(ctx context.Context, userID uuid.UUID) (int64, error)
+	// SetRead đánh dấu đã đọc/chưa đọc, trả về false nếu thông báo không có trong inbox
+	SetRead(ctx context.Context, userID uuid.UUID, id string, read bool) (bool, error)
+	MarkAllRead(ctx context.Context, userID uuid.UUID) error
+	// GetPreferences trả về các lựa chọn user đã lưu, loại event chưa chọn không có trong map
+	GetPreferences(ctx context.Context, userID uuid.UUID) (map[string]NotificationDelivery, error)
+	SetPreferences(ctx context.Context, userID uuid.UUID, preferences map[string]NotificationDelivery) error
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ResourceType là loại tài nguyên được phân quyền
+type ResourceType string
+
+const (
+	ResourceNote    ResourceType = "NOTE"
+	ResourceFolder  ResourceType = "FOLDER"
+	ResourceComment ResourceType = "COMMENT"
+	ResourceTeam    ResourceType = "TEAM"
+)
+
+// AccessGrantSource cho biết một grant đến từ đâu
+type AccessGrantSource string
+
+const (
+	// AccessGrantOwnership là share OWNER của người tạo asset
+	AccessGrantOwnership AccessGrantSource = "OWNERSHIP"
+	// AccessGrantDirect là share trực tiếp cho user
+	AccessGrantDirect AccessGrantSource = "DIRECT"
+	// AccessGrantTeam là share cho một team mà user đang là thành viên
+	AccessGrantTeam AccessGrantSource = "TEAM"
+)
+
+// AccessGrant là một share góp phần vào quyền hiệu lực của user trên một asset.
+// ResourceType/ResourceID là asset mang share: chính asset đang xét, hoặc folder chứa nó khi Inherited.
+type AccessGrant struct {
+	ShareID      uuid.UUID
+	Source       AccessGrantSource
+	ResourceType ResourceType
+	ResourceID   uuid.UUID
+	Inherited    bool
+	TeamID       *uuid.UUID
+	TeamRole     *TeamAccessRole
+	// AccessLevel là mức ghi trên share; EffectiveLevel là mức thực sự áp dụng (guest chỉ được READ qua team share)
+	AccessLevel    AccessLevel
+	EffectiveLevel AccessLevel
+	ExpiresAt      *time.Time
+}
+
+// AccessExplanation giải thích quyền hiệu lực của user trên asset: AccessLevel là quyền cao nhất trong Grants
+type AccessExplanation struct {
+	ResourceType ResourceType
+	ResourceID   uuid.UUID
+	UserID       uuid.UUID
+	AccessLevel  AccessLevel
+	Grants       []AccessGrant
+}
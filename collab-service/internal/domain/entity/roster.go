@@ -13,31 +13,56 @@ const (
 	TeamOwner   TeamAccessRole = "OWNER"
 	TeamManager TeamAccessRole = "MANAGER"
 	TeamMember  TeamAccessRole = "MEMBER"
+	TeamGuest   TeamAccessRole = "GUEST"
 	TeamNone    TeamAccessRole = "NONE"
 )
 
 // IsHigherOrEqualTo checks if the current role has equal or higher privileges than the given role
 func (r TeamAccessRole) IsHigherOrEqualTo(other TeamAccessRole) bool {
 	roleWeight := map[TeamAccessRole]int{
-		TeamOwner:   3,
-		TeamManager: 2,
-		TeamMember:  1,
+		TeamOwner:   4,
+		TeamManager: 3,
+		TeamMember:  2,
+		TeamGuest:   1,
 	}
 
 	return roleWeight[r] >= roleWeight[other]
 }
 
-// Roster represents a user's membership in a team
+// InheritedRole returns the role a user holding r in a parent team gets in its
+// sub-teams: owners and managers of a parent can manage every team beneath it,
+// while plain membership does not propagate down the hierarchy.
+func (r TeamAccessRole) InheritedRole() TeamAccessRole {
+	if r.IsHigherOrEqualTo(TeamManager) {
+		return TeamManager
+	}
+	return TeamNone
+}
+
+// IsGuest reports whether the role is the restricted GUEST role. Guests only get
+// read access through the team and cannot manage other members or browse the hierarchy.
+func (r TeamAccessRole) IsGuest() bool {
+	return r == TeamGuest
+}
+
+// Roster represents a user's membership in a team.
+// A membership with ExpiresAt set is removed by the sweeper once that time has passed.
 type Roster struct {
 	ID        uuid.UUID
 	UserID    uuid.UUID
 	TeamID    uuid.UUID
 	Team      *Team
 	Role      TeamAccessRole
+	ExpiresAt *time.Time
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// IsExpired reports whether a time-bound membership has run out at the given time
+func (r *Roster) IsExpired(now time.Time) bool {
+	return r.ExpiresAt != nil && !r.ExpiresAt.After(now)
+}
+
 // NewRoster creates a new roster entry
 func NewRoster(userID uuid.UUID, team *Team, role TeamAccessRole) *Roster {
 	return &Roster{
@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchHitType là loại asset của một kết quả tìm kiếm
+type SearchHitType string
+
+const (
+	SearchHitNote   SearchHitType = "NOTE"
+	SearchHitFolder SearchHitType = "FOLDER"
+)
+
+// SearchQuery là truy vấn full-text cùng các bộ lọc; bộ lọc nil/rỗng nghĩa là không lọc
+type SearchQuery struct {
+	Text string
+	// Types giới hạn loại asset; rỗng là tìm cả note và folder
+	Types []SearchHitType
+	// FolderID giới hạn kết quả trong cây con của folder (không gồm chính folder đó)
+	FolderID *uuid.UUID
+	// TeamID giới hạn kết quả ở các asset team truy cập được qua team share, kể cả kế thừa từ folder cha
+	TeamID *uuid.UUID
+	// OwnerID giới hạn kết quả ở các asset mà user này là owner
+	OwnerID *uuid.UUID
+	// UpdatedFrom/UpdatedTo lọc theo thời điểm cập nhật, [from, to)
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+
+	Offset int
+	Limit  int
+}
+
+// IncludesType reports whether the query searches assets of the given type
+func (q *SearchQuery) IncludesType(t SearchHitType) bool {
+	if len(q.Types) == 0 {
+		return true
+	}
+	for _, qt := range q.Types {
+		if qt == t {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchHit là một asset khớp truy vấn. Highlight và Snippet đánh dấu từ khớp bằng
+// SearchMarkStart/SearchMarkStop, tầng application quyết định cách hiển thị.
+type SearchHit struct {
+	Type      SearchHitType
+	ID        uuid.UUID
+	Title     string
+	ParentID  *uuid.UUID
+	Rank      float64
+	Highlight string
+	Snippet   string
+	UpdatedAt time.Time
+}
+
+const (
+	SearchMarkStart = "\x02"
+	SearchMarkStop  = "\x03"
+)
+
+type SearchRepository interface {
+	// Search trả về một trang kết quả xếp theo độ liên quan và tổng số kết quả,
+	// chỉ gồm các asset user truy cập được qua share trực tiếp, share của folder cha hoặc share cho team
+	Search(ctx context.Context, userID uuid.UUID, query *SearchQuery) ([]*SearchHit, int64, error)
+}
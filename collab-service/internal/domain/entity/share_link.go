@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ShareLinkAssetType string
+
+const (
+	ShareLinkNote   ShareLinkAssetType = "NOTE"
+	ShareLinkFolder ShareLinkAssetType = "FOLDER"
+)
+
+// ShareLink là link công khai chỉ đọc tới một note hoặc folder, ai có token đều mở được mà không cần đăng nhập.
+// Chỉ lưu SHA-256 của token; Token gốc chỉ có giá trị ngay sau khi tạo.
+type ShareLink struct {
+	ID           uuid.UUID
+	AssetType    ShareLinkAssetType
+	AssetID      uuid.UUID
+	Token        string
+	TokenHash    string
+	PasswordHash string // bcrypt, rỗng nếu link không đặt mật khẩu
+	ExpiresAt    *time.Time
+	ViewCount    int64
+	LastViewedAt *time.Time
+	RevokedAt    *time.Time
+	CreatedBy    uuid.UUID
+	CreatedAt    time.Time
+}
+
+func (l *ShareLink) HasPassword() bool {
+	return l.PasswordHash != ""
+}
+
+func (l *ShareLink) IsExpired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
+
+func (l *ShareLink) IsRevoked() bool {
+	return l.RevokedAt != nil
+}
+
+// ShareLinkAccessOutcome là kết quả của một lần mở link, được ghi vào access log
+type ShareLinkAccessOutcome string
+
+const (
+	ShareLinkAccessGranted          ShareLinkAccessOutcome = "GRANTED"
+	ShareLinkAccessPasswordRequired ShareLinkAccessOutcome = "PASSWORD_REQUIRED"
+	ShareLinkAccessWrongPassword    ShareLinkAccessOutcome = "WRONG_PASSWORD"
+	ShareLinkAccessThrottled        ShareLinkAccessOutcome = "THROTTLED"
+	ShareLinkAccessExpired          ShareLinkAccessOutcome = "EXPIRED"
+	ShareLinkAccessRevoked          ShareLinkAccessOutcome = "REVOKED"
+	// ShareLinkAccessUnavailable: asset đã vào thùng rác, bị xoá, hoặc nằm ngoài folder được share
+	ShareLinkAccessUnavailable ShareLinkAccessOutcome = "UNAVAILABLE"
+)
+
+type ShareLinkAccess struct {
+	ID        uuid.UUID
+	LinkID    uuid.UUID
+	Outcome   ShareLinkAccessOutcome
+	AssetType ShareLinkAssetType // note hoặc folder được mở, có thể nằm bên trong folder của link
+	AssetID   uuid.UUID
+	IP        string
+	UserAgent string
+	CreatedAt time.Time
+}
+
+type ShareLinkRepository interface {
+	Create(ctx context.Context, link *ShareLink) (*ShareLink, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*ShareLink, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*ShareLink, error)
+	// ListByAsset trả về mọi link của asset, kể cả link đã hết hạn hoặc bị thu hồi, mới nhất trước
+	ListByAsset(ctx context.Context, assetType ShareLinkAssetType, assetID uuid.UUID) ([]*ShareLink, error)
+	CountActive(ctx context.Context, assetType ShareLinkAssetType, assetID uuid.UUID, now time.Time) (int64, error)
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	// RecordAccess ghi access log; lần mở thành công còn tăng view count của link
+	RecordAccess(ctx context.Context, access *ShareLinkAccess) error
+	ListAccesses(ctx context.Context, linkID uuid.UUID, offset, limit int) ([]*ShareLinkAccess, int64, error)
+	// CountFailedAttempts đếm số lần nhập sai mật khẩu từ ip kể từ since
+	CountFailedAttempts(ctx context.Context, linkID uuid.UUID, ip string, since time.Time) (int64, error)
+}
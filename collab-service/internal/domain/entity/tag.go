@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTagColor được dùng khi tạo tag không chọn màu
+const DefaultTagColor = "#9E9E9E"
+
+var tagColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// IsValidTagColor reports whether color is a hex color in the form #RRGGBB
+func IsValidTagColor(color string) bool {
+	return tagColorPattern.MatchString(color)
+}
+
+// Tag là nhãn gắn lên note. Tag cá nhân (OwnerID) chỉ chủ sở hữu nhìn thấy và sử dụng;
+// tag của team (TeamID) dùng chung cho mọi thành viên team. Đúng một trong hai được đặt.
+type Tag struct {
+	ID    uuid.UUID
+	Name  string
+	Color string
+
+	OwnerID *uuid.UUID
+	TeamID  *uuid.UUID
+
+	CreatedBy uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// IsTeamTag reports whether the tag is shared by a team rather than owned by a single user
+func (t *Tag) IsTeamTag() bool {
+	return t.TeamID != nil
+}
+
+type TagRepository interface {
+	Create(ctx context.Context, tag *Tag) (*Tag, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Tag, error)
+	// FindByName tìm tag cùng tên (không phân biệt hoa thường) trong phạm vi của user hoặc team
+	FindByName(ctx context.Context, ownerID, teamID *uuid.UUID, name string) (*Tag, error)
+	// ListVisible trả về tag cá nhân của user và tag của các team user đang là thành viên
+	ListVisible(ctx context.Context, userID uuid.UUID) ([]*Tag, error)
+	Update(ctx context.Context, tag *Tag) error
+	// Delete xoá tag và gỡ tag khỏi mọi note
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// ListByNote trả về các tag trên note mà user nhìn thấy được
+	ListByNote(ctx context.Context, noteID, userID uuid.UUID) ([]*Tag, error)
+	// AddToNote gắn tag lên note, trả về false nếu note đã có tag này
+	AddToNote(ctx context.Context, noteID, tagID, taggedBy uuid.UUID) (bool, error)
+	// RemoveFromNote gỡ tag khỏi note, trả về false nếu note không có tag này
+	RemoveFromNote(ctx context.Context, noteID, tagID uuid.UUID) (bool, error)
+}
@@ -9,11 +9,15 @@ import (
 
 // Team represents a team entity in the domain
 type Team struct {
-	ID        uuid.UUID
-	Name      string
-	Rosters   []Roster
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID         uuid.UUID
+	Name       string
+	ParentID   *uuid.UUID
+	Rosters    []Roster
+	Children   []*Team
+	ArchivedAt *time.Time
+	ArchivedBy *uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
 }
 
 // NewTeam creates a new Team instance
@@ -26,21 +30,55 @@ func NewTeam(name string, rosters []Roster) *Team {
 	}
 }
 
+// IsArchived reports whether the team has been archived and is therefore read-only
+func (t *Team) IsArchived() bool {
+	return t.ArchivedAt != nil
+}
+
 func (t *Team) AddRoster(roster Roster) {
 	t.Rosters = append(t.Rosters, roster)
 	roster.Team = t
 }
 
+// BuildTeamTree links a flat list of teams (as returned by GetSubtree) into a tree
+// and returns the node whose ID is rootID, or nil if it is not in the list.
+func BuildTeamTree(rootID uuid.UUID, teams []*Team) *Team {
+	byID := make(map[uuid.UUID]*Team, len(teams))
+	for _, t := range teams {
+		t.Children = nil
+		byID[t.ID] = t
+	}
+
+	for _, t := range teams {
+		if t.ID == rootID || t.ParentID == nil {
+			continue
+		}
+		if parent, ok := byID[*t.ParentID]; ok {
+			parent.Children = append(parent.Children, t)
+		}
+	}
+
+	return byID[rootID]
+}
+
 type TeamRepository interface {
 	Create(ctx context.Context, team *Team) (*Team, error)
 	ExistsByID(ctx context.Context, id uuid.UUID) (bool, error)
-	GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*Team, error)
+	GetAllByUserID(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]*Team, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*Team, error)
 	GetRole(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) (TeamAccessRole, error)
+	GetSubtree(ctx context.Context, teamID uuid.UUID) ([]*Team, error)
+	GetAncestorIDs(ctx context.Context, teamID uuid.UUID) ([]uuid.UUID, error)
+	SetParent(ctx context.Context, teamID uuid.UUID, parentID *uuid.UUID) error
 	List(ctx context.Context) ([]*Team, error)
-	AddMembers(ctx context.Context, teamID uuid.UUID, members []uuid.UUID) error
+	AddMembers(ctx context.Context, teamID uuid.UUID, members []uuid.UUID, role TeamAccessRole, expiresAt *time.Time) error
 	AddManager(ctx context.Context, teamID uuid.UUID, managerID uuid.UUID) error
 	RemoveMember(ctx context.Context, teamID uuid.UUID, memberID uuid.UUID) error
+	ListExpiredRosters(ctx context.Context, now time.Time) ([]*Roster, error)
+	RemoveExpiredMember(ctx context.Context, teamID uuid.UUID, memberID uuid.UUID, now time.Time) (bool, error)
 	Update(ctx context.Context, team *Team) (*Team, error)
+	Archive(ctx context.Context, id uuid.UUID, archivedBy uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) error
+	ListArchivedBefore(ctx context.Context, cutoff time.Time) ([]uuid.UUID, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TeamActivity là một thay đổi trên team được ghi lại từ stream team activity
+type TeamActivity struct {
+	ID           uuid.UUID
+	EventID      string
+	TeamID       uuid.UUID
+	EventType    string
+	ActorID      string // user ID, hoặc "system" với thay đổi do job nền
+	TargetUserID *uuid.UUID
+	OccurredAt   time.Time
+	CreatedAt    time.Time
+}
+
+type TeamActivityRepository interface {
+	// Record lưu activity; activity trùng EventID bị bỏ qua để xử lý lại message an toàn
+	Record(ctx context.Context, activity *TeamActivity) error
+	List(ctx context.Context, teamID uuid.UUID, eventTypes []string, offset, limit int) ([]*TeamActivity, int64, error)
+}
@@ -31,6 +31,8 @@ type UserRepository interface {
 	ExistsByID(ctx context.Context, id uuid.UUID) (bool, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*User, error)
 	List(ctx context.Context, userType *UserType, userIDs []uuid.UUID) ([]*User, error)
+	ListByEmails(ctx context.Context, emails []string) ([]*User, error)
+	ListByUsernames(ctx context.Context, usernames []string) ([]*User, error)
 	Update(ctx context.Context, user *User) (*User, error)
 	Delete(ctx context.Context, id string) error
 }
@@ -0,0 +1,7 @@
+package entity
+
+import "errors"
+
+// ErrStaleVersion được repository trả về khi cập nhật với Version cũ hơn version hiện tại trong DB,
+// tức là asset đã bị người khác sửa sau khi client đọc.
+var ErrStaleVersion = errors.New("version is stale")
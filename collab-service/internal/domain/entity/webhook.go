@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook là một endpoint do team đăng ký để nhận event qua HTTP POST có ký HMAC.
+// EventTypes rỗng nghĩa là nhận mọi event của team.
+type Webhook struct {
+	ID             uuid.UUID
+	TeamID         uuid.UUID
+	URL            string
+	Secret         string
+	EventTypes     []string
+	Active         bool
+	FailureCount   int // số lần gửi thất bại liên tiếp, về 0 khi gửi thành công
+	DisabledAt     *time.Time
+	DisabledReason string
+	CreatedBy      uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Accepts cho biết webhook có nhận loại event này không
+func (w *Webhook) Accepts(eventType string) bool {
+	return len(w.EventTypes) == 0 || slices.Contains(w.EventTypes, eventType)
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "SUCCEEDED"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookDelivery là một event cần gửi tới một webhook, kèm kết quả của lần thử gần nhất
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	WebhookID      uuid.UUID
+	EventID        string
+	EventType      string
+	Payload        string
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  *time.Time
+	ResponseStatus *int
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *Webhook) (*Webhook, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*Webhook, error)
+	ListByTeam(ctx context.Context, teamID uuid.UUID) ([]*Webhook, error)
+	ListActiveByTeams(ctx context.Context, teamIDs []uuid.UUID) ([]*Webhook, error)
+	Update(ctx context.Context, webhook *Webhook) error
+	// Delete xoá webhook cùng lịch sử gửi của nó
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// TeamIDsForAsset trả về các team có share trên note/folder hoặc trên folder cha của nó
+	TeamIDsForAsset(ctx context.Context, assetType string, assetID uuid.UUID) ([]uuid.UUID, error)
+
+	// EnqueueDeliveries thêm delivery chờ gửi; event đã có delivery cho cùng webhook được bỏ qua
+	EnqueueDeliveries(ctx context.Context, deliveries []*WebhookDelivery) error
+	// ClaimDueDeliveries lấy tối đa limit delivery tới hạn và dời NextAttemptAt thêm lease
+	// để instance khác không gửi trùng trong lúc đang gửi
+	ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*WebhookDelivery, error)
+	SaveDelivery(ctx context.Context, delivery *WebhookDelivery) error
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, offset, limit int) ([]*WebhookDelivery, int64, error)
+	// FailPendingDeliveries đánh dấu mọi delivery đang chờ của webhook là thất bại, dùng khi webhook bị tắt
+	FailPendingDeliveries(ctx context.Context, webhookID uuid.UUID, reason string) error
+	DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// RecordResult cập nhật FailureCount sau một lần gửi và trả về giá trị mới
+	RecordResult(ctx context.Context, webhookID uuid.UUID, succeeded bool) (int, error)
+	Disable(ctx context.Context, webhookID uuid.UUID, reason string, at time.Time) error
+}
+
+// ErrWebhookAddressBlocked được trả về khi URL của webhook trỏ tới địa chỉ nội bộ (loopback, mạng riêng)
+var ErrWebhookAddressBlocked = errors.New("webhook address is not allowed")
+
+// WebhookSender gửi một request POST tới endpoint webhook, trả về status code và đoạn đầu của body response
+type WebhookSender interface {
+	Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, string, error)
+}
@@ -0,0 +1,229 @@
+// Package ot cài đặt operational transformation cho văn bản thuần, theo mô hình retain/insert/delete.
+// Vị trí và độ dài được tính theo rune (code point), không theo byte.
+package ot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+var (
+	ErrBaseLength = errors.New("operation base length does not match the document")
+	ErrInvalidOp  = errors.New("invalid operation component")
+)
+
+// Component là một bước của operation: đúng một trong Retain, Insert hoặc Delete được đặt
+type Component struct {
+	Retain int    `json:"retain,omitempty"`
+	Insert string `json:"insert,omitempty"`
+	Delete int    `json:"delete,omitempty"`
+}
+
+func (c Component) isRetain() bool { return c.Retain > 0 }
+func (c Component) isInsert() bool { return c.Insert != "" }
+func (c Component) isDelete() bool { return c.Delete > 0 }
+
+// Operation là chuỗi component duyệt toàn bộ văn bản từ đầu tới cuối
+type Operation []Component
+
+// UnmarshalJSON kiểm tra mỗi component có đúng một trường hợp lệ
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	var raw []Component
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var op Operation
+	for _, c := range raw {
+		set := 0
+		if c.Retain != 0 {
+			set++
+		}
+		if c.Insert != "" {
+			set++
+		}
+		if c.Delete != 0 {
+			set++
+		}
+		if set != 1 || c.Retain < 0 || c.Delete < 0 {
+			return fmt.Errorf("%w: %+v", ErrInvalidOp, c)
+		}
+		op = op.push(c)
+	}
+	*o = op
+	return nil
+}
+
+// push thêm component và gộp với component cuối nếu cùng loại
+func (o Operation) push(c Component) Operation {
+	if n := len(o); n > 0 {
+		last := &o[n-1]
+		switch {
+		case c.isRetain() && last.isRetain():
+			last.Retain += c.Retain
+			return o
+		case c.isInsert() && last.isInsert():
+			last.Insert += c.Insert
+			return o
+		case c.isDelete() && last.isDelete():
+			last.Delete += c.Delete
+			return o
+		case c.isInsert() && last.isDelete():
+			// Chuẩn hoá: insert luôn đứng trước delete liền kề
+			if n > 1 && o[n-2].isInsert() {
+				o[n-2].Insert += c.Insert
+				return o
+			}
+			o = append(o, *last)
+			o[n-1] = c
+			return o
+		}
+	}
+	if !c.isRetain() && !c.isInsert() && !c.isDelete() {
+		return o
+	}
+	return append(o, c)
+}
+
+// BaseLen là độ dài (rune) của văn bản mà operation áp dụng lên
+func (o Operation) BaseLen() int {
+	n := 0
+	for _, c := range o {
+		n += c.Retain + c.Delete
+	}
+	return n
+}
+
+// TargetLen là độ dài (rune) của văn bản sau khi áp dụng operation
+func (o Operation) TargetLen() int {
+	n := 0
+	for _, c := range o {
+		n += c.Retain + utf8.RuneCountInString(c.Insert)
+	}
+	return n
+}
+
+// IsNoop trả về true nếu operation không thay đổi văn bản
+func (o Operation) IsNoop() bool {
+	for _, c := range o {
+		if !c.isRetain() {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply áp dụng operation lên văn bản
+func (o Operation) Apply(doc string) (string, error) {
+	runes := []rune(doc)
+	if o.BaseLen() != len(runes) {
+		return "", fmt.Errorf("%w: expected %d, got %d", ErrBaseLength, o.BaseLen(), len(runes))
+	}
+
+	result := make([]rune, 0, o.TargetLen())
+	pos := 0
+	for _, c := range o {
+		switch {
+		case c.isRetain():
+			result = append(result, runes[pos:pos+c.Retain]...)
+			pos += c.Retain
+		case c.isInsert():
+			result = append(result, []rune(c.Insert)...)
+		case c.isDelete():
+			pos += c.Delete
+		}
+	}
+	return string(result), nil
+}
+
+// Transform nhận hai operation a và b áp dụng đồng thời lên cùng một văn bản và trả về a', b'
+// sao cho apply(apply(doc, a), b') == apply(apply(doc, b), a').
+// Khi cả hai cùng insert tại một vị trí, insert của a đứng trước.
+func Transform(a, b Operation) (Operation, Operation, error) {
+	if a.BaseLen() != b.BaseLen() {
+		return nil, nil, fmt.Errorf("%w: concurrent operations have different base lengths", ErrBaseLength)
+	}
+
+	var aPrime, bPrime Operation
+	ia, ib := 0, 0
+	var ca, cb *Component
+	next := func(op Operation, i *int) *Component {
+		if *i >= len(op) {
+			return nil
+		}
+		c := op[*i]
+		*i++
+		return &c
+	}
+	ca, cb = next(a, &ia), next(b, &ib)
+
+	for ca != nil || cb != nil {
+		if ca != nil && ca.isInsert() {
+			aPrime = aPrime.push(*ca)
+			bPrime = bPrime.push(Component{Retain: utf8.RuneCountInString(ca.Insert)})
+			ca = next(a, &ia)
+			continue
+		}
+		if cb != nil && cb.isInsert() {
+			aPrime = aPrime.push(Component{Retain: utf8.RuneCountInString(cb.Insert)})
+			bPrime = bPrime.push(*cb)
+			cb = next(b, &ib)
+			continue
+		}
+		if ca == nil || cb == nil {
+			return nil, nil, fmt.Errorf("%w: operations do not cover the same document", ErrBaseLength)
+		}
+
+		lenA, lenB := ca.Retain+ca.Delete, cb.Retain+cb.Delete
+		n := min(lenA, lenB)
+		switch {
+		case ca.isRetain() && cb.isRetain():
+			aPrime = aPrime.push(Component{Retain: n})
+			bPrime = bPrime.push(Component{Retain: n})
+		case ca.isDelete() && cb.isRetain():
+			aPrime = aPrime.push(Component{Delete: n})
+		case ca.isRetain() && cb.isDelete():
+			bPrime = bPrime.push(Component{Delete: n})
+		}
+		// Cả hai cùng delete: phần đó đã bị xoá ở cả hai phía, không cần thêm gì
+
+		ca = shorten(ca, n, lenA, a, &ia, next)
+		cb = shorten(cb, n, lenB, b, &ib, next)
+	}
+
+	return aPrime, bPrime, nil
+}
+
+// shorten bỏ n rune đầu của component; nếu đã dùng hết thì chuyển sang component kế tiếp
+func shorten(c *Component, n, length int, op Operation, i *int, next func(Operation, *int) *Component) *Component {
+	if n == length {
+		return next(op, i)
+	}
+	if c.isRetain() {
+		c.Retain -= n
+	} else {
+		c.Delete -= n
+	}
+	return c
+}
+
+// TransformIndex dịch chuyển vị trí con trỏ theo operation, dùng cho cursor của người dùng khác
+func TransformIndex(index int, op Operation) int {
+	newIndex, pos := index, 0
+	for _, c := range op {
+		if pos > index {
+			break
+		}
+		switch {
+		case c.isRetain():
+			pos += c.Retain
+		case c.isInsert():
+			newIndex += utf8.RuneCountInString(c.Insert)
+		case c.isDelete():
+			newIndex -= min(c.Delete, index-pos)
+			pos += c.Delete
+		}
+	}
+	return newIndex
+}
@@ -0,0 +1,204 @@
+package ot
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func retain(n int) Component    { return Component{Retain: n} }
+func insert(s string) Component { return Component{Insert: s} }
+func del(n int) Component       { return Component{Delete: n} }
+
+func op(components ...Component) Operation {
+	var o Operation
+	for _, c := range components {
+		o = o.push(c)
+	}
+	return o
+}
+
+// assertConverges kiểm tra apply(apply(doc, a), b') == apply(apply(doc, b), a') và trả về văn bản chung
+func assertConverges(t *testing.T, doc string, a, b, aPrime, bPrime Operation) string {
+	t.Helper()
+
+	afterA, err := a.Apply(doc)
+	require.NoError(t, err)
+	left, err := bPrime.Apply(afterA)
+	require.NoError(t, err)
+
+	afterB, err := b.Apply(doc)
+	require.NoError(t, err)
+	right, err := aPrime.Apply(afterB)
+	require.NoError(t, err)
+
+	assert.Equal(t, left, right, "doc %q, a %v, b %v", doc, a, b)
+	return left
+}
+
+func TestTransform(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    string
+		a, b   Operation
+		aPrime Operation
+		bPrime Operation
+		want   string
+	}{
+		{
+			name:   "inserts at the same position keep a first",
+			doc:    "abc",
+			a:      op(retain(1), insert("X"), retain(2)),
+			b:      op(retain(1), insert("Y"), retain(2)),
+			aPrime: op(retain(1), insert("X"), retain(3)),
+			bPrime: op(retain(2), insert("Y"), retain(2)),
+			want:   "aXYbc",
+		},
+		{
+			name:   "inserts at different positions",
+			doc:    "abc",
+			a:      op(insert("X"), retain(3)),
+			b:      op(retain(3), insert("Y")),
+			aPrime: op(insert("X"), retain(4)),
+			bPrime: op(retain(4), insert("Y")),
+			want:   "XabcY",
+		},
+		{
+			name:   "overlapping deletes remove the shared range once",
+			doc:    "abcdef",
+			a:      op(retain(1), del(3), retain(2)),
+			b:      op(retain(2), del(3), retain(1)),
+			aPrime: op(retain(1), del(1), retain(1)),
+			bPrime: op(retain(1), del(1), retain(1)),
+			want:   "af",
+		},
+		{
+			name:   "identical deletes",
+			doc:    "abcd",
+			a:      op(retain(1), del(2), retain(1)),
+			b:      op(retain(1), del(2), retain(1)),
+			aPrime: op(retain(2)),
+			bPrime: op(retain(2)),
+			want:   "ad",
+		},
+		{
+			name:   "insert inside a concurrently deleted range survives",
+			doc:    "abcd",
+			a:      op(retain(2), insert("X"), retain(2)),
+			b:      op(retain(1), del(2), retain(1)),
+			aPrime: op(retain(1), insert("X"), retain(1)),
+			bPrime: op(retain(1), del(1), retain(1), del(1), retain(1)),
+			want:   "aXd",
+		},
+		{
+			name:   "multi-byte runes are counted as one position",
+			doc:    "hé世🙂",
+			a:      op(retain(2), insert("ñ"), retain(2)),
+			b:      op(retain(1), del(1), retain(1), del(1)),
+			aPrime: op(retain(1), insert("ñ"), retain(1)),
+			bPrime: op(retain(1), del(1), retain(2), del(1)),
+			want:   "hñ世",
+		},
+		{
+			name:   "operations on an empty document",
+			doc:    "",
+			a:      op(insert("🙂")),
+			b:      op(insert("é")),
+			aPrime: op(insert("🙂"), retain(1)),
+			bPrime: op(retain(1), insert("é")),
+			want:   "🙂é",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aPrime, bPrime, err := Transform(tt.a, tt.b)
+			require.NoError(t, err)
+			assert.Equal(t, tt.aPrime, aPrime)
+			assert.Equal(t, tt.bPrime, bPrime)
+			assert.Equal(t, tt.want, assertConverges(t, tt.doc, tt.a, tt.b, aPrime, bPrime))
+		})
+	}
+}
+
+func TestTransformBaseLengthMismatch(t *testing.T) {
+	_, _, err := Transform(op(retain(3)), op(retain(2)))
+	assert.ErrorIs(t, err, ErrBaseLength)
+}
+
+// randomOperation sinh operation ngẫu nhiên hợp lệ trên văn bản dài docLen rune
+func randomOperation(r *rand.Rand, docLen int, alphabet []rune) Operation {
+	var o Operation
+	for pos := 0; pos < docLen; {
+		n := 1 + r.Intn(docLen-pos)
+		switch r.Intn(3) {
+		case 0:
+			o = o.push(retain(n))
+			pos += n
+		case 1:
+			o = o.push(del(n))
+			pos += n
+		default:
+			o = o.push(insert(randomText(r, 1+r.Intn(3), alphabet)))
+		}
+	}
+	if r.Intn(2) == 0 {
+		o = o.push(insert(randomText(r, 1+r.Intn(3), alphabet)))
+	}
+	return o
+}
+
+func randomText(r *rand.Rand, n int, alphabet []rune) string {
+	text := make([]rune, n)
+	for i := range text {
+		text[i] = alphabet[r.Intn(len(alphabet))]
+	}
+	return string(text)
+}
+
+func TestTransformConverges(t *testing.T) {
+	alphabet := []rune("ab ñé世🙂")
+	r := rand.New(rand.NewSource(42))
+
+	for i := 0; i < 5000; i++ {
+		doc := randomText(r, r.Intn(12), alphabet)
+		length := len([]rune(doc))
+		a, b := randomOperation(r, length, alphabet), randomOperation(r, length, alphabet)
+
+		aPrime, bPrime, err := Transform(a, b)
+		require.NoError(t, err, "doc %q, a %v, b %v", doc, a, b)
+		assert.Equal(t, b.TargetLen(), aPrime.BaseLen())
+		assert.Equal(t, a.TargetLen(), bPrime.BaseLen())
+		assertConverges(t, doc, a, b, aPrime, bPrime)
+		if t.Failed() {
+			return
+		}
+	}
+}
+
+func TestTransformIndex(t *testing.T) {
+	tests := []struct {
+		name  string
+		index int
+		op    Operation
+		want  int
+	}{
+		{"insert before the cursor", 3, op(retain(1), insert("ab"), retain(4)), 5},
+		{"insert at the cursor pushes it right", 1, op(retain(1), insert("ab"), retain(4)), 3},
+		{"insert after the cursor", 1, op(retain(3), insert("x"), retain(2)), 1},
+		{"delete before the cursor", 5, op(retain(1), del(2), retain(3)), 3},
+		{"delete spanning the cursor moves it to the start", 2, op(retain(1), del(3), retain(2)), 1},
+		{"delete after the cursor", 1, op(retain(2), del(2), retain(2)), 1},
+		{"multi-byte insert counts runes", 2, op(insert("ñ🙂"), retain(4)), 4},
+		{"cursor at the end of the document", 4, op(retain(2), del(2)), 2},
+		{"noop", 2, op(retain(4)), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, TransformIndex(tt.index, tt.op))
+		})
+	}
+}
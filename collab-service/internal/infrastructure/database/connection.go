@@ -65,7 +65,7 @@ func Connect(connectString string) {
 		db = db.Debug()
 	}
 
-	db.AutoMigrate(&model.TeamModel{}, &model.RosterModel{}, &model.FolderModel{}, &model.NoteModel{}, &model.NoteShareModel{}, &model.FolderShareModel{})
+	db.AutoMigrate(&model.TeamModel{}, &model.RosterModel{}, &model.FolderModel{}, &model.NoteModel{}, &model.NoteShareModel{}, &model.FolderShareModel{}, &model.TeamActivityModel{}, &model.NoteRevisionModel{}, &model.TagModel{}, &model.NoteTagModel{}, &model.AttachmentModel{}, &model.CommentModel{}, &model.WebhookModel{}, &model.WebhookDeliveryModel{}, &model.ShareLinkModel{}, &model.ShareLinkAccessModel{}, &model.AccessRequestModel{})
 
 	log.Println("Auto migrations completed successfully")
 }
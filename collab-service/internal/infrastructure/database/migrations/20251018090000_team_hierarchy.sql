@@ -0,0 +1,4 @@
+-- Modify "teams" table
+ALTER TABLE "public"."teams" ADD COLUMN "parent_id" uuid NULL, ADD CONSTRAINT "fk_teams_parent" FOREIGN KEY ("parent_id") REFERENCES "public"."teams" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION;
+-- Create index "idx_teams_parent_id" to table: "teams"
+CREATE INDEX "idx_teams_parent_id" ON "public"."teams" ("parent_id");
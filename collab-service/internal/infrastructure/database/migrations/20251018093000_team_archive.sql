@@ -0,0 +1,4 @@
+-- Modify "teams" table
+ALTER TABLE "public"."teams" ADD COLUMN "archived_at" timestamptz NULL, ADD COLUMN "archived_by" uuid NULL;
+-- Create index "idx_teams_archived_at" to table: "teams"
+CREATE INDEX "idx_teams_archived_at" ON "public"."teams" ("archived_at");
@@ -0,0 +1,4 @@
+-- Modify "rosters" table
+ALTER TABLE "public"."rosters" ADD COLUMN "expires_at" timestamptz NULL;
+-- Create index "idx_rosters_expires_at" to table: "rosters"
+CREATE INDEX "idx_rosters_expires_at" ON "public"."rosters" ("expires_at");
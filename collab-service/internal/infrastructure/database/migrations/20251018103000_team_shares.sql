@@ -0,0 +1,8 @@
+-- Modify "folder_shares" table
+ALTER TABLE "public"."folder_shares" ALTER COLUMN "user_id" DROP NOT NULL, ADD COLUMN "team_id" uuid NULL, ADD CONSTRAINT "chk_folder_shares_principal" CHECK ((user_id IS NULL) <> (team_id IS NULL));
+-- Create index "idx_folder_shares_team_id" to table: "folder_shares"
+CREATE INDEX "idx_folder_shares_team_id" ON "public"."folder_shares" ("team_id");
+-- Modify "note_shares" table
+ALTER TABLE "public"."note_shares" ALTER COLUMN "user_id" DROP NOT NULL, ADD COLUMN "team_id" uuid NULL, ADD CONSTRAINT "chk_note_shares_principal" CHECK ((user_id IS NULL) <> (team_id IS NULL));
+-- Create index "idx_note_shares_team_id" to table: "note_shares"
+CREATE INDEX "idx_note_shares_team_id" ON "public"."note_shares" ("team_id");
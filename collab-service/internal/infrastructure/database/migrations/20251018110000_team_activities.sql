@@ -0,0 +1,18 @@
+-- Create "team_activities" table
+CREATE TABLE "public"."team_activities" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "event_id" character varying(64) NOT NULL,
+  "team_id" uuid NOT NULL,
+  "event_type" character varying(40) NOT NULL,
+  "actor_id" character varying(64) NOT NULL,
+  "target_user_id" uuid NULL,
+  "occurred_at" timestamptz NOT NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "idx_team_activities_event_id" to table: "team_activities"
+CREATE UNIQUE INDEX "idx_team_activities_event_id" ON "public"."team_activities" ("event_id");
+-- Create index "idx_team_activities_event_type" to table: "team_activities"
+CREATE INDEX "idx_team_activities_event_type" ON "public"."team_activities" ("event_type");
+-- Create index "idx_team_activities_team_occurred" to table: "team_activities"
+CREATE INDEX "idx_team_activities_team_occurred" ON "public"."team_activities" ("team_id", "occurred_at" DESC);
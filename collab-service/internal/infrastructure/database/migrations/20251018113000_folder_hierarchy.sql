@@ -0,0 +1,4 @@
+-- Modify "folders" table
+ALTER TABLE "public"."folders" ADD COLUMN "parent_id" uuid NULL, ADD CONSTRAINT "fk_folders_parent" FOREIGN KEY ("parent_id") REFERENCES "public"."folders" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION;
+-- Create index "idx_folders_parent_id" to table: "folders"
+CREATE INDEX "idx_folders_parent_id" ON "public"."folders" ("parent_id");
@@ -0,0 +1,15 @@
+-- Create "note_revisions" table
+CREATE TABLE "public"."note_revisions" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "note_id" uuid NOT NULL,
+  "number" bigint NOT NULL,
+  "title" character varying(255) NOT NULL,
+  "body" text NOT NULL,
+  "content_hash" character varying(64) NOT NULL,
+  "author_id" uuid NOT NULL,
+  "restored_from" bigint NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "idx_note_revisions_note_number" to table: "note_revisions"
+CREATE UNIQUE INDEX "idx_note_revisions_note_number" ON "public"."note_revisions" ("note_id", "number");
@@ -0,0 +1,4 @@
+-- Modify "folders" table
+ALTER TABLE "public"."folders" ADD COLUMN "version" bigint NOT NULL DEFAULT 1;
+-- Modify "notes" table
+ALTER TABLE "public"."notes" ADD COLUMN "version" bigint NOT NULL DEFAULT 1;
@@ -0,0 +1,8 @@
+-- Modify "folders" table
+ALTER TABLE "public"."folders" ADD COLUMN "deleted_at" timestamptz NULL, ADD COLUMN "deleted_by" uuid NULL;
+-- Create index "idx_folders_deleted_at" to table: "folders"
+CREATE INDEX "idx_folders_deleted_at" ON "public"."folders" ("deleted_at");
+-- Modify "notes" table
+ALTER TABLE "public"."notes" ADD COLUMN "deleted_at" timestamptz NULL, ADD COLUMN "deleted_by" uuid NULL;
+-- Create index "idx_notes_deleted_at" to table: "notes"
+CREATE INDEX "idx_notes_deleted_at" ON "public"."notes" ("deleted_at");
@@ -0,0 +1,4 @@
+-- Create index "idx_notes_search" to table: "notes"
+CREATE INDEX "idx_notes_search" ON "public"."notes" USING GIN ((setweight(to_tsvector('simple'::regconfig, "title"), 'A') || setweight(to_tsvector('simple'::regconfig, "body"), 'B')));
+-- Create index "idx_folders_search" to table: "folders"
+CREATE INDEX "idx_folders_search" ON "public"."folders" USING GIN (to_tsvector('simple'::regconfig, "name"));
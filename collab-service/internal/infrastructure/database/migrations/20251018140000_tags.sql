@@ -0,0 +1,33 @@
+-- Create "tags" table
+CREATE TABLE "public"."tags" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "name" character varying(64) NOT NULL,
+  "color" character varying(7) NOT NULL,
+  "owner_id" uuid NULL,
+  "team_id" uuid NULL,
+  "created_by" uuid NOT NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_tags_team" FOREIGN KEY ("team_id") REFERENCES "public"."teams" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_tags_owner_id" to table: "tags"
+CREATE INDEX "idx_tags_owner_id" ON "public"."tags" ("owner_id");
+-- Create index "idx_tags_team_id" to table: "tags"
+CREATE INDEX "idx_tags_team_id" ON "public"."tags" ("team_id");
+-- Create index "idx_tags_owner_name" to table: "tags"
+CREATE UNIQUE INDEX "idx_tags_owner_name" ON "public"."tags" ("owner_id", (lower(("name")::text))) WHERE ("owner_id" IS NOT NULL);
+-- Create index "idx_tags_team_name" to table: "tags"
+CREATE UNIQUE INDEX "idx_tags_team_name" ON "public"."tags" ("team_id", (lower(("name")::text))) WHERE ("team_id" IS NOT NULL);
+-- Create "note_tags" table
+CREATE TABLE "public"."note_tags" (
+  "note_id" uuid NOT NULL,
+  "tag_id" uuid NOT NULL,
+  "tagged_by" uuid NOT NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("note_id", "tag_id"),
+  CONSTRAINT "fk_note_tags_note" FOREIGN KEY ("note_id") REFERENCES "public"."notes" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION,
+  CONSTRAINT "fk_note_tags_tag" FOREIGN KEY ("tag_id") REFERENCES "public"."tags" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_note_tags_tag_id" to table: "note_tags"
+CREATE INDEX "idx_note_tags_tag_id" ON "public"."note_tags" ("tag_id");
@@ -0,0 +1,16 @@
+-- Create "attachments" table
+CREATE TABLE "public"."attachments" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "note_id" uuid NOT NULL,
+  "file_name" character varying(255) NOT NULL,
+  "mime_type" character varying(255) NOT NULL,
+  "size" bigint NOT NULL,
+  "checksum" character varying(64) NOT NULL,
+  "storage_key" character varying(512) NOT NULL,
+  "uploaded_by" uuid NOT NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_attachments_note" FOREIGN KEY ("note_id") REFERENCES "public"."notes" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_attachments_note_id" to table: "attachments"
+CREATE INDEX "idx_attachments_note_id" ON "public"."attachments" ("note_id");
@@ -0,0 +1,24 @@
+-- Create "comments" table
+CREATE TABLE "public"."comments" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "note_id" uuid NOT NULL,
+  "parent_id" uuid NULL,
+  "author_id" uuid NOT NULL,
+  "body" text NOT NULL,
+  "anchor_start" bigint NULL,
+  "anchor_end" bigint NULL,
+  "anchor_quote" text NULL,
+  "resolved_at" timestamptz NULL,
+  "resolved_by" uuid NULL,
+  "edited_at" timestamptz NULL,
+  "deleted_at" timestamptz NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_comments_note" FOREIGN KEY ("note_id") REFERENCES "public"."notes" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION,
+  CONSTRAINT "fk_comments_parent" FOREIGN KEY ("parent_id") REFERENCES "public"."comments" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_comments_note_id" to table: "comments"
+CREATE INDEX "idx_comments_note_id" ON "public"."comments" ("note_id");
+-- Create index "idx_comments_parent_id" to table: "comments"
+CREATE INDEX "idx_comments_parent_id" ON "public"."comments" ("parent_id");
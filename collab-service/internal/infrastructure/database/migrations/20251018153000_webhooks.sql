@@ -0,0 +1,43 @@
+-- Create "webhooks" table
+CREATE TABLE "public"."webhooks" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "team_id" uuid NOT NULL,
+  "url" text NOT NULL,
+  "secret" character varying(128) NOT NULL,
+  "event_types" jsonb NOT NULL,
+  "active" boolean NOT NULL DEFAULT true,
+  "failure_count" bigint NOT NULL DEFAULT 0,
+  "disabled_at" timestamptz NULL,
+  "disabled_reason" text NULL,
+  "created_by" uuid NOT NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_webhooks_team" FOREIGN KEY ("team_id") REFERENCES "public"."teams" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_webhooks_team_id" to table: "webhooks"
+CREATE INDEX "idx_webhooks_team_id" ON "public"."webhooks" ("team_id");
+-- Create "webhook_deliveries" table
+CREATE TABLE "public"."webhook_deliveries" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "webhook_id" uuid NOT NULL,
+  "event_id" character varying(64) NOT NULL,
+  "event_type" character varying(64) NOT NULL,
+  "payload" text NOT NULL,
+  "status" character varying(16) NOT NULL,
+  "attempts" bigint NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamptz NULL,
+  "response_status" bigint NULL,
+  "last_error" text NULL,
+  "delivered_at" timestamptz NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_webhook_deliveries_webhook" FOREIGN KEY ("webhook_id") REFERENCES "public"."webhooks" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_webhook_deliveries_event" to table: "webhook_deliveries"
+CREATE UNIQUE INDEX "idx_webhook_deliveries_event" ON "public"."webhook_deliveries" ("webhook_id", "event_id");
+-- Create index "idx_webhook_deliveries_due" to table: "webhook_deliveries"
+CREATE INDEX "idx_webhook_deliveries_due" ON "public"."webhook_deliveries" ("status", "next_attempt_at");
+-- Create index "idx_webhook_deliveries_created_at" to table: "webhook_deliveries"
+CREATE INDEX "idx_webhook_deliveries_created_at" ON "public"."webhook_deliveries" ("created_at");
@@ -0,0 +1,36 @@
+-- Create "share_links" table
+CREATE TABLE "public"."share_links" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "asset_type" character varying(16) NOT NULL,
+  "asset_id" uuid NOT NULL,
+  "token_hash" character varying(64) NOT NULL,
+  "password_hash" character varying(72) NULL,
+  "expires_at" timestamptz NULL,
+  "view_count" bigint NOT NULL DEFAULT 0,
+  "last_viewed_at" timestamptz NULL,
+  "revoked_at" timestamptz NULL,
+  "created_by" uuid NOT NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "idx_share_links_asset" to table: "share_links"
+CREATE INDEX "idx_share_links_asset" ON "public"."share_links" ("asset_type", "asset_id");
+-- Create index "idx_share_links_expires_at" to table: "share_links"
+CREATE INDEX "idx_share_links_expires_at" ON "public"."share_links" ("expires_at");
+-- Create index "idx_share_links_token_hash" to table: "share_links"
+CREATE UNIQUE INDEX "idx_share_links_token_hash" ON "public"."share_links" ("token_hash");
+-- Create "share_link_accesses" table
+CREATE TABLE "public"."share_link_accesses" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "link_id" uuid NOT NULL,
+  "outcome" character varying(32) NOT NULL,
+  "asset_type" character varying(16) NOT NULL,
+  "asset_id" uuid NOT NULL,
+  "ip" character varying(64) NULL,
+  "user_agent" character varying(512) NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_share_link_accesses_link" FOREIGN KEY ("link_id") REFERENCES "public"."share_links" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_share_link_accesses_link" to table: "share_link_accesses"
+CREATE INDEX "idx_share_link_accesses_link" ON "public"."share_link_accesses" ("link_id", "created_at");
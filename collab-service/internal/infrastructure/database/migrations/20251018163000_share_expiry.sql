@@ -0,0 +1,8 @@
+-- Modify "folder_shares" table
+ALTER TABLE "public"."folder_shares" ADD COLUMN "expires_at" timestamptz NULL, ADD COLUMN "reminder_sent_at" timestamptz NULL;
+-- Create index "idx_folder_shares_expires_at" to table: "folder_shares"
+CREATE INDEX "idx_folder_shares_expires_at" ON "public"."folder_shares" ("expires_at");
+-- Modify "note_shares" table
+ALTER TABLE "public"."note_shares" ADD COLUMN "expires_at" timestamptz NULL, ADD COLUMN "reminder_sent_at" timestamptz NULL;
+-- Create index "idx_note_shares_expires_at" to table: "note_shares"
+CREATE INDEX "idx_note_shares_expires_at" ON "public"."note_shares" ("expires_at");
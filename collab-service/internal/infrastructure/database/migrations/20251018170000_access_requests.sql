@@ -0,0 +1,20 @@
+-- Create "access_requests" table
+CREATE TABLE "public"."access_requests" (
+  "id" uuid NOT NULL DEFAULT gen_random_uuid(),
+  "asset_type" character varying(16) NOT NULL,
+  "asset_id" uuid NOT NULL,
+  "requester_id" uuid NOT NULL,
+  "access_level" character varying(10) NOT NULL,
+  "message" character varying(500) NULL,
+  "status" character varying(16) NOT NULL DEFAULT 'PENDING',
+  "decided_by" uuid NULL,
+  "decided_at" timestamptz NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "idx_access_requests_asset" to table: "access_requests"
+CREATE INDEX "idx_access_requests_asset" ON "public"."access_requests" ("asset_type", "asset_id");
+-- Create index "idx_access_requests_pending" to table: "access_requests"
+CREATE UNIQUE INDEX "idx_access_requests_pending" ON "public"."access_requests" ("asset_type", "asset_id", "requester_id") WHERE ((status)::text = 'PENDING'::text);
+-- Create index "idx_access_requests_requester_id" to table: "access_requests"
+CREATE INDEX "idx_access_requests_requester_id" ON "public"."access_requests" ("requester_id");
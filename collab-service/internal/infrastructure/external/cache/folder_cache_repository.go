@@ -43,6 +43,11 @@ func (f *FolderRepositoryWithCache) GetAccessLevel(ctx context.Context, folderID
 	return f.repo.GetAccessLevel(ctx, folderID, userID)
 }
 
+// ExplainAccess implements entity.FolderRepository.
+func (f *FolderRepositoryWithCache) ExplainAccess(ctx context.Context, folderID, userID uuid.UUID) (*entity.AccessExplanation, error) {
+	return f.repo.ExplainAccess(ctx, folderID, userID)
+}
+
 // GetAllForCanAccess implements entity.FolderRepository.
 func (f *FolderRepositoryWithCache) GetAllForCanAccess(ctx context.Context, userID uuid.UUID) ([]*entity.Folder, error) {
 	return f.repo.GetAllForCanAccess(ctx, userID)
@@ -64,11 +69,86 @@ func (f *FolderRepositoryWithCache) RevokeAccess(ctx context.Context, folderID u
 }
 
 // ShareFolder implements entity.FolderRepository.
-func (f *FolderRepositoryWithCache) ShareFolder(ctx context.Context, folderID uuid.UUID, userID uuid.UUID, accessLevel entity.AccessLevel) error {
-	return f.repo.ShareFolder(ctx, folderID, userID, accessLevel)
+func (f *FolderRepositoryWithCache) ShareFolder(ctx context.Context, folderID uuid.UUID, userID uuid.UUID, accessLevel entity.AccessLevel, expiresAt *time.Time) error {
+	return f.repo.ShareFolder(ctx, folderID, userID, accessLevel, expiresAt)
+}
+
+// ShareFolderWithTeam implements entity.FolderRepository.
+func (f *FolderRepositoryWithCache) ShareFolderWithTeam(ctx context.Context, folderID uuid.UUID, teamID uuid.UUID, accessLevel entity.AccessLevel, expiresAt *time.Time) error {
+	return f.repo.ShareFolderWithTeam(ctx, folderID, teamID, accessLevel, expiresAt)
+}
+
+// RevokeTeamAccess implements entity.FolderRepository.
+func (f *FolderRepositoryWithCache) RevokeTeamAccess(ctx context.Context, folderID uuid.UUID, teamID uuid.UUID) error {
+	return f.repo.RevokeTeamAccess(ctx, folderID, teamID)
 }
 
 // Update implements entity.FolderRepository.
 func (f *FolderRepositoryWithCache) Update(ctx context.Context, folder *entity.Folder) error {
 	return f.repo.Update(ctx, folder)
 }
+
+// GetChildren implements entity.FolderRepository.
+func (f *FolderRepositoryWithCache) GetChildren(ctx context.Context, folderID uuid.UUID) ([]*entity.Folder, error) {
+	return f.repo.GetChildren(ctx, folderID)
+}
+
+// GetSubtreeIDs implements entity.FolderRepository.
+func (f *FolderRepositoryWithCache) GetSubtreeIDs(ctx context.Context, folderID uuid.UUID) ([]uuid.UUID, error) {
+	return f.repo.GetSubtreeIDs(ctx, folderID)
+}
+
+// GetBreadcrumbs implements entity.FolderRepository.
+func (f *FolderRepositoryWithCache) GetBreadcrumbs(ctx context.Context, folderID uuid.UUID, userID uuid.UUID) ([]*entity.Folder, error) {
+	return f.repo.GetBreadcrumbs(ctx, folderID, userID)
+}
+
+// SetParent implements entity.FolderRepository.
+func (f *FolderRepositoryWithCache) SetParent(ctx context.Context, folderID uuid.UUID, parentID *uuid.UUID) error {
+	return f.repo.SetParent(ctx, folderID, parentID)
+}
+
+// ListExpiredShares implements entity.FolderRepository.
+func (f *FolderRepositoryWithCache) ListExpiredShares(ctx context.Context, now time.Time, limit int) ([]*entity.FolderShare, error) {
+	return f.repo.ListExpiredShares(ctx, now, limit)
+}
+
+// DeleteExpiredShare implements entity.FolderRepository.
+func (f *FolderRepositoryWithCache) DeleteExpiredShare(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	return f.repo.DeleteExpiredShare(ctx, id, now)
+}
+
+// ListSharesExpiringBefore implements entity.FolderRepository.
+func (f *FolderRepositoryWithCache) ListSharesExpiringBefore(ctx context.Context, now time.Time, before time.Time, limit int) ([]*entity.FolderShare, error) {
+	return f.repo.ListSharesExpiringBefore(ctx, now, before, limit)
+}
+
+// MarkShareReminded implements entity.FolderRepository.
+func (f *FolderRepositoryWithCache) MarkShareReminded(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	return f.repo.MarkShareReminded(ctx, id, at)
+}
+
+// Trash implements entity.FolderRepository.
+func (f *FolderRepositoryWithCache) Trash(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error {
+	return f.repo.Trash(ctx, id, deletedBy)
+}
+
+// Restore implements entity.FolderRepository.
+func (f *FolderRepositoryWithCache) Restore(ctx context.Context, id uuid.UUID) error {
+	return f.repo.Restore(ctx, id)
+}
+
+// GetTrashed implements entity.FolderRepository.
+func (f *FolderRepositoryWithCache) GetTrashed(ctx context.Context, id uuid.UUID) (*entity.Folder, error) {
+	return f.repo.GetTrashed(ctx, id)
+}
+
+// ListTrash implements entity.FolderRepository.
+func (f *FolderRepositoryWithCache) ListTrash(ctx context.Context, userID uuid.UUID) ([]*entity.Folder, error) {
+	return f.repo.ListTrash(ctx, userID)
+}
+
+// ListTrashedBefore implements entity.FolderRepository.
+func (f *FolderRepositoryWithCache) ListTrashedBefore(ctx context.Context, cutoff time.Time) ([]uuid.UUID, error) {
+	return f.repo.ListTrashedBefore(ctx, cutoff)
+}
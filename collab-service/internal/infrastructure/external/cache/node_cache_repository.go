@@ -48,11 +48,21 @@ func (n *NoteRepositoryWithCache) GetAccessLevel(ctx context.Context, noteID uui
 	return n.dbRepo.GetAccessLevel(ctx, noteID, userID)
 }
 
+// ExplainAccess implements entity.NoteRepository.
+func (n *NoteRepositoryWithCache) ExplainAccess(ctx context.Context, noteID, userID uuid.UUID) (*entity.AccessExplanation, error) {
+	return n.dbRepo.ExplainAccess(ctx, noteID, userID)
+}
+
 // GetAllCanAccess implements entity.NoteRepository.
 func (n *NoteRepositoryWithCache) GetAllCanAccess(ctx context.Context, userID uuid.UUID) ([]*entity.Note, error) {
 	return n.dbRepo.GetAllCanAccess(ctx, userID)
 }
 
+// GetAllCanAccessByTags implements entity.NoteRepository.
+func (n *NoteRepositoryWithCache) GetAllCanAccessByTags(ctx context.Context, userID uuid.UUID, tagGroups [][]uuid.UUID) ([]*entity.Note, error) {
+	return n.dbRepo.GetAllCanAccessByTags(ctx, userID, tagGroups)
+}
+
 // GetByFolderID implements entity.NoteRepository.
 func (n *NoteRepositoryWithCache) GetByFolderID(ctx context.Context, folderID uuid.UUID) ([]*entity.Note, error) {
 	return n.dbRepo.GetByFolderID(ctx, folderID)
@@ -74,11 +84,76 @@ func (n *NoteRepositoryWithCache) RevokeAccess(ctx context.Context, noteID uuid.
 }
 
 // ShareNote implements entity.NoteRepository.
-func (n *NoteRepositoryWithCache) ShareNote(ctx context.Context, noteID uuid.UUID, userID uuid.UUID, accessLevel entity.AccessLevel) error {
-	return n.dbRepo.ShareNote(ctx, noteID, userID, accessLevel)
+func (n *NoteRepositoryWithCache) ShareNote(ctx context.Context, noteID uuid.UUID, userID uuid.UUID, accessLevel entity.AccessLevel, expiresAt *time.Time) error {
+	return n.dbRepo.ShareNote(ctx, noteID, userID, accessLevel, expiresAt)
+}
+
+// ShareNoteWithTeam implements entity.NoteRepository.
+func (n *NoteRepositoryWithCache) ShareNoteWithTeam(ctx context.Context, noteID uuid.UUID, teamID uuid.UUID, accessLevel entity.AccessLevel, expiresAt *time.Time) error {
+	return n.dbRepo.ShareNoteWithTeam(ctx, noteID, teamID, accessLevel, expiresAt)
+}
+
+// RevokeTeamAccess implements entity.NoteRepository.
+func (n *NoteRepositoryWithCache) RevokeTeamAccess(ctx context.Context, noteID uuid.UUID, teamID uuid.UUID) error {
+	return n.dbRepo.RevokeTeamAccess(ctx, noteID, teamID)
 }
 
 // Update implements entity.NoteRepository.
 func (n *NoteRepositoryWithCache) Update(ctx context.Context, note *entity.Note) error {
 	return n.dbRepo.Update(ctx, note)
 }
+
+// Move implements entity.NoteRepository.
+func (n *NoteRepositoryWithCache) Move(ctx context.Context, noteID uuid.UUID, folderID uuid.UUID, policy entity.NoteSharePolicy) error {
+	return n.dbRepo.Move(ctx, noteID, folderID, policy)
+}
+
+// Copy implements entity.NoteRepository.
+func (n *NoteRepositoryWithCache) Copy(ctx context.Context, noteID uuid.UUID, folderID uuid.UUID, userID uuid.UUID, policy entity.NoteSharePolicy) (*entity.Note, error) {
+	return n.dbRepo.Copy(ctx, noteID, folderID, userID, policy)
+}
+
+// ListExpiredShares implements entity.NoteRepository.
+func (n *NoteRepositoryWithCache) ListExpiredShares(ctx context.Context, now time.Time, limit int) ([]*entity.NoteShare, error) {
+	return n.dbRepo.ListExpiredShares(ctx, now, limit)
+}
+
+// DeleteExpiredShare implements entity.NoteRepository.
+func (n *NoteRepositoryWithCache) DeleteExpiredShare(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	return n.dbRepo.DeleteExpiredShare(ctx, id, now)
+}
+
+// ListSharesExpiringBefore implements entity.NoteRepository.
+func (n *NoteRepositoryWithCache) ListSharesExpiringBefore(ctx context.Context, now time.Time, before time.Time, limit int) ([]*entity.NoteShare, error) {
+	return n.dbRepo.ListSharesExpiringBefore(ctx, now, before, limit)
+}
+
+// MarkShareReminded implements entity.NoteRepository.
+func (n *NoteRepositoryWithCache) MarkShareReminded(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	return n.dbRepo.MarkShareReminded(ctx, id, at)
+}
+
+// Trash implements entity.NoteRepository.
+func (n *NoteRepositoryWithCache) Trash(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error {
+	return n.dbRepo.Trash(ctx, id, deletedBy)
+}
+
+// Restore implements entity.NoteRepository.
+func (n *NoteRepositoryWithCache) Restore(ctx context.Context, id uuid.UUID) error {
+	return n.dbRepo.Restore(ctx, id)
+}
+
+// GetTrashed implements entity.NoteRepository.
+func (n *NoteRepositoryWithCache) GetTrashed(ctx context.Context, id uuid.UUID) (*entity.Note, error) {
+	return n.dbRepo.GetTrashed(ctx, id)
+}
+
+// ListTrash implements entity.NoteRepository.
+func (n *NoteRepositoryWithCache) ListTrash(ctx context.Context, userID uuid.UUID) ([]*entity.Note, error) {
+	return n.dbRepo.ListTrash(ctx, userID)
+}
+
+// ListTrashedBefore implements entity.NoteRepository.
+func (n *NoteRepositoryWithCache) ListTrashedBefore(ctx context.Context, cutoff time.Time) ([]uuid.UUID, error) {
+	return n.dbRepo.ListTrashedBefore(ctx, cutoff)
+}
@@ -0,0 +1,341 @@
+package cache
+
+import (
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/domain/ot"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// collabOpLogSize là số op gần nhất được giữ lại để transform op của client đang chậm
+	collabOpLogSize = 500
+	// collabDraftTTL: bản nháp không có ai chỉnh sửa sẽ hết hạn và được nạp lại từ DB ở lần mở sau
+	collabDraftTTL = 24 * time.Hour
+	// collabPresenceTimeout: phiên không gửi heartbeat trong khoảng này coi như đã rời đi
+	collabPresenceTimeout = 90 * time.Second
+	// collabPersistLock giới hạn thời gian một instance giữ quyền lưu snapshot
+	collabPersistLock  = 15 * time.Second
+	collabApplyRetries = 20
+)
+
+// NoteCollabStore lưu bản nháp chung của note trên Redis để mọi instance collab-service
+// cùng áp dụng op theo một thứ tự duy nhất, và fan-out message qua Redis pub/sub
+type NoteCollabStore struct {
+	rdb *redis.Client
+}
+
+func NewNoteCollabStore(rdb *redis.Client) entity.NoteCollabStore {
+	return &NoteCollabStore{rdb: rdb}
+}
+
+func collabKey(noteID uuid.UUID, suffix string) string {
+	return fmt.Sprintf("collab:note:%s:%s", noteID.String(), suffix)
+}
+
+// Load implements entity.NoteCollabStore.
+func (s *NoteCollabStore) Load(ctx context.Context, noteID uuid.UUID, initial func() (string, error)) (string, int64, error) {
+	docKey, revKey, opsKey := collabKey(noteID, "doc"), collabKey(noteID, "rev"), collabKey(noteID, "ops")
+
+	for attempt := 0; attempt < collabApplyRetries; attempt++ {
+		var doc string
+		var rev int64
+		err := s.rdb.Watch(ctx, func(tx *redis.Tx) error {
+			values, err := tx.MGet(ctx, docKey, revKey).Result()
+			if err != nil {
+				return err
+			}
+			if values[0] != nil && values[1] != nil {
+				doc = values[0].(string)
+				_, err = fmt.Sscan(values[1].(string), &rev)
+				return err
+			}
+
+			// Chưa có bản nháp: khởi tạo từ nội dung đã lưu của note
+			if doc, err = initial(); err != nil {
+				return err
+			}
+			rev = 0
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, docKey, doc, collabDraftTTL)
+				pipe.Set(ctx, revKey, rev, collabDraftTTL)
+				pipe.Del(ctx, opsKey, collabKey(noteID, "persisted"))
+				return nil
+			})
+			return err
+		}, docKey, revKey)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		return doc, rev, err
+	}
+	return "", 0, fmt.Errorf("failed to load collaborative draft of note %s: too much contention", noteID)
+}
+
+// Apply implements entity.NoteCollabStore.
+func (s *NoteCollabStore) Apply(ctx context.Context, noteID uuid.UUID, baseRev int64, op *entity.CollabOp) (*entity.CollabOp, error) {
+	docKey, revKey, opsKey := collabKey(noteID, "doc"), collabKey(noteID, "rev"), collabKey(noteID, "ops")
+
+	for attempt := 0; attempt < collabApplyRetries; attempt++ {
+		var applied *entity.CollabOp
+		err := s.rdb.Watch(ctx, func(tx *redis.Tx) error {
+			values, err := tx.MGet(ctx, docKey, revKey).Result()
+			if err != nil {
+				return err
+			}
+			if values[0] == nil || values[1] == nil {
+				return entity.ErrResyncRequired
+			}
+			doc := values[0].(string)
+			var rev int64
+			if _, err := fmt.Sscan(values[1].(string), &rev); err != nil {
+				return err
+			}
+			if baseRev > rev || baseRev < 0 {
+				return entity.ErrResyncRequired
+			}
+
+			// Transform qua các op đã được áp dụng sau baseRev
+			transformed := op.Op
+			if baseRev < rev {
+				concurrent, err := readOps(ctx, tx, opsKey, baseRev, rev)
+				if err != nil {
+					return err
+				}
+				if int64(len(concurrent)) != rev-baseRev {
+					return entity.ErrResyncRequired
+				}
+				for _, c := range concurrent {
+					if transformed, _, err = ot.Transform(transformed, c.Op); err != nil {
+						return err
+					}
+				}
+			}
+
+			newDoc, err := transformed.Apply(doc)
+			if err != nil {
+				return err
+			}
+
+			applied = &entity.CollabOp{
+				Rev:       rev + 1,
+				Op:        transformed,
+				OpID:      op.OpID,
+				SessionID: op.SessionID,
+				UserID:    op.UserID,
+			}
+			entry, err := json.Marshal(applied)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, docKey, newDoc, collabDraftTTL)
+				pipe.Set(ctx, revKey, applied.Rev, collabDraftTTL)
+				pipe.RPush(ctx, opsKey, entry)
+				pipe.LTrim(ctx, opsKey, -collabOpLogSize, -1)
+				pipe.Expire(ctx, opsKey, collabDraftTTL)
+				return nil
+			})
+			return err
+		}, docKey, revKey)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return applied, nil
+	}
+	return nil, fmt.Errorf("failed to apply operation on note %s: too much contention", noteID)
+}
+
+// Ops implements entity.NoteCollabStore.
+func (s *NoteCollabStore) Ops(ctx context.Context, noteID uuid.UUID, fromRev, toRev int64) ([]*entity.CollabOp, error) {
+	ops, err := readOps(ctx, s.rdb, collabKey(noteID, "ops"), fromRev, toRev)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(ops)) != toRev-fromRev {
+		return nil, entity.ErrResyncRequired
+	}
+	return ops, nil
+}
+
+// readOps đọc op log và lọc các op có revision trong khoảng (fromRev, toRev]
+func readOps(ctx context.Context, c redis.Cmdable, opsKey string, fromRev, toRev int64) ([]*entity.CollabOp, error) {
+	entries, err := c.LRange(ctx, opsKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []*entity.CollabOp
+	for _, entry := range entries {
+		var op entity.CollabOp
+		if err := json.Unmarshal([]byte(entry), &op); err != nil {
+			return nil, err
+		}
+		if op.Rev > fromRev && op.Rev <= toRev {
+			ops = append(ops, &op)
+		}
+	}
+	return ops, nil
+}
+
+// Snapshot implements entity.NoteCollabStore.
+func (s *NoteCollabStore) Snapshot(ctx context.Context, noteID uuid.UUID) (string, int64, bool, error) {
+	values, err := s.rdb.MGet(ctx, collabKey(noteID, "doc"), collabKey(noteID, "rev"), collabKey(noteID, "persisted")).Result()
+	if err != nil || values[0] == nil || values[1] == nil {
+		return "", 0, false, err
+	}
+
+	var rev, persisted int64
+	if _, err := fmt.Sscan(values[1].(string), &rev); err != nil {
+		return "", 0, false, err
+	}
+	if values[2] != nil {
+		if _, err := fmt.Sscan(values[2].(string), &persisted); err != nil {
+			return "", 0, false, err
+		}
+	}
+	if rev <= persisted {
+		return "", 0, false, nil
+	}
+
+	locked, err := s.rdb.SetNX(ctx, collabKey(noteID, "persist-lock"), rev, collabPersistLock).Result()
+	if err != nil || !locked {
+		return "", 0, false, err
+	}
+
+	// Đọc lại sau khi có lock: doc và rev phải cùng một thời điểm
+	values, err = s.rdb.MGet(ctx, collabKey(noteID, "doc"), collabKey(noteID, "rev")).Result()
+	if err != nil || values[0] == nil || values[1] == nil {
+		s.rdb.Del(ctx, collabKey(noteID, "persist-lock"))
+		return "", 0, false, err
+	}
+	if _, err := fmt.Sscan(values[1].(string), &rev); err != nil {
+		s.rdb.Del(ctx, collabKey(noteID, "persist-lock"))
+		return "", 0, false, err
+	}
+	return values[0].(string), rev, true, nil
+}
+
+// MarkPersisted implements entity.NoteCollabStore.
+func (s *NoteCollabStore) MarkPersisted(ctx context.Context, noteID uuid.UUID, rev int64) error {
+	_, err := s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, collabKey(noteID, "persisted"), rev, collabDraftTTL)
+		pipe.Del(ctx, collabKey(noteID, "persist-lock"))
+		return nil
+	})
+	return err
+}
+
+// Reset implements entity.NoteCollabStore.
+// Revision vẫn tăng để op của client dựa trên bản nháp cũ không thể transform qua op log và phải resync.
+func (s *NoteCollabStore) Reset(ctx context.Context, noteID uuid.UUID, doc string) (int64, error) {
+	docKey, revKey, opsKey := collabKey(noteID, "doc"), collabKey(noteID, "rev"), collabKey(noteID, "ops")
+
+	for attempt := 0; attempt < collabApplyRetries; attempt++ {
+		var rev int64
+		err := s.rdb.Watch(ctx, func(tx *redis.Tx) error {
+			current, err := tx.Get(ctx, revKey).Result()
+			if err != nil && !errors.Is(err, redis.Nil) {
+				return err
+			}
+			if err == nil {
+				if _, err := fmt.Sscan(current, &rev); err != nil {
+					return err
+				}
+			}
+			rev++
+
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, docKey, doc, collabDraftTTL)
+				pipe.Set(ctx, revKey, rev, collabDraftTTL)
+				pipe.Del(ctx, opsKey, collabKey(noteID, "persist-lock"))
+				pipe.Set(ctx, collabKey(noteID, "persisted"), rev, collabDraftTTL)
+				return nil
+			})
+			return err
+		}, docKey, revKey)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		return rev, err
+	}
+	return 0, fmt.Errorf("failed to reset collaborative draft of note %s: too much contention", noteID)
+}
+
+// Publish implements entity.NoteCollabStore.
+func (s *NoteCollabStore) Publish(ctx context.Context, noteID uuid.UUID, message []byte) error {
+	return s.rdb.Publish(ctx, collabKey(noteID, "events"), message).Err()
+}
+
+// Subscribe implements entity.NoteCollabStore. Hàm trả về dùng để huỷ subscription.
+func (s *NoteCollabStore) Subscribe(ctx context.Context, noteID uuid.UUID) (<-chan []byte, func(), error) {
+	pubsub := s.rdb.Subscribe(ctx, collabKey(noteID, "events"))
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, nil, err
+	}
+
+	messages := make(chan []byte, 256)
+	go func() {
+		defer close(messages)
+		for msg := range pubsub.Channel() {
+			messages <- []byte(msg.Payload)
+		}
+	}()
+
+	return messages, func() { _ = pubsub.Close() }, nil
+}
+
+// SetPresence implements entity.NoteCollabStore.
+func (s *NoteCollabStore) SetPresence(ctx context.Context, noteID uuid.UUID, presence *entity.CollabPresence) error {
+	data, err := json.Marshal(presence)
+	if err != nil {
+		return err
+	}
+	key := collabKey(noteID, "presence")
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, presence.SessionID, data)
+		pipe.Expire(ctx, key, collabDraftTTL)
+		return nil
+	})
+	return err
+}
+
+// RemovePresence implements entity.NoteCollabStore.
+func (s *NoteCollabStore) RemovePresence(ctx context.Context, noteID uuid.UUID, sessionID string) error {
+	return s.rdb.HDel(ctx, collabKey(noteID, "presence"), sessionID).Err()
+}
+
+// ListPresence implements entity.NoteCollabStore. Phiên quá hạn heartbeat bị bỏ qua và dọn đi.
+func (s *NoteCollabStore) ListPresence(ctx context.Context, noteID uuid.UUID) ([]*entity.CollabPresence, error) {
+	key := collabKey(noteID, "presence")
+	entries, err := s.rdb.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	presences := make([]*entity.CollabPresence, 0, len(entries))
+	var stale []string
+	for sessionID, data := range entries {
+		var p entity.CollabPresence
+		if err := json.Unmarshal([]byte(data), &p); err != nil || time.Since(p.SeenAt) > collabPresenceTimeout {
+			stale = append(stale, sessionID)
+			continue
+		}
+		presences = append(presences, &p)
+	}
+	if len(stale) > 0 {
+		s.rdb.HDel(ctx, key, stale...)
+	}
+	return presences, nil
+}
@@ -0,0 +1,156 @@
+package cache
+
+import (
+	"collab-service/internal/domain/entity"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// NotificationStore đọc inbox do collab-consumer ghi (internal/cache/notification.go bên collab-consumer):
+// ZSET notifications:{userId} giữ thứ tự, HASH :items giữ nội dung, SET :unread giữ các id chưa đọc.
+// Inbox bị giới hạn vài trăm phần tử nên lọc và phân trang được làm trong bộ nhớ.
+type NotificationStore struct {
+	rdb *redis.Client
+}
+
+func NewNotificationStore(rdb *redis.Client) entity.NotificationStore {
+	return &NotificationStore{rdb: rdb}
+}
+
+func notificationKey(userID uuid.UUID, suffix string) string {
+	if suffix == "" {
+		return fmt.Sprintf("notifications:%s", userID.String())
+	}
+	return fmt.Sprintf("notifications:%s:%s", userID.String(), suffix)
+}
+
+// List implements entity.NotificationStore.
+func (s *NotificationStore) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, offset, limit int) ([]*entity.Notification, int64, error) {
+	ids, err := s.rdb.ZRevRange(ctx, notificationKey(userID, ""), 0, -1).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err := s.unreadSet(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if unreadOnly {
+		filtered := ids[:0]
+		for _, id := range ids {
+			if unread[id] {
+				filtered = append(filtered, id)
+			}
+		}
+		ids = filtered
+	}
+
+	total := int64(len(ids))
+	if offset >= len(ids) {
+		return []*entity.Notification{}, total, nil
+	}
+	ids = ids[offset:min(offset+limit, len(ids))]
+
+	payloads, err := s.rdb.HMGet(ctx, notificationKey(userID, "items"), ids...).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	notifications := make([]*entity.Notification, 0, len(ids))
+	for i, payload := range payloads {
+		raw, ok := payload.(string)
+		if !ok {
+			// Item đã bị cắt khỏi inbox giữa hai lệnh đọc
+			continue
+		}
+		var n entity.Notification
+		if err := json.Unmarshal([]byte(raw), &n); err != nil {
+			return nil, 0, err
+		}
+		n.ID = ids[i]
+		n.Read = !unread[ids[i]]
+		notifications = append(notifications, &n)
+	}
+	return notifications, total, nil
+}
+
+// UnreadCount implements entity.NotificationStore.
+func (s *NotificationStore) UnreadCount(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.rdb.SCard(ctx, notificationKey(userID, "unread")).Result()
+}
+
+// SetRead implements entity.NotificationStore.
+func (s *NotificationStore) SetRead(ctx context.Context, userID uuid.UUID, id string, read bool) (bool, error) {
+	exists, err := s.rdb.HExists(ctx, notificationKey(userID, "items"), id).Result()
+	if err != nil || !exists {
+		return false, err
+	}
+	if read {
+		return true, s.rdb.SRem(ctx, notificationKey(userID, "unread"), id).Err()
+	}
+
+	// Giữ TTL của SET unread giống hai key còn lại của inbox
+	ttl, err := s.rdb.TTL(ctx, notificationKey(userID, "items")).Result()
+	if err != nil {
+		return false, err
+	}
+	_, err = s.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, notificationKey(userID, "unread"), id)
+		if ttl > 0 {
+			pipe.Expire(ctx, notificationKey(userID, "unread"), ttl)
+		}
+		return nil
+	})
+	return true, err
+}
+
+// MarkAllRead implements entity.NotificationStore.
+func (s *NotificationStore) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	return s.rdb.Del(ctx, notificationKey(userID, "unread")).Err()
+}
+
+func preferenceKey(userID uuid.UUID) string {
+	return fmt.Sprintf("notification_prefs:%s", userID.String())
+}
+
+// GetPreferences implements entity.NotificationStore.
+// Lựa chọn gửi email nằm ở HASH notification_prefs:{userId}, collab-consumer đọc khi gửi thông báo.
+func (s *NotificationStore) GetPreferences(ctx context.Context, userID uuid.UUID) (map[string]entity.NotificationDelivery, error) {
+	values, err := s.rdb.HGetAll(ctx, preferenceKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	preferences := make(map[string]entity.NotificationDelivery, len(values))
+	for eventType, delivery := range values {
+		preferences[eventType] = entity.NotificationDelivery(delivery)
+	}
+	return preferences, nil
+}
+
+// SetPreferences implements entity.NotificationStore.
+func (s *NotificationStore) SetPreferences(ctx context.Context, userID uuid.UUID, preferences map[string]entity.NotificationDelivery) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+	values := make(map[string]interface{}, len(preferences))
+	for eventType, delivery := range preferences {
+		values[eventType] = string(delivery)
+	}
+	return s.rdb.HSet(ctx, preferenceKey(userID), values).Err()
+}
+
+func (s *NotificationStore) unreadSet(ctx context.Context, userID uuid.UUID) (map[string]bool, error) {
+	members, err := s.rdb.SMembers(ctx, notificationKey(userID, "unread")).Result()
+	if err != nil {
+		return nil, err
+	}
+	unread := make(map[string]bool, len(members))
+	for _, id := range members {
+		unread[id] = true
+	}
+	return unread, nil
+}
@@ -3,6 +3,8 @@ package cache
 import (
 	"collab-service/internal/domain/entity"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -18,14 +20,21 @@ type TeamRepositoryWithCache struct {
 }
 
 type CachedMember struct {
-	UserID uuid.UUID             `json:"user_id"`
-	Role   entity.TeamAccessRole `json:"role"`
+	UserID    uuid.UUID             `json:"user_id"`
+	Role      entity.TeamAccessRole `json:"role"`
+	ExpiresAt *time.Time            `json:"expires_at,omitempty"`
+	JoinedAt  time.Time             `json:"joined_at"`
 }
 
 type CachedTeam struct {
-	ID      uuid.UUID      `json:"id"`
-	Name    string         `json:"name"`
-	Members []CachedMember `json:"members"`
+	ID         uuid.UUID      `json:"id"`
+	Name       string         `json:"name"`
+	ParentID   *uuid.UUID     `json:"parent_id,omitempty"`
+	Members    []CachedMember `json:"members"`
+	ArchivedAt *time.Time     `json:"archived_at,omitempty"`
+	ArchivedBy *uuid.UUID     `json:"archived_by,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
 }
 
 func NewTeamRepositoryWithCache(dbRepo entity.TeamRepository, rdb *redis.Client, ttl time.Duration) entity.TeamRepository {
@@ -36,8 +45,9 @@ func NewTeamRepositoryWithCache(dbRepo entity.TeamRepository, rdb *redis.Client,
 	}
 }
 
+// teamCacheKey không dùng chung key "team:%s:members" (set) mà collab-consumer đang ghi
 func teamCacheKey(teamID uuid.UUID) string {
-	return fmt.Sprintf("team:%s:members", teamID.String())
+	return fmt.Sprintf("team:%s", teamID.String())
 }
 
 //
@@ -45,44 +55,73 @@ func teamCacheKey(teamID uuid.UUID) string {
 //
 
 func (r *TeamRepositoryWithCache) cacheTeam(ctx context.Context, team *entity.Team) error {
-	memberIDs := make([]interface{}, len(team.Rosters))
+	cached := CachedTeam{
+		ID:         team.ID,
+		Name:       team.Name,
+		ParentID:   team.ParentID,
+		Members:    make([]CachedMember, len(team.Rosters)),
+		ArchivedAt: team.ArchivedAt,
+		ArchivedBy: team.ArchivedBy,
+		CreatedAt:  team.CreatedAt,
+		UpdatedAt:  team.UpdatedAt,
+	}
 	for i, ro := range team.Rosters {
-		memberIDs[i] = ro.UserID.String()
+		cached.Members[i] = CachedMember{UserID: ro.UserID, Role: ro.Role, ExpiresAt: ro.ExpiresAt, JoinedAt: ro.CreatedAt}
 	}
 
-	// ghi set members
-	if err := r.rdb.SAdd(ctx, teamCacheKey(team.ID), memberIDs...).Err(); err != nil {
+	data, err := json.Marshal(cached)
+	if err != nil {
 		return err
 	}
-	return nil
+	return r.rdb.Set(ctx, teamCacheKey(team.ID), data, r.ttl).Err()
 }
 
 func (r *TeamRepositoryWithCache) getCachedTeam(ctx context.Context, teamID uuid.UUID) (*entity.Team, error) {
-	userIDs, err := r.rdb.SMembers(ctx, teamCacheKey(teamID)).Result()
-	if len(userIDs) == 0 {
+	data, err := r.rdb.Get(ctx, teamCacheKey(teamID)).Bytes()
+	if errors.Is(err, redis.Nil) {
 		return nil, nil // cache miss
 	}
 	if err != nil {
 		return nil, err
 	}
 
-	rosters := make([]entity.Roster, len(userIDs))
-	for i, uid := range userIDs {
-		uidParsed, _ := uuid.Parse(uid)
-		rosters[i] = entity.Roster{
-			UserID: uidParsed,
-			TeamID: teamID,
-			// không có role
+	var cached CachedTeam
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, err
+	}
+
+	// Thành viên có thể hết hạn sau khi team được cache; bỏ qua họ giống Preload trong dbRepo
+	now := time.Now()
+	rosters := make([]entity.Roster, 0, len(cached.Members))
+	for _, m := range cached.Members {
+		if m.ExpiresAt != nil && !m.ExpiresAt.After(now) {
+			continue
 		}
+		rosters = append(rosters, entity.Roster{
+			UserID:    m.UserID,
+			TeamID:    teamID,
+			Role:      m.Role,
+			ExpiresAt: m.ExpiresAt,
+			CreatedAt: m.JoinedAt,
+		})
 	}
 
 	return &entity.Team{
-		ID:      teamID,
-		Name:    "", // Name không cache
-		Rosters: rosters,
+		ID:         cached.ID,
+		Name:       cached.Name,
+		ParentID:   cached.ParentID,
+		Rosters:    rosters,
+		ArchivedAt: cached.ArchivedAt,
+		ArchivedBy: cached.ArchivedBy,
+		CreatedAt:  cached.CreatedAt,
+		UpdatedAt:  cached.UpdatedAt,
 	}, nil
 }
 
+func (r *TeamRepositoryWithCache) invalidate(ctx context.Context, teamID uuid.UUID) {
+	_ = r.rdb.Del(ctx, teamCacheKey(teamID)).Err()
+}
+
 //
 // IMPLEMENT ENTITY.TEAMREPOSITORY
 //
@@ -107,10 +146,11 @@ func (r *TeamRepositoryWithCache) GetRole(ctx context.Context, teamID uuid.UUID,
 	return r.dbRepo.GetRole(ctx, teamID, userID)
 }
 
-func (r *TeamRepositoryWithCache) AddMembers(ctx context.Context, teamID uuid.UUID, members []uuid.UUID) error {
-	if err := r.dbRepo.AddMembers(ctx, teamID, members); err != nil {
+func (r *TeamRepositoryWithCache) AddMembers(ctx context.Context, teamID uuid.UUID, members []uuid.UUID, role entity.TeamAccessRole, expiresAt *time.Time) error {
+	if err := r.dbRepo.AddMembers(ctx, teamID, members, role, expiresAt); err != nil {
 		return err
 	}
+	r.invalidate(ctx, teamID)
 	return nil
 }
 
@@ -118,7 +158,7 @@ func (r *TeamRepositoryWithCache) AddManager(ctx context.Context, teamID uuid.UU
 	if err := r.dbRepo.AddManager(ctx, teamID, managerID); err != nil {
 		return err
 	}
-
+	r.invalidate(ctx, teamID)
 	return nil
 }
 
@@ -126,19 +166,59 @@ func (r *TeamRepositoryWithCache) RemoveMember(ctx context.Context, teamID uuid.
 	if err := r.dbRepo.RemoveMember(ctx, teamID, memberID); err != nil {
 		return err
 	}
-
+	r.invalidate(ctx, teamID)
 	return nil
 }
 
+// ListExpiredRosters implements entity.TeamRepository.
+func (r *TeamRepositoryWithCache) ListExpiredRosters(ctx context.Context, now time.Time) ([]*entity.Roster, error) {
+	return r.dbRepo.ListExpiredRosters(ctx, now)
+}
+
+// RemoveExpiredMember implements entity.TeamRepository.
+func (r *TeamRepositoryWithCache) RemoveExpiredMember(ctx context.Context, teamID uuid.UUID, memberID uuid.UUID, now time.Time) (bool, error) {
+	removed, err := r.dbRepo.RemoveExpiredMember(ctx, teamID, memberID, now)
+	if err != nil {
+		return false, err
+	}
+	if removed {
+		r.invalidate(ctx, teamID)
+	}
+	return removed, nil
+}
+
 // Create implements entity.TeamRepository.
 func (r *TeamRepositoryWithCache) Create(ctx context.Context, team *entity.Team) (*entity.Team, error) {
 	return r.dbRepo.Create(ctx, team)
 }
 
+// Archive implements entity.TeamRepository.
+func (r *TeamRepositoryWithCache) Archive(ctx context.Context, id uuid.UUID, archivedBy uuid.UUID) error {
+	if err := r.dbRepo.Archive(ctx, id, archivedBy); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+// Restore implements entity.TeamRepository.
+func (r *TeamRepositoryWithCache) Restore(ctx context.Context, id uuid.UUID) error {
+	if err := r.dbRepo.Restore(ctx, id); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+// ListArchivedBefore implements entity.TeamRepository.
+func (r *TeamRepositoryWithCache) ListArchivedBefore(ctx context.Context, cutoff time.Time) ([]uuid.UUID, error) {
+	return r.dbRepo.ListArchivedBefore(ctx, cutoff)
+}
+
 // Delete implements entity.TeamRepository.
 func (r *TeamRepositoryWithCache) Delete(ctx context.Context, id uuid.UUID) error {
 	// Invalidate cache before deletion
-	_ = r.rdb.Del(ctx, teamCacheKey(id)).Err()
+	r.invalidate(ctx, id)
 	return r.dbRepo.Delete(ctx, id)
 }
 
@@ -148,8 +228,8 @@ func (r *TeamRepositoryWithCache) ExistsByID(ctx context.Context, id uuid.UUID)
 }
 
 // GetAllByUserID implements entity.TeamRepository.
-func (r *TeamRepositoryWithCache) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Team, error) {
-	return r.dbRepo.GetAllByUserID(ctx, userID)
+func (r *TeamRepositoryWithCache) GetAllByUserID(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]*entity.Team, error) {
+	return r.dbRepo.GetAllByUserID(ctx, userID, includeArchived)
 }
 
 // List implements entity.TeamRepository.
@@ -160,5 +240,25 @@ func (r *TeamRepositoryWithCache) List(ctx context.Context) ([]*entity.Team, err
 // Update implements entity.TeamRepository.
 func (r *TeamRepositoryWithCache) Update(ctx context.Context, team *entity.Team) (*entity.Team, error) {
 	// Invalidate cache before update
+	r.invalidate(ctx, team.ID)
 	return r.dbRepo.Update(ctx, team)
 }
+
+// GetSubtree implements entity.TeamRepository.
+func (r *TeamRepositoryWithCache) GetSubtree(ctx context.Context, teamID uuid.UUID) ([]*entity.Team, error) {
+	return r.dbRepo.GetSubtree(ctx, teamID)
+}
+
+// GetAncestorIDs implements entity.TeamRepository.
+func (r *TeamRepositoryWithCache) GetAncestorIDs(ctx context.Context, teamID uuid.UUID) ([]uuid.UUID, error) {
+	return r.dbRepo.GetAncestorIDs(ctx, teamID)
+}
+
+// SetParent implements entity.TeamRepository.
+func (r *TeamRepositoryWithCache) SetParent(ctx context.Context, teamID uuid.UUID, parentID *uuid.UUID) error {
+	if err := r.dbRepo.SetParent(ctx, teamID, parentID); err != nil {
+		return err
+	}
+	r.invalidate(ctx, teamID)
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 	"encoding/json"
 	"sync"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // EventType represents the type of asset event
@@ -20,6 +22,11 @@ const (
 	FolderDeleted  EventType = "FOLDER_DELETED"
 	FolderShared   EventType = "FOLDER_SHARED"
 	FolderUnshared EventType = "FOLDER_UNSHARED"
+	FolderMoved    EventType = "FOLDER_MOVED"
+	FolderRestored EventType = "FOLDER_RESTORED"
+
+	FolderTeamShared   EventType = "FOLDER_TEAM_SHARED"
+	FolderTeamUnshared EventType = "FOLDER_TEAM_UNSHARED"
 
 	// Note events
 	NoteCreated  EventType = "NOTE_CREATED"
@@ -27,6 +34,26 @@ const (
 	NoteDeleted  EventType = "NOTE_DELETED"
 	NoteShared   EventType = "NOTE_SHARED"
 	NoteUnshared EventType = "NOTE_UNSHARED"
+	NoteMoved    EventType = "NOTE_MOVED"
+	NoteCopied   EventType = "NOTE_COPIED"
+	NoteRestored EventType = "NOTE_RESTORED"
+
+	NoteTeamShared   EventType = "NOTE_TEAM_SHARED"
+	NoteTeamUnshared EventType = "NOTE_TEAM_UNSHARED"
+
+	// Comment events
+	CommentAdded EventType = "COMMENT_ADDED"
+
+	// Mention events
+	UserMentioned EventType = "USER_MENTIONED"
+
+	// ShareExpiring nhắc user rằng share trên note/folder sắp hết hạn
+	ShareExpiring EventType = "SHARE_EXPIRING"
+
+	// Access request events; yêu cầu được duyệt còn phát NOTE_SHARED/FOLDER_SHARED nếu có share mới
+	AccessRequested       EventType = "ACCESS_REQUESTED"
+	AccessRequestApproved EventType = "ACCESS_REQUEST_APPROVED"
+	AccessRequestDenied   EventType = "ACCESS_REQUEST_DENIED"
 )
 
 type AssetType string
@@ -37,17 +64,27 @@ const (
 )
 
 type AssetEvent struct {
+	EventId     string             `json:"eventId"`
 	EventType   EventType          `json:"eventType"`
 	AssetType   AssetType          `json:"assetType"`
 	AssetId     string             `json:"assetId"`
 	OwnerId     string             `json:"ownerId"`
 	ActionBy    string             `json:"actionBy"`
 	AccessLevel entity.AccessLevel `json:"accessLevel"`
+	TeamId      string             `json:"teamId,omitempty"`
+	ParentId    string             `json:"parentId,omitempty"`
+	TagsAdded   []string           `json:"tagsAdded,omitempty"`
+	TagsRemoved []string           `json:"tagsRemoved,omitempty"`
+	CommentId   string             `json:"commentId,omitempty"`
+	ReplyTo     string             `json:"replyTo,omitempty"`
+	TargetUser  string             `json:"targetUserId,omitempty"`
+	ExpiresAt   string             `json:"expiresAt,omitempty"`
 	Timestamp   string             `json:"timestamp"`
 }
 
 func NewAssetEvent(eventType EventType, assetType AssetType, assetId, ownerId, actionBy, timestamp string, accessLevel entity.AccessLevel) *AssetEvent {
 	return &AssetEvent{
+		EventId:     uuid.NewString(),
 		EventType:   eventType,
 		AssetType:   assetType,
 		AssetId:     assetId,
@@ -58,6 +95,77 @@ func NewAssetEvent(eventType EventType, assetType AssetType, assetId, ownerId, a
 	}
 }
 
+// NewUnshareEvent tạo event NOTE_UNSHARED/FOLDER_UNSHARED khi actionBy thu hồi quyền của userId;
+// như event share, ownerId và targetUserId là user bị thu hồi quyền
+func NewUnshareEvent(eventType EventType, assetType AssetType, assetId, userId, actionBy, timestamp string) *AssetEvent {
+	e := NewAssetEvent(eventType, assetType, assetId, userId, actionBy, timestamp, entity.AccessLevelNone)
+	e.TargetUser = userId
+	return e
+}
+
+// NewTeamShareEvent tạo event share/unshare asset cho cả một team
+func NewTeamShareEvent(eventType EventType, assetType AssetType, assetId, teamId, actionBy, timestamp string, accessLevel entity.AccessLevel) *AssetEvent {
+	e := NewAssetEvent(eventType, assetType, assetId, "", actionBy, timestamp, accessLevel)
+	e.TeamId = teamId
+	return e
+}
+
+// NewMoveEvent tạo event di chuyển asset sang folder cha mới; parentId rỗng là gốc
+func NewMoveEvent(eventType EventType, assetType AssetType, assetId, parentId, actionBy, timestamp string) *AssetEvent {
+	e := NewAssetEvent(eventType, assetType, assetId, "", actionBy, timestamp, entity.AccessLevelNone)
+	e.ParentId = parentId
+	return e
+}
+
+// NewNoteTagsEvent tạo event NOTE_UPDATED cho thay đổi tag trên note, tagsAdded/tagsRemoved là ID của tag
+func NewNoteTagsEvent(assetId, ownerId, actionBy, timestamp string, tagsAdded, tagsRemoved []string) *AssetEvent {
+	e := NewAssetEvent(NoteUpdated, Note, assetId, ownerId, actionBy, timestamp, entity.AccessLevelNone)
+	e.TagsAdded = tagsAdded
+	e.TagsRemoved = tagsRemoved
+	return e
+}
+
+// NewCommentEvent tạo event COMMENT_ADDED trên note; replyTo là comment gốc của thread nếu comment là reply
+func NewCommentEvent(noteId, ownerId, actionBy, commentId, replyTo, timestamp string) *AssetEvent {
+	e := NewAssetEvent(CommentAdded, Note, noteId, ownerId, actionBy, timestamp, entity.AccessLevelNone)
+	e.CommentId = commentId
+	e.ReplyTo = replyTo
+	return e
+}
+
+// NewMentionEvent tạo event USER_MENTIONED khi targetUserId được @mention trong note, hoặc trong comment nếu commentId khác rỗng
+func NewMentionEvent(noteId, ownerId, actionBy, targetUserId, commentId, timestamp string) *AssetEvent {
+	e := NewAssetEvent(UserMentioned, Note, noteId, ownerId, actionBy, timestamp, entity.AccessLevelNone)
+	e.TargetUser = targetUserId
+	e.CommentId = commentId
+	return e
+}
+
+// NewShareExpiredEvent tạo event NOTE_UNSHARED/FOLDER_UNSHARED (hoặc *_TEAM_UNSHARED khi teamId khác rỗng)
+// khi sweeper xoá share đã hết hạn; ownerId và targetUserId là user mất quyền
+func NewShareExpiredEvent(eventType EventType, assetType AssetType, assetId, userId, teamId, timestamp string) *AssetEvent {
+	e := NewAssetEvent(eventType, assetType, assetId, userId, SystemActor, timestamp, entity.AccessLevelNone)
+	e.TeamId = teamId
+	e.TargetUser = userId
+	return e
+}
+
+// NewShareExpiringEvent tạo event SHARE_EXPIRING gửi cho userId, người có share sắp hết hạn vào expiresAt (RFC 3339)
+func NewShareExpiringEvent(assetType AssetType, assetId, userId, expiresAt, timestamp string, accessLevel entity.AccessLevel) *AssetEvent {
+	e := NewAssetEvent(ShareExpiring, assetType, assetId, userId, SystemActor, timestamp, accessLevel)
+	e.TargetUser = userId
+	e.ExpiresAt = expiresAt
+	return e
+}
+
+// NewAccessRequestEvent tạo event ACCESS_REQUESTED (targetUserId là owner), ACCESS_REQUEST_APPROVED hoặc
+// ACCESS_REQUEST_DENIED (targetUserId là người đã yêu cầu); accessLevel là quyền được yêu cầu hoặc được cấp
+func NewAccessRequestEvent(eventType EventType, assetType AssetType, assetId, ownerId, actionBy, targetUserId, timestamp string, accessLevel entity.AccessLevel) *AssetEvent {
+	e := NewAssetEvent(eventType, assetType, assetId, ownerId, actionBy, timestamp, accessLevel)
+	e.TargetUser = targetUserId
+	return e
+}
+
 type AssetChangeProducer struct {
 	Producer *kafka.Producer
 }
@@ -0,0 +1,90 @@
+package event
+
+import (
+	"collab-service/config"
+	"collab-service/internal/infrastructure/external/event/kafka"
+	"collab-service/internal/infrastructure/logger"
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// AssetEventHandler xử lý một AssetEvent đọc từ stream asset change; raw là payload gốc của message
+type AssetEventHandler func(ctx context.Context, e *AssetEvent, raw []byte) error
+
+// AssetChangeConsumer đọc lại các AssetEvent do AssetChangeProducer publish.
+// Các module đăng ký handler qua Subscribe, main gọi Start/Stop.
+type AssetChangeConsumer struct {
+	consumer *kafka.Consumer
+	handlers []AssetEventHandler
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+}
+
+var (
+	assetChangeConsumerInstance *AssetChangeConsumer
+	assetChangeConsumerOnce     sync.Once
+)
+
+func GetAssetChangeConsumer() *AssetChangeConsumer {
+	assetChangeConsumerOnce.Do(func() {
+		assetChangeConsumerInstance = &AssetChangeConsumer{}
+	})
+	return assetChangeConsumerInstance
+}
+
+func (c *AssetChangeConsumer) Subscribe(handler AssetEventHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers = append(c.handlers, handler)
+}
+
+// Start bắt đầu đọc stream nếu có handler đăng ký
+func (c *AssetChangeConsumer) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancel != nil || len(c.handlers) == 0 {
+		return
+	}
+
+	cfg := config.GetConfig()
+	c.consumer = kafka.NewConsumer(cfg.KafkaAddresses, cfg.AssetChangeTopic, cfg.AssetChangeGroupID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	handlers := append([]AssetEventHandler(nil), c.handlers...)
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.consumer.Run(ctx, func(ctx context.Context, _, value []byte) error {
+			var e AssetEvent
+			if err := json.Unmarshal(value, &e); err != nil {
+				// Message hỏng không thể xử lý lại, bỏ qua
+				logger.Error("Invalid asset event", "error", err.Error())
+				return nil
+			}
+			for _, handle := range handlers {
+				if err := handle(ctx, &e, value); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}()
+}
+
+func (c *AssetChangeConsumer) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancel == nil {
+		return
+	}
+	c.cancel()
+	c.wg.Wait()
+	if err := c.consumer.Close(); err != nil {
+		logger.Error("Failed to close asset change consumer", "error", err.Error())
+	}
+	c.cancel = nil
+}
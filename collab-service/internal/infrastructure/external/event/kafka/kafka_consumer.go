@@ -0,0 +1,58 @@
+package kafka
+
+import (
+	"collab-service/internal/infrastructure/logger"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// MessageHandler xử lý một message; trả lỗi để consumer thử lại
+type MessageHandler func(ctx context.Context, key, value []byte) error
+
+type Consumer struct {
+	reader     *kafka.Reader
+	maxRetries int
+}
+
+func NewConsumer(brokers []string, topic, groupID string) *Consumer {
+	logger.Info("Initializing Kafka consumer", "brokers", brokers, "topic", topic, "groupId", groupID)
+	return &Consumer{
+		reader:     NewReader(brokers, topic, groupID),
+		maxRetries: 3,
+	}
+}
+
+// Run đọc message cho tới khi ctx bị huỷ. Offset chỉ được commit sau khi handler xử lý xong;
+// message lỗi quá maxRetries lần sẽ được log và bỏ qua để không chặn partition.
+func (c *Consumer) Run(ctx context.Context, handler MessageHandler) {
+	for {
+		msg, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return
+			}
+			logger.Error("Failed to fetch message from kafka", "error", err.Error())
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for attempt := 1; attempt <= c.maxRetries; attempt++ {
+			if err = handler(ctx, msg.Key, msg.Value); err == nil {
+				break
+			}
+			logger.Error("Failed to handle kafka message", "topic", msg.Topic, "offset", msg.Offset, "attempt", attempt, "error", err.Error())
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+
+		if err := c.reader.CommitMessages(ctx, msg); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Error("Failed to commit kafka message", "error", err.Error())
+		}
+	}
+}
+
+func (c *Consumer) Close() error {
+	return c.reader.Close()
+}
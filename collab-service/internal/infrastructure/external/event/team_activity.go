@@ -2,34 +2,46 @@ package event
 
 import (
 	"collab-service/config"
+	"collab-service/internal/domain/entity"
 	"collab-service/internal/infrastructure/external/event/kafka"
 	"collab-service/internal/infrastructure/logger"
 	"context"
 	"encoding/json"
 	"sync"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type TeamEventType string
 
 const (
 	TeamCreated    TeamEventType = "TEAM_CREATED"
+	TeamArchived   TeamEventType = "TEAM_ARCHIVED"
+	TeamRestored   TeamEventType = "TEAM_RESTORED"
 	MemberAdded    TeamEventType = "MEMBER_ADDED"
 	MemberRemoved  TeamEventType = "MEMBER_REMOVED"
 	ManagerAdded   TeamEventType = "MANAGER_ADDED"
 	ManagerRemoved TeamEventType = "MANAGER_REMOVED"
 )
 
+// SystemActor is used as PerformedBy for changes made by background jobs rather than a user
+const SystemActor = "system"
+
 type TeamEvent struct {
+	EventID      string        `json:"eventId"`
 	EventType    TeamEventType `json:"eventType"`
 	TeamID       string        `json:"teamId"`
 	PerformedBy  string        `json:"performedBy"`
 	TargetUserID string        `json:"targetUserId,omitempty"`
-	Timestamp    string        `json:"timestamp"`
+	// MemberIDs lists the members affected by a team-wide change such as TEAM_ARCHIVED or TEAM_RESTORED
+	MemberIDs []string `json:"memberIds,omitempty"`
+	Timestamp string   `json:"timestamp"`
 }
 
 func NewTeamEvent(eventType TeamEventType, teamID, performedBy, targetUserID string) *TeamEvent {
 	return &TeamEvent{
+		EventID:      uuid.NewString(),
 		EventType:    eventType,
 		TeamID:       teamID,
 		PerformedBy:  performedBy,
@@ -38,6 +50,15 @@ func NewTeamEvent(eventType TeamEventType, teamID, performedBy, targetUserID str
 	}
 }
 
+// NewTeamMembersEvent creates a team-wide event that notifies every current member of the team
+func NewTeamMembersEvent(eventType TeamEventType, team *entity.Team, performedBy string) *TeamEvent {
+	e := NewTeamEvent(eventType, team.ID.String(), performedBy, "")
+	for _, roster := range team.Rosters {
+		e.MemberIDs = append(e.MemberIDs, roster.UserID.String())
+	}
+	return e
+}
+
 type TeamActivityProducer struct {
 	Producer *kafka.Producer
 }
@@ -0,0 +1,90 @@
+package event
+
+import (
+	"collab-service/config"
+	"collab-service/internal/infrastructure/external/event/kafka"
+	"collab-service/internal/infrastructure/logger"
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// TeamEventHandler xử lý một TeamEvent đọc từ stream team activity
+type TeamEventHandler func(ctx context.Context, e *TeamEvent) error
+
+// TeamActivityConsumer đọc lại các TeamEvent do TeamActivityProducer publish.
+// Các module đăng ký handler qua Subscribe, main gọi Start/Stop.
+type TeamActivityConsumer struct {
+	consumer *kafka.Consumer
+	handlers []TeamEventHandler
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+}
+
+var (
+	teamActivityConsumerInstance *TeamActivityConsumer
+	teamActivityConsumerOnce     sync.Once
+)
+
+func GetTeamActivityConsumer() *TeamActivityConsumer {
+	teamActivityConsumerOnce.Do(func() {
+		teamActivityConsumerInstance = &TeamActivityConsumer{}
+	})
+	return teamActivityConsumerInstance
+}
+
+func (c *TeamActivityConsumer) Subscribe(handler TeamEventHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers = append(c.handlers, handler)
+}
+
+// Start bắt đầu đọc stream nếu có handler đăng ký
+func (c *TeamActivityConsumer) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancel != nil || len(c.handlers) == 0 {
+		return
+	}
+
+	cfg := config.GetConfig()
+	c.consumer = kafka.NewConsumer(cfg.KafkaAddresses, cfg.TeamActivityTopic, cfg.TeamActivityGroupID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	handlers := append([]TeamEventHandler(nil), c.handlers...)
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.consumer.Run(ctx, func(ctx context.Context, _, value []byte) error {
+			var e TeamEvent
+			if err := json.Unmarshal(value, &e); err != nil {
+				// Message hỏng không thể xử lý lại, bỏ qua
+				logger.Error("Invalid team event", "error", err.Error())
+				return nil
+			}
+			for _, handle := range handlers {
+				if err := handle(ctx, &e); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}()
+}
+
+func (c *TeamActivityConsumer) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancel == nil {
+		return
+	}
+	c.cancel()
+	c.wg.Wait()
+	if err := c.consumer.Close(); err != nil {
+		logger.Error("Failed to close team activity consumer", "error", err.Error())
+	}
+	c.cancel = nil
+}
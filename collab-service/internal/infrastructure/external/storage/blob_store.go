@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"collab-service/config"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/logger"
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+var (
+	blobStoreInstance entity.BlobStore
+	blobStoreOnce     sync.Once
+)
+
+// GetBlobStore trả về blob store theo ATTACHMENT_STORAGE: "local" (mặc định) hoặc "s3"
+func GetBlobStore() entity.BlobStore {
+	blobStoreOnce.Do(func() {
+		cfg := config.GetConfig()
+		var err error
+		switch cfg.AttachmentStorage {
+		case "s3":
+			var store *S3Store
+			store, err = NewS3Store(S3Options{
+				Endpoint:  cfg.S3Endpoint,
+				Region:    cfg.S3Region,
+				Bucket:    cfg.S3Bucket,
+				AccessKey: cfg.S3AccessKey,
+				SecretKey: cfg.S3SecretKey,
+				PathStyle: cfg.S3PathStyle,
+			})
+			if err == nil {
+				ensureBucket(store)
+				blobStoreInstance = store
+			}
+		case "local", "":
+			blobStoreInstance, err = NewLocalStore(cfg.AttachmentLocalDir)
+		default:
+			log.Fatalf("unknown ATTACHMENT_STORAGE %q, expected local or s3", cfg.AttachmentStorage)
+		}
+		if err != nil {
+			log.Fatalf("failed to initialize attachment storage: %v", err)
+		}
+	})
+	return blobStoreInstance
+}
+
+// ensureBucket chỉ ghi log khi lỗi: storage có thể khởi động sau service, upload sẽ báo lỗi nếu bucket vẫn chưa có
+func ensureBucket(store *S3Store) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := store.EnsureBucket(ctx); err != nil {
+		logger.Warn("Failed to ensure attachment bucket", "error", err.Error())
+	}
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorePath(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{name: "nested key", key: "notes/abc/blob", want: "notes/abc/blob"},
+		{name: "dot segments inside the root", key: "notes/../blob", want: "blob"},
+		{name: "absolute key stays under the root", key: "/etc/passwd", want: "etc/passwd"},
+		{name: "parent directory", key: "../x", wantErr: true},
+		{name: "escape after a segment", key: "notes/../../x", wantErr: true},
+		{name: "root itself", key: "", wantErr: true},
+		{name: "dot dot", key: "..", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := store.path(tt.key)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(store.root, filepath.FromSlash(tt.want)), p)
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package storage
+
+import (
+	"bytes"
+	"collab-service/internal/domain/entity"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	s3Service         = "s3"
+	s3SigningAlgo     = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3AmzDateFormat   = "20060102T150405Z"
+)
+
+// S3Options cấu hình kết nối tới dịch vụ tương thích S3 (AWS S3, MinIO, ...)
+type S3Options struct {
+	// Endpoint dạng http(s)://host[:port]; bỏ trống dùng endpoint AWS của Region
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle dùng URL endpoint/bucket/key thay vì bucket.endpoint/key, cần cho MinIO
+	PathStyle bool
+}
+
+// S3Store lưu blob trên bucket S3, ký request bằng AWS Signature Version 4
+type S3Store struct {
+	opts     S3Options
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+func NewS3Store(opts S3Options) (*S3Store, error) {
+	if opts.Bucket == "" || opts.AccessKey == "" || opts.SecretKey == "" {
+		return nil, errors.New("s3 storage requires bucket, access key and secret key")
+	}
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", opts.Region)
+	}
+
+	endpoint, err := url.Parse(strings.TrimSuffix(opts.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", opts.Endpoint)
+	}
+
+	return &S3Store{
+		opts:     opts,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 5 * time.Minute},
+		now:      time.Now,
+	}, nil
+}
+
+// EnsureBucket tạo bucket nếu chưa tồn tại
+func (s *S3Store) EnsureBucket(ctx context.Context) error {
+	resp, err := s.do(ctx, http.MethodHead, "", nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("s3 head bucket %s: unexpected status %s", s.opts.Bucket, resp.Status)
+	}
+
+	var body []byte
+	if s.opts.Region != "us-east-1" {
+		body = []byte(`<CreateBucketConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><LocationConstraint>` +
+			s.opts.Region + `</LocationConstraint></CreateBucketConfiguration>`)
+	}
+	resp, err = s.do(ctx, http.MethodPut, "", body, nil)
+	if err != nil {
+		return err
+	}
+	return s3Error(resp, "create bucket", http.StatusOK)
+}
+
+// Put implements entity.BlobStore.
+func (s *S3Store) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	if size < 0 {
+		return fmt.Errorf("s3 put %s: size is required", key)
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, key, content)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	// Nội dung được stream nên không băm trước được; checksum đã được tính ở tầng service
+	s.sign(req, s3UnsignedPayload)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	return s3Error(resp, "put "+key, http.StatusOK)
+}
+
+// Get implements entity.BlobStore.
+func (s *S3Store) Get(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	if length == 0 {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	header := http.Header{}
+	if offset > 0 || length > 0 {
+		rng := fmt.Sprintf("bytes=%d-", offset)
+		if length > 0 {
+			rng += fmt.Sprint(offset + length - 1)
+		}
+		header.Set("Range", rng)
+	}
+
+	resp, err := s.do(ctx, http.MethodGet, key, nil, header)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, entity.ErrBlobNotFound
+	default:
+		return nil, s3Error(resp, "get "+key)
+	}
+}
+
+// Delete implements entity.BlobStore.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	return s3Error(resp, "delete "+key, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+}
+
+// do gửi request đã ký với body nhỏ nằm sẵn trong bộ nhớ (hoặc không có body)
+func (s *S3Store) do(ctx context.Context, method, key string, body []byte, header http.Header) (*http.Response, error) {
+	req, err := s.newRequest(ctx, method, key, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	if len(body) == 0 {
+		req.Body = http.NoBody
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	hash := sha256.Sum256(body)
+	s.sign(req, hex.EncodeToString(hash[:]))
+	return s.client.Do(req)
+}
+
+func (s *S3Store) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	u := *s.endpoint
+	path := strings.TrimSuffix(u.Path, "/")
+	if s.opts.PathStyle {
+		path += "/" + s.opts.Bucket
+	} else {
+		u.Host = s.opts.Bucket + "." + u.Host
+	}
+	if key != "" {
+		path += "/" + key
+	}
+	if path == "" {
+		path = "/"
+	}
+	u.Path = path
+	u.RawPath = s3EscapePath(path)
+
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+// sign thêm header Authorization theo AWS Signature Version 4.
+// Chỉ ký host và các header x-amz-*, đủ cho các thao tác object đơn giản.
+func (s *S3Store) sign(req *http.Request, payloadHash string) {
+	t := s.now().UTC()
+	amzDate := t.Format(s3AmzDateFormat)
+	date := t.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.opts.Region, s3Service, "aws4_request"}, "/")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{s3SigningAlgo, amzDate, scope, hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.opts.SecretKey), date)
+	key = hmacSHA256(key, s.opts.Region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SigningAlgo, s.opts.AccessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3EscapePath encode từng ký tự ngoài tập unreserved của RFC 3986, giữ nguyên dấu /
+func s3EscapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// s3Error đóng response và trả lỗi kèm nội dung lỗi của S3 nếu status không nằm trong ok
+func s3Error(resp *http.Response, op string, ok ...int) error {
+	defer resp.Body.Close()
+	for _, code := range ok {
+		if resp.StatusCode == code {
+			io.Copy(io.Discard, resp.Body)
+			return nil
+		}
+	}
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 %s: %s: %s", op, resp.Status, strings.TrimSpace(string(detail)))
+}
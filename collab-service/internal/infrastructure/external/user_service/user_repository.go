@@ -150,7 +150,7 @@ func (u *UserRepositoryImpl) List(ctx context.Context, userType *entity.UserType
 	}
 
 	// Pass nil for UserType if you want to get users of any type
-	users, err := u.client.GetUsers(ctx, userType, userIDStrings)
+	users, err := u.client.GetUsers(ctx, userType, userIDStrings, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -164,6 +164,46 @@ func (u *UserRepositoryImpl) List(ctx context.Context, userType *entity.UserType
 	return domainUsers, nil
 }
 
+// ListByEmails implements entity.UserRepository.
+// Các email được resolve trong một request duy nhất tới user-service.
+func (u *UserRepositoryImpl) ListByEmails(ctx context.Context, emails []string) ([]*entity.User, error) {
+	if len(emails) == 0 {
+		return []*entity.User{}, nil
+	}
+
+	users, err := u.client.GetUsers(ctx, nil, nil, emails, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	domainUsers := make([]*entity.User, len(users))
+	for i, user := range users {
+		domainUsers[i] = user.ToDomain()
+	}
+
+	return domainUsers, nil
+}
+
+// ListByUsernames implements entity.UserRepository.
+// So khớp username không phân biệt hoa thường, username không tồn tại bị bỏ qua.
+func (u *UserRepositoryImpl) ListByUsernames(ctx context.Context, usernames []string) ([]*entity.User, error) {
+	if len(usernames) == 0 {
+		return []*entity.User{}, nil
+	}
+
+	users, err := u.client.GetUsers(ctx, nil, nil, nil, usernames)
+	if err != nil {
+		return nil, err
+	}
+
+	domainUsers := make([]*entity.User, len(users))
+	for i, user := range users {
+		domainUsers[i] = user.ToDomain()
+	}
+
+	return domainUsers, nil
+}
+
 // Update implements entity.UserRepository.
 func (u *UserRepositoryImpl) Update(ctx context.Context, user *entity.User) (*entity.User, error) {
 	panic("unimplemented")
@@ -111,7 +111,7 @@ func (c *GraphQLClient) doRequest(req *graphql.Request, respData interface{}) er
 }
 
 // Methods
-func (c *GraphQLClient) GetUsers(ctx context.Context, role *entity.UserType, userIDs []string) ([]UserModel, error) {
+func (c *GraphQLClient) GetUsers(ctx context.Context, role *entity.UserType, userIDs []string, emails []string, usernames []string) ([]UserModel, error) {
 	req := graphql.NewRequest(queries.GetUsers)
 	if role != nil {
 		req.Var("role", *role)
@@ -119,14 +119,22 @@ func (c *GraphQLClient) GetUsers(ctx context.Context, role *entity.UserType, use
 	if len(userIDs) > 0 {
 		req.Var("userIds", userIDs)
 	}
+	if len(emails) > 0 {
+		req.Var("emails", emails)
+	}
+	if len(usernames) > 0 {
+		req.Var("usernames", usernames)
+	}
 	var resp struct {
-		Users []UserModel `json:"users"`
+		Users struct {
+			Users []UserModel `json:"users"`
+		} `json:"users"`
 	}
 	if ctx == nil {
 		ctx = context.Background()
 	}
 	err := c.client.Run(ctx, req, &resp)
-	return resp.Users, err
+	return resp.Users.Users, err
 }
 
 func (c *GraphQLClient) GetUser(ctx context.Context, userID uuid.UUID) (*entity.User, error) {
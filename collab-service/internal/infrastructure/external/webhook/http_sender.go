@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"bytes"
+	"collab-service/internal/domain/entity"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// maxResponseSnippet là số byte đầu của body response được giữ lại trong delivery log
+const maxResponseSnippet = 512
+
+// HTTPSender gửi webhook qua HTTP. Khi allowPrivate=false, kết nối tới địa chỉ loopback, mạng riêng
+// hoặc link-local bị chặn ở bước dial để URL do team nhập không chạm được vào hạ tầng nội bộ.
+type HTTPSender struct {
+	client *http.Client
+}
+
+func NewHTTPSender(timeout time.Duration, allowPrivate bool) entity.WebhookSender {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = blockPrivateAddresses
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+
+	return &HTTPSender{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// Không đi theo redirect: receiver phải trả lời trực tiếp tại URL đã đăng ký
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send implements entity.WebhookSender.
+func (s *HTTPSender) Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseSnippet))
+	// Đọc hết phần còn lại (có giới hạn) để connection được dùng lại
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, string(snippet), nil
+}
+
+func blockPrivateAddresses(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("%w: %s", entity.ErrWebhookAddressBlocked, host)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"collab-service/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccessRequestModel không có khoá ngoại tới asset vì asset có thể là note hoặc folder;
+// yêu cầu được xoá cùng asset khi asset bị xoá vĩnh viễn.
+// Unique index một phần đảm bảo mỗi user chỉ có một yêu cầu PENDING trên một asset.
+type AccessRequestModel struct {
+	ID          uuid.UUID          `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	AssetType   string             `gorm:"type:varchar(16);not null;index:idx_access_requests_asset,priority:1;uniqueIndex:idx_access_requests_pending,priority:1,where:status = 'PENDING'"`
+	AssetID     uuid.UUID          `gorm:"type:uuid;not null;index:idx_access_requests_asset,priority:2;uniqueIndex:idx_access_requests_pending,priority:2,where:status = 'PENDING'"`
+	RequesterID uuid.UUID          `gorm:"type:uuid;not null;index;uniqueIndex:idx_access_requests_pending,priority:3,where:status = 'PENDING'"`
+	AccessLevel entity.AccessLevel `gorm:"type:varchar(10);not null"`
+	Message     string             `gorm:"type:varchar(500)"`
+	Status      string             `gorm:"type:varchar(16);not null;default:'PENDING'"`
+	DecidedBy   *uuid.UUID         `gorm:"type:uuid"`
+	DecidedAt   *time.Time
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+func (AccessRequestModel) TableName() string {
+	return "access_requests"
+}
+
+func (m *AccessRequestModel) ToDomain() *entity.AccessRequest {
+	return &entity.AccessRequest{
+		ID:          m.ID,
+		AssetType:   entity.AccessRequestAssetType(m.AssetType),
+		AssetID:     m.AssetID,
+		RequesterID: m.RequesterID,
+		AccessLevel: m.AccessLevel,
+		Message:     m.Message,
+		Status:      entity.AccessRequestStatus(m.Status),
+		DecidedBy:   m.DecidedBy,
+		DecidedAt:   m.DecidedAt,
+		CreatedAt:   m.CreatedAt,
+	}
+}
+
+func AccessRequestModelFromDomain(r *entity.AccessRequest) *AccessRequestModel {
+	return &AccessRequestModel{
+		ID:          r.ID,
+		AssetType:   string(r.AssetType),
+		AssetID:     r.AssetID,
+		RequesterID: r.RequesterID,
+		AccessLevel: r.AccessLevel,
+		Message:     r.Message,
+		Status:      string(r.Status),
+		DecidedBy:   r.DecidedBy,
+		DecidedAt:   r.DecidedAt,
+		CreatedAt:   r.CreatedAt,
+	}
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"collab-service/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AttachmentModel struct {
+	ID         uuid.UUID  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	NoteID     uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Note       *NoteModel `gorm:"foreignKey:NoteID;references:ID"`
+	FileName   string     `gorm:"type:varchar(255);not null"`
+	MimeType   string     `gorm:"type:varchar(255);not null"`
+	Size       int64      `gorm:"not null"`
+	Checksum   string     `gorm:"type:varchar(64);not null"`
+	StorageKey string     `gorm:"type:varchar(512);not null"`
+	UploadedBy uuid.UUID  `gorm:"type:uuid;not null"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+}
+
+func (AttachmentModel) TableName() string {
+	return "attachments"
+}
+
+func (m *AttachmentModel) ToDomain() *entity.Attachment {
+	return &entity.Attachment{
+		ID:         m.ID,
+		NoteID:     m.NoteID,
+		FileName:   m.FileName,
+		MimeType:   m.MimeType,
+		Size:       m.Size,
+		Checksum:   m.Checksum,
+		StorageKey: m.StorageKey,
+		UploadedBy: m.UploadedBy,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+func AttachmentModelFromDomain(a *entity.Attachment) *AttachmentModel {
+	return &AttachmentModel{
+		ID:         a.ID,
+		NoteID:     a.NoteID,
+		FileName:   a.FileName,
+		MimeType:   a.MimeType,
+		Size:       a.Size,
+		Checksum:   a.Checksum,
+		StorageKey: a.StorageKey,
+		UploadedBy: a.UploadedBy,
+		CreatedAt:  a.CreatedAt,
+	}
+}
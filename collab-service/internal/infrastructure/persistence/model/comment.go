@@ -0,0 +1,79 @@
+package model
+
+import (
+	"collab-service/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CommentModel struct {
+	ID       uuid.UUID     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	NoteID   uuid.UUID     `gorm:"type:uuid;not null;index"`
+	Note     *NoteModel    `gorm:"foreignKey:NoteID;references:ID"`
+	ParentID *uuid.UUID    `gorm:"type:uuid;index"`
+	Parent   *CommentModel `gorm:"foreignKey:ParentID;references:ID"`
+	AuthorID uuid.UUID     `gorm:"type:uuid;not null"`
+	Body     string        `gorm:"type:text;not null"`
+
+	AnchorStart *int
+	AnchorEnd   *int
+	AnchorQuote *string `gorm:"type:text"`
+
+	ResolvedAt *time.Time
+	ResolvedBy *uuid.UUID `gorm:"type:uuid"`
+	EditedAt   *time.Time
+	DeletedAt  *time.Time
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (CommentModel) TableName() string {
+	return "comments"
+}
+
+func (m *CommentModel) ToDomain() *entity.Comment {
+	comment := &entity.Comment{
+		ID:         m.ID,
+		NoteID:     m.NoteID,
+		ParentID:   m.ParentID,
+		AuthorID:   m.AuthorID,
+		Body:       m.Body,
+		ResolvedAt: m.ResolvedAt,
+		ResolvedBy: m.ResolvedBy,
+		EditedAt:   m.EditedAt,
+		DeletedAt:  m.DeletedAt,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+	if m.AnchorStart != nil && m.AnchorEnd != nil {
+		comment.Anchor = &entity.CommentAnchor{Start: *m.AnchorStart, End: *m.AnchorEnd}
+		if m.AnchorQuote != nil {
+			comment.Anchor.Quote = *m.AnchorQuote
+		}
+	}
+	return comment
+}
+
+func CommentModelFromDomain(c *entity.Comment) *CommentModel {
+	m := &CommentModel{
+		ID:         c.ID,
+		NoteID:     c.NoteID,
+		ParentID:   c.ParentID,
+		AuthorID:   c.AuthorID,
+		Body:       c.Body,
+		ResolvedAt: c.ResolvedAt,
+		ResolvedBy: c.ResolvedBy,
+		EditedAt:   c.EditedAt,
+		DeletedAt:  c.DeletedAt,
+		CreatedAt:  c.CreatedAt,
+		UpdatedAt:  c.UpdatedAt,
+	}
+	if c.Anchor != nil {
+		m.AnchorStart = &c.Anchor.Start
+		m.AnchorEnd = &c.Anchor.End
+		m.AnchorQuote = &c.Anchor.Quote
+	}
+	return m
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type FolderModel struct {
@@ -12,9 +13,17 @@ type FolderModel struct {
 
 	Name string `gorm:"type:varchar(255);not null"`
 
+	ParentID *uuid.UUID   `gorm:"type:uuid;index"`
+	Parent   *FolderModel `gorm:"foreignKey:ParentID;references:ID"`
+
+	Version int64 `gorm:"not null;default:1"`
+
 	Notes  []NoteModel        `gorm:"foreignKey:FolderID;references:ID"`
 	Shared []FolderShareModel `gorm:"foreignKey:FolderID;references:ID"`
 
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+	DeletedBy *uuid.UUID     `gorm:"type:uuid"`
+
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime"`
 }
@@ -25,10 +34,14 @@ func (FolderModel) TableName() string {
 
 func (m *FolderModel) ToDomain() *entity.Folder {
 	folder := &entity.Folder{
-		ID:     m.ID,
-		Name:   m.Name,
-		Notes:  make([]entity.Note, len(m.Notes)),
-		Shared: make([]entity.FolderShare, len(m.Shared)),
+		ID:        m.ID,
+		Name:      m.Name,
+		ParentID:  m.ParentID,
+		Version:   m.Version,
+		Notes:     make([]entity.Note, len(m.Notes)),
+		Shared:    make([]entity.FolderShare, len(m.Shared)),
+		DeletedAt: deletedAtToDomain(m.DeletedAt),
+		DeletedBy: m.DeletedBy,
 	}
 
 	for i, note := range m.Notes {
@@ -46,10 +59,14 @@ func (m *FolderModel) ToDomain() *entity.Folder {
 
 func FolderModelFromDomain(folderEntity *entity.Folder) *FolderModel {
 	m := &FolderModel{
-		ID:     folderEntity.ID,
-		Name:   folderEntity.Name,
-		Notes:  make([]NoteModel, len(folderEntity.Notes)),
-		Shared: make([]FolderShareModel, len(folderEntity.Shared)),
+		ID:        folderEntity.ID,
+		Name:      folderEntity.Name,
+		ParentID:  folderEntity.ParentID,
+		Version:   folderEntity.Version,
+		Notes:     make([]NoteModel, len(folderEntity.Notes)),
+		Shared:    make([]FolderShareModel, len(folderEntity.Shared)),
+		DeletedAt: deletedAtFromDomain(folderEntity.DeletedAt),
+		DeletedBy: folderEntity.DeletedBy,
 	}
 	for i, note := range folderEntity.Notes {
 		m.Notes[i] = *NoteModelFromDomain(&note)
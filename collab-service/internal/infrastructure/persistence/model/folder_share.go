@@ -8,12 +8,16 @@ import (
 )
 
 type FolderShareModel struct {
-	ID       uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	FolderID uuid.UUID `gorm:"type:uuid;not null;index"`
-	UserID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	ID       uuid.UUID  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	FolderID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	UserID   *uuid.UUID `gorm:"type:uuid;index"`
+	TeamID   *uuid.UUID `gorm:"type:uuid;index"`
 
 	AccessLevel entity.AccessLevel `gorm:"type:varchar(10);not null;default:'READ'"`
 
+	ExpiresAt      *time.Time `gorm:"index"`
+	ReminderSentAt *time.Time
+
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime"`
 }
@@ -23,19 +27,34 @@ func (FolderShareModel) TableName() string {
 }
 
 func (m *FolderShareModel) ToDomain() *entity.FolderShare {
-	return &entity.FolderShare{
+	share := &entity.FolderShare{
 		ID:          m.ID,
 		FolderID:    m.FolderID,
-		UserID:      m.UserID,
+		TeamID:      m.TeamID,
 		AccessLevel: m.AccessLevel,
+		ExpiresAt:   m.ExpiresAt,
+	}
+	if m.UserID != nil {
+		share.UserID = *m.UserID
 	}
+	return share
 }
 
 func FolderShareModelFromDomain(folderShareEntity *entity.FolderShare) *FolderShareModel {
 	return &FolderShareModel{
 		ID:          folderShareEntity.ID,
 		FolderID:    folderShareEntity.FolderID,
-		UserID:      folderShareEntity.UserID,
+		UserID:      sharePrincipal(folderShareEntity.UserID),
+		TeamID:      folderShareEntity.TeamID,
 		AccessLevel: folderShareEntity.AccessLevel,
+		ExpiresAt:   folderShareEntity.ExpiresAt,
+	}
+}
+
+// sharePrincipal chuyển user ID của domain sang cột nullable; uuid.Nil nghĩa là share cho team
+func sharePrincipal(userID uuid.UUID) *uuid.UUID {
+	if userID == uuid.Nil {
+		return nil
 	}
+	return &userID
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type NoteModel struct {
@@ -18,6 +19,12 @@ type NoteModel struct {
 
 	Shared []NoteShareModel `gorm:"foreignKey:NoteID;references:ID"`
 
+	Version int64 `gorm:"not null;default:1"`
+
+	// Soft delete: gorm tự loại các note đã vào thùng rác khỏi mọi query trừ khi dùng Unscoped
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+	DeletedBy *uuid.UUID     `gorm:"type:uuid"`
+
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime"`
 }
@@ -44,6 +51,9 @@ func (m *NoteModel) ToDomain() *entity.Note {
 		FolderID:  m.FolderID,
 		Folder:    folder,
 		Shared:    shared,
+		Version:   m.Version,
+		DeletedAt: deletedAtToDomain(m.DeletedAt),
+		DeletedBy: m.DeletedBy,
 		CreatedAt: m.CreatedAt,
 		UpdatedAt: m.UpdatedAt,
 	}
@@ -66,8 +76,25 @@ func NoteModelFromDomain(noteEntity *entity.Note) *NoteModel {
 		FolderID:  noteEntity.FolderID,
 		Folder:    folderModel,
 		Shared:    shared,
+		Version:   noteEntity.Version,
+		DeletedAt: deletedAtFromDomain(noteEntity.DeletedAt),
+		DeletedBy: noteEntity.DeletedBy,
 		CreatedAt: noteEntity.CreatedAt,
 		UpdatedAt: noteEntity.UpdatedAt,
 	}
 	return m
 }
+
+func deletedAtToDomain(deletedAt gorm.DeletedAt) *time.Time {
+	if !deletedAt.Valid {
+		return nil
+	}
+	return &deletedAt.Time
+}
+
+func deletedAtFromDomain(deletedAt *time.Time) gorm.DeletedAt {
+	if deletedAt == nil {
+		return gorm.DeletedAt{}
+	}
+	return gorm.DeletedAt{Time: *deletedAt, Valid: true}
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"collab-service/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NoteRevisionModel struct {
+	ID           uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	NoteID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_note_revisions_note_number,priority:1"`
+	Number       int       `gorm:"not null;uniqueIndex:idx_note_revisions_note_number,priority:2"`
+	Title        string    `gorm:"type:varchar(255);not null"`
+	Body         string    `gorm:"type:text;not null"`
+	ContentHash  string    `gorm:"type:varchar(64);not null"`
+	AuthorID     uuid.UUID `gorm:"type:uuid;not null"`
+	RestoredFrom *int
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+func (NoteRevisionModel) TableName() string {
+	return "note_revisions"
+}
+
+func (m *NoteRevisionModel) ToDomain() *entity.NoteRevision {
+	return &entity.NoteRevision{
+		ID:           m.ID,
+		NoteID:       m.NoteID,
+		Number:       m.Number,
+		Title:        m.Title,
+		Body:         m.Body,
+		ContentHash:  m.ContentHash,
+		AuthorID:     m.AuthorID,
+		RestoredFrom: m.RestoredFrom,
+		CreatedAt:    m.CreatedAt,
+	}
+}
+
+func NoteRevisionModelFromDomain(r *entity.NoteRevision) *NoteRevisionModel {
+	return &NoteRevisionModel{
+		ID:           r.ID,
+		NoteID:       r.NoteID,
+		Number:       r.Number,
+		Title:        r.Title,
+		Body:         r.Body,
+		ContentHash:  r.ContentHash,
+		AuthorID:     r.AuthorID,
+		RestoredFrom: r.RestoredFrom,
+	}
+}
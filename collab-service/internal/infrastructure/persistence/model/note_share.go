@@ -8,12 +8,16 @@ import (
 )
 
 type NoteShareModel struct {
-	ID     uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
-	NoteID uuid.UUID `gorm:"type:uuid;not null;index"`
-	UserID uuid.UUID `gorm:"type:uuid;not null;index"`
+	ID     uuid.UUID  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	NoteID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	UserID *uuid.UUID `gorm:"type:uuid;index"`
+	TeamID *uuid.UUID `gorm:"type:uuid;index"`
 
 	AccessLevel entity.AccessLevel `gorm:"type:varchar(10);not null;default:'READ'"`
 
+	ExpiresAt      *time.Time `gorm:"index"`
+	ReminderSentAt *time.Time
+
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 }
 
@@ -22,21 +26,28 @@ func (NoteShareModel) TableName() string {
 }
 
 func (m *NoteShareModel) ToDomain() *entity.NoteShare {
-	return &entity.NoteShare{
+	share := &entity.NoteShare{
 		ID:          m.ID,
 		NoteID:      m.NoteID,
-		UserID:      m.UserID,
+		TeamID:      m.TeamID,
 		AccessLevel: m.AccessLevel,
+		ExpiresAt:   m.ExpiresAt,
 		CreatedAt:   m.CreatedAt,
 	}
+	if m.UserID != nil {
+		share.UserID = *m.UserID
+	}
+	return share
 }
 
 func NoteShareModelFromDomain(noteShareEntity *entity.NoteShare) *NoteShareModel {
 	return &NoteShareModel{
 		ID:          noteShareEntity.ID,
 		NoteID:      noteShareEntity.NoteID,
-		UserID:      noteShareEntity.UserID,
+		UserID:      sharePrincipal(noteShareEntity.UserID),
+		TeamID:      noteShareEntity.TeamID,
 		AccessLevel: noteShareEntity.AccessLevel,
+		ExpiresAt:   noteShareEntity.ExpiresAt,
 		CreatedAt:   noteShareEntity.CreatedAt,
 	}
 }
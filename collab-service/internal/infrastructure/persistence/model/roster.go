@@ -13,6 +13,7 @@ type RosterModel struct {
 	TeamID    uuid.UUID             `gorm:"type:uuid;index;uniqueIndex:idx_team_user"`
 	Team      *TeamModel            `gorm:"foreignKey:TeamID;references:ID"`
 	Role      entity.TeamAccessRole `gorm:"type:varchar(20);default:'MEMBER'"`
+	ExpiresAt *time.Time            `gorm:"index"`
 	CreatedAt time.Time             `gorm:"autoCreateTime"`
 	UpdatedAt time.Time             `gorm:"autoUpdateTime"`
 
@@ -27,19 +28,23 @@ func (RosterModel) TableName() string {
 // Convert RosterModel -> domain.Roster
 func (m *RosterModel) ToDomain() *entity.Roster {
 	return &entity.Roster{
-		ID:     m.ID,
-		UserID: m.UserID,
-		TeamID: m.TeamID,
-		Role:   m.Role,
+		ID:        m.ID,
+		UserID:    m.UserID,
+		TeamID:    m.TeamID,
+		Role:      m.Role,
+		ExpiresAt: m.ExpiresAt,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
 	}
 }
 
 // Convert entity.Roster -> RosterModel
 func RosterModelFromDomain(r *entity.Roster) *RosterModel {
 	return &RosterModel{
-		ID:     r.ID,
-		UserID: r.UserID,
-		TeamID: r.TeamID,
-		Role:   r.Role,
+		ID:        r.ID,
+		UserID:    r.UserID,
+		TeamID:    r.TeamID,
+		Role:      r.Role,
+		ExpiresAt: r.ExpiresAt,
 	}
 }
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"collab-service/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShareLinkModel không có khoá ngoại tới asset vì asset có thể là note hoặc folder;
+// link được xoá cùng asset khi asset bị xoá vĩnh viễn
+type ShareLinkModel struct {
+	ID           uuid.UUID  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	AssetType    string     `gorm:"type:varchar(16);not null;index:idx_share_links_asset,priority:1"`
+	AssetID      uuid.UUID  `gorm:"type:uuid;not null;index:idx_share_links_asset,priority:2"`
+	TokenHash    string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	PasswordHash string     `gorm:"type:varchar(72)"`
+	ExpiresAt    *time.Time `gorm:"index"`
+	ViewCount    int64      `gorm:"not null;default:0"`
+	LastViewedAt *time.Time
+	RevokedAt    *time.Time
+	CreatedBy    uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+func (ShareLinkModel) TableName() string {
+	return "share_links"
+}
+
+func (m *ShareLinkModel) ToDomain() *entity.ShareLink {
+	return &entity.ShareLink{
+		ID:           m.ID,
+		AssetType:    entity.ShareLinkAssetType(m.AssetType),
+		AssetID:      m.AssetID,
+		TokenHash:    m.TokenHash,
+		PasswordHash: m.PasswordHash,
+		ExpiresAt:    m.ExpiresAt,
+		ViewCount:    m.ViewCount,
+		LastViewedAt: m.LastViewedAt,
+		RevokedAt:    m.RevokedAt,
+		CreatedBy:    m.CreatedBy,
+		CreatedAt:    m.CreatedAt,
+	}
+}
+
+func ShareLinkModelFromDomain(l *entity.ShareLink) *ShareLinkModel {
+	return &ShareLinkModel{
+		ID:           l.ID,
+		AssetType:    string(l.AssetType),
+		AssetID:      l.AssetID,
+		TokenHash:    l.TokenHash,
+		PasswordHash: l.PasswordHash,
+		ExpiresAt:    l.ExpiresAt,
+		ViewCount:    l.ViewCount,
+		LastViewedAt: l.LastViewedAt,
+		RevokedAt:    l.RevokedAt,
+		CreatedBy:    l.CreatedBy,
+		CreatedAt:    l.CreatedAt,
+	}
+}
+
+type ShareLinkAccessModel struct {
+	ID        uuid.UUID       `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	LinkID    uuid.UUID       `gorm:"type:uuid;not null;index:idx_share_link_accesses_link,priority:1"`
+	Link      *ShareLinkModel `gorm:"foreignKey:LinkID;references:ID"`
+	Outcome   string          `gorm:"type:varchar(32);not null"`
+	AssetType string          `gorm:"type:varchar(16);not null"`
+	AssetID   uuid.UUID       `gorm:"type:uuid;not null"`
+	IP        string          `gorm:"type:varchar(64)"`
+	UserAgent string          `gorm:"type:varchar(512)"`
+	CreatedAt time.Time       `gorm:"autoCreateTime;index:idx_share_link_accesses_link,priority:2"`
+}
+
+func (ShareLinkAccessModel) TableName() string {
+	return "share_link_accesses"
+}
+
+func (m *ShareLinkAccessModel) ToDomain() *entity.ShareLinkAccess {
+	return &entity.ShareLinkAccess{
+		ID:        m.ID,
+		LinkID:    m.LinkID,
+		Outcome:   entity.ShareLinkAccessOutcome(m.Outcome),
+		AssetType: entity.ShareLinkAssetType(m.AssetType),
+		AssetID:   m.AssetID,
+		IP:        m.IP,
+		UserAgent: m.UserAgent,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+func ShareLinkAccessModelFromDomain(a *entity.ShareLinkAccess) *ShareLinkAccessModel {
+	return &ShareLinkAccessModel{
+		ID:        a.ID,
+		LinkID:    a.LinkID,
+		Outcome:   string(a.Outcome),
+		AssetType: string(a.AssetType),
+		AssetID:   a.AssetID,
+		IP:        a.IP,
+		UserAgent: a.UserAgent,
+		CreatedAt: a.CreatedAt,
+	}
+}
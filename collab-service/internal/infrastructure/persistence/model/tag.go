@@ -0,0 +1,67 @@
+package model
+
+import (
+	"collab-service/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TagModel struct {
+	ID    uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	Name  string    `gorm:"type:varchar(64);not null"`
+	Color string    `gorm:"type:varchar(7);not null"`
+
+	OwnerID *uuid.UUID `gorm:"type:uuid;index"`
+	TeamID  *uuid.UUID `gorm:"type:uuid;index"`
+	Team    *TeamModel `gorm:"foreignKey:TeamID;references:ID"`
+
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (TagModel) TableName() string {
+	return "tags"
+}
+
+func (m *TagModel) ToDomain() *entity.Tag {
+	return &entity.Tag{
+		ID:        m.ID,
+		Name:      m.Name,
+		Color:     m.Color,
+		OwnerID:   m.OwnerID,
+		TeamID:    m.TeamID,
+		CreatedBy: m.CreatedBy,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func TagModelFromDomain(t *entity.Tag) *TagModel {
+	return &TagModel{
+		ID:        t.ID,
+		Name:      t.Name,
+		Color:     t.Color,
+		OwnerID:   t.OwnerID,
+		TeamID:    t.TeamID,
+		CreatedBy: t.CreatedBy,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+// NoteTagModel là bảng nối nhiều-nhiều giữa note và tag
+type NoteTagModel struct {
+	NoteID   uuid.UUID  `gorm:"primaryKey;type:uuid"`
+	Note     *NoteModel `gorm:"foreignKey:NoteID;references:ID"`
+	TagID    uuid.UUID  `gorm:"primaryKey;type:uuid;index"`
+	Tag      *TagModel  `gorm:"foreignKey:TagID;references:ID"`
+	TaggedBy uuid.UUID  `gorm:"type:uuid;not null"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (NoteTagModel) TableName() string {
+	return "note_tags"
+}
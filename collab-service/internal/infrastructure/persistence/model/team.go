@@ -10,8 +10,13 @@ import (
 type TeamModel struct {
 	ID       uuid.UUID `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
 	TeamName string
+	ParentID *uuid.UUID    `gorm:"type:uuid;index"`
+	Parent   *TeamModel    `gorm:"foreignKey:ParentID;references:ID"`
 	Rosters  []RosterModel `gorm:"foreignKey:TeamID"`
 
+	ArchivedAt *time.Time `gorm:"index"`
+	ArchivedBy *uuid.UUID `gorm:"type:uuid"`
+
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime"`
 }
@@ -39,11 +44,14 @@ func (m *TeamModel) ToDomain() *entity.Team {
 	}
 
 	return &entity.Team{
-		ID:        m.ID,
-		Name:      m.TeamName,
-		Rosters:   rosters,
-		CreatedAt: m.CreatedAt,
-		UpdatedAt: m.UpdatedAt,
+		ID:         m.ID,
+		Name:       m.TeamName,
+		ParentID:   m.ParentID,
+		Rosters:    rosters,
+		ArchivedAt: m.ArchivedAt,
+		ArchivedBy: m.ArchivedBy,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
 	}
 }
 
@@ -54,9 +62,12 @@ func TeamModelFromDomain(t *entity.Team) *TeamModel {
 		rosters[i] = *RosterModelFromDomain(&r)
 	}
 	return &TeamModel{
-		TeamName:  t.Name,
-		Rosters:   rosters,
-		CreatedAt: t.CreatedAt,
-		UpdatedAt: t.UpdatedAt,
+		TeamName:   t.Name,
+		ParentID:   t.ParentID,
+		Rosters:    rosters,
+		ArchivedAt: t.ArchivedAt,
+		ArchivedBy: t.ArchivedBy,
+		CreatedAt:  t.CreatedAt,
+		UpdatedAt:  t.UpdatedAt,
 	}
 }
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"collab-service/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TeamActivityModel struct {
+	ID           uuid.UUID  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	EventID      string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	TeamID       uuid.UUID  `gorm:"type:uuid;not null;index:idx_team_activities_team_occurred,priority:1"`
+	EventType    string     `gorm:"type:varchar(40);not null;index"`
+	ActorID      string     `gorm:"type:varchar(64);not null"`
+	TargetUserID *uuid.UUID `gorm:"type:uuid"`
+	OccurredAt   time.Time  `gorm:"not null;index:idx_team_activities_team_occurred,priority:2,sort:desc"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime"`
+}
+
+func (TeamActivityModel) TableName() string {
+	return "team_activities"
+}
+
+func (m *TeamActivityModel) ToDomain() *entity.TeamActivity {
+	return &entity.TeamActivity{
+		ID:           m.ID,
+		EventID:      m.EventID,
+		TeamID:       m.TeamID,
+		EventType:    m.EventType,
+		ActorID:      m.ActorID,
+		TargetUserID: m.TargetUserID,
+		OccurredAt:   m.OccurredAt,
+		CreatedAt:    m.CreatedAt,
+	}
+}
+
+func TeamActivityModelFromDomain(a *entity.TeamActivity) *TeamActivityModel {
+	return &TeamActivityModel{
+		ID:           a.ID,
+		EventID:      a.EventID,
+		TeamID:       a.TeamID,
+		EventType:    a.EventType,
+		ActorID:      a.ActorID,
+		TargetUserID: a.TargetUserID,
+		OccurredAt:   a.OccurredAt,
+	}
+}
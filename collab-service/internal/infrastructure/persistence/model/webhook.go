@@ -0,0 +1,128 @@
+package model
+
+import (
+	"collab-service/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookModel struct {
+	ID     uuid.UUID  `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	TeamID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Team   *TeamModel `gorm:"foreignKey:TeamID;references:ID"`
+	URL    string     `gorm:"type:text;not null"`
+	Secret string     `gorm:"type:varchar(128);not null"`
+
+	EventTypes []string `gorm:"type:jsonb;serializer:json;not null"`
+
+	Active         bool `gorm:"not null;default:true"`
+	FailureCount   int  `gorm:"not null;default:0"`
+	DisabledAt     *time.Time
+	DisabledReason string `gorm:"type:text"`
+
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (WebhookModel) TableName() string {
+	return "webhooks"
+}
+
+func (m *WebhookModel) ToDomain() *entity.Webhook {
+	return &entity.Webhook{
+		ID:             m.ID,
+		TeamID:         m.TeamID,
+		URL:            m.URL,
+		Secret:         m.Secret,
+		EventTypes:     m.EventTypes,
+		Active:         m.Active,
+		FailureCount:   m.FailureCount,
+		DisabledAt:     m.DisabledAt,
+		DisabledReason: m.DisabledReason,
+		CreatedBy:      m.CreatedBy,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+func WebhookModelFromDomain(w *entity.Webhook) *WebhookModel {
+	eventTypes := w.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return &WebhookModel{
+		ID:             w.ID,
+		TeamID:         w.TeamID,
+		URL:            w.URL,
+		Secret:         w.Secret,
+		EventTypes:     eventTypes,
+		Active:         w.Active,
+		FailureCount:   w.FailureCount,
+		DisabledAt:     w.DisabledAt,
+		DisabledReason: w.DisabledReason,
+		CreatedBy:      w.CreatedBy,
+		CreatedAt:      w.CreatedAt,
+		UpdatedAt:      w.UpdatedAt,
+	}
+}
+
+type WebhookDeliveryModel struct {
+	ID        uuid.UUID     `gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	WebhookID uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex:idx_webhook_deliveries_event"`
+	Webhook   *WebhookModel `gorm:"foreignKey:WebhookID;references:ID"`
+	EventID   string        `gorm:"type:varchar(64);not null;uniqueIndex:idx_webhook_deliveries_event"`
+	EventType string        `gorm:"type:varchar(64);not null"`
+	Payload   string        `gorm:"type:text;not null"`
+
+	Status         string     `gorm:"type:varchar(16);not null;index:idx_webhook_deliveries_due,priority:1"`
+	Attempts       int        `gorm:"not null;default:0"`
+	NextAttemptAt  *time.Time `gorm:"index:idx_webhook_deliveries_due,priority:2"`
+	ResponseStatus *int
+	LastError      string `gorm:"type:text"`
+	DeliveredAt    *time.Time
+
+	CreatedAt time.Time `gorm:"autoCreateTime;index"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (WebhookDeliveryModel) TableName() string {
+	return "webhook_deliveries"
+}
+
+func (m *WebhookDeliveryModel) ToDomain() *entity.WebhookDelivery {
+	return &entity.WebhookDelivery{
+		ID:             m.ID,
+		WebhookID:      m.WebhookID,
+		EventID:        m.EventID,
+		EventType:      m.EventType,
+		Payload:        m.Payload,
+		Status:         entity.WebhookDeliveryStatus(m.Status),
+		Attempts:       m.Attempts,
+		NextAttemptAt:  m.NextAttemptAt,
+		ResponseStatus: m.ResponseStatus,
+		LastError:      m.LastError,
+		DeliveredAt:    m.DeliveredAt,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+func WebhookDeliveryModelFromDomain(d *entity.WebhookDelivery) *WebhookDeliveryModel {
+	return &WebhookDeliveryModel{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
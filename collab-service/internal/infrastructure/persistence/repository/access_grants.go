@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/persistence/model"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// activeTeamIDsSQL trả về các team (chưa archive) mà user đang là thành viên còn hiệu lực
+const activeTeamIDsSQL = `
+	SELECT rosters.team_id FROM rosters
+	JOIN teams ON teams.id = rosters.team_id
+	WHERE rosters.user_id = ?
+	AND (rosters.expires_at IS NULL OR rosters.expires_at > NOW())
+	AND teams.archived_at IS NULL`
+
+// activeShareSQL giữ lại share chưa hết hạn; share đã hết hạn không còn cấp quyền
+// ngay cả khi sweeper chưa kịp xoá. Dùng cho bảng share không đặt alias.
+const activeShareSQL = `(expires_at IS NULL OR expires_at > NOW())`
+
+// accessibleFolderIDsSQL trả về mọi folder user truy cập được: folder được share trực tiếp
+// hoặc qua team, cùng toàn bộ folder con bên dưới (quyền được kế thừa xuống cây).
+// Folder trong thùng rác và share đã hết hạn không mang quyền.
+const accessibleFolderIDsSQL = `
+	WITH RECURSIVE accessible AS (
+		SELECT fs.folder_id AS id FROM folder_shares fs
+		JOIN folders sf ON sf.id = fs.folder_id AND sf.deleted_at IS NULL
+		WHERE (fs.user_id = ? OR fs.team_id IN (` + activeTeamIDsSQL + `))
+		AND (fs.expires_at IS NULL OR fs.expires_at > NOW())
+		UNION
+		SELECT f.id FROM folders f JOIN accessible a ON f.parent_id = a.id
+		WHERE f.deleted_at IS NULL
+	)
+	SELECT id FROM accessible`
+
+// sharedNoteIDsSQL trả về các note được share còn hiệu lực cho user, trực tiếp hoặc qua team
+const sharedNoteIDsSQL = `
+	SELECT note_id FROM note_shares
+	WHERE (user_id = ? OR team_id IN (` + activeTeamIDsSQL + `)) AND ` + activeShareSQL
+
+// canAccessNoteSQL là điều kiện note user truy cập được, áp lên bảng notes (hoặc alias) truyền vào:
+// note được share trực tiếp/qua team, hoặc nằm trong folder user truy cập được. Tham số: userID ×4.
+func canAccessNoteSQL(notes string) string {
+	return `(` + notes + `.id IN (` + sharedNoteIDsSQL + `) OR ` + notes + `.folder_id IN (` + accessibleFolderIDsSQL + `))`
+}
+
+// teamNoteSQL là điều kiện note team truy cập được: note share cho team,
+// hoặc nằm trong folder share cho team kể cả folder con. Tham số: teamID ×2.
+func teamNoteSQL(notes string) string {
+	return `(` + notes + `.id IN (SELECT note_id FROM note_shares WHERE team_id = ? AND ` + activeShareSQL + `) OR ` +
+		notes + `.folder_id IN (` + teamFolderIDsSQL + `))`
+}
+
+// accessGrant là một dòng share áp dụng cho user: trực tiếp (TeamRole rỗng) hoặc thông qua team.
+// AssetID là folder/note mang share, có thể là folder cha của asset đang xét.
+type accessGrant struct {
+	ShareID     uuid.UUID
+	AssetID     uuid.UUID
+	Resource    entity.ResourceType `gorm:"-"`
+	AccessLevel entity.AccessLevel
+	TeamID      *uuid.UUID
+	TeamRole    *entity.TeamAccessRole
+	ExpiresAt   *time.Time
+}
+
+// effective áp giới hạn của role trong team: guest chỉ được READ qua team share
+func (g accessGrant) effective() entity.AccessLevel {
+	if g.TeamRole != nil && g.TeamRole.IsGuest() && g.AccessLevel.Priority() > entity.AccessLevelRead.Priority() {
+		return entity.AccessLevelRead
+	}
+	return g.AccessLevel
+}
+
+// shareGrants lấy mọi share còn hiệu lực của user trên các asset, gồm share trực tiếp và share cho các team của user.
+// table là folder_shares hoặc note_shares, assetColumn là folder_id hoặc note_id.
+func shareGrants(db *gorm.DB, table, assetColumn string, assetIDs []uuid.UUID, userID uuid.UUID) ([]accessGrant, error) {
+	var grants []accessGrant
+	if len(assetIDs) == 0 {
+		return grants, nil
+	}
+	err := db.Raw(fmt.Sprintf(`
+		SELECT s.id AS share_id, s.%[2]s AS asset_id, s.access_level, s.team_id, r.role AS team_role, s.expires_at
+		FROM %[1]s s
+		LEFT JOIN rosters r ON r.team_id = s.team_id AND r.user_id = ?
+			AND (r.expires_at IS NULL OR r.expires_at > NOW())
+			AND NOT EXISTS (SELECT 1 FROM teams t WHERE t.id = r.team_id AND t.archived_at IS NOT NULL)
+		WHERE s.%[2]s IN ? AND (s.user_id = ? OR r.id IS NOT NULL)
+		AND (s.expires_at IS NULL OR s.expires_at > NOW())`, table, assetColumn),
+		userID, assetIDs, userID).
+		Scan(&grants).Error
+	return grants, err
+}
+
+// shareUpdates là các cột được ghi khi share lại cho user/team đã có share.
+// Hạn mới thay hạn cũ (nil là vĩnh viễn) và share sẽ được nhắc lại trước khi hết hạn.
+func shareUpdates(accessLevel entity.AccessLevel, expiresAt *time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"access_level":     accessLevel,
+		"expires_at":       expiresAt,
+		"reminder_sent_at": nil,
+	}
+}
+
+// folderGrants lấy share của user trên folder và mọi folder cha của nó
+func folderGrants(db *gorm.DB, folderID, userID uuid.UUID) ([]accessGrant, error) {
+	chain, err := folderChainIDs(db, folderID)
+	if err != nil {
+		return nil, err
+	}
+	grants, err := shareGrants(db, "folder_shares", "folder_id", chain, userID)
+	for i := range grants {
+		grants[i].Resource = entity.ResourceFolder
+	}
+	return grants, err
+}
+
+// noteGrants lấy share của user trên note cùng share trên folder chứa note và các folder cha.
+// Note không tồn tại hoặc trong thùng rác trả về gorm.ErrRecordNotFound: khi đó không còn quyền nào, kể cả share trực tiếp.
+func noteGrants(db *gorm.DB, noteID, userID uuid.UUID) ([]accessGrant, error) {
+	var folderIDs []uuid.UUID
+	if err := db.Model(&model.NoteModel{}).Where("id = ?", noteID).Pluck("folder_id", &folderIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(folderIDs) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	grants, err := shareGrants(db, "note_shares", "note_id", []uuid.UUID{noteID}, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range grants {
+		grants[i].Resource = entity.ResourceNote
+	}
+
+	if folderID := folderIDs[0]; folderID != uuid.Nil {
+		inherited, err := folderGrants(db, folderID, userID)
+		if err != nil {
+			return nil, err
+		}
+		grants = append(grants, inherited...)
+	}
+	return grants, nil
+}
+
+// highestGrant trả về quyền cao nhất trong các grant, NONE nếu không có grant nào
+func highestGrant(grants []accessGrant) entity.AccessLevel {
+	level := entity.AccessLevelNone
+	for _, g := range grants {
+		level = entity.MaxAccessLevel(level, g.effective())
+	}
+	return level
+}
+
+// explainGrants dựng AccessExplanation từ chính các grant dùng để kiểm tra quyền,
+// nên kết quả luôn khớp với GetAccessLevel. Grant mạnh nhất đứng trước.
+func explainGrants(resourceType entity.ResourceType, resourceID, userID uuid.UUID, grants []accessGrant) *entity.AccessExplanation {
+	explained := make([]entity.AccessGrant, len(grants))
+	for i, g := range grants {
+		source := entity.AccessGrantDirect
+		switch {
+		case g.TeamID != nil:
+			source = entity.AccessGrantTeam
+		case g.AccessLevel == entity.AccessLevelOwner:
+			source = entity.AccessGrantOwnership
+		}
+		explained[i] = entity.AccessGrant{
+			ShareID:        g.ShareID,
+			Source:         source,
+			ResourceType:   g.Resource,
+			ResourceID:     g.AssetID,
+			Inherited:      g.Resource != resourceType || g.AssetID != resourceID,
+			TeamID:         g.TeamID,
+			TeamRole:       g.TeamRole,
+			AccessLevel:    g.AccessLevel,
+			EffectiveLevel: g.effective(),
+			ExpiresAt:      g.ExpiresAt,
+		}
+	}
+	sort.SliceStable(explained, func(i, j int) bool {
+		return explained[i].EffectiveLevel.Priority() > explained[j].EffectiveLevel.Priority()
+	})
+
+	return &entity.AccessExplanation{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		UserID:       userID,
+		AccessLevel:  highestGrant(grants),
+		Grants:       explained,
+	}
+}
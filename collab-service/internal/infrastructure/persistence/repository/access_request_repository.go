@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/persistence/model"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AccessRequestRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAccessRequestRepository(db *gorm.DB) entity.AccessRequestRepository {
+	return &AccessRequestRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create implements entity.AccessRequestRepository.
+func (r *AccessRequestRepositoryImpl) Create(ctx context.Context, request *entity.AccessRequest) (*entity.AccessRequest, error) {
+	m := model.AccessRequestModelFromDomain(request)
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// GetByID implements entity.AccessRequestRepository.
+func (r *AccessRequestRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.AccessRequest, error) {
+	var m model.AccessRequestModel
+	if err := r.db.WithContext(ctx).First(&m, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// GetPending implements entity.AccessRequestRepository.
+func (r *AccessRequestRepositoryImpl) GetPending(ctx context.Context, assetType entity.AccessRequestAssetType, assetID, requesterID uuid.UUID) (*entity.AccessRequest, error) {
+	var m model.AccessRequestModel
+	err := r.db.WithContext(ctx).
+		Where("asset_type = ? AND asset_id = ? AND requester_id = ? AND status = ?",
+			string(assetType), assetID, requesterID, string(entity.AccessRequestPending)).
+		First(&m).Error
+	if err != nil {
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// ListByAsset implements entity.AccessRequestRepository.
+func (r *AccessRequestRepositoryImpl) ListByAsset(ctx context.Context, assetType entity.AccessRequestAssetType, assetID uuid.UUID, status *entity.AccessRequestStatus) ([]*entity.AccessRequest, error) {
+	query := r.db.WithContext(ctx).Where("asset_type = ? AND asset_id = ?", string(assetType), assetID)
+	if status != nil {
+		query = query.Where("status = ?", string(*status))
+	}
+
+	var models []model.AccessRequestModel
+	if err := query.Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	requests := make([]*entity.AccessRequest, len(models))
+	for i := range models {
+		requests[i] = models[i].ToDomain()
+	}
+	return requests, nil
+}
+
+// Decide implements entity.AccessRequestRepository.
+func (r *AccessRequestRepositoryImpl) Decide(ctx context.Context, id uuid.UUID, status entity.AccessRequestStatus, decidedBy uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.AccessRequestModel{}).
+		Where("id = ? AND status = ?", id, string(entity.AccessRequestPending)).
+		Updates(map[string]interface{}{
+			"status":     string(status),
+			"decided_by": decidedBy,
+			"decided_at": at,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Reopen implements entity.AccessRequestRepository.
+func (r *AccessRequestRepositoryImpl) Reopen(ctx context.Context, id uuid.UUID, decidedBy uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&model.AccessRequestModel{}).
+		Where("id = ? AND status = ? AND decided_by = ?", id, string(entity.AccessRequestApproved), decidedBy).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM access_requests other
+			WHERE other.asset_type = access_requests.asset_type AND other.asset_id = access_requests.asset_id
+			AND other.requester_id = access_requests.requester_id AND other.status = ?)`, string(entity.AccessRequestPending)).
+		Updates(map[string]interface{}{
+			"status":     string(entity.AccessRequestPending),
+			"decided_by": nil,
+			"decided_at": nil,
+		}).Error
+}
+
+// deleteAccessRequests xoá yêu cầu truy cập của các asset
+func deleteAccessRequests(tx *gorm.DB, assetType entity.AccessRequestAssetType, assetIDs []uuid.UUID) error {
+	return tx.Where("asset_type = ? AND asset_id IN ?", string(assetType), assetIDs).Delete(&model.AccessRequestModel{}).Error
+}
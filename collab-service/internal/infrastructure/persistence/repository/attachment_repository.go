@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/persistence/model"
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AttachmentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) entity.AttachmentRepository {
+	return &AttachmentRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create implements entity.AttachmentRepository.
+func (r *AttachmentRepositoryImpl) Create(ctx context.Context, attachment *entity.Attachment, quota entity.AttachmentQuota) (*entity.Attachment, error) {
+	m := model.AttachmentModelFromDomain(attachment)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkAttachmentQuota(tx, attachment, quota, true); err != nil {
+			return err
+		}
+		return tx.Create(m).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// GetByID implements entity.AttachmentRepository.
+func (r *AttachmentRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.Attachment, error) {
+	var m model.AttachmentModel
+	if err := r.db.WithContext(ctx).First(&m, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// ListByNote implements entity.AttachmentRepository.
+func (r *AttachmentRepositoryImpl) ListByNote(ctx context.Context, noteID uuid.UUID) ([]*entity.Attachment, error) {
+	var models []model.AttachmentModel
+	if err := r.db.WithContext(ctx).
+		Where("note_id = ?", noteID).
+		Order("created_at").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	attachments := make([]*entity.Attachment, len(models))
+	for i, m := range models {
+		attachments[i] = m.ToDomain()
+	}
+	return attachments, nil
+}
+
+// Delete implements entity.AttachmentRepository.
+func (r *AttachmentRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.AttachmentModel{}, "id = ?", id).Error
+}
+
+// CheckQuota implements entity.AttachmentRepository.
+func (r *AttachmentRepositoryImpl) CheckQuota(ctx context.Context, attachment *entity.Attachment, quota entity.AttachmentQuota) error {
+	return checkAttachmentQuota(r.db.WithContext(ctx), attachment, quota, false)
+}
+
+// checkAttachmentQuota tính dung lượng attachment cho mọi team truy cập note, hoặc cho owner note
+// khi note không share cho team nào. Với lock, mỗi team/owner bị tính được khoá bằng advisory lock
+// tới hết transaction nên hai upload đồng thời không cùng đọc một usage cũ.
+func checkAttachmentQuota(db *gorm.DB, attachment *entity.Attachment, quota entity.AttachmentQuota, lock bool) error {
+	teamIDs, err := teamIDsForNote(db, attachment.NoteID)
+	if err != nil {
+		return err
+	}
+
+	if len(teamIDs) == 0 {
+		ownerID, err := noteOwnerID(db, attachment.NoteID)
+		if err != nil {
+			return err
+		}
+		if ownerID == uuid.Nil {
+			ownerID = attachment.UploadedBy
+		}
+		if lock {
+			if err := lockAttachmentQuota(db, ownerID); err != nil {
+				return err
+			}
+		}
+		usage, err := ownerUsage(db, ownerID)
+		if err != nil {
+			return err
+		}
+		if usage+attachment.Size > quota.User {
+			return &entity.AttachmentQuotaError{OwnerID: ownerID, Used: usage, Quota: quota.User}
+		}
+		return nil
+	}
+
+	// Khoá theo thứ tự cố định để hai upload cùng chạm nhiều team không deadlock
+	sort.Slice(teamIDs, func(i, j int) bool { return teamIDs[i].String() < teamIDs[j].String() })
+	for _, teamID := range teamIDs {
+		if lock {
+			if err := lockAttachmentQuota(db, teamID); err != nil {
+				return err
+			}
+		}
+		usage, err := teamUsage(db, teamID)
+		if err != nil {
+			return err
+		}
+		if usage+attachment.Size > quota.Team {
+			return &entity.AttachmentQuotaError{TeamID: teamID, Used: usage, Quota: quota.Team}
+		}
+	}
+	return nil
+}
+
+// lockAttachmentQuota giữ advisory lock theo team/user tới khi transaction kết thúc
+func lockAttachmentQuota(tx *gorm.DB, id uuid.UUID) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", "attachment-quota:"+id.String()).Error
+}
+
+func noteOwnerID(db *gorm.DB, noteID uuid.UUID) (uuid.UUID, error) {
+	var ownerID uuid.UUID
+	err := db.Table("note_shares").
+		Select("user_id").
+		Where("note_id = ? AND access_level = ?", noteID, entity.AccessLevelOwner).
+		Scan(&ownerID).Error
+	return ownerID, err
+}
+
+// teamIDsForNote trả về các team có share trên note hoặc trên folder chứa note (kể cả folder cha)
+func teamIDsForNote(db *gorm.DB, noteID uuid.UUID) ([]uuid.UUID, error) {
+	var folderIDs []uuid.UUID
+	if err := db.Unscoped().Model(&model.NoteModel{}).Where("id = ?", noteID).Pluck("folder_id", &folderIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(folderIDs) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	chain, err := folderChainIDs(db, folderIDs[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var teamIDs []uuid.UUID
+	err = db.Raw(`
+		SELECT team_id FROM note_shares WHERE note_id = ? AND team_id IS NOT NULL AND `+activeShareSQL+`
+		UNION
+		SELECT team_id FROM folder_shares WHERE folder_id IN ? AND team_id IS NOT NULL AND `+activeShareSQL,
+		noteID, chain).
+		Scan(&teamIDs).Error
+	return teamIDs, err
+}
+
+// teamUsage là tổng dung lượng attachment của các note team truy cập được qua team share.
+// Note trong thùng rác vẫn được tính cho tới khi bị purge vì blob của chúng vẫn còn.
+func teamUsage(db *gorm.DB, teamID uuid.UUID) (int64, error) {
+	var usage int64
+	err := db.Raw(`
+		SELECT COALESCE(SUM(a.size), 0) FROM attachments a
+		JOIN notes n ON n.id = a.note_id
+		WHERE `+teamNoteSQL("n"),
+		teamID, teamID).
+		Scan(&usage).Error
+	return usage, err
+}
+
+// ownerUsage là tổng dung lượng attachment của các note user sở hữu, tính cả note trong thùng rác
+func ownerUsage(db *gorm.DB, ownerID uuid.UUID) (int64, error) {
+	var usage int64
+	err := db.Raw(`
+		SELECT COALESCE(SUM(a.size), 0) FROM attachments a
+		WHERE a.note_id IN (SELECT note_id FROM note_shares WHERE user_id = ? AND access_level = ?)`,
+		ownerID, entity.AccessLevelOwner).
+		Scan(&usage).Error
+	return usage, err
+}
+
+// StorageKeysOfNote implements entity.AttachmentRepository.
+func (r *AttachmentRepositoryImpl) StorageKeysOfNote(ctx context.Context, noteID uuid.UUID) ([]string, error) {
+	var keys []string
+	err := r.db.WithContext(ctx).Model(&model.AttachmentModel{}).
+		Where("note_id = ?", noteID).
+		Pluck("storage_key", &keys).Error
+	return keys, err
+}
+
+// StorageKeysInFolder implements entity.AttachmentRepository.
+func (r *AttachmentRepositoryImpl) StorageKeysInFolder(ctx context.Context, folderID uuid.UUID) ([]string, error) {
+	var keys []string
+	err := r.db.WithContext(ctx).Raw(folderDescendantsCTE+`
+		SELECT a.storage_key FROM attachments a
+		JOIN notes n ON n.id = a.note_id
+		WHERE n.folder_id IN (SELECT id FROM descendants)`,
+		folderID).
+		Scan(&keys).Error
+	return keys, err
+}
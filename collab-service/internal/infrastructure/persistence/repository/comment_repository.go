@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/persistence/model"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CommentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) entity.CommentRepository {
+	return &CommentRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create implements entity.CommentRepository.
+func (r *CommentRepositoryImpl) Create(ctx context.Context, comment *entity.Comment) (*entity.Comment, error) {
+	m := model.CommentModelFromDomain(comment)
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// GetByID implements entity.CommentRepository.
+func (r *CommentRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.Comment, error) {
+	var m model.CommentModel
+	if err := r.db.WithContext(ctx).First(&m, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// ListByNote implements entity.CommentRepository.
+func (r *CommentRepositoryImpl) ListByNote(ctx context.Context, noteID uuid.UUID) ([]*entity.Comment, error) {
+	var models []model.CommentModel
+	if err := r.db.WithContext(ctx).
+		Where("note_id = ?", noteID).
+		Order("created_at, id").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	comments := make([]*entity.Comment, len(models))
+	for i, m := range models {
+		comments[i] = m.ToDomain()
+	}
+	return comments, nil
+}
+
+// UpdateBody implements entity.CommentRepository.
+func (r *CommentRepositoryImpl) UpdateBody(ctx context.Context, id uuid.UUID, body string, editedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.CommentModel{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"body":      body,
+		"edited_at": editedAt,
+	}).Error
+}
+
+// SetResolved implements entity.CommentRepository.
+func (r *CommentRepositoryImpl) SetResolved(ctx context.Context, id uuid.UUID, resolvedBy *uuid.UUID, resolvedAt *time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.CommentModel{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"resolved_by": resolvedBy,
+		"resolved_at": resolvedAt,
+	}).Error
+}
+
+// CountReplies implements entity.CommentRepository.
+func (r *CommentRepositoryImpl) CountReplies(ctx context.Context, id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.CommentModel{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// MarkDeleted implements entity.CommentRepository.
+func (r *CommentRepositoryImpl) MarkDeleted(ctx context.Context, id uuid.UUID, deletedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.CommentModel{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"body":         "",
+		"anchor_quote": nil,
+		"deleted_at":   deletedAt,
+	}).Error
+}
+
+// Delete implements entity.CommentRepository. Xoá comment gốc thì xoá luôn các reply.
+func (r *CommentRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("parent_id = ?", id).Delete(&model.CommentModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.CommentModel{}, "id = ?", id).Error
+	})
+}
+
+// deleteNoteComments xoá comment của các note, reply trước comment gốc để không vi phạm khoá ngoại parent_id
+func deleteNoteComments(tx *gorm.DB, noteIDs []uuid.UUID) error {
+	if err := tx.Where("note_id IN ? AND parent_id IS NOT NULL", noteIDs).Delete(&model.CommentModel{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("note_id IN ?", noteIDs).Delete(&model.CommentModel{}).Error
+}
@@ -4,6 +4,7 @@ import (
 	"collab-service/internal/domain/entity"
 	"collab-service/internal/infrastructure/persistence/model"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -37,61 +38,220 @@ func (f *FolderRepositoryImpl) GetOwner(ctx context.Context, folderID uuid.UUID)
 	return uuid.Parse(ownerID)
 }
 
+// Delete xoá vĩnh viễn folder cùng toàn bộ folder con, note và share bên dưới, kể cả những mục đang trong thùng rác
 func (f *FolderRepositoryImpl) Delete(ctx context.Context, folderID uuid.UUID) error {
 	return f.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// 1. Xóa folder_shares
-		if err := tx.Where("folder_id = ?", folderID).Delete(&model.FolderShareModel{}).Error; err != nil {
+		// 1. Lấy folder và toàn bộ folder con
+		folderIDs, err := folderSubtreeIDs(tx, folderID)
+		if err != nil {
 			return err
 		}
+		if len(folderIDs) == 0 {
+			return gorm.ErrRecordNotFound
+		}
 
-		// 2. Lấy danh sách note_id trong folder
+		// 2. Xóa folder_shares
+		if err := tx.Where("folder_id IN ?", folderIDs).Delete(&model.FolderShareModel{}).Error; err != nil {
+			return err
+		}
+
+		// 3. Lấy danh sách note_id trong các folder
 		var noteIDs []uuid.UUID
-		if err := tx.Model(&model.NoteModel{}).
-			Where("folder_id = ?", folderID).
+		if err := tx.Unscoped().Model(&model.NoteModel{}).
+			Where("folder_id IN ?", folderIDs).
 			Pluck("id", &noteIDs).Error; err != nil {
 			return err
 		}
 
 		if len(noteIDs) > 0 {
-			// 3. Xóa note_shares của các note này
+			// 4. Xóa note_shares của các note này
 			if err := tx.Where("note_id IN ?", noteIDs).Delete(&model.NoteShareModel{}).Error; err != nil {
 				return err
 			}
 
-			// 4. Xóa notes
-			if err := tx.Where("id IN ?", noteIDs).Delete(&model.NoteModel{}).Error; err != nil {
+			// 5. Xóa lịch sử revision, tag, comment, file đính kèm, link công khai, yêu cầu truy cập và notes
+			if err := tx.Where("note_id IN ?", noteIDs).Delete(&model.NoteRevisionModel{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("note_id IN ?", noteIDs).Delete(&model.NoteTagModel{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("note_id IN ?", noteIDs).Delete(&model.AttachmentModel{}).Error; err != nil {
+				return err
+			}
+			if err := deleteNoteComments(tx, noteIDs); err != nil {
+				return err
+			}
+			if err := deleteShareLinks(tx, entity.ShareLinkNote, noteIDs); err != nil {
+				return err
+			}
+			if err := deleteAccessRequests(tx, entity.AccessRequestNote, noteIDs); err != nil {
+				return err
+			}
+
+			if err := tx.Unscoped().Where("id IN ?", noteIDs).Delete(&model.NoteModel{}).Error; err != nil {
 				return err
 			}
 		}
 
-		// 5. Xóa folder
-		if err := tx.Delete(&model.FolderModel{}, "id = ?", folderID).Error; err != nil {
+		// 6. Xóa link công khai và yêu cầu truy cập của các folder
+		if err := deleteShareLinks(tx, entity.ShareLinkFolder, folderIDs); err != nil {
+			return err
+		}
+		if err := deleteAccessRequests(tx, entity.AccessRequestFolder, folderIDs); err != nil {
 			return err
 		}
 
+		// 7. Xóa folders, con trước cha để không vi phạm khoá ngoại parent_id
+		for i := len(folderIDs) - 1; i >= 0; i-- {
+			if err := tx.Unscoped().Delete(&model.FolderModel{}, "id = ?", folderIDs[i]).Error; err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 }
 
-// GetAccessLevel implements entity.FolderRepository.
-func (f *FolderRepositoryImpl) GetAccessLevel(ctx context.Context, folderID uuid.UUID, userID uuid.UUID) (entity.AccessLevel, error) {
-	var accessLevelStr string
-	var shareModel model.FolderShareModel
+// Trash implements entity.FolderRepository.
+// Mọi folder con và note chưa bị xoá được đánh dấu cùng một deleted_at để Restore nhận ra chúng
+// thuộc cùng lần xoá; những mục đã vào thùng rác trước đó giữ nguyên thời điểm xoá riêng.
+func (f *FolderRepositoryImpl) Trash(ctx context.Context, folderID uuid.UUID, deletedBy uuid.UUID) error {
+	return f.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		folderIDs, err := folderSubtreeIDs(tx, folderID)
+		if err != nil {
+			return err
+		}
+		if len(folderIDs) == 0 {
+			return gorm.ErrRecordNotFound
+		}
 
-	if err := f.db.WithContext(ctx).Table("folder_shares").Where("folder_id = ? AND user_id = ?", folderID, userID).First(&shareModel).Error; err != nil {
-		return entity.AccessLevelNone, err
+		values := map[string]interface{}{
+			"deleted_at": time.Now(),
+			"deleted_by": deletedBy,
+		}
+		if err := tx.Model(&model.NoteModel{}).Where("folder_id IN ?", folderIDs).Updates(values).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&model.FolderModel{}).Where("id IN ?", folderIDs).Updates(values)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// Restore implements entity.FolderRepository.
+// Share không bị động tới khi xoá nên quyền trên folder và các note được khôi phục nguyên vẹn.
+func (f *FolderRepositoryImpl) Restore(ctx context.Context, folderID uuid.UUID) error {
+	return f.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var folder model.FolderModel
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&folder, "id = ?", folderID).Error; err != nil {
+			return err
+		}
+
+		folderIDs, err := folderSubtreeIDs(tx, folderID)
+		if err != nil {
+			return err
+		}
+
+		values := map[string]interface{}{
+			"deleted_at": nil,
+			"deleted_by": nil,
+		}
+		if err := tx.Unscoped().Model(&model.FolderModel{}).
+			Where("id IN ? AND deleted_at = ?", folderIDs, folder.DeletedAt.Time).
+			Updates(values).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&model.NoteModel{}).
+			Where("folder_id IN ? AND deleted_at = ?", folderIDs, folder.DeletedAt.Time).
+			Updates(values).Error
+	})
+}
+
+// GetTrashed implements entity.FolderRepository.
+func (f *FolderRepositoryImpl) GetTrashed(ctx context.Context, id uuid.UUID) (*entity.Folder, error) {
+	var m model.FolderModel
+	if err := f.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(&m, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// ListTrash implements entity.FolderRepository.
+func (f *FolderRepositoryImpl) ListTrash(ctx context.Context, userID uuid.UUID) ([]*entity.Folder, error) {
+	var models []model.FolderModel
+	if err := f.db.WithContext(ctx).Unscoped().Model(&model.FolderModel{}).
+		Where("folders.deleted_at IS NOT NULL").
+		Where("NOT EXISTS (SELECT 1 FROM folders p WHERE p.id = folders.parent_id AND p.deleted_at = folders.deleted_at)").
+		Where("folders.deleted_by = ? OR folders.id IN (SELECT folder_id FROM folder_shares WHERE user_id = ? AND access_level = ?)",
+			userID, userID, entity.AccessLevelOwner).
+		Order("folders.deleted_at DESC").
+		Find(&models).Error; err != nil {
+		return nil, err
 	}
 
-	accessLevelStr = string(shareModel.AccessLevel)
+	folders := make([]*entity.Folder, len(models))
+	for i, m := range models {
+		folders[i] = m.ToDomain()
+	}
+	return folders, nil
+}
 
-	return entity.AccessLevel(accessLevelStr), nil
+// ListTrashedBefore implements entity.FolderRepository.
+// Chỉ trả về folder gốc của mỗi lần xoá, lần xoá cũ trước, để Delete trên từng folder không đụng nhau.
+func (f *FolderRepositoryImpl) ListTrashedBefore(ctx context.Context, cutoff time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := f.db.WithContext(ctx).Unscoped().Model(&model.FolderModel{}).
+		Where("folders.deleted_at IS NOT NULL AND folders.deleted_at < ?", cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM folders p WHERE p.id = folders.parent_id AND p.deleted_at = folders.deleted_at)").
+		Order("folders.deleted_at").
+		Pluck("folders.id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
 
+// GetAccessLevel implements entity.FolderRepository.
+// Quyền hiệu lực là quyền cao nhất giữa share trực tiếp và share cho các team của user,
+// trên chính folder hoặc bất kỳ folder cha nào (quyền được kế thừa xuống cây).
+func (f *FolderRepositoryImpl) GetAccessLevel(ctx context.Context, folderID uuid.UUID, userID uuid.UUID) (entity.AccessLevel, error) {
+	grants, err := folderGrants(f.db.WithContext(ctx), folderID, userID)
+	if err != nil {
+		return entity.AccessLevelNone, err
+	}
+	if len(grants) == 0 {
+		return entity.AccessLevelNone, gorm.ErrRecordNotFound
+	}
+	return highestGrant(grants), nil
+}
+
+// ExplainAccess implements entity.FolderRepository.
+// Folder tồn tại nhưng user không có share nào trả về explanation với quyền NONE.
+func (f *FolderRepositoryImpl) ExplainAccess(ctx context.Context, folderID, userID uuid.UUID) (*entity.AccessExplanation, error) {
+	db := f.db.WithContext(ctx)
+	grants, err := folderGrants(db, folderID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(grants) == 0 {
+		if err := db.Select("id").First(&model.FolderModel{}, "id = ?", folderID).Error; err != nil {
+			return nil, err
+		}
+	}
+	return explainGrants(entity.ResourceFolder, folderID, userID, grants), nil
 }
 
 // GetAllForCanAccess implements entity.FolderRepository.
 func (f *FolderRepositoryImpl) GetAllForCanAccess(ctx context.Context, userID uuid.UUID) ([]*entity.Folder, error) {
 	var models []model.FolderModel
-	if err := f.db.WithContext(ctx).Model(&model.FolderModel{}).Joins("JOIN folder_shares ON folder_shares.folder_id = folders.id").Where("folder_shares.user_id = ?", userID).Find(&models).Error; err != nil {
+	if err := f.db.WithContext(ctx).Model(&model.FolderModel{}).
+		Where(`folders.id IN (`+accessibleFolderIDsSQL+`)`, userID, userID).
+		Find(&models).Error; err != nil {
 		return nil, err
 	}
 
@@ -102,6 +262,110 @@ func (f *FolderRepositoryImpl) GetAllForCanAccess(ctx context.Context, userID uu
 	return folders, nil
 }
 
+// folderAncestorsCTE đi từ một folder lên tới folder gốc. depth = 0 là chính folder đó.
+// Folder trong thùng rác bị bỏ qua nên folder đã xoá không có chuỗi cha (và không có quyền).
+const folderAncestorsCTE = `
+	WITH RECURSIVE ancestors AS (
+		SELECT id, parent_id, 0 AS depth FROM folders WHERE id = ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT f.id, f.parent_id, a.depth + 1 FROM folders f JOIN ancestors a ON f.id = a.parent_id
+		WHERE f.deleted_at IS NULL
+	)`
+
+// folderDescendantsCTE đi từ một folder xuống mọi folder con bên dưới. depth = 0 là chính folder đó.
+// Không lọc thùng rác: dùng cho cả xoá, khôi phục và purge.
+const folderDescendantsCTE = `
+	WITH RECURSIVE descendants AS (
+		SELECT id, 0 AS depth FROM folders WHERE id = ?
+		UNION ALL
+		SELECT f.id, d.depth + 1 FROM folders f JOIN descendants d ON f.parent_id = d.id
+	)`
+
+// folderChainIDs trả về folder và các folder cha, từ gốc xuống tới folder đó
+func folderChainIDs(db *gorm.DB, folderID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := db.Raw(folderAncestorsCTE+` SELECT id FROM ancestors ORDER BY depth DESC`, folderID).Scan(&ids).Error
+	return ids, err
+}
+
+// folderSubtreeIDs trả về folder và mọi folder con, cha đứng trước con
+func folderSubtreeIDs(db *gorm.DB, folderID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := db.Raw(folderDescendantsCTE+` SELECT id FROM descendants ORDER BY depth`, folderID).Scan(&ids).Error
+	return ids, err
+}
+
+// GetBreadcrumbs implements entity.FolderRepository.
+// Chỉ trả về phần đường dẫn user nhìn thấy được: bắt đầu từ folder cha cao nhất mà user có share.
+func (f *FolderRepositoryImpl) GetBreadcrumbs(ctx context.Context, folderID, userID uuid.UUID) ([]*entity.Folder, error) {
+	db := f.db.WithContext(ctx)
+	chain, err := folderChainIDs(db, folderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	grants, err := shareGrants(db, "folder_shares", "folder_id", chain, userID)
+	if err != nil {
+		return nil, err
+	}
+	granted := make(map[uuid.UUID]bool, len(grants))
+	for _, g := range grants {
+		granted[g.AssetID] = true
+	}
+	start := 0
+	for start < len(chain) && !granted[chain[start]] {
+		start++
+	}
+	if start == len(chain) {
+		return []*entity.Folder{}, nil
+	}
+	chain = chain[start:]
+
+	var models []model.FolderModel
+	if err := db.Where("id IN ?", chain).Find(&models).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*entity.Folder, len(models))
+	for _, m := range models {
+		byID[m.ID] = m.ToDomain()
+	}
+
+	breadcrumbs := make([]*entity.Folder, 0, len(chain))
+	for _, id := range chain {
+		if folder, ok := byID[id]; ok {
+			breadcrumbs = append(breadcrumbs, folder)
+		}
+	}
+	return breadcrumbs, nil
+}
+
+// GetChildren implements entity.FolderRepository.
+func (f *FolderRepositoryImpl) GetChildren(ctx context.Context, folderID uuid.UUID) ([]*entity.Folder, error) {
+	var models []model.FolderModel
+	if err := f.db.WithContext(ctx).Where("parent_id = ?", folderID).Order("name").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	folders := make([]*entity.Folder, len(models))
+	for i, m := range models {
+		folders[i] = m.ToDomain()
+	}
+	return folders, nil
+}
+
+// GetSubtreeIDs implements entity.FolderRepository.
+func (f *FolderRepositoryImpl) GetSubtreeIDs(ctx context.Context, folderID uuid.UUID) ([]uuid.UUID, error) {
+	return folderSubtreeIDs(f.db.WithContext(ctx), folderID)
+}
+
+// SetParent implements entity.FolderRepository.
+func (f *FolderRepositoryImpl) SetParent(ctx context.Context, folderID uuid.UUID, parentID *uuid.UUID) error {
+	return f.db.WithContext(ctx).Model(&model.FolderModel{}).Where("id = ?", folderID).Update("parent_id", parentID).Error
+}
+
 // GetByID implements entity.FolderRepository.
 func (f *FolderRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.Folder, error) {
 	var model model.FolderModel
@@ -112,18 +376,59 @@ func (f *FolderRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*enti
 }
 
 // Update implements entity.FolderRepository.
+// Nếu folder.Version > 0 thì chỉ cập nhật khi version trong DB khớp, ngược lại trả về entity.ErrStaleVersion.
 func (f *FolderRepositoryImpl) Update(ctx context.Context, folder *entity.Folder) error {
-	model := model.FolderModelFromDomain(folder)
-	return f.db.WithContext(ctx).Model(&model).Updates(map[string]interface{}{
-		"name": model.Name,
-	}).Error
+	query := f.db.WithContext(ctx).Model(&model.FolderModel{}).Where("id = ?", folder.ID)
+	if folder.Version > 0 {
+		query = query.Where("version = ?", folder.Version)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"name":    folder.Name,
+		"version": gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if folder.Version > 0 {
+			return entity.ErrStaleVersion
+		}
+		return gorm.ErrRecordNotFound
+	}
+
+	if folder.Version > 0 {
+		folder.Version++
+		return nil
+	}
+	return f.db.WithContext(ctx).Model(&model.FolderModel{}).Where("id = ?", folder.ID).Pluck("version", &folder.Version).Error
 }
 
-func (r *FolderRepositoryImpl) ShareFolder(ctx context.Context, folderID, userID uuid.UUID, accessLevel entity.AccessLevel) error {
+// ShareFolder tạo hoặc cập nhật share trực tiếp của folder cho user
+func (r *FolderRepositoryImpl) ShareFolder(ctx context.Context, folderID, userID uuid.UUID, accessLevel entity.AccessLevel, expiresAt *time.Time) error {
+	// Không ghi đè share OWNER: owner tự share cho mình hoặc co-owner share cho owner sẽ hạ quyền owner
+	result := r.db.WithContext(ctx).Table("folder_shares").
+		Where("folder_id = ? AND user_id = ? AND access_level <> ?", folderID, userID, entity.AccessLevelOwner).
+		Updates(shareUpdates(accessLevel, expiresAt))
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+
+	var owners int64
+	if err := r.db.WithContext(ctx).Table("folder_shares").
+		Where("folder_id = ? AND user_id = ? AND access_level = ?", folderID, userID, entity.AccessLevelOwner).
+		Count(&owners).Error; err != nil {
+		return err
+	}
+	if owners > 0 {
+		return entity.ErrShareTargetIsOwner
+	}
+
 	return r.db.WithContext(ctx).Table("folder_shares").Create(map[string]interface{}{
 		"folder_id":    folderID,
 		"user_id":      userID,
 		"access_level": accessLevel,
+		"expires_at":   expiresAt,
 	}).Error
 }
 
@@ -131,8 +436,87 @@ func (r *FolderRepositoryImpl) RevokeAccess(ctx context.Context, folderID, userI
 	return r.db.WithContext(ctx).Table("folder_shares").Where("folder_id = ? AND user_id = ?", folderID, userID).Delete(nil).Error
 }
 
+// ShareFolderWithTeam tạo hoặc cập nhật share của folder cho cả một team
+func (r *FolderRepositoryImpl) ShareFolderWithTeam(ctx context.Context, folderID, teamID uuid.UUID, accessLevel entity.AccessLevel, expiresAt *time.Time) error {
+	db := r.db.WithContext(ctx)
+	result := db.Model(&model.FolderShareModel{}).
+		Where("folder_id = ? AND team_id = ?", folderID, teamID).
+		Updates(shareUpdates(accessLevel, expiresAt))
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+
+	return db.Create(&model.FolderShareModel{
+		FolderID:    folderID,
+		TeamID:      &teamID,
+		AccessLevel: accessLevel,
+		ExpiresAt:   expiresAt,
+	}).Error
+}
+
+// RevokeTeamAccess xóa share của folder cho team
+func (r *FolderRepositoryImpl) RevokeTeamAccess(ctx context.Context, folderID, teamID uuid.UUID) error {
+	return r.db.WithContext(ctx).Table("folder_shares").Where("folder_id = ? AND team_id = ?", folderID, teamID).Delete(nil).Error
+}
+
 func (r *FolderRepositoryImpl) ChangeAccessLevel(ctx context.Context, folderID, userID uuid.UUID, accessLevel entity.AccessLevel) error {
 	return r.db.WithContext(ctx).Table("folder_shares").
 		Where("folder_id = ? AND user_id = ?", folderID, userID).
 		Update("access_level", accessLevel).Error
 }
+
+// ListExpiredShares implements entity.FolderRepository.
+func (r *FolderRepositoryImpl) ListExpiredShares(ctx context.Context, now time.Time, limit int) ([]*entity.FolderShare, error) {
+	var models []model.FolderShareModel
+	err := r.db.WithContext(ctx).
+		Where("expires_at <= ?", now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return folderSharesToDomain(models), nil
+}
+
+// DeleteExpiredShare implements entity.FolderRepository.
+// Điều kiện hết hạn được kiểm tra lại lúc xoá để không xoá share vừa được gia hạn.
+func (r *FolderRepositoryImpl) DeleteExpiredShare(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND expires_at <= ?", id, now).
+		Delete(&model.FolderShareModel{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListSharesExpiringBefore implements entity.FolderRepository.
+// Folder trong thùng rác không được nhắc vì user đã không truy cập được.
+func (r *FolderRepositoryImpl) ListSharesExpiringBefore(ctx context.Context, now, before time.Time, limit int) ([]*entity.FolderShare, error) {
+	var models []model.FolderShareModel
+	err := r.db.WithContext(ctx).
+		Joins("JOIN folders a ON a.id = folder_shares.folder_id AND a.deleted_at IS NULL").
+		Where("folder_shares.user_id IS NOT NULL AND folder_shares.reminder_sent_at IS NULL").
+		Where("folder_shares.expires_at > ? AND folder_shares.expires_at <= ?", now, before).
+		Order("folder_shares.expires_at").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return folderSharesToDomain(models), nil
+}
+
+// MarkShareReminded implements entity.FolderRepository.
+func (r *FolderRepositoryImpl) MarkShareReminded(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.FolderShareModel{}).
+		Where("id = ? AND reminder_sent_at IS NULL", id).
+		Update("reminder_sent_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+func folderSharesToDomain(models []model.FolderShareModel) []*entity.FolderShare {
+	shares := make([]*entity.FolderShare, len(models))
+	for i := range models {
+		shares[i] = models[i].ToDomain()
+	}
+	return shares
+}
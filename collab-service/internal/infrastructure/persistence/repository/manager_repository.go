@@ -2,7 +2,9 @@ package repository
 
 import (
 	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/persistence/model"
 	"context"
+	"strings"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -18,71 +20,70 @@ func NewManagerRepository(db *gorm.DB) *ManagerRepositoryImpl {
 	}
 }
 
-// GetAssetsByTeamID retrieves all notes that are accessible by members of a team
-func (r *ManagerRepositoryImpl) GetAssetsByTeamID(ctx context.Context, teamID uuid.UUID) ([]*entity.Note, error) {
-	var notes []*entity.Note
+// GetAssetsByTeamIDs retrieves all notes that are accessible by the given teams or by any of their
+// active members, either through a note share or through a share on the folder or one of its ancestors
+func (r *ManagerRepositoryImpl) GetAssetsByTeamIDs(ctx context.Context, teamIDs []uuid.UUID) ([]*entity.Note, error) {
+	if len(teamIDs) == 0 {
+		return []*entity.Note{}, nil
+	}
 
-	// Find notes owned by team members
-	ownedNotes := r.db.WithContext(ctx).
-		Table("notes n").
-		Select("n.*").
-		Joins("JOIN team_members tm ON n.owner_id = tm.user_id").
-		Where("tm.team_id = ?", teamID)
+	var members []uuid.UUID
+	if err := r.db.WithContext(ctx).
+		Model(&model.RosterModel{}).
+		Distinct("user_id").
+		Where("team_id IN ?", teamIDs).
+		Where(activeRosterSQL).
+		Pluck("user_id", &members).Error; err != nil {
+		return nil, err
+	}
 
-	// Find notes shared with the team
-	sharedNotes := r.db.WithContext(ctx).
-		Table("notes n").
-		Select("n.*").
-		Joins("JOIN note_shares ns ON n.id = ns.note_id").
-		Joins("JOIN team_members tm ON ns.team_id = tm.team_id").
-		Where("tm.team_id = ?", teamID)
+	conditions := make([]string, 0, len(members)+len(teamIDs))
+	args := make([]interface{}, 0, 4*len(members)+2*len(teamIDs))
 
-	// Combine the queries
-	err := r.db.WithContext(ctx).
-		Table("(?) UNION (?)", ownedNotes, sharedNotes).
-		Scan(&notes).Error
+	// Notes each member can access: shared directly or through their teams, or living
+	// anywhere below a folder they can access
+	for _, memberID := range members {
+		conditions = append(conditions, canAccessNoteSQL("notes"))
+		args = append(args, memberID, memberID, memberID, memberID)
+	}
+
+	// Notes shared with the teams themselves, including folders shared with them and their sub-folders
+	for _, teamID := range teamIDs {
+		conditions = append(conditions, teamNoteSQL("notes"))
+		args = append(args, teamID, teamID)
+	}
 
+	var models []model.NoteModel
+	err := r.db.WithContext(ctx).
+		Model(&model.NoteModel{}).
+		Where(strings.Join(conditions, " OR "), args...).
+		Find(&models).Error
 	if err != nil {
 		return nil, err
 	}
 
+	notes := make([]*entity.Note, len(models))
+	for i, m := range models {
+		notes[i] = m.ToDomain()
+	}
 	return notes, nil
 }
 
-// GetAssetsByUserID retrieves all notes owned by or shared with the user
+// GetAssetsByUserID retrieves all notes the user owns or can access: notes shared with them directly
+// (the OWNER share marks ownership) or through their teams, and notes below folders they can access
 func (r *ManagerRepositoryImpl) GetAssetsByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Note, error) {
-	var notes []*entity.Note
-
-	// Find notes owned by the user
-	ownedNotes := r.db.WithContext(ctx).
-		Table("notes n").
-		Select("DISTINCT n.*").
-		Joins("LEFT JOIN note_shares ns ON n.id = ns.note_id").
-		Where("n.owner_id = ?", userID)
-
-	// Find notes directly shared with the user
-	directlyShared := r.db.WithContext(ctx).
-		Table("notes n").
-		Select("n.*").
-		Joins("JOIN note_shares ns ON n.id = ns.note_id").
-		Where("ns.user_id = ?", userID)
-
-	// Find notes shared with teams the user belongs to
-	teamShared := r.db.WithContext(ctx).
-		Table("notes n").
-		Select("n.*").
-		Joins("JOIN note_shares ns ON n.id = ns.note_id").
-		Joins("JOIN team_members tm ON ns.team_id = tm.team_id").
-		Where("tm.user_id = ?", userID)
-
-	// Combine the queries
+	var models []model.NoteModel
 	err := r.db.WithContext(ctx).
-		Table("(?) UNION (?) UNION (?)", ownedNotes, directlyShared, teamShared).
-		Scan(&notes).Error
-
+		Model(&model.NoteModel{}).
+		Where(canAccessNoteSQL("notes"), userID, userID, userID, userID).
+		Find(&models).Error
 	if err != nil {
 		return nil, err
 	}
 
+	notes := make([]*entity.Note, len(models))
+	for i, m := range models {
+		notes[i] = m.ToDomain()
+	}
 	return notes, nil
 }
@@ -4,6 +4,7 @@ import (
 	"collab-service/internal/domain/entity"
 	"collab-service/internal/infrastructure/persistence/model"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -30,12 +31,13 @@ func (r *NoteRepositoryImpl) Create(ctx context.Context, note *entity.Note, user
 			return err
 		}
 
-		// Gán lại ID mới được DB sinh ra vào domain
+		// Gán lại ID và version mới được DB sinh ra vào domain
 		note.ID = noteModel.ID
+		note.Version = noteModel.Version
 		// Tạo record share cho owner
 		noteShare := model.NoteShareModel{
 			NoteID:      noteModel.ID,
-			UserID:      userId,
+			UserID:      &userId,
 			AccessLevel: entity.AccessLevelOwner,
 		}
 		if err := tx.Create(&noteShare).Error; err != nil {
@@ -76,45 +78,37 @@ func (r *NoteRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity
 }
 
 func (r *NoteRepositoryImpl) GetFolderAccessLevel(ctx context.Context, folderID, userID uuid.UUID) (entity.AccessLevel, error) {
-	var accessLevelStr string
-	var shareModel model.FolderShareModel
-
-	if err := r.db.WithContext(ctx).Table("folder_shares").Where("folder_id = ? AND user_id = ?", folderID, userID).First(&shareModel).Error; err != nil {
+	grants, err := folderGrants(r.db.WithContext(ctx), folderID, userID)
+	if err != nil {
 		return entity.AccessLevelNone, err
 	}
-
-	accessLevelStr = string(shareModel.AccessLevel)
-
-	return entity.AccessLevel(accessLevelStr), nil
+	if len(grants) == 0 {
+		return entity.AccessLevelNone, gorm.ErrRecordNotFound
+	}
+	return highestGrant(grants), nil
 }
 
 // GetAccessLevel implements entity.NoteRepository.
+// Quyền hiệu lực là quyền cao nhất giữa share trên note và share trên folder chứa note hoặc các folder cha,
+// tính cả share trực tiếp và share cho các team mà user là thành viên.
 func (r *NoteRepositoryImpl) GetAccessLevel(ctx context.Context, noteID uuid.UUID, userID uuid.UUID) (entity.AccessLevel, error) {
-	type result struct {
-		NoteAccessLevel   entity.AccessLevel
-		FolderAccessLevel entity.AccessLevel
-	}
-
-	var rs result
-
-	err := r.db.WithContext(ctx).
-		Table("notes").
-		Select(`
-			note_shares.access_level   AS note_access_level,
-			folder_shares.access_level AS folder_access_level
-		`).
-		Joins("LEFT JOIN note_shares ON notes.id = note_shares.note_id AND note_shares.user_id = ?", userID).
-		Joins("LEFT JOIN folders ON notes.folder_id = folders.id").
-		Joins("LEFT JOIN folder_shares ON folders.id = folder_shares.folder_id AND folder_shares.user_id = ?", userID).
-		Where("notes.id = ?", noteID).
-		Scan(&rs).Error
-
+	grants, err := noteGrants(r.db.WithContext(ctx), noteID, userID)
 	if err != nil {
 		return entity.AccessLevelNone, err
 	}
 
 	// Trả về quyền cao nhất
-	return entity.MaxAccessLevel(rs.NoteAccessLevel, rs.FolderAccessLevel), nil
+	return highestGrant(grants), nil
+}
+
+// ExplainAccess implements entity.NoteRepository.
+// Dùng cùng tập grant với GetAccessLevel nên không thể lệch khỏi kiểm tra quyền thực tế.
+func (r *NoteRepositoryImpl) ExplainAccess(ctx context.Context, noteID, userID uuid.UUID) (*entity.AccessExplanation, error) {
+	grants, err := noteGrants(r.db.WithContext(ctx), noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return explainGrants(entity.ResourceNote, noteID, userID, grants), nil
 }
 
 // GetByFolderID implements entity.NoteRepository.
@@ -131,27 +125,38 @@ func (r *NoteRepositoryImpl) GetByFolderID(ctx context.Context, folderID uuid.UU
 	return notes, nil
 }
 
+// canAccessQuery lấy tất cả notes mà user có quyền:
+//  1. Share trực tiếp hoặc qua team qua note_shares
+//  2. Nằm trong các folder user truy cập được, kể cả quyền kế thừa từ folder cha
+func (r *NoteRepositoryImpl) canAccessQuery(ctx context.Context, userID uuid.UUID) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Model(&model.NoteModel{}).
+		Where(canAccessNoteSQL("notes"), userID, userID, userID, userID)
+}
+
 func (r *NoteRepositoryImpl) GetAllCanAccess(ctx context.Context, userID uuid.UUID) ([]*entity.Note, error) {
 	var models []model.NoteModel
-	if err := r.db.WithContext(ctx).Model(&model.NoteModel{}).Where("user_id = ?", userID).Find(&models).Error; err != nil {
+	if err := r.canAccessQuery(ctx, userID).Find(&models).Error; err != nil {
 		return nil, err
 	}
 
-	// Lấy tất cả notes mà user có quyền:
-	//  1) Share trực tiếp qua note_shares
-	//  2) Nằm trong các folder mà user là owner hoặc được share qua folders_share
-	if err := r.db.WithContext(ctx).
-		Model(&model.NoteModel{}).
-		Joins("LEFT JOIN folders ON notes.folder_id = folders.id").
-		Joins("LEFT JOIN note_shares ON notes.id = note_shares.note_id").
-		Joins("LEFT JOIN folders_share ON folders.id = folders_share.folder_id").
-		Where(`
-        folders.user_id = ?                    -- user sở hữu folder chứa note
-        OR note_shares.user_id = ?            -- note được share trực tiếp
-        OR folders_share.user_id = ?          -- folder được share với user
-    `, userID, userID, userID).
-		Group("notes.id"). // tránh duplicate
-		Find(&models).Error; err != nil {
+	notes := make([]*entity.Note, len(models))
+	for i, model := range models {
+		notes[i] = model.ToDomain()
+	}
+	return notes, nil
+}
+
+// GetAllCanAccessByTags implements entity.NoteRepository.
+// Mỗi nhóm trong tagGroups là các tag được chấp nhận cho một bộ lọc; note phải khớp mọi nhóm.
+func (r *NoteRepositoryImpl) GetAllCanAccessByTags(ctx context.Context, userID uuid.UUID, tagGroups [][]uuid.UUID) ([]*entity.Note, error) {
+	query := r.canAccessQuery(ctx, userID)
+	for _, tagIDs := range tagGroups {
+		query = query.Where("EXISTS (SELECT 1 FROM note_tags nt WHERE nt.note_id = notes.id AND nt.tag_id IN ?)", tagIDs)
+	}
+
+	var models []model.NoteModel
+	if err := query.Find(&models).Error; err != nil {
 		return nil, err
 	}
 
@@ -162,11 +167,31 @@ func (r *NoteRepositoryImpl) GetAllCanAccess(ctx context.Context, userID uuid.UU
 	return notes, nil
 }
 
-func (r *NoteRepositoryImpl) ShareNote(ctx context.Context, noteID, userID uuid.UUID, accessLevel entity.AccessLevel) error {
+// ShareNote tạo hoặc cập nhật share trực tiếp của note cho user
+func (r *NoteRepositoryImpl) ShareNote(ctx context.Context, noteID, userID uuid.UUID, accessLevel entity.AccessLevel, expiresAt *time.Time) error {
+	// Không ghi đè share OWNER: owner tự share cho mình hoặc co-owner share cho owner sẽ hạ quyền owner
+	result := r.db.WithContext(ctx).Table("note_shares").
+		Where("note_id = ? AND user_id = ? AND access_level <> ?", noteID, userID, entity.AccessLevelOwner).
+		Updates(shareUpdates(accessLevel, expiresAt))
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+
+	var owners int64
+	if err := r.db.WithContext(ctx).Table("note_shares").
+		Where("note_id = ? AND user_id = ? AND access_level = ?", noteID, userID, entity.AccessLevelOwner).
+		Count(&owners).Error; err != nil {
+		return err
+	}
+	if owners > 0 {
+		return entity.ErrShareTargetIsOwner
+	}
+
 	return r.db.WithContext(ctx).Table("note_shares").Create(map[string]interface{}{
 		"note_id":      noteID,
 		"user_id":      userID,
 		"access_level": accessLevel,
+		"expires_at":   expiresAt,
 	}).Error
 }
 
@@ -174,6 +199,29 @@ func (r *NoteRepositoryImpl) RevokeAccess(ctx context.Context, noteID, userID uu
 	return r.db.WithContext(ctx).Table("note_shares").Where("note_id = ? AND user_id = ?", noteID, userID).Delete(nil).Error
 }
 
+// ShareNoteWithTeam tạo hoặc cập nhật share của note cho cả một team
+func (r *NoteRepositoryImpl) ShareNoteWithTeam(ctx context.Context, noteID, teamID uuid.UUID, accessLevel entity.AccessLevel, expiresAt *time.Time) error {
+	db := r.db.WithContext(ctx)
+	result := db.Model(&model.NoteShareModel{}).
+		Where("note_id = ? AND team_id = ?", noteID, teamID).
+		Updates(shareUpdates(accessLevel, expiresAt))
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+
+	return db.Create(&model.NoteShareModel{
+		NoteID:      noteID,
+		TeamID:      &teamID,
+		AccessLevel: accessLevel,
+		ExpiresAt:   expiresAt,
+	}).Error
+}
+
+// RevokeTeamAccess xóa share của note cho team
+func (r *NoteRepositoryImpl) RevokeTeamAccess(ctx context.Context, noteID, teamID uuid.UUID) error {
+	return r.db.WithContext(ctx).Table("note_shares").Where("note_id = ? AND team_id = ?", noteID, teamID).Delete(nil).Error
+}
+
 func (r *NoteRepositoryImpl) ChangeAccessLevel(ctx context.Context, noteID, userID uuid.UUID, accessLevel entity.AccessLevel) error {
 
 	return r.db.WithContext(ctx).Table("note_shares").
@@ -181,19 +229,94 @@ func (r *NoteRepositoryImpl) ChangeAccessLevel(ctx context.Context, noteID, user
 		Update("access_level", accessLevel).Error
 }
 
+// ListExpiredShares implements entity.NoteRepository.
+func (r *NoteRepositoryImpl) ListExpiredShares(ctx context.Context, now time.Time, limit int) ([]*entity.NoteShare, error) {
+	var models []model.NoteShareModel
+	err := r.db.WithContext(ctx).
+		Where("expires_at <= ?", now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return noteSharesToDomain(models), nil
+}
+
+// DeleteExpiredShare implements entity.NoteRepository.
+// Điều kiện hết hạn được kiểm tra lại lúc xoá để không xoá share vừa được gia hạn.
+func (r *NoteRepositoryImpl) DeleteExpiredShare(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND expires_at <= ?", id, now).
+		Delete(&model.NoteShareModel{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListSharesExpiringBefore implements entity.NoteRepository.
+// Note trong thùng rác không được nhắc vì user đã không truy cập được.
+func (r *NoteRepositoryImpl) ListSharesExpiringBefore(ctx context.Context, now, before time.Time, limit int) ([]*entity.NoteShare, error) {
+	var models []model.NoteShareModel
+	err := r.db.WithContext(ctx).
+		Joins("JOIN notes a ON a.id = note_shares.note_id AND a.deleted_at IS NULL").
+		Where("note_shares.user_id IS NOT NULL AND note_shares.reminder_sent_at IS NULL").
+		Where("note_shares.expires_at > ? AND note_shares.expires_at <= ?", now, before).
+		Order("note_shares.expires_at").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return noteSharesToDomain(models), nil
+}
+
+// MarkShareReminded implements entity.NoteRepository.
+func (r *NoteRepositoryImpl) MarkShareReminded(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.NoteShareModel{}).
+		Where("id = ? AND reminder_sent_at IS NULL", id).
+		Update("reminder_sent_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+func noteSharesToDomain(models []model.NoteShareModel) []*entity.NoteShare {
+	shares := make([]*entity.NoteShare, len(models))
+	for i := range models {
+		shares[i] = models[i].ToDomain()
+	}
+	return shares
+}
+
+// Update cập nhật title và body, luôn bỏ qua folder_id.
+// Nếu note.Version > 0 thì chỉ cập nhật khi version trong DB khớp, ngược lại trả về entity.ErrStaleVersion.
+// Sau khi cập nhật note.Version là version mới.
 func (r *NoteRepositoryImpl) Update(ctx context.Context, note *entity.Note) error {
-	noteModel := model.NoteModelFromDomain(note)
+	query := r.db.WithContext(ctx).Model(&model.NoteModel{}).Where("id = ?", note.ID)
+	if note.Version > 0 {
+		query = query.Where("version = ?", note.Version)
+	}
 
-	// Luôn bỏ qua folder_id
-	return r.db.WithContext(ctx).
-		Model(&noteModel).
-		Where("id = ?", note.ID).
-		Updates(map[string]interface{}{
-			"title": note.Title,
-			"body":  note.Body,
-		}).Error
+	result := query.Updates(map[string]interface{}{
+		"title":   note.Title,
+		"body":    note.Body,
+		"version": gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if note.Version > 0 {
+			return entity.ErrStaleVersion
+		}
+		return gorm.ErrRecordNotFound
+	}
+
+	if note.Version > 0 {
+		note.Version++
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&model.NoteModel{}).Where("id = ?", note.ID).Pluck("version", &note.Version).Error
 }
 
+// Delete xoá vĩnh viễn note cùng share và lịch sử revision, kể cả khi note đang trong thùng rác
 func (r *NoteRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Xoá tất cả share liên quan
@@ -203,8 +326,34 @@ func (r *NoteRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 			return err
 		}
 
-		// Xoá note
+		// Xoá lịch sử revision, tag, comment, link công khai, yêu cầu truy cập và metadata file đính kèm (blob được dọn ở tầng service)
+		if err := tx.WithContext(ctx).
+			Where("note_id = ?", id).
+			Delete(&model.NoteRevisionModel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.WithContext(ctx).
+			Where("note_id = ?", id).
+			Delete(&model.NoteTagModel{}).Error; err != nil {
+			return err
+		}
 		if err := tx.WithContext(ctx).
+			Where("note_id = ?", id).
+			Delete(&model.AttachmentModel{}).Error; err != nil {
+			return err
+		}
+		if err := deleteNoteComments(tx, []uuid.UUID{id}); err != nil {
+			return err
+		}
+		if err := deleteShareLinks(tx, entity.ShareLinkNote, []uuid.UUID{id}); err != nil {
+			return err
+		}
+		if err := deleteAccessRequests(tx, entity.AccessRequestNote, []uuid.UUID{id}); err != nil {
+			return err
+		}
+
+		// Xoá note
+		if err := tx.WithContext(ctx).Unscoped().
 			Delete(&model.NoteModel{}, "id = ?", id).Error; err != nil {
 			return err
 		}
@@ -212,3 +361,152 @@ func (r *NoteRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 		return nil
 	})
 }
+
+// Trash implements entity.NoteRepository.
+func (r *NoteRepositoryImpl) Trash(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&model.NoteModel{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"deleted_at": time.Now(),
+		"deleted_by": deletedBy,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Restore implements entity.NoteRepository.
+func (r *NoteRepositoryImpl) Restore(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Unscoped().Model(&model.NoteModel{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"deleted_by": nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetTrashed implements entity.NoteRepository.
+func (r *NoteRepositoryImpl) GetTrashed(ctx context.Context, id uuid.UUID) (*entity.Note, error) {
+	var m model.NoteModel
+	if err := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(&m, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// ListTrash implements entity.NoteRepository.
+func (r *NoteRepositoryImpl) ListTrash(ctx context.Context, userID uuid.UUID) ([]*entity.Note, error) {
+	var models []model.NoteModel
+	if err := r.db.WithContext(ctx).Unscoped().Model(&model.NoteModel{}).
+		Where("notes.deleted_at IS NOT NULL").
+		Where("NOT EXISTS (SELECT 1 FROM folders p WHERE p.id = notes.folder_id AND p.deleted_at = notes.deleted_at)").
+		Where("notes.deleted_by = ? OR notes.id IN (SELECT note_id FROM note_shares WHERE user_id = ? AND access_level = ?)",
+			userID, userID, entity.AccessLevelOwner).
+		Order("notes.deleted_at DESC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	notes := make([]*entity.Note, len(models))
+	for i, m := range models {
+		notes[i] = m.ToDomain()
+	}
+	return notes, nil
+}
+
+// ListTrashedBefore implements entity.NoteRepository.
+func (r *NoteRepositoryImpl) ListTrashedBefore(ctx context.Context, cutoff time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := r.db.WithContext(ctx).Unscoped().Model(&model.NoteModel{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Move chuyển note sang folder khác. Với NoteSharesReset chỉ share OWNER được giữ lại.
+func (r *NoteRepositoryImpl) Move(ctx context.Context, noteID, folderID uuid.UUID, policy entity.NoteSharePolicy) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.NoteModel{}).Where("id = ?", noteID).Update("folder_id", folderID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if policy == entity.NoteSharesReset {
+			return tx.Where("note_id = ? AND access_level <> ?", noteID, entity.AccessLevelOwner).
+				Delete(&model.NoteShareModel{}).Error
+		}
+		return nil
+	})
+}
+
+// Copy tạo bản sao của note trong folder đích, user thực hiện là owner của bản sao.
+// Với NoteSharesKeep các share khác (user và team) được sao chép sang bản sao.
+func (r *NoteRepositoryImpl) Copy(ctx context.Context, noteID, folderID, userID uuid.UUID, policy entity.NoteSharePolicy) (*entity.Note, error) {
+	var copied *entity.Note
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var source model.NoteModel
+		if err := tx.First(&source, "id = ?", noteID).Error; err != nil {
+			return err
+		}
+
+		noteCopy := model.NoteModel{
+			Title:    source.Title,
+			Body:     source.Body,
+			FolderID: folderID,
+		}
+		if err := tx.Create(&noteCopy).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&model.NoteShareModel{
+			NoteID:      noteCopy.ID,
+			UserID:      &userID,
+			AccessLevel: entity.AccessLevelOwner,
+		}).Error; err != nil {
+			return err
+		}
+
+		if policy == entity.NoteSharesKeep {
+			var shares []model.NoteShareModel
+			if err := tx.Where("note_id = ? AND access_level <> ?", noteID, entity.AccessLevelOwner).
+				Where("user_id IS NULL OR user_id <> ?", userID).
+				Where(activeShareSQL).
+				Find(&shares).Error; err != nil {
+				return err
+			}
+			for _, share := range shares {
+				if err := tx.Create(&model.NoteShareModel{
+					NoteID:      noteCopy.ID,
+					UserID:      share.UserID,
+					TeamID:      share.TeamID,
+					AccessLevel: share.AccessLevel,
+					ExpiresAt:   share.ExpiresAt,
+				}).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		copied = noteCopy.ToDomain()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
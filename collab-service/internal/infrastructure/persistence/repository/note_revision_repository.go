@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/persistence/model"
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NoteRevisionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewNoteRevisionRepository(db *gorm.DB) entity.NoteRevisionRepository {
+	return &NoteRevisionRepositoryImpl{
+		db: db,
+	}
+}
+
+// Append implements entity.NoteRevisionRepository
+func (r *NoteRevisionRepositoryImpl) Append(ctx context.Context, revision *entity.NoteRevision, keep int) (*entity.NoteRevision, error) {
+	var saved *entity.NoteRevision
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Khoá dòng note để hai lần cập nhật đồng thời không lấy trùng số revision
+		var note model.NoteModel
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&note, "id = ?", revision.NoteID).Error; err != nil {
+			return err
+		}
+
+		var last int
+		if err := tx.Model(&model.NoteRevisionModel{}).
+			Where("note_id = ?", revision.NoteID).
+			Select("COALESCE(MAX(number), 0)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+
+		m := model.NoteRevisionModelFromDomain(revision)
+		m.Number = last + 1
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+
+		if keep > 0 {
+			if err := tx.Where("note_id = ? AND number <= ?", revision.NoteID, m.Number-keep).
+				Delete(&model.NoteRevisionModel{}).Error; err != nil {
+				return err
+			}
+		}
+
+		saved = m.ToDomain()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// Latest implements entity.NoteRevisionRepository, trả về nil nếu note chưa có revision nào
+func (r *NoteRevisionRepositoryImpl) Latest(ctx context.Context, noteID uuid.UUID) (*entity.NoteRevision, error) {
+	var models []model.NoteRevisionModel
+	if err := r.db.WithContext(ctx).Where("note_id = ?", noteID).Order("number DESC").Limit(1).Find(&models).Error; err != nil {
+		return nil, err
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+	return models[0].ToDomain(), nil
+}
+
+// GetByNumber implements entity.NoteRevisionRepository
+func (r *NoteRevisionRepositoryImpl) GetByNumber(ctx context.Context, noteID uuid.UUID, number int) (*entity.NoteRevision, error) {
+	var m model.NoteRevisionModel
+	if err := r.db.WithContext(ctx).First(&m, "note_id = ? AND number = ?", noteID, number).Error; err != nil {
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// List implements entity.NoteRevisionRepository, newest first
+func (r *NoteRevisionRepositoryImpl) List(ctx context.Context, noteID uuid.UUID, offset, limit int) ([]*entity.NoteRevision, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.NoteRevisionModel{}).Where("note_id = ?", noteID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []model.NoteRevisionModel
+	if err := query.Order("number DESC").Offset(offset).Limit(limit).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	revisions := make([]*entity.NoteRevision, len(models))
+	for i, m := range models {
+		revisions[i] = m.ToDomain()
+	}
+	return revisions, total, nil
+}
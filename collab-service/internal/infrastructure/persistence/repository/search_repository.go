@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"collab-service/internal/domain/entity"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// searchConfig là text search configuration của Postgres. "simple" không stem và không bỏ stop word,
+// phù hợp với nội dung trộn tiếng Việt và tiếng Anh.
+const searchConfig = "simple"
+
+// noteDocumentSQL và folderDocumentSQL phải giữ đúng biểu thức của GIN index trong migration
+// thì Postgres mới dùng được index. Từ khớp trong title được xếp hạng cao hơn trong body.
+const (
+	noteDocumentSQL   = `(setweight(to_tsvector('` + searchConfig + `', n.title), 'A') || setweight(to_tsvector('` + searchConfig + `', n.body), 'B'))`
+	folderDocumentSQL = `to_tsvector('` + searchConfig + `', f.name)`
+)
+
+// teamFolderIDsSQL trả về các folder team truy cập được qua team share, cùng toàn bộ folder con bên dưới
+const teamFolderIDsSQL = `
+	WITH RECURSIVE team_folders AS (
+		SELECT fs.folder_id AS id FROM folder_shares fs
+		JOIN folders sf ON sf.id = fs.folder_id AND sf.deleted_at IS NULL
+		WHERE fs.team_id = ? AND (fs.expires_at IS NULL OR fs.expires_at > NOW())
+		UNION
+		SELECT f.id FROM folders f JOIN team_folders t ON f.parent_id = t.id
+		WHERE f.deleted_at IS NULL
+	)
+	SELECT id FROM team_folders`
+
+var (
+	highlightTitleOptions = fmt.Sprintf("HighlightAll=true, StartSel=%s, StopSel=%s", entity.SearchMarkStart, entity.SearchMarkStop)
+	highlightBodyOptions  = fmt.Sprintf("MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=\" ... \", StartSel=%s, StopSel=%s", entity.SearchMarkStart, entity.SearchMarkStop)
+)
+
+type SearchRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) entity.SearchRepository {
+	return &SearchRepositoryImpl{
+		db: db,
+	}
+}
+
+type searchRow struct {
+	Type      entity.SearchHitType
+	ID        uuid.UUID
+	Title     string
+	ParentID  *uuid.UUID
+	Rank      float64
+	Highlight string
+	Snippet   string
+	UpdatedAt time.Time
+}
+
+// searchFilter gom các điều kiện WHERE và tham số tương ứng theo đúng thứ tự
+type searchFilter struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (f *searchFilter) add(condition string, args ...interface{}) {
+	f.conditions = append(f.conditions, condition)
+	f.args = append(f.args, args...)
+}
+
+func (f *searchFilter) sql() string {
+	return strings.Join(f.conditions, " AND ")
+}
+
+func noteSearchSQL(userID uuid.UUID, query *entity.SearchQuery) (string, []interface{}) {
+	filter := &searchFilter{}
+	filter.add(noteDocumentSQL + ` @@ s.q`)
+	filter.add(`n.deleted_at IS NULL`)
+	filter.add(canAccessNoteSQL("n"), userID, userID, userID, userID)
+
+	if query.FolderID != nil {
+		filter.add(`n.folder_id IN (`+folderDescendantsCTE+` SELECT id FROM descendants)`, *query.FolderID)
+	}
+	if query.TeamID != nil {
+		filter.add(teamNoteSQL("n"), *query.TeamID, *query.TeamID)
+	}
+	if query.OwnerID != nil {
+		filter.add(`n.id IN (SELECT note_id FROM note_shares WHERE user_id = ? AND access_level = ?)`, *query.OwnerID, entity.AccessLevelOwner)
+	}
+	if query.UpdatedFrom != nil {
+		filter.add(`n.updated_at >= ?`, *query.UpdatedFrom)
+	}
+	if query.UpdatedTo != nil {
+		filter.add(`n.updated_at < ?`, *query.UpdatedTo)
+	}
+
+	sql := `
+		SELECT 'NOTE' AS type, n.id, n.title, n.body, n.folder_id AS parent_id, n.updated_at,
+			ts_rank(` + noteDocumentSQL + `, s.q) AS rank
+		FROM notes n CROSS JOIN search s
+		WHERE ` + filter.sql()
+	return sql, filter.args
+}
+
+func folderSearchSQL(userID uuid.UUID, query *entity.SearchQuery) (string, []interface{}) {
+	filter := &searchFilter{}
+	filter.add(folderDocumentSQL + ` @@ s.q`)
+	filter.add(`f.deleted_at IS NULL`)
+	filter.add(`f.id IN (`+accessibleFolderIDsSQL+`)`, userID, userID)
+
+	if query.FolderID != nil {
+		filter.add(`f.id <> ? AND f.id IN (`+folderDescendantsCTE+` SELECT id FROM descendants)`, *query.FolderID, *query.FolderID)
+	}
+	if query.TeamID != nil {
+		filter.add(`f.id IN (`+teamFolderIDsSQL+`)`, *query.TeamID)
+	}
+	if query.OwnerID != nil {
+		filter.add(`f.id IN (SELECT folder_id FROM folder_shares WHERE user_id = ? AND access_level = ?)`, *query.OwnerID, entity.AccessLevelOwner)
+	}
+	if query.UpdatedFrom != nil {
+		filter.add(`f.updated_at >= ?`, *query.UpdatedFrom)
+	}
+	if query.UpdatedTo != nil {
+		filter.add(`f.updated_at < ?`, *query.UpdatedTo)
+	}
+
+	sql := `
+		SELECT 'FOLDER' AS type, f.id, f.name AS title, '' AS body, f.parent_id, f.updated_at,
+			ts_rank(` + folderDocumentSQL + `, s.q) AS rank
+		FROM folders f CROSS JOIN search s
+		WHERE ` + filter.sql()
+	return sql, filter.args
+}
+
+// Search implements entity.SearchRepository.
+// Truy vấn dùng cú pháp websearch của Postgres: "cụm từ", OR, -loại trừ.
+func (r *SearchRepositoryImpl) Search(ctx context.Context, userID uuid.UUID, query *entity.SearchQuery) ([]*entity.SearchHit, int64, error) {
+	parts := []string{}
+	args := []interface{}{query.Text}
+	if query.IncludesType(entity.SearchHitNote) {
+		sql, partArgs := noteSearchSQL(userID, query)
+		parts = append(parts, sql)
+		args = append(args, partArgs...)
+	}
+	if query.IncludesType(entity.SearchHitFolder) {
+		sql, partArgs := folderSearchSQL(userID, query)
+		parts = append(parts, sql)
+		args = append(args, partArgs...)
+	}
+	if len(parts) == 0 {
+		return []*entity.SearchHit{}, 0, nil
+	}
+
+	searchCTE := `WITH search AS (SELECT websearch_to_tsquery('` + searchConfig + `', ?) AS q)`
+	hitsSQL := strings.Join(parts, " UNION ALL ")
+	orderSQL := ` ORDER BY rank DESC, updated_at DESC, id`
+
+	db := r.db.WithContext(ctx)
+
+	var total int64
+	if err := db.Raw(searchCTE+` SELECT COUNT(*) FROM (`+hitsSQL+`) hits`, args...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Highlight chỉ tính cho các kết quả trong trang, ts_headline tốn kém hơn nhiều so với so khớp index
+	var rows []searchRow
+	if err := db.Raw(searchCTE+`, page AS (SELECT * FROM (`+hitsSQL+`) hits`+orderSQL+` LIMIT ? OFFSET ?)
+		SELECT page.type, page.id, page.title, page.parent_id, page.updated_at, page.rank,
+			ts_headline('`+searchConfig+`', page.title, s.q, ?) AS highlight,
+			CASE WHEN page.type = 'NOTE' THEN ts_headline('`+searchConfig+`', page.body, s.q, ?) ELSE '' END AS snippet
+		FROM page CROSS JOIN search s`+orderSQL,
+		append(args, query.Limit, query.Offset, highlightTitleOptions, highlightBodyOptions)...).
+		Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	hits := make([]*entity.SearchHit, len(rows))
+	for i, row := range rows {
+		hits[i] = &entity.SearchHit{
+			Type:      row.Type,
+			ID:        row.ID,
+			Title:     row.Title,
+			ParentID:  row.ParentID,
+			Rank:      row.Rank,
+			Highlight: row.Highlight,
+			Snippet:   row.Snippet,
+			UpdatedAt: row.UpdatedAt,
+		}
+	}
+	return hits, total, nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/persistence/model"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ShareLinkRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewShareLinkRepository(db *gorm.DB) entity.ShareLinkRepository {
+	return &ShareLinkRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create implements entity.ShareLinkRepository.
+func (r *ShareLinkRepositoryImpl) Create(ctx context.Context, link *entity.ShareLink) (*entity.ShareLink, error) {
+	m := model.ShareLinkModelFromDomain(link)
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return nil, err
+	}
+	created := m.ToDomain()
+	created.Token = link.Token
+	return created, nil
+}
+
+// GetByID implements entity.ShareLinkRepository.
+func (r *ShareLinkRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.ShareLink, error) {
+	var m model.ShareLinkModel
+	if err := r.db.WithContext(ctx).First(&m, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// GetByTokenHash implements entity.ShareLinkRepository.
+func (r *ShareLinkRepositoryImpl) GetByTokenHash(ctx context.Context, tokenHash string) (*entity.ShareLink, error) {
+	var m model.ShareLinkModel
+	if err := r.db.WithContext(ctx).First(&m, "token_hash = ?", tokenHash).Error; err != nil {
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// ListByAsset implements entity.ShareLinkRepository.
+func (r *ShareLinkRepositoryImpl) ListByAsset(ctx context.Context, assetType entity.ShareLinkAssetType, assetID uuid.UUID) ([]*entity.ShareLink, error) {
+	var models []model.ShareLinkModel
+	if err := r.db.WithContext(ctx).
+		Where("asset_type = ? AND asset_id = ?", string(assetType), assetID).
+		Order("created_at DESC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	links := make([]*entity.ShareLink, len(models))
+	for i := range models {
+		links[i] = models[i].ToDomain()
+	}
+	return links, nil
+}
+
+// CountActive implements entity.ShareLinkRepository.
+func (r *ShareLinkRepositoryImpl) CountActive(ctx context.Context, assetType entity.ShareLinkAssetType, assetID uuid.UUID, now time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.ShareLinkModel{}).
+		Where("asset_type = ? AND asset_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", string(assetType), assetID, now).
+		Count(&count).Error
+	return count, err
+}
+
+// Revoke implements entity.ShareLinkRepository.
+func (r *ShareLinkRepositoryImpl) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.ShareLinkModel{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+// RecordAccess implements entity.ShareLinkRepository.
+func (r *ShareLinkRepositoryImpl) RecordAccess(ctx context.Context, access *entity.ShareLinkAccess) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(model.ShareLinkAccessModelFromDomain(access)).Error; err != nil {
+			return err
+		}
+		if access.Outcome != entity.ShareLinkAccessGranted {
+			return nil
+		}
+		return tx.Model(&model.ShareLinkModel{}).
+			Where("id = ?", access.LinkID).
+			Updates(map[string]interface{}{
+				"view_count":     gorm.Expr("view_count + 1"),
+				"last_viewed_at": access.CreatedAt,
+			}).Error
+	})
+}
+
+// ListAccesses implements entity.ShareLinkRepository.
+func (r *ShareLinkRepositoryImpl) ListAccesses(ctx context.Context, linkID uuid.UUID, offset, limit int) ([]*entity.ShareLinkAccess, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.ShareLinkAccessModel{}).Where("link_id = ?", linkID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []model.ShareLinkAccessModel
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	accesses := make([]*entity.ShareLinkAccess, len(models))
+	for i := range models {
+		accesses[i] = models[i].ToDomain()
+	}
+	return accesses, total, nil
+}
+
+// CountFailedAttempts implements entity.ShareLinkRepository.
+func (r *ShareLinkRepositoryImpl) CountFailedAttempts(ctx context.Context, linkID uuid.UUID, ip string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.ShareLinkAccessModel{}).
+		Where("link_id = ? AND ip = ? AND outcome = ? AND created_at >= ?", linkID, ip, string(entity.ShareLinkAccessWrongPassword), since).
+		Count(&count).Error
+	return count, err
+}
+
+// deleteShareLinks xoá link công khai của các asset cùng access log của chúng
+func deleteShareLinks(tx *gorm.DB, assetType entity.ShareLinkAssetType, assetIDs []uuid.UUID) error {
+	linkIDs := tx.Model(&model.ShareLinkModel{}).Select("id").Where("asset_type = ? AND asset_id IN ?", string(assetType), assetIDs)
+	if err := tx.Where("link_id IN (?)", linkIDs).Delete(&model.ShareLinkAccessModel{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("asset_type = ? AND asset_id IN ?", string(assetType), assetIDs).Delete(&model.ShareLinkModel{}).Error
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/persistence/model"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// visibleTagSQL lọc các tag user nhìn thấy: tag cá nhân của user và tag của các team còn hiệu lực của user
+const visibleTagSQL = `(tags.owner_id = ? OR tags.team_id IN (` + activeTeamIDsSQL + `))`
+
+type TagRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) entity.TagRepository {
+	return &TagRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create implements entity.TagRepository.
+func (r *TagRepositoryImpl) Create(ctx context.Context, tag *entity.Tag) (*entity.Tag, error) {
+	tagModel := model.TagModelFromDomain(tag)
+	if err := r.db.WithContext(ctx).Create(tagModel).Error; err != nil {
+		return nil, err
+	}
+	return tagModel.ToDomain(), nil
+}
+
+// GetByID implements entity.TagRepository.
+func (r *TagRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.Tag, error) {
+	var m model.TagModel
+	if err := r.db.WithContext(ctx).First(&m, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// FindByName implements entity.TagRepository. Trả về nil nếu không có tag trùng tên.
+func (r *TagRepositoryImpl) FindByName(ctx context.Context, ownerID, teamID *uuid.UUID, name string) (*entity.Tag, error) {
+	query := r.db.WithContext(ctx).Where("LOWER(name) = LOWER(?)", name)
+	if teamID != nil {
+		query = query.Where("team_id = ?", *teamID)
+	} else {
+		query = query.Where("owner_id = ?", ownerID)
+	}
+
+	var m model.TagModel
+	if err := query.First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// ListVisible implements entity.TagRepository.
+func (r *TagRepositoryImpl) ListVisible(ctx context.Context, userID uuid.UUID) ([]*entity.Tag, error) {
+	var models []model.TagModel
+	if err := r.db.WithContext(ctx).
+		Where(visibleTagSQL, userID, userID).
+		Order("LOWER(name)").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return tagsToDomain(models), nil
+}
+
+// Update implements entity.TagRepository.
+func (r *TagRepositoryImpl) Update(ctx context.Context, tag *entity.Tag) error {
+	return r.db.WithContext(ctx).Model(&model.TagModel{}).Where("id = ?", tag.ID).Updates(map[string]interface{}{
+		"name":  tag.Name,
+		"color": tag.Color,
+	}).Error
+}
+
+// Delete implements entity.TagRepository.
+func (r *TagRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", id).Delete(&model.NoteTagModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.TagModel{}, "id = ?", id).Error
+	})
+}
+
+// ListByNote implements entity.TagRepository.
+func (r *TagRepositoryImpl) ListByNote(ctx context.Context, noteID, userID uuid.UUID) ([]*entity.Tag, error) {
+	var models []model.TagModel
+	if err := r.db.WithContext(ctx).
+		Joins("JOIN note_tags ON note_tags.tag_id = tags.id").
+		Where("note_tags.note_id = ?", noteID).
+		Where(visibleTagSQL, userID, userID).
+		Order("LOWER(tags.name)").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return tagsToDomain(models), nil
+}
+
+// AddToNote implements entity.TagRepository.
+func (r *TagRepositoryImpl) AddToNote(ctx context.Context, noteID, tagID, taggedBy uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model.NoteTagModel{
+		NoteID:   noteID,
+		TagID:    tagID,
+		TaggedBy: taggedBy,
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+// RemoveFromNote implements entity.TagRepository.
+func (r *TagRepositoryImpl) RemoveFromNote(ctx context.Context, noteID, tagID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Where("note_id = ? AND tag_id = ?", noteID, tagID).Delete(&model.NoteTagModel{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func tagsToDomain(models []model.TagModel) []*entity.Tag {
+	tags := make([]*entity.Tag, len(models))
+	for i, m := range models {
+		tags[i] = m.ToDomain()
+	}
+	return tags
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/persistence/model"
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TeamActivityRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTeamActivityRepository(db *gorm.DB) entity.TeamActivityRepository {
+	return &TeamActivityRepositoryImpl{
+		db: db,
+	}
+}
+
+// Record implements entity.TeamActivityRepository
+func (r *TeamActivityRepositoryImpl) Record(ctx context.Context, activity *entity.TeamActivity) error {
+	m := model.TeamActivityModelFromDomain(activity)
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "event_id"}}, DoNothing: true}).
+		Create(m).Error
+}
+
+// List implements entity.TeamActivityRepository, newest first
+func (r *TeamActivityRepositoryImpl) List(ctx context.Context, teamID uuid.UUID, eventTypes []string, offset, limit int) ([]*entity.TeamActivity, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.TeamActivityModel{}).Where("team_id = ?", teamID)
+	if len(eventTypes) > 0 {
+		query = query.Where("event_type IN ?", eventTypes)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []model.TeamActivityModel
+	if err := query.Order("occurred_at DESC").Offset(offset).Limit(limit).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	activities := make([]*entity.TeamActivity, len(models))
+	for i, m := range models {
+		activities[i] = m.ToDomain()
+	}
+	return activities, total, nil
+}
@@ -4,7 +4,7 @@ import (
 	"collab-service/internal/domain/entity"
 	"collab-service/internal/infrastructure/persistence/model"
 	"context"
-	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -45,12 +45,18 @@ func (r *TeamRepositoryImpl) Create(ctx context.Context, team *entity.Team) (*en
 }
 
 // GetAllByUserID implements entity.TeamRepository
-func (r *TeamRepositoryImpl) GetAllByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Team, error) {
+func (r *TeamRepositoryImpl) GetAllByUserID(ctx context.Context, userID uuid.UUID, includeArchived bool) ([]*entity.Team, error) {
 	var models []model.TeamModel
-	if err := r.db.WithContext(ctx).
+	query := r.db.WithContext(ctx).
 		Joins("JOIN rosters ON rosters.team_id = teams.id").
 		Where("rosters.user_id = ?", userID).
-		Preload("Rosters").
+		Where("rosters.expires_at IS NULL OR rosters.expires_at > NOW()")
+	if !includeArchived {
+		query = query.Where("teams.archived_at IS NULL")
+	}
+
+	if err := query.
+		Preload("Rosters", activeRosterSQL).
 		Find(&models).Error; err != nil {
 		return nil, err
 	}
@@ -73,7 +79,7 @@ func (r *TeamRepositoryImpl) ExistsByID(ctx context.Context, id uuid.UUID) (bool
 // GetByID implements entity.TeamRepository
 func (r *TeamRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.Team, error) {
 	var model model.TeamModel
-	if err := r.db.WithContext(ctx).Preload("Rosters").First(&model, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Rosters", activeRosterSQL).First(&model, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return model.ToDomain(), nil
@@ -82,7 +88,7 @@ func (r *TeamRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity
 // List implements entity.TeamRepository
 func (r *TeamRepositoryImpl) List(ctx context.Context) ([]*entity.Team, error) {
 	var models []model.TeamModel
-	if err := r.db.WithContext(ctx).Preload("Rosters").Find(&models).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Rosters", activeRosterSQL).Find(&models).Error; err != nil {
 		return nil, err
 	}
 
@@ -93,39 +99,114 @@ func (r *TeamRepositoryImpl) List(ctx context.Context) ([]*entity.Team, error) {
 	return teams, nil
 }
 
-// Get role
+// activeRosterSQL filters out time-bound memberships whose expiry has passed
+const activeRosterSQL = "expires_at IS NULL OR expires_at > NOW()"
+
+// ancestorsCTE walks from a team up to the root of its hierarchy. depth is 0 for the team itself.
+const ancestorsCTE = `
+	WITH RECURSIVE ancestors AS (
+		SELECT id, parent_id, 0 AS depth FROM teams WHERE id = ?
+		UNION ALL
+		SELECT t.id, t.parent_id, a.depth + 1 FROM teams t JOIN ancestors a ON t.id = a.parent_id
+	)`
+
+// descendantsCTE walks from a team down to every sub-team beneath it.
+const descendantsCTE = `
+	WITH RECURSIVE descendants AS (
+		SELECT id FROM teams WHERE id = ?
+		UNION ALL
+		SELECT t.id FROM teams t JOIN descendants d ON t.parent_id = d.id
+	)`
+
+// GetRole returns the effective role of a user in a team: the direct roster role,
+// raised by whatever role the user inherits from managing one of the team's ancestors.
 func (r *TeamRepositoryImpl) GetRole(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) (entity.TeamAccessRole, error) {
-	var roster struct {
-		Role string
+	var rows []struct {
+		Role  string
+		Depth int
 	}
 
 	err := r.db.WithContext(ctx).
-		Table("rosters").
-		Select("role").
-		Where("team_id = ? AND user_id = ?", teamID, userID).
-		First(&roster).Error
-
+		Raw(ancestorsCTE+`
+		SELECT rosters.role, ancestors.depth
+		FROM ancestors
+		JOIN rosters ON rosters.team_id = ancestors.id
+		WHERE rosters.user_id = ?
+		AND (rosters.expires_at IS NULL OR rosters.expires_at > NOW())`, teamID, userID).
+		Scan(&rows).Error
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return entity.TeamNone, nil
-		}
 		return "", err
 	}
 
-	return entity.TeamAccessRole(roster.Role), nil
+	role := entity.TeamNone
+	for _, row := range rows {
+		candidate := entity.TeamAccessRole(row.Role)
+		if row.Depth > 0 {
+			candidate = candidate.InheritedRole()
+		}
+		if candidate != entity.TeamNone && candidate.IsHigherOrEqualTo(role) {
+			role = candidate
+		}
+	}
+
+	return role, nil
+}
+
+// GetSubtree implements entity.TeamRepository
+func (r *TeamRepositoryImpl) GetSubtree(ctx context.Context, teamID uuid.UUID) ([]*entity.Team, error) {
+	var ids []uuid.UUID
+	if err := r.db.WithContext(ctx).
+		Raw(descendantsCTE+` SELECT id FROM descendants`, teamID).
+		Scan(&ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var models []model.TeamModel
+	if err := r.db.WithContext(ctx).Preload("Rosters", activeRosterSQL).Where("id IN ?", ids).Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	teams := make([]*entity.Team, len(models))
+	for i, m := range models {
+		teams[i] = m.ToDomain()
+	}
+	return teams, nil
+}
+
+// GetAncestorIDs returns the IDs of every ancestor of a team, nearest parent first.
+func (r *TeamRepositoryImpl) GetAncestorIDs(ctx context.Context, teamID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := r.db.WithContext(ctx).
+		Raw(ancestorsCTE+` SELECT id FROM ancestors WHERE depth > 0 ORDER BY depth`, teamID).
+		Scan(&ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// SetParent implements entity.TeamRepository
+func (r *TeamRepositoryImpl) SetParent(ctx context.Context, teamID uuid.UUID, parentID *uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&model.TeamModel{}).
+		Where("id = ?", teamID).
+		Update("parent_id", parentID).Error
 }
 
 // Add implements entity.TeamRepository
-func (r *TeamRepositoryImpl) AddMembers(ctx context.Context, teamID uuid.UUID, members []uuid.UUID) error {
+func (r *TeamRepositoryImpl) AddMembers(ctx context.Context, teamID uuid.UUID, members []uuid.UUID, role entity.TeamAccessRole, expiresAt *time.Time) error {
 
 	// Thêm members mới
 	for _, memberID := range members {
 
 		// Lưu vào DB
 		rosterModel := model.RosterModel{
-			UserID: memberID,
-			TeamID: teamID,
-			Role:   entity.TeamMember, // Mặc định là MEMBER
+			UserID:    memberID,
+			TeamID:    teamID,
+			Role:      role, // MEMBER hoặc GUEST
+			ExpiresAt: expiresAt,
 		}
 		if err := r.db.WithContext(ctx).Create(&rosterModel).Error; err != nil {
 			return err
@@ -153,6 +234,31 @@ func (r *TeamRepositoryImpl) RemoveMember(ctx context.Context, teamID uuid.UUID,
 	return r.db.WithContext(ctx).Where("team_id = ? AND user_id = ?", teamID, memberID).Delete(&model.RosterModel{}).Error
 }
 
+// ListExpiredRosters returns every time-bound membership whose expiry is at or before now
+func (r *TeamRepositoryImpl) ListExpiredRosters(ctx context.Context, now time.Time) ([]*entity.Roster, error) {
+	var models []model.RosterModel
+	if err := r.db.WithContext(ctx).
+		Where("expires_at IS NOT NULL AND expires_at <= ?", now).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	rosters := make([]*entity.Roster, len(models))
+	for i, m := range models {
+		rosters[i] = m.ToDomain()
+	}
+	return rosters, nil
+}
+
+// RemoveExpiredMember implements entity.TeamRepository.
+// Điều kiện hết hạn được kiểm tra lại lúc xoá để không xoá membership vừa được gia hạn.
+func (r *TeamRepositoryImpl) RemoveExpiredMember(ctx context.Context, teamID uuid.UUID, memberID uuid.UUID, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("team_id = ? AND user_id = ? AND expires_at <= ?", teamID, memberID, now).
+		Delete(&model.RosterModel{})
+	return result.RowsAffected > 0, result.Error
+}
+
 // Update implements entity.TeamRepository
 func (r *TeamRepositoryImpl) Update(ctx context.Context, team *entity.Team) (*entity.Team, error) {
 	teamModel := model.TeamModelFromDomain(team)
@@ -165,11 +271,89 @@ func (r *TeamRepositoryImpl) Update(ctx context.Context, team *entity.Team) (*en
 	return team, nil
 }
 
+// Archive implements entity.TeamRepository
+func (r *TeamRepositoryImpl) Archive(ctx context.Context, id uuid.UUID, archivedBy uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&model.TeamModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"archived_at": time.Now(),
+			"archived_by": archivedBy,
+		}).Error
+}
+
+// Restore implements entity.TeamRepository
+func (r *TeamRepositoryImpl) Restore(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&model.TeamModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"archived_at": nil,
+			"archived_by": nil,
+		}).Error
+}
+
+// ListArchivedBefore returns the IDs of teams archived before cutoff
+func (r *TeamRepositoryImpl) ListArchivedBefore(ctx context.Context, cutoff time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := r.db.WithContext(ctx).
+		Model(&model.TeamModel{}).
+		Where("archived_at IS NOT NULL AND archived_at < ?", cutoff).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
 // Delete implements entity.TeamRepository
 func (r *TeamRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 	tx := r.db.WithContext(ctx).Begin()
 
-	// First delete all associated rosters
+	// Detach sub-teams so they become top-level teams
+	if err := tx.Model(&model.TeamModel{}).Where("parent_id = ?", id).Update("parent_id", nil).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Remove shares granted to the team
+	if err := tx.Delete(&model.FolderShareModel{}, "team_id = ?", id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(&model.NoteShareModel{}, "team_id = ?", id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Remove the team's tags from notes, then the tags themselves
+	if err := tx.Where("tag_id IN (?)", tx.Model(&model.TagModel{}).Select("id").Where("team_id = ?", id)).
+		Delete(&model.NoteTagModel{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(&model.TagModel{}, "team_id = ?", id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Drop the activity feed of the team
+	if err := tx.Delete(&model.TeamActivityModel{}, "team_id = ?", id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Remove the team's webhooks together with their delivery logs
+	if err := tx.Where("webhook_id IN (?)", tx.Model(&model.WebhookModel{}).Select("id").Where("team_id = ?", id)).
+		Delete(&model.WebhookDeliveryModel{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(&model.WebhookModel{}, "team_id = ?", id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Then delete all associated rosters
 	if err := tx.Delete(&model.RosterModel{}, "team_id = ?", id).Error; err != nil {
 		tx.Rollback()
 		return err
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/infrastructure/persistence/model"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) entity.WebhookRepository {
+	return &WebhookRepositoryImpl{
+		db: db,
+	}
+}
+
+// Create implements entity.WebhookRepository.
+func (r *WebhookRepositoryImpl) Create(ctx context.Context, webhook *entity.Webhook) (*entity.Webhook, error) {
+	m := model.WebhookModelFromDomain(webhook)
+	if err := r.db.WithContext(ctx).Create(m).Error; err != nil {
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// GetByID implements entity.WebhookRepository.
+func (r *WebhookRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*entity.Webhook, error) {
+	var m model.WebhookModel
+	if err := r.db.WithContext(ctx).First(&m, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return m.ToDomain(), nil
+}
+
+// ListByTeam implements entity.WebhookRepository.
+func (r *WebhookRepositoryImpl) ListByTeam(ctx context.Context, teamID uuid.UUID) ([]*entity.Webhook, error) {
+	var models []model.WebhookModel
+	if err := r.db.WithContext(ctx).Where("team_id = ?", teamID).Order("created_at").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return webhooksToDomain(models), nil
+}
+
+// ListActiveByTeams implements entity.WebhookRepository.
+func (r *WebhookRepositoryImpl) ListActiveByTeams(ctx context.Context, teamIDs []uuid.UUID) ([]*entity.Webhook, error) {
+	if len(teamIDs) == 0 {
+		return []*entity.Webhook{}, nil
+	}
+	var models []model.WebhookModel
+	if err := r.db.WithContext(ctx).Where("team_id IN ? AND active", teamIDs).Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return webhooksToDomain(models), nil
+}
+
+// Update implements entity.WebhookRepository.
+func (r *WebhookRepositoryImpl) Update(ctx context.Context, webhook *entity.Webhook) error {
+	m := model.WebhookModelFromDomain(webhook)
+	return r.db.WithContext(ctx).Model(m).
+		Select("url", "secret", "event_types", "active", "failure_count", "disabled_at", "disabled_reason").
+		Updates(m).Error
+}
+
+// Delete implements entity.WebhookRepository.
+func (r *WebhookRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.WebhookDeliveryModel{}, "webhook_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.WebhookModel{}, "id = ?", id).Error
+	})
+}
+
+// TeamIDsForAsset implements entity.WebhookRepository.
+func (r *WebhookRepositoryImpl) TeamIDsForAsset(ctx context.Context, assetType string, assetID uuid.UUID) ([]uuid.UUID, error) {
+	db := r.db.WithContext(ctx)
+	if assetType == "NOTE" {
+		return teamIDsForNote(db, assetID)
+	}
+
+	chain, err := folderChainIDs(db, assetID)
+	if err != nil || len(chain) == 0 {
+		return nil, err
+	}
+	var teamIDs []uuid.UUID
+	err = db.Model(&model.FolderShareModel{}).
+		Distinct("team_id").
+		Where("folder_id IN ? AND team_id IS NOT NULL AND "+activeShareSQL, chain).
+		Pluck("team_id", &teamIDs).Error
+	return teamIDs, err
+}
+
+// EnqueueDeliveries implements entity.WebhookRepository.
+func (r *WebhookRepositoryImpl) EnqueueDeliveries(ctx context.Context, deliveries []*entity.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	models := make([]*model.WebhookDeliveryModel, len(deliveries))
+	for i, d := range deliveries {
+		models[i] = model.WebhookDeliveryModelFromDomain(d)
+	}
+	// Event được đọc lại từ Kafka không tạo delivery thứ hai
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "webhook_id"}, {Name: "event_id"}}, DoNothing: true}).
+		Create(&models).Error
+}
+
+// ClaimDueDeliveries implements entity.WebhookRepository.
+func (r *WebhookRepositoryImpl) ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*entity.WebhookDelivery, error) {
+	var models []model.WebhookDeliveryModel
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE webhook_deliveries SET next_attempt_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		now.Add(lease), now, string(entity.WebhookDeliveryPending), now, limit).
+		Scan(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*entity.WebhookDelivery, len(models))
+	for i := range models {
+		deliveries[i] = models[i].ToDomain()
+	}
+	return deliveries, nil
+}
+
+// SaveDelivery implements entity.WebhookRepository.
+func (r *WebhookRepositoryImpl) SaveDelivery(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	m := model.WebhookDeliveryModelFromDomain(delivery)
+	return r.db.WithContext(ctx).Model(m).
+		Select("status", "attempts", "next_attempt_at", "response_status", "last_error", "delivered_at").
+		Updates(m).Error
+}
+
+// ListDeliveries implements entity.WebhookRepository.
+func (r *WebhookRepositoryImpl) ListDeliveries(ctx context.Context, webhookID uuid.UUID, offset, limit int) ([]*entity.WebhookDelivery, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.WebhookDeliveryModel{}).Where("webhook_id = ?", webhookID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []model.WebhookDeliveryModel
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	deliveries := make([]*entity.WebhookDelivery, len(models))
+	for i := range models {
+		deliveries[i] = models[i].ToDomain()
+	}
+	return deliveries, total, nil
+}
+
+// FailPendingDeliveries implements entity.WebhookRepository.
+func (r *WebhookRepositoryImpl) FailPendingDeliveries(ctx context.Context, webhookID uuid.UUID, reason string) error {
+	return r.db.WithContext(ctx).Model(&model.WebhookDeliveryModel{}).
+		Where("webhook_id = ? AND status = ?", webhookID, string(entity.WebhookDeliveryPending)).
+		Updates(map[string]interface{}{
+			"status":          string(entity.WebhookDeliveryFailed),
+			"next_attempt_at": nil,
+			"last_error":      reason,
+		}).Error
+}
+
+// DeleteDeliveriesBefore implements entity.WebhookRepository.
+// Delivery đang chờ gửi không bị xoá dù đã cũ.
+func (r *WebhookRepositoryImpl) DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("created_at < ? AND status <> ?", before, string(entity.WebhookDeliveryPending)).
+		Delete(&model.WebhookDeliveryModel{})
+	return result.RowsAffected, result.Error
+}
+
+// RecordResult implements entity.WebhookRepository.
+func (r *WebhookRepositoryImpl) RecordResult(ctx context.Context, webhookID uuid.UUID, succeeded bool) (int, error) {
+	var failures int
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE webhooks SET failure_count = CASE WHEN ? THEN 0 ELSE failure_count + 1 END
+		WHERE id = ?
+		RETURNING failure_count`,
+		succeeded, webhookID).
+		Scan(&failures).Error
+	return failures, err
+}
+
+// Disable implements entity.WebhookRepository.
+func (r *WebhookRepositoryImpl) Disable(ctx context.Context, webhookID uuid.UUID, reason string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.WebhookModel{}).
+		Where("id = ?", webhookID).
+		Updates(map[string]interface{}{
+			"active":          false,
+			"disabled_at":     at,
+			"disabled_reason": reason,
+		}).Error
+}
+
+func webhooksToDomain(models []model.WebhookModel) []*entity.Webhook {
+	webhooks := make([]*entity.Webhook, len(models))
+	for i := range models {
+		webhooks[i] = models[i].ToDomain()
+	}
+	return webhooks
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"collab-service/internal/infrastructure/logger"
+	"context"
+	"sync"
+	"time"
+)
+
+// Job is a unit of background work run periodically by the Scheduler
+type Job func(ctx context.Context) error
+
+type entry struct {
+	name     string
+	interval time.Duration
+	job      Job
+}
+
+// Scheduler runs registered jobs on fixed intervals until it is stopped
+type Scheduler struct {
+	mu      sync.Mutex
+	entries []entry
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+var (
+	schedulerInstance *Scheduler
+	schedulerOnce     sync.Once
+)
+
+// GetScheduler returns the process-wide scheduler used by the bootstrap modules
+func GetScheduler() *Scheduler {
+	schedulerOnce.Do(func() {
+		schedulerInstance = &Scheduler{}
+	})
+	return schedulerInstance
+}
+
+// Every registers a job to run once per interval. Jobs must be registered before Start.
+func (s *Scheduler) Every(name string, interval time.Duration, job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry{name: name, interval: interval, job: job})
+}
+
+// Start launches one goroutine per registered job
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, e := range s.entries {
+		s.wg.Add(1)
+		go s.run(ctx, e)
+	}
+	logger.Info("Scheduler started", "jobs", len(s.entries))
+}
+
+// Stop cancels all running jobs and waits for them to return
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	s.wg.Wait()
+	logger.Info("Scheduler stopped")
+}
+
+func (s *Scheduler) run(ctx context.Context, e entry) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.job(ctx); err != nil {
+				logger.Error("Scheduled job failed", "job", e.name, "error", err.Error())
+			}
+		}
+	}
+}
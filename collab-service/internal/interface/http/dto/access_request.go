@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"collab-service/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateAccessRequestRequest xin quyền READ hoặc WRITE; message được gửi kèm cho owner
+type CreateAccessRequestRequest struct {
+	AccessLevel entity.AccessLevel `json:"access_level" binding:"required"`
+	Message     string             `json:"message,omitempty"`
+}
+
+// ApproveAccessRequestRequest cho phép owner cấp mức quyền khác mức được yêu cầu và đặt hạn cho share; đều không bắt buộc
+type ApproveAccessRequestRequest struct {
+	AccessLevel *entity.AccessLevel `json:"access_level,omitempty"`
+	ExpiresAt   *time.Time          `json:"expires_at,omitempty"`
+}
+
+type AccessRequestResponse struct {
+	ID          uuid.UUID                     `json:"id"`
+	AssetType   entity.AccessRequestAssetType `json:"asset_type"`
+	AssetID     uuid.UUID                     `json:"asset_id"`
+	RequesterID uuid.UUID                     `json:"requester_id"`
+	AccessLevel entity.AccessLevel            `json:"access_level"`
+	Message     string                        `json:"message,omitempty"`
+	Status      entity.AccessRequestStatus    `json:"status"`
+	DecidedBy   *uuid.UUID                    `json:"decided_by,omitempty"`
+	DecidedAt   *time.Time                    `json:"decided_at,omitempty"`
+	CreatedAt   time.Time                     `json:"created_at"`
+}
+
+func ToAccessRequestResponse(r *entity.AccessRequest) AccessRequestResponse {
+	return AccessRequestResponse{
+		ID:          r.ID,
+		AssetType:   r.AssetType,
+		AssetID:     r.AssetID,
+		RequesterID: r.RequesterID,
+		AccessLevel: r.AccessLevel,
+		Message:     r.Message,
+		Status:      r.Status,
+		DecidedBy:   r.DecidedBy,
+		DecidedAt:   r.DecidedAt,
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
+func ToAccessRequestResponses(requests []*entity.AccessRequest) []AccessRequestResponse {
+	responses := make([]AccessRequestResponse, len(requests))
+	for i, r := range requests {
+		responses[i] = ToAccessRequestResponse(r)
+	}
+	return responses
+}
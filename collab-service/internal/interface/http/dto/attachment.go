@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"collab-service/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AttachmentResponse struct {
+	ID         uuid.UUID `json:"id"`
+	NoteID     uuid.UUID `json:"note_id"`
+	FileName   string    `json:"file_name"`
+	MimeType   string    `json:"mime_type"`
+	Size       int64     `json:"size"`
+	Checksum   string    `json:"checksum"`
+	UploadedBy uuid.UUID `json:"uploaded_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func ToAttachmentResponse(a *entity.Attachment) AttachmentResponse {
+	return AttachmentResponse{
+		ID:         a.ID,
+		NoteID:     a.NoteID,
+		FileName:   a.FileName,
+		MimeType:   a.MimeType,
+		Size:       a.Size,
+		Checksum:   a.Checksum,
+		UploadedBy: a.UploadedBy,
+		CreatedAt:  a.CreatedAt,
+	}
+}
+
+func ToAttachmentResponses(attachments []*entity.Attachment) []AttachmentResponse {
+	responses := make([]AttachmentResponse, len(attachments))
+	for i, a := range attachments {
+		responses[i] = ToAttachmentResponse(a)
+	}
+	return responses
+}
@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommentAnchorRequest gắn comment vào đoạn [start, end) của body note, tính theo ký tự
+type CommentAnchorRequest struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type CreateCommentRequest struct {
+	Body   string                `json:"body" binding:"required"`
+	Anchor *CommentAnchorRequest `json:"anchor,omitempty"`
+}
+
+// ToAnchor trả về nil nếu request không gắn anchor
+func (r CreateCommentRequest) ToAnchor() *entity.CommentAnchor {
+	if r.Anchor == nil {
+		return nil
+	}
+	return &entity.CommentAnchor{Start: r.Anchor.Start, End: r.Anchor.End}
+}
+
+type ReplyCommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+type CommentAnchorResponse struct {
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+	Quote string `json:"quote"`
+}
+
+type CommentResponse struct {
+	ID         uuid.UUID              `json:"id"`
+	NoteID     uuid.UUID              `json:"note_id"`
+	ParentID   *uuid.UUID             `json:"parent_id,omitempty"`
+	AuthorID   uuid.UUID              `json:"author_id"`
+	Body       string                 `json:"body"`
+	Anchor     *CommentAnchorResponse `json:"anchor,omitempty"`
+	Resolved   bool                   `json:"resolved"`
+	ResolvedAt *time.Time             `json:"resolved_at,omitempty"`
+	ResolvedBy *uuid.UUID             `json:"resolved_by,omitempty"`
+	EditedAt   *time.Time             `json:"edited_at,omitempty"`
+	Deleted    bool                   `json:"deleted"`
+	CreatedAt  time.Time              `json:"created_at"`
+	Replies    []CommentResponse      `json:"replies,omitempty"`
+}
+
+func ToCommentResponse(c *entity.Comment) CommentResponse {
+	response := CommentResponse{
+		ID:         c.ID,
+		NoteID:     c.NoteID,
+		ParentID:   c.ParentID,
+		AuthorID:   c.AuthorID,
+		Body:       c.Body,
+		Resolved:   c.IsResolved(),
+		ResolvedAt: c.ResolvedAt,
+		ResolvedBy: c.ResolvedBy,
+		EditedAt:   c.EditedAt,
+		Deleted:    c.IsDeleted(),
+		CreatedAt:  c.CreatedAt,
+	}
+	if c.Anchor != nil {
+		response.Anchor = &CommentAnchorResponse{Start: c.Anchor.Start, End: c.Anchor.End, Quote: c.Anchor.Quote}
+	}
+	return response
+}
+
+func ToCommentThreadResponses(threads []*application.CommentThread) []CommentResponse {
+	responses := make([]CommentResponse, len(threads))
+	for i, thread := range threads {
+		responses[i] = ToCommentResponse(thread.Root)
+		responses[i].Replies = make([]CommentResponse, len(thread.Replies))
+		for j, reply := range thread.Replies {
+			responses[i].Replies[j] = ToCommentResponse(reply)
+		}
+	}
+	return responses
+}
@@ -2,25 +2,54 @@ package dto
 
 import (
 	"collab-service/internal/domain/entity"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type CreateFolderRequest struct {
-	Name string `json:"name" binding:"required"`
+	Name     string     `json:"name" binding:"required"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
 }
 
 type UpdateFolderRequest struct {
 	Name string `json:"name" binding:"required"`
 }
 
+// ShareFolderRequest share folder cho một user (user_id) hoặc cả một team (team_id).
+// expires_at (RFC 3339) là tuỳ chọn; bỏ trống thì share không hết hạn.
 type ShareFolderRequest struct {
-	UserID      uuid.UUID          `json:"user_id" binding:"required"`
+	UserID      uuid.UUID          `json:"user_id"`
+	TeamID      *uuid.UUID         `json:"team_id,omitempty"`
 	AccessLevel entity.AccessLevel `json:"access_level" binding:"required"`
+	ExpiresAt   *time.Time         `json:"expires_at,omitempty"`
+}
+
+// MoveFolderRequest chuyển folder sang folder cha khác; parent_id null đưa folder về gốc
+type MoveFolderRequest struct {
+	ParentID *uuid.UUID `json:"parent_id"`
 }
 
 type FolderResponse struct {
-	ID     uuid.UUID            `json:"id"`
-	Name   string               `json:"name"`
-	Shared []entity.FolderShare `json:"shared,omitempty"`
+	ID         uuid.UUID            `json:"id"`
+	Name       string               `json:"name"`
+	ParentID   *uuid.UUID           `json:"parent_id,omitempty"`
+	Version    int64                `json:"version"`
+	SubFolders []FolderResponse     `json:"sub_folders,omitempty"`
+	Shared     []entity.FolderShare `json:"shared,omitempty"`
+}
+
+// BreadcrumbResponse là một phần tử trên đường dẫn tới folder
+type BreadcrumbResponse struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+func ToFolderResponse(folder *entity.Folder) FolderResponse {
+	return FolderResponse{
+		ID:       folder.ID,
+		Name:     folder.Name,
+		ParentID: folder.ParentID,
+		Version:  folder.Version,
+	}
 }
@@ -2,6 +2,7 @@ package dto
 
 import (
 	"collab-service/internal/domain/entity"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -18,9 +19,20 @@ type UpdateNoteRequest struct {
 	FolderID uuid.UUID `json:"folder_id"`
 }
 
+// ShareNoteRequest share note cho một user (user_id) hoặc cả một team (team_id).
+// expires_at (RFC 3339) là tuỳ chọn; bỏ trống thì share không hết hạn.
 type ShareNoteRequest struct {
 	AccessLevel entity.AccessLevel `json:"access_level"`
 	UserID      uuid.UUID          `json:"user_id"`
+	TeamID      *uuid.UUID         `json:"team_id,omitempty"`
+	ExpiresAt   *time.Time         `json:"expires_at,omitempty"`
+}
+
+// MoveNoteRequest chuyển hoặc sao chép note sang folder_id.
+// share_policy: KEEP (mặc định) giữ các share của note, RESET chỉ giữ owner.
+type MoveNoteRequest struct {
+	FolderID    uuid.UUID              `json:"folder_id" binding:"required"`
+	SharePolicy entity.NoteSharePolicy `json:"share_policy,omitempty"`
 }
 
 type NoteResponse struct {
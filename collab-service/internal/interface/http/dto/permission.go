@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"collab-service/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccessGrantResponse là một share góp phần vào quyền hiệu lực.
+// resource_type/resource_id là asset mang share; inherited là true khi share nằm trên folder cha.
+type AccessGrantResponse struct {
+	ShareID        uuid.UUID                `json:"share_id"`
+	Source         entity.AccessGrantSource `json:"source"`
+	ResourceType   entity.ResourceType      `json:"resource_type"`
+	ResourceID     uuid.UUID                `json:"resource_id"`
+	Inherited      bool                     `json:"inherited"`
+	TeamID         *uuid.UUID               `json:"team_id,omitempty"`
+	TeamRole       *entity.TeamAccessRole   `json:"team_role,omitempty"`
+	AccessLevel    entity.AccessLevel       `json:"access_level"`
+	EffectiveLevel entity.AccessLevel       `json:"effective_level"`
+	ExpiresAt      *time.Time               `json:"expires_at,omitempty"`
+}
+
+type AccessExplanationResponse struct {
+	ResourceType entity.ResourceType   `json:"resource_type"`
+	ResourceID   uuid.UUID             `json:"resource_id"`
+	UserID       uuid.UUID             `json:"user_id"`
+	AccessLevel  entity.AccessLevel    `json:"access_level"`
+	Grants       []AccessGrantResponse `json:"grants"`
+}
+
+func ToAccessExplanationResponse(e *entity.AccessExplanation) AccessExplanationResponse {
+	grants := make([]AccessGrantResponse, len(e.Grants))
+	for i, g := range e.Grants {
+		grants[i] = AccessGrantResponse{
+			ShareID:        g.ShareID,
+			Source:         g.Source,
+			ResourceType:   g.ResourceType,
+			ResourceID:     g.ResourceID,
+			Inherited:      g.Inherited,
+			TeamID:         g.TeamID,
+			TeamRole:       g.TeamRole,
+			AccessLevel:    g.AccessLevel,
+			EffectiveLevel: g.EffectiveLevel,
+			ExpiresAt:      g.ExpiresAt,
+		}
+	}
+	return AccessExplanationResponse{
+		ResourceType: e.ResourceType,
+		ResourceID:   e.ResourceID,
+		UserID:       e.UserID,
+		AccessLevel:  e.AccessLevel,
+		Grants:       grants,
+	}
+}
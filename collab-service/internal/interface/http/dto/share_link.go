@@ -0,0 +1,161 @@
+package dto
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateShareLinkRequest tạo link công khai chỉ đọc; password và expires_at đều không bắt buộc
+type CreateShareLinkRequest struct {
+	Password  string     `json:"password,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type ShareLinkResponse struct {
+	ID           uuid.UUID                 `json:"id"`
+	AssetType    entity.ShareLinkAssetType `json:"asset_type"`
+	AssetID      uuid.UUID                 `json:"asset_id"`
+	HasPassword  bool                      `json:"has_password"`
+	ExpiresAt    *time.Time                `json:"expires_at,omitempty"`
+	Expired      bool                      `json:"expired"`
+	ViewCount    int64                     `json:"view_count"`
+	LastViewedAt *time.Time                `json:"last_viewed_at,omitempty"`
+	RevokedAt    *time.Time                `json:"revoked_at,omitempty"`
+	CreatedBy    uuid.UUID                 `json:"created_by"`
+	CreatedAt    time.Time                 `json:"created_at"`
+}
+
+// CreatedShareLinkResponse kèm token và đường dẫn /s/{token}; chỉ trả về một lần khi tạo link
+type CreatedShareLinkResponse struct {
+	ShareLinkResponse
+	Token string `json:"token"`
+	Path  string `json:"path"`
+}
+
+type ShareLinkAccessResponse struct {
+	ID        uuid.UUID                     `json:"id"`
+	Outcome   entity.ShareLinkAccessOutcome `json:"outcome"`
+	AssetType entity.ShareLinkAssetType     `json:"asset_type"`
+	AssetID   uuid.UUID                     `json:"asset_id"`
+	IP        string                        `json:"ip"`
+	UserAgent string                        `json:"user_agent,omitempty"`
+	CreatedAt time.Time                     `json:"created_at"`
+}
+
+type ShareLinkAccessesResponse struct {
+	Accesses []ShareLinkAccessResponse `json:"accesses"`
+	Page     int                       `json:"page"`
+	PageSize int                       `json:"page_size"`
+	Total    int64                     `json:"total"`
+}
+
+// SharedNoteResponse là note được xem qua link công khai, không kèm share hay thông tin owner
+type SharedNoteResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type SharedFolderResponse struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// SharedContentResponse là nội dung trả về cho người mở link: note, hoặc folder cùng nội dung trực tiếp bên trong.
+// access_level luôn là READ.
+type SharedContentResponse struct {
+	AssetType   entity.ShareLinkAssetType `json:"asset_type"`
+	AccessLevel entity.AccessLevel        `json:"access_level"`
+	ExpiresAt   *time.Time                `json:"expires_at,omitempty"`
+	Note        *SharedNoteResponse       `json:"note,omitempty"`
+	Folder      *SharedFolderResponse     `json:"folder,omitempty"`
+	SubFolders  []SharedFolderResponse    `json:"sub_folders,omitempty"`
+	Notes       []SharedNoteResponse      `json:"notes,omitempty"`
+}
+
+func ToShareLinkResponse(l *entity.ShareLink) ShareLinkResponse {
+	return ShareLinkResponse{
+		ID:           l.ID,
+		AssetType:    l.AssetType,
+		AssetID:      l.AssetID,
+		HasPassword:  l.HasPassword(),
+		ExpiresAt:    l.ExpiresAt,
+		Expired:      l.IsExpired(time.Now()),
+		ViewCount:    l.ViewCount,
+		LastViewedAt: l.LastViewedAt,
+		RevokedAt:    l.RevokedAt,
+		CreatedBy:    l.CreatedBy,
+		CreatedAt:    l.CreatedAt,
+	}
+}
+
+func ToShareLinkResponses(links []*entity.ShareLink) []ShareLinkResponse {
+	responses := make([]ShareLinkResponse, len(links))
+	for i, l := range links {
+		responses[i] = ToShareLinkResponse(l)
+	}
+	return responses
+}
+
+func ToCreatedShareLinkResponse(l *entity.ShareLink) CreatedShareLinkResponse {
+	return CreatedShareLinkResponse{
+		ShareLinkResponse: ToShareLinkResponse(l),
+		Token:             l.Token,
+		Path:              "/s/" + l.Token,
+	}
+}
+
+func ToShareLinkAccessesResponse(page *application.ShareLinkAccessPage) ShareLinkAccessesResponse {
+	accesses := make([]ShareLinkAccessResponse, len(page.Accesses))
+	for i, a := range page.Accesses {
+		accesses[i] = ShareLinkAccessResponse{
+			ID:        a.ID,
+			Outcome:   a.Outcome,
+			AssetType: a.AssetType,
+			AssetID:   a.AssetID,
+			IP:        a.IP,
+			UserAgent: a.UserAgent,
+			CreatedAt: a.CreatedAt,
+		}
+	}
+	return ShareLinkAccessesResponse{
+		Accesses: accesses,
+		Page:     page.Page,
+		PageSize: page.PageSize,
+		Total:    page.Total,
+	}
+}
+
+// ToSharedContentResponse chỉ trả body của note được mở; danh sách note trong folder chỉ có tiêu đề
+func ToSharedContentResponse(content *application.SharedContent) SharedContentResponse {
+	response := SharedContentResponse{
+		AssetType:   entity.ShareLinkNote,
+		AccessLevel: entity.AccessLevelRead,
+		ExpiresAt:   content.Link.ExpiresAt,
+	}
+	if content.Note != nil {
+		response.Note = &SharedNoteResponse{
+			ID:        content.Note.ID,
+			Title:     content.Note.Title,
+			Body:      content.Note.Body,
+			UpdatedAt: content.Note.UpdatedAt,
+		}
+		return response
+	}
+
+	response.AssetType = entity.ShareLinkFolder
+	response.Folder = &SharedFolderResponse{ID: content.Folder.ID, Name: content.Folder.Name}
+	response.SubFolders = make([]SharedFolderResponse, len(content.SubFolders))
+	for i, f := range content.SubFolders {
+		response.SubFolders[i] = SharedFolderResponse{ID: f.ID, Name: f.Name}
+	}
+	response.Notes = make([]SharedNoteResponse, len(content.Notes))
+	for i, n := range content.Notes {
+		response.Notes[i] = SharedNoteResponse{ID: n.ID, Title: n.Title, UpdatedAt: n.UpdatedAt}
+	}
+	return response
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"collab-service/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateTagRequest tạo tag cá nhân, hoặc tag dùng chung của team nếu có team_id.
+// color dạng #RRGGBB, bỏ trống dùng màu mặc định.
+type CreateTagRequest struct {
+	Name   string     `json:"name" binding:"required"`
+	Color  string     `json:"color,omitempty"`
+	TeamID *uuid.UUID `json:"team_id,omitempty"`
+}
+
+// UpdateTagRequest đổi tên và/hoặc màu, trường bỏ trống được giữ nguyên
+type UpdateTagRequest struct {
+	Name  *string `json:"name,omitempty"`
+	Color *string `json:"color,omitempty"`
+}
+
+type TagResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	Color     string     `json:"color"`
+	OwnerID   *uuid.UUID `json:"owner_id,omitempty"`
+	TeamID    *uuid.UUID `json:"team_id,omitempty"`
+	CreatedBy uuid.UUID  `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func ToTagResponse(tag *entity.Tag) TagResponse {
+	return TagResponse{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		Color:     tag.Color,
+		OwnerID:   tag.OwnerID,
+		TeamID:    tag.TeamID,
+		CreatedBy: tag.CreatedBy,
+		CreatedAt: tag.CreatedAt,
+		UpdatedAt: tag.UpdatedAt,
+	}
+}
+
+func ToTagResponses(tags []*entity.Tag) []TagResponse {
+	responses := make([]TagResponse, len(tags))
+	for i, tag := range tags {
+		responses[i] = ToTagResponse(tag)
+	}
+	return responses
+}
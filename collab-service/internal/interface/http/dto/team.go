@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"collab-service/internal/application"
 	"collab-service/internal/domain/entity"
 	"time"
 
@@ -10,12 +11,20 @@ import (
 // CreateTeamRequest represents the request payload for creating a team
 type CreateTeamRequest struct {
 	TeamName string      `json:"teamName" binding:"required"`
+	ParentID *uuid.UUID  `json:"parentId,omitempty"`
 	Managers []uuid.UUID `json:"managers,omitempty"`
 	Members  []uuid.UUID `json:"members,omitempty"`
 }
 
+// MoveTeamRequest re-parents a team; a null parentId makes it a top-level team
+type MoveTeamRequest struct {
+	ParentID *uuid.UUID `json:"parentId"`
+}
+
 type AddMembersRequest struct {
-	MemberIDs []uuid.UUID `json:"memberIds" binding:"required"`
+	MemberIDs []uuid.UUID           `json:"memberIds" binding:"required"`
+	Role      entity.TeamAccessRole `json:"role"`      // MEMBER (mặc định) hoặc GUEST
+	ExpiresAt *time.Time            `json:"expiresAt"` // Thời điểm membership hết hạn, bỏ trống nếu vĩnh viễn
 }
 type UpdateTeamRequest struct {
 	Team entity.Team `json:"team" binding:"required"`
@@ -28,17 +37,46 @@ type RosterID struct {
 
 // TeamResponse represents the response payload for team operations
 type TeamResponse struct {
-	ID       uuid.UUID `json:"id"`
-	TeamName string    `json:"teamName"`
-	// Rosters   []RosterResponse `json:"rosters,omitempty"`
-	Members   []uuid.UUID `json:"members,omitempty"`
-	CreatedAt time.Time   `json:"createdAt"`
-	UpdatedAt time.Time   `json:"updatedAt"`
+	ID         uuid.UUID        `json:"id"`
+	TeamName   string           `json:"teamName"`
+	ParentID   *uuid.UUID       `json:"parentId,omitempty"`
+	Rosters    []RosterResponse `json:"rosters,omitempty"`
+	Members    []uuid.UUID      `json:"members,omitempty"`
+	ArchivedAt *time.Time       `json:"archivedAt,omitempty"`
+	CreatedAt  time.Time        `json:"createdAt"`
+	UpdatedAt  time.Time        `json:"updatedAt"`
 }
 
 type RosterResponse struct {
-	UserID uuid.UUID `json:"userId"`
-	// Role   entity.TeamAccessRole `json:"role"`
+	UserID    uuid.UUID             `json:"userId"`
+	Role      entity.TeamAccessRole `json:"role"`
+	JoinedAt  time.Time             `json:"joinedAt"`
+	ExpiresAt *time.Time            `json:"expiresAt,omitempty"`
+}
+
+// TeamMemberResponse là một member kèm thông tin user lấy từ user-service
+type TeamMemberResponse struct {
+	UserID    uuid.UUID             `json:"userId"`
+	Username  string                `json:"username"`
+	Email     string                `json:"email"`
+	AvatarURL string                `json:"avatarUrl"`
+	Role      entity.TeamAccessRole `json:"role"`
+	JoinedAt  time.Time             `json:"joinedAt"`
+	ExpiresAt *time.Time            `json:"expiresAt,omitempty"`
+}
+
+// TeamMembersResponse là một trang trong danh sách member của team
+type TeamMembersResponse struct {
+	Members  []TeamMemberResponse `json:"members"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"pageSize"`
+	Total    int                  `json:"total"`
+}
+
+// TeamTreeResponse represents a team together with its nested sub-teams
+type TeamTreeResponse struct {
+	TeamResponse
+	Children []*TeamTreeResponse `json:"children"`
 }
 
 // TeamListResponse represents a list of teams
@@ -52,16 +90,63 @@ func ToResponse(team *entity.Team) *TeamResponse {
 	}
 
 	var memberIDs []uuid.UUID
+	var rosterResponses []RosterResponse
 	for _, roster := range team.Rosters {
 		memberIDs = append(memberIDs, roster.UserID)
+		rosterResponses = append(rosterResponses, RosterResponse{
+			UserID:    roster.UserID,
+			Role:      roster.Role,
+			JoinedAt:  roster.CreatedAt,
+			ExpiresAt: roster.ExpiresAt,
+		})
 	}
 
 	return &TeamResponse{
-		ID:       team.ID,
-		TeamName: team.Name,
-		// Rosters:   rosterResponses,
-		Members:   memberIDs,
-		CreatedAt: team.CreatedAt,
-		UpdatedAt: team.UpdatedAt,
+		ID:         team.ID,
+		TeamName:   team.Name,
+		ParentID:   team.ParentID,
+		Rosters:    rosterResponses,
+		Members:    memberIDs,
+		ArchivedAt: team.ArchivedAt,
+		CreatedAt:  team.CreatedAt,
+		UpdatedAt:  team.UpdatedAt,
+	}
+}
+
+func ToTreeResponse(team *entity.Team) *TeamTreeResponse {
+	if team == nil {
+		return nil
+	}
+
+	children := make([]*TeamTreeResponse, len(team.Children))
+	for i, child := range team.Children {
+		children[i] = ToTreeResponse(child)
+	}
+
+	return &TeamTreeResponse{
+		TeamResponse: *ToResponse(team),
+		Children:     children,
+	}
+}
+
+func ToMembersResponse(page *application.TeamMemberPage) *TeamMembersResponse {
+	members := make([]TeamMemberResponse, len(page.Members))
+	for i, m := range page.Members {
+		members[i] = TeamMemberResponse{
+			UserID:    m.UserID,
+			Username:  m.Username,
+			Email:     m.Email,
+			AvatarURL: m.AvatarURL,
+			Role:      m.Role,
+			JoinedAt:  m.JoinedAt,
+			ExpiresAt: m.ExpiresAt,
+		}
+	}
+
+	return &TeamMembersResponse{
+		Members:  members,
+		Page:     page.Page,
+		PageSize: page.PageSize,
+		Total:    page.Total,
 	}
 }
@@ -0,0 +1,122 @@
+package dto
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/domain/entity"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportMemberEntry là một member trong payload JSON của import roster
+type ImportMemberEntry struct {
+	Email     string                `json:"email,omitempty"`
+	UserID    *uuid.UUID            `json:"userId,omitempty"`
+	Role      entity.TeamAccessRole `json:"role,omitempty"`      // MANAGER, MEMBER (mặc định) hoặc GUEST
+	ExpiresAt *time.Time            `json:"expiresAt,omitempty"` // Chỉ áp dụng cho MEMBER và GUEST
+}
+
+// ImportMembersRequest là payload JSON của import roster
+type ImportMembersRequest struct {
+	Members []ImportMemberEntry `json:"members" binding:"required"`
+}
+
+// ToImportRows chuyển payload JSON sang các dòng import, đánh số dòng từ 1
+func (r ImportMembersRequest) ToImportRows() []application.MemberImportRow {
+	rows := make([]application.MemberImportRow, len(r.Members))
+	for i, m := range r.Members {
+		rows[i] = application.MemberImportRow{
+			Row:       i + 1,
+			UserID:    m.UserID,
+			Email:     m.Email,
+			Role:      entity.TeamAccessRole(strings.ToUpper(string(m.Role))),
+			ExpiresAt: m.ExpiresAt,
+		}
+	}
+	return rows
+}
+
+// ParseMemberImportCSV đọc file CSV có header. Các cột được hỗ trợ:
+// "user" (email hoặc user ID), "email", "user_id", "role" và "expires_at" (RFC3339).
+// Số dòng được tính theo file, header là dòng 1.
+func ParseMemberImportCSV(r io.Reader) ([]application.MemberImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("csv file is empty")
+		}
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	_, hasUser := columns["user"]
+	_, hasEmail := columns["email"]
+	_, hasID := columns["user_id"]
+	if !hasUser && !hasEmail && !hasID {
+		return nil, fmt.Errorf("csv header must contain a user, email or user_id column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []application.MemberImportRow
+	line := 1
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		row := application.MemberImportRow{
+			Row:   line,
+			Email: field(record, "email"),
+			Role:  entity.TeamAccessRole(strings.ToUpper(field(record, "role"))),
+		}
+
+		if user := field(record, "user"); user != "" {
+			if id, err := uuid.Parse(user); err == nil {
+				row.UserID = &id
+			} else {
+				row.Email = user
+			}
+		}
+		if raw := field(record, "user_id"); raw != "" {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid user_id %q", line, raw)
+			}
+			row.UserID = &id
+		}
+		if raw := field(record, "expires_at"); raw != "" {
+			expiresAt, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid expires_at %q", line, raw)
+			}
+			row.ExpiresAt = &expiresAt
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
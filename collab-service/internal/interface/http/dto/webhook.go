@@ -0,0 +1,115 @@
+package dto
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/domain/entity"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateWebhookRequest đăng ký webhook cho team; bỏ trống secret để server tự sinh
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" binding:"required"`
+	EventTypes []string `json:"eventTypes" binding:"required"`
+	Secret     string   `json:"secret,omitempty"`
+}
+
+// UpdateWebhookRequest chỉ thay đổi các trường được gửi lên; active=true bật lại webhook đã bị tắt tự động
+type UpdateWebhookRequest struct {
+	URL        *string   `json:"url,omitempty"`
+	EventTypes *[]string `json:"eventTypes,omitempty"`
+	Active     *bool     `json:"active,omitempty"`
+}
+
+type WebhookResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	TeamID         uuid.UUID  `json:"teamId"`
+	URL            string     `json:"url"`
+	EventTypes     []string   `json:"eventTypes"`
+	Active         bool       `json:"active"`
+	FailureCount   int        `json:"failureCount"`
+	DisabledAt     *time.Time `json:"disabledAt,omitempty"`
+	DisabledReason string     `json:"disabledReason,omitempty"`
+	CreatedBy      uuid.UUID  `json:"createdBy"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+// WebhookSecretResponse chỉ được trả về khi tạo webhook hoặc đổi secret, sau đó secret không thể đọc lại
+type WebhookSecretResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uuid.UUID                    `json:"id"`
+	EventID        string                       `json:"eventId"`
+	EventType      string                       `json:"eventType"`
+	Status         entity.WebhookDeliveryStatus `json:"status"`
+	Attempts       int                          `json:"attempts"`
+	NextAttemptAt  *time.Time                   `json:"nextAttemptAt,omitempty"`
+	ResponseStatus *int                         `json:"responseStatus,omitempty"`
+	LastError      string                       `json:"lastError,omitempty"`
+	DeliveredAt    *time.Time                   `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time                    `json:"createdAt"`
+}
+
+type WebhookDeliveriesResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+	Page       int                       `json:"page"`
+	PageSize   int                       `json:"pageSize"`
+	Total      int64                     `json:"total"`
+}
+
+func ToWebhookResponse(w *entity.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:             w.ID,
+		TeamID:         w.TeamID,
+		URL:            w.URL,
+		EventTypes:     w.EventTypes,
+		Active:         w.Active,
+		FailureCount:   w.FailureCount,
+		DisabledAt:     w.DisabledAt,
+		DisabledReason: w.DisabledReason,
+		CreatedBy:      w.CreatedBy,
+		CreatedAt:      w.CreatedAt,
+		UpdatedAt:      w.UpdatedAt,
+	}
+}
+
+func ToWebhookResponses(webhooks []*entity.Webhook) []WebhookResponse {
+	responses := make([]WebhookResponse, len(webhooks))
+	for i, w := range webhooks {
+		responses[i] = ToWebhookResponse(w)
+	}
+	return responses
+}
+
+func ToWebhookSecretResponse(w *entity.Webhook) WebhookSecretResponse {
+	return WebhookSecretResponse{WebhookResponse: ToWebhookResponse(w), Secret: w.Secret}
+}
+
+func ToWebhookDeliveriesResponse(page *application.WebhookDeliveryPage) WebhookDeliveriesResponse {
+	deliveries := make([]WebhookDeliveryResponse, len(page.Deliveries))
+	for i, d := range page.Deliveries {
+		deliveries[i] = WebhookDeliveryResponse{
+			ID:             d.ID,
+			EventID:        d.EventID,
+			EventType:      d.EventType,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			NextAttemptAt:  d.NextAttemptAt,
+			ResponseStatus: d.ResponseStatus,
+			LastError:      d.LastError,
+			DeliveredAt:    d.DeliveredAt,
+			CreatedAt:      d.CreatedAt,
+		}
+	}
+	return WebhookDeliveriesResponse{
+		Deliveries: deliveries,
+		Page:       page.Page,
+		PageSize:   page.PageSize,
+		Total:      page.Total,
+	}
+}
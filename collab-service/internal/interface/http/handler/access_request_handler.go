@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/interface/http/dto"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AccessRequestHandler serves access requests on notes and folders: users request access, owners approve or deny
+type AccessRequestHandler struct {
+	accessRequestService *application.AccessRequestService
+}
+
+func NewAccessRequestHandler(service *application.AccessRequestService) *AccessRequestHandler {
+	return &AccessRequestHandler{
+		accessRequestService: service,
+	}
+}
+
+// @Security BearerAuth
+// @Summary Request access to a note
+// @Description Asks the owner of a note for READ or WRITE access. The owner is notified.
+// @Tags access-requests
+// @Accept json
+// @Produce json
+// @Param noteID path string true "Note ID"
+// @Param request body dto.CreateAccessRequestRequest true "Requested access level and optional message"
+// @Success 201 {object} dto.AccessRequestResponse
+// @Failure 409 {object} object "Access already granted or a request is already pending"
+// @Router /notes/{noteID}/access-requests [post]
+func (h *AccessRequestHandler) CreateNoteRequest(c *gin.Context) {
+	h.create(c, entity.AccessRequestNote, "noteID")
+}
+
+// @Security BearerAuth
+// @Summary List access requests of a note
+// @Tags access-requests
+// @Produce json
+// @Param id path string true "Note ID"
+// @Param status query string false "PENDING, APPROVED or DENIED (default all)"
+// @Success 200 {array} dto.AccessRequestResponse
+// @Router /notes/{id}/access-requests [get]
+func (h *AccessRequestHandler) ListNoteRequests(c *gin.Context) {
+	h.list(c, entity.AccessRequestNote, "id")
+}
+
+// @Security BearerAuth
+// @Summary Approve an access request on a note
+// @Description Shares the note with the requester. access_level and expires_at optionally override the requested level and make the share temporary.
+// @Tags access-requests
+// @Accept json
+// @Produce json
+// @Param noteID path string true "Note ID"
+// @Param requestID path string true "Access request ID"
+// @Param request body dto.ApproveAccessRequestRequest false "Optional access level and expiry"
+// @Success 200 {object} dto.AccessRequestResponse
+// @Router /notes/{noteID}/access-requests/{requestID}/approve [post]
+func (h *AccessRequestHandler) ApproveNoteRequest(c *gin.Context) {
+	h.approve(c, entity.AccessRequestNote, "noteID")
+}
+
+// @Security BearerAuth
+// @Summary Deny an access request on a note
+// @Tags access-requests
+// @Produce json
+// @Param noteID path string true "Note ID"
+// @Param requestID path string true "Access request ID"
+// @Success 200 {object} dto.AccessRequestResponse
+// @Router /notes/{noteID}/access-requests/{requestID}/deny [post]
+func (h *AccessRequestHandler) DenyNoteRequest(c *gin.Context) {
+	h.deny(c, entity.AccessRequestNote, "noteID")
+}
+
+// @Security BearerAuth
+// @Summary Request access to a folder
+// @Description Asks the owner of a folder for READ or WRITE access. The owner is notified.
+// @Tags access-requests
+// @Accept json
+// @Produce json
+// @Param folderID path string true "Folder ID"
+// @Param request body dto.CreateAccessRequestRequest true "Requested access level and optional message"
+// @Success 201 {object} dto.AccessRequestResponse
+// @Failure 409 {object} object "Access already granted or a request is already pending"
+// @Router /folders/{folderID}/access-requests [post]
+func (h *AccessRequestHandler) CreateFolderRequest(c *gin.Context) {
+	h.create(c, entity.AccessRequestFolder, "folderID")
+}
+
+// @Security BearerAuth
+// @Summary List access requests of a folder
+// @Tags access-requests
+// @Produce json
+// @Param folderID path string true "Folder ID"
+// @Param status query string false "PENDING, APPROVED or DENIED (default all)"
+// @Success 200 {array} dto.AccessRequestResponse
+// @Router /folders/{folderID}/access-requests [get]
+func (h *AccessRequestHandler) ListFolderRequests(c *gin.Context) {
+	h.list(c, entity.AccessRequestFolder, "folderID")
+}
+
+// @Security BearerAuth
+// @Summary Approve an access request on a folder
+// @Description Shares the folder with the requester. access_level and expires_at optionally override the requested level and make the share temporary.
+// @Tags access-requests
+// @Accept json
+// @Produce json
+// @Param folderID path string true "Folder ID"
+// @Param requestID path string true "Access request ID"
+// @Param request body dto.ApproveAccessRequestRequest false "Optional access level and expiry"
+// @Success 200 {object} dto.AccessRequestResponse
+// @Router /folders/{folderID}/access-requests/{requestID}/approve [post]
+func (h *AccessRequestHandler) ApproveFolderRequest(c *gin.Context) {
+	h.approve(c, entity.AccessRequestFolder, "folderID")
+}
+
+// @Security BearerAuth
+// @Summary Deny an access request on a folder
+// @Tags access-requests
+// @Produce json
+// @Param folderID path string true "Folder ID"
+// @Param requestID path string true "Access request ID"
+// @Success 200 {object} dto.AccessRequestResponse
+// @Router /folders/{folderID}/access-requests/{requestID}/deny [post]
+func (h *AccessRequestHandler) DenyFolderRequest(c *gin.Context) {
+	h.deny(c, entity.AccessRequestFolder, "folderID")
+}
+
+func (h *AccessRequestHandler) create(c *gin.Context, assetType entity.AccessRequestAssetType, param string) {
+	assetID, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + accessRequestAssetLabel(assetType) + " ID"})
+		return
+	}
+
+	var request dto.CreateAccessRequestRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	created, err := h.accessRequestService.Create(c, assetType, assetID, request.AccessLevel, request.Message)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToAccessRequestResponse(created))
+}
+
+func (h *AccessRequestHandler) list(c *gin.Context, assetType entity.AccessRequestAssetType, param string) {
+	assetID, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + accessRequestAssetLabel(assetType) + " ID"})
+		return
+	}
+
+	var status *entity.AccessRequestStatus
+	if raw := c.Query("status"); raw != "" {
+		s := entity.AccessRequestStatus(strings.ToUpper(raw))
+		if !s.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "status must be PENDING, APPROVED or DENIED"})
+			return
+		}
+		status = &s
+	}
+
+	requests, err := h.accessRequestService.List(c, assetType, assetID, status)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToAccessRequestResponses(requests))
+}
+
+func (h *AccessRequestHandler) approve(c *gin.Context, assetType entity.AccessRequestAssetType, param string) {
+	assetID, requestID, ok := parseAccessRequestParams(c, assetType, param)
+	if !ok {
+		return
+	}
+
+	var request dto.ApproveAccessRequestRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+	}
+
+	approved, err := h.accessRequestService.Approve(c, assetType, assetID, requestID, request.AccessLevel, request.ExpiresAt)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToAccessRequestResponse(approved))
+}
+
+func (h *AccessRequestHandler) deny(c *gin.Context, assetType entity.AccessRequestAssetType, param string) {
+	assetID, requestID, ok := parseAccessRequestParams(c, assetType, param)
+	if !ok {
+		return
+	}
+
+	denied, err := h.accessRequestService.Deny(c, assetType, assetID, requestID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToAccessRequestResponse(denied))
+}
+
+func parseAccessRequestParams(c *gin.Context, assetType entity.AccessRequestAssetType, param string) (uuid.UUID, uuid.UUID, bool) {
+	assetID, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + accessRequestAssetLabel(assetType) + " ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	requestID, err := uuid.Parse(c.Param("requestID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid access request ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return assetID, requestID, true
+}
+
+func accessRequestAssetLabel(assetType entity.AccessRequestAssetType) string {
+	if assetType == entity.AccessRequestNote {
+		return "note"
+	}
+	return "folder"
+}
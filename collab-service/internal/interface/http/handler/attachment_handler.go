@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/interface/http/dto"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// multipartOverhead là phần dư cho header của form multipart ngoài nội dung file
+const multipartOverhead = 1 << 20
+
+// AttachmentHandler serves uploads and downloads of note attachments
+type AttachmentHandler struct {
+	attachmentService *application.AttachmentService
+}
+
+func NewAttachmentHandler(service *application.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: service,
+	}
+}
+
+// @Security BearerAuth
+// @Summary Upload an attachment
+// @Description Upload a file to a note as multipart form field "file". Requires write access on the note.
+// @Description Rejected with 413 when the file is too large or a team with access to the note is over its quota.
+// @Tags notes
+// @Accept multipart/form-data
+// @Produce json
+// @Param noteID path string true "Note ID"
+// @Param file formData file true "File to attach"
+// @Success 201 {object} dto.AttachmentResponse
+// @Router /notes/{noteID}/attachments [post]
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.attachmentService.MaxSize()+multipartOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("attachment exceeds the maximum size of %d bytes", h.attachmentService.MaxSize())})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "multipart field \"file\" is required"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.Upload(c, noteID, header.Filename, header.Size, file)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToAttachmentResponse(attachment))
+}
+
+// @Security BearerAuth
+// @Summary List attachments
+// @Description List the attachments of a note
+// @Tags notes
+// @Produce json
+// @Param id path string true "Note ID"
+// @Success 200 {array} dto.AttachmentResponse
+// @Router /notes/{id}/attachments [get]
+func (h *AttachmentHandler) List(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return
+	}
+
+	attachments, err := h.attachmentService.List(c, noteID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToAttachmentResponses(attachments))
+}
+
+// @Security BearerAuth
+// @Summary Download an attachment
+// @Description Download the content of an attachment. Supports a single byte range via the Range header.
+// @Tags notes
+// @Produce octet-stream
+// @Param id path string true "Note ID"
+// @Param attachmentID path string true "Attachment ID"
+// @Param Range header string false "Byte range, e.g. bytes=0-1023"
+// @Router /notes/{id}/attachments/{attachmentID} [get]
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	noteID, attachmentID, ok := parseAttachmentParams(c, "id")
+	if !ok {
+		return
+	}
+
+	attachment, err := h.attachmentService.Get(c, noteID, attachmentID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	etag := strconv.Quote(attachment.Checksum)
+	rangeHeader := c.GetHeader("Range")
+	// If-Range: chỉ trả một phần khi nội dung client đang có vẫn là bản hiện tại
+	if ifRange := c.GetHeader("If-Range"); ifRange != "" && ifRange != etag {
+		rangeHeader = ""
+	}
+
+	start, length, partial, err := parseByteRange(rangeHeader, attachment.Size)
+	if err != nil {
+		c.Header("Content-Range", fmt.Sprintf("bytes */%d", attachment.Size))
+		c.JSON(http.StatusRequestedRangeNotSatisfiable, gin.H{"error": err.Error()})
+		return
+	}
+
+	content, err := h.attachmentService.Open(c.Request.Context(), attachment, start, length)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+	defer content.Close()
+
+	headers := map[string]string{
+		"Accept-Ranges":          "bytes",
+		"ETag":                   etag,
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	}
+	status := http.StatusOK
+	if partial {
+		status = http.StatusPartialContent
+		headers["Content-Range"] = fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, attachment.Size)
+	}
+
+	c.DataFromReader(status, length, attachment.MimeType, content, headers)
+}
+
+// @Security BearerAuth
+// @Summary Delete an attachment
+// @Description Remove an attachment from a note. Requires write access on the note.
+// @Tags notes
+// @Param noteID path string true "Note ID"
+// @Param attachmentID path string true "Attachment ID"
+// @Router /notes/{noteID}/attachments/{attachmentID} [delete]
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	noteID, attachmentID, ok := parseAttachmentParams(c, "noteID")
+	if !ok {
+		return
+	}
+
+	if err := h.attachmentService.Delete(c, noteID, attachmentID); err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func parseAttachmentParams(c *gin.Context, noteParam string) (uuid.UUID, uuid.UUID, bool) {
+	noteID, err := uuid.Parse(c.Param(noteParam))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	attachmentID, err := uuid.Parse(c.Param("attachmentID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return noteID, attachmentID, true
+}
+
+// parseByteRange đọc header Range dạng bytes=a-b, bytes=a- hoặc bytes=-n trên nội dung dài size byte.
+// Header rỗng, sai cú pháp hoặc nhiều range thì trả toàn bộ nội dung (RFC 9110 cho phép bỏ qua Range);
+// chỉ trả lỗi khi range hợp lệ nhưng nằm ngoài nội dung.
+func parseByteRange(header string, size int64) (start, length int64, partial bool, err error) {
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return 0, size, false, nil
+	}
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, size, false, nil
+	}
+
+	end := size - 1
+	switch {
+	case first == "":
+		// bytes=-n: n byte cuối
+		n, parseErr := strconv.ParseInt(last, 10, 64)
+		if parseErr != nil || n < 0 {
+			return 0, size, false, nil
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, false, errors.New("requested range not satisfiable")
+		}
+		start = max(size-n, 0)
+	default:
+		var parseErr error
+		start, parseErr = strconv.ParseInt(first, 10, 64)
+		if parseErr != nil || start < 0 {
+			return 0, size, false, nil
+		}
+		if last != "" {
+			requestedEnd, parseErr := strconv.ParseInt(last, 10, 64)
+			if parseErr != nil || requestedEnd < start {
+				return 0, size, false, nil
+			}
+			end = min(requestedEnd, end)
+		}
+		if start >= size {
+			return 0, 0, false, errors.New("requested range not satisfiable")
+		}
+	}
+	return start, end - start + 1, true, nil
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		size        int64
+		wantStart   int64
+		wantLength  int64
+		wantPartial bool
+		wantErr     bool
+	}{
+		{name: "no header", header: "", size: 10, wantLength: 10},
+		{name: "closed range", header: "bytes=0-4", size: 10, wantLength: 5, wantPartial: true},
+		{name: "open-ended range", header: "bytes=5-", size: 10, wantStart: 5, wantLength: 5, wantPartial: true},
+		{name: "end past the content is clamped", header: "bytes=3-100", size: 10, wantStart: 3, wantLength: 7, wantPartial: true},
+		{name: "suffix range", header: "bytes=-3", size: 10, wantStart: 7, wantLength: 3, wantPartial: true},
+		{name: "suffix longer than the content", header: "bytes=-20", size: 10, wantLength: 10, wantPartial: true},
+		{name: "empty suffix is not satisfiable", header: "bytes=-0", size: 10, wantErr: true},
+		{name: "suffix of empty content is not satisfiable", header: "bytes=-5", size: 0, wantErr: true},
+		{name: "start equal to size is not satisfiable", header: "bytes=10-", size: 10, wantErr: true},
+		{name: "start past size is not satisfiable", header: "bytes=12-15", size: 10, wantErr: true},
+		{name: "end before start is ignored", header: "bytes=9-3", size: 10, wantLength: 10},
+		{name: "multiple ranges are ignored", header: "bytes=0-1,4-5", size: 10, wantLength: 10},
+		{name: "other unit is ignored", header: "items=0-1", size: 10, wantLength: 10},
+		{name: "malformed range is ignored", header: "bytes=abc", size: 10, wantLength: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, length, partial, err := parseByteRange(tt.header, tt.size)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantLength, length)
+			assert.Equal(t, tt.wantPartial, partial)
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/interface/http/dto"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CommentHandler serves comment threads on notes
+type CommentHandler struct {
+	commentService *application.CommentService
+}
+
+func NewCommentHandler(service *application.CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: service,
+	}
+}
+
+// @Security BearerAuth
+// @Summary List comment threads
+// @Description Comment threads on a note with their replies, oldest first
+// @Tags comments
+// @Produce json
+// @Param id path string true "Note ID"
+// @Param resolved query bool false "Only resolved (true) or open (false) threads"
+// @Success 200 {array} dto.CommentResponse
+// @Router /notes/{id}/comments [get]
+func (h *CommentHandler) List(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return
+	}
+
+	var resolved *bool
+	if raw := c.Query("resolved"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resolved filter"})
+			return
+		}
+		resolved = &value
+	}
+
+	threads, err := h.commentService.ListThreads(c, noteID, resolved)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCommentThreadResponses(threads))
+}
+
+// @Security BearerAuth
+// @Summary Comment on a note
+// @Description Start a comment thread, optionally anchored to a character range of the note body. Requires read access.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param noteID path string true "Note ID"
+// @Param body body dto.CreateCommentRequest true "Comment"
+// @Success 201 {object} dto.CommentResponse
+// @Router /notes/{noteID}/comments [post]
+func (h *CommentHandler) Create(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return
+	}
+
+	var req dto.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.commentService.AddComment(c, noteID, req.Body, req.ToAnchor())
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToCommentResponse(comment))
+}
+
+// @Security BearerAuth
+// @Summary Reply to a comment thread
+// @Description Add a reply to the thread containing the comment. Requires read access.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param noteID path string true "Note ID"
+// @Param commentID path string true "Comment ID"
+// @Param body body dto.ReplyCommentRequest true "Reply"
+// @Success 201 {object} dto.CommentResponse
+// @Router /notes/{noteID}/comments/{commentID}/replies [post]
+func (h *CommentHandler) Reply(c *gin.Context) {
+	noteID, commentID, ok := parseCommentParams(c, "noteID")
+	if !ok {
+		return
+	}
+
+	var req dto.ReplyCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reply, err := h.commentService.Reply(c, noteID, commentID, req.Body)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToCommentResponse(reply))
+}
+
+// @Security BearerAuth
+// @Summary Edit a comment
+// @Description Change the text of your own comment
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Note ID"
+// @Param commentID path string true "Comment ID"
+// @Param body body dto.UpdateCommentRequest true "New text"
+// @Success 200 {object} dto.CommentResponse
+// @Router /notes/{id}/comments/{commentID} [put]
+func (h *CommentHandler) Update(c *gin.Context) {
+	noteID, commentID, ok := parseCommentParams(c, "id")
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.commentService.Edit(c, noteID, commentID, req.Body)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCommentResponse(comment))
+}
+
+// @Security BearerAuth
+// @Summary Delete a comment
+// @Description Delete your own comment. A thread that still has replies keeps a placeholder for its first comment.
+// @Tags comments
+// @Param noteID path string true "Note ID"
+// @Param commentID path string true "Comment ID"
+// @Router /notes/{noteID}/comments/{commentID} [delete]
+func (h *CommentHandler) Delete(c *gin.Context) {
+	noteID, commentID, ok := parseCommentParams(c, "noteID")
+	if !ok {
+		return
+	}
+
+	if err := h.commentService.Delete(c, noteID, commentID); err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Security BearerAuth
+// @Summary Resolve a comment thread
+// @Description Mark the thread containing the comment as resolved. Requires write access.
+// @Tags comments
+// @Produce json
+// @Param noteID path string true "Note ID"
+// @Param commentID path string true "Comment ID"
+// @Success 200 {object} dto.CommentResponse
+// @Router /notes/{noteID}/comments/{commentID}/resolve [post]
+func (h *CommentHandler) Resolve(c *gin.Context) {
+	noteID, commentID, ok := parseCommentParams(c, "noteID")
+	if !ok {
+		return
+	}
+
+	root, err := h.commentService.Resolve(c, noteID, commentID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCommentResponse(root))
+}
+
+// @Security BearerAuth
+// @Summary Reopen a comment thread
+// @Description Reopen a resolved thread. Requires write access.
+// @Tags comments
+// @Produce json
+// @Param noteID path string true "Note ID"
+// @Param commentID path string true "Comment ID"
+// @Success 200 {object} dto.CommentResponse
+// @Router /notes/{noteID}/comments/{commentID}/reopen [post]
+func (h *CommentHandler) Reopen(c *gin.Context) {
+	noteID, commentID, ok := parseCommentParams(c, "noteID")
+	if !ok {
+		return
+	}
+
+	root, err := h.commentService.Reopen(c, noteID, commentID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCommentResponse(root))
+}
+
+func parseCommentParams(c *gin.Context, noteParam string) (uuid.UUID, uuid.UUID, bool) {
+	noteID, err := uuid.Parse(c.Param(noteParam))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	commentID, err := uuid.Parse(c.Param("commentID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return noteID, commentID, true
+}
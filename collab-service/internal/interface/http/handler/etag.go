@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag ghi version hiện tại của note/folder vào header ETag
+func setETag(c *gin.Context, version int64) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// ifMatchVersion đọc version client mong đợi từ header If-Match.
+// Trả về 0 khi không có header hoặc If-Match là "*", tức là cập nhật không điều kiện.
+func ifMatchVersion(c *gin.Context) (int64, error) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	value = strings.TrimPrefix(value, "W/")
+	version, err := strconv.ParseInt(strings.Trim(value, `"`), 10, 64)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid If-Match header %q", c.GetHeader("If-Match"))
+	}
+	return version, nil
+}
@@ -23,7 +23,7 @@ func NewFolderHandler(folderService *application.FolderService) *FolderHandler {
 
 // @Security BearerAuth
 // @Summary Create a new folder
-// @Description Create a new folder with the given name
+// @Description Create a new folder with the given name, optionally inside a parent folder
 // @Tags folders
 // @Accept json
 // @Produce json
@@ -37,30 +37,30 @@ func (h *FolderHandler) Create(c *gin.Context) {
 		return
 	}
 
-	folder, err := h.folderService.Create(c, request.Name)
+	folder, err := h.folderService.Create(c, request.Name, request.ParentID)
 	if err != nil {
 		application.HandleError(c, err)
 		return
 	}
 
-	var response dto.FolderResponse
-	response.ID = folder.ID
-	response.Name = folder.Name
-
-	c.JSON(http.StatusCreated, response)
+	c.JSON(http.StatusCreated, dto.ToFolderResponse(folder))
 }
 
 // @Security BearerAuth
 // @Summary Get a folder by ID
-// @Description Get a folder by its unique ID
+// @Description Get a folder by its unique ID together with its direct sub-folders
 // @Tags folders
 // @Produce json
-// @Param id path string true "Folder ID"
-// @Router /folders/{id} [get]
+// @Param folderID path string true "Folder ID"
+// @Router /folders/{folderID} [get]
 func (h *FolderHandler) GetByID(c *gin.Context) {
-	id := c.Param("id")
+	folderID, err := uuid.Parse(c.Param("folderID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		return
+	}
 
-	folder, err := h.folderService.GetFolderByID(c, uuid.MustParse(id))
+	folder, err := h.folderService.GetFolderByID(c, folderID)
 	if err != nil {
 		application.HandleError(c, err)
 		return
@@ -70,10 +70,18 @@ func (h *FolderHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	var response dto.FolderResponse
-	response.ID = folder.ID
-	response.Name = folder.Name
+	children, err := h.folderService.GetChildren(c, folderID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	response := dto.ToFolderResponse(folder)
+	for _, child := range children {
+		response.SubFolders = append(response.SubFolders, dto.ToFolderResponse(child))
+	}
 
+	setETag(c, folder.Version)
 	c.JSON(http.StatusOK, response)
 }
 
@@ -92,10 +100,7 @@ func (h *FolderHandler) GetAllCanAccess(c *gin.Context) {
 
 	var response []dto.FolderResponse
 	for _, folder := range folders {
-		response = append(response, dto.FolderResponse{
-			ID:   folder.ID,
-			Name: folder.Name,
-		})
+		response = append(response, dto.ToFolderResponse(folder))
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -108,6 +113,7 @@ func (h *FolderHandler) GetAllCanAccess(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param folderID path string true "Folder ID"
+// @Param If-Match header string false "ETag from a previous read; the update fails with 412 if the folder changed since"
 // @Param request body dto.UpdateFolderRequest true "Folder update request"
 // @Router /folders/{folderID} [put]
 func (h *FolderHandler) Update(c *gin.Context) {
@@ -123,6 +129,12 @@ func (h *FolderHandler) Update(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	folder, err := h.folderService.GetFolderByID(c, folderID)
 	if err != nil {
 		application.HandleError(c, err)
@@ -130,12 +142,14 @@ func (h *FolderHandler) Update(c *gin.Context) {
 	}
 
 	folder.Name = request.Name
+	folder.Version = version
 
 	if err := h.folderService.Update(c, folder); err != nil {
 		application.HandleError(c, err)
 		return
 	}
 
+	setETag(c, folder.Version)
 	c.JSON(http.StatusOK, gin.H{"message": "Folder updated successfully"})
 }
 
@@ -158,8 +172,8 @@ func (h *FolderHandler) Delete(c *gin.Context) {
 }
 
 // @Security BearerAuth
-// @Summary Share a folder with a user
-// @Description Share a folder with a user by their ID and set access level
+// @Summary Share a folder with a user or a team
+// @Description Share a folder with a user, or with every member of a team when team_id is set. An optional expires_at makes the share temporary
 // @Tags folders
 // @Accept json
 // @Produce json
@@ -179,7 +193,15 @@ func (h *FolderHandler) ShareFolder(c *gin.Context) {
 		return
 	}
 
-	if err := h.folderService.ShareFolder(c, folderID, rep.UserID, rep.AccessLevel); err != nil {
+	if rep.TeamID != nil {
+		err = h.folderService.ShareFolderWithTeam(c, folderID, *rep.TeamID, rep.AccessLevel, rep.ExpiresAt)
+	} else if rep.UserID != uuid.Nil {
+		err = h.folderService.ShareFolder(c, folderID, rep.UserID, rep.AccessLevel, rep.ExpiresAt)
+	} else {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id or team_id is required"})
+		return
+	}
+	if err != nil {
 		application.HandleError(c, err)
 		return
 	}
@@ -215,3 +237,118 @@ func (h *FolderHandler) RevokeAccess(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Folder access revoked successfully"})
 }
+
+// @Security BearerAuth
+// @Summary Unshare a folder with a team
+// @Description Remove the share granted to a team on a folder
+// @Tags folders
+// @Produce json
+// @Param folderID path string true "Folder ID"
+// @Param teamID path string true "Team ID"
+// @Router /folders/{folderID}/team-shares/{teamID} [delete]
+func (h *FolderHandler) RevokeTeamAccess(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("teamID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	folderID, err := uuid.Parse(c.Param("folderID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		return
+	}
+
+	if err := h.folderService.RevokeTeamAccess(c, folderID, teamID); err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Folder team access revoked successfully"})
+}
+
+// @Security BearerAuth
+// @Summary Get the breadcrumbs of a folder
+// @Description Get the path from the top-most folder the user can see down to the given folder
+// @Tags folders
+// @Produce json
+// @Param folderID path string true "Folder ID"
+// @Router /folders/{folderID}/breadcrumbs [get]
+func (h *FolderHandler) GetBreadcrumbs(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		return
+	}
+
+	folders, err := h.folderService.GetBreadcrumbs(c, folderID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	response := make([]dto.BreadcrumbResponse, len(folders))
+	for i, folder := range folders {
+		response[i] = dto.BreadcrumbResponse{ID: folder.ID, Name: folder.Name}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Security BearerAuth
+// @Summary Explain access to a folder
+// @Description Returns the effective access level of a user on a folder and every share that contributed to it, including shares inherited from parent folders. Only the owner can explain another user's access.
+// @Tags folders
+// @Produce json
+// @Param folderID path string true "Folder ID"
+// @Param userId query string false "User to explain (default the caller)"
+// @Success 200 {object} dto.AccessExplanationResponse
+// @Router /folders/{folderID}/permissions/explain [get]
+func (h *FolderHandler) ExplainAccess(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		return
+	}
+	userID, ok := parseExplainUserID(c)
+	if !ok {
+		return
+	}
+
+	explanation, err := h.folderService.ExplainAccess(c, folderID, userID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToAccessExplanationResponse(explanation))
+}
+
+// @Security BearerAuth
+// @Summary Move a folder
+// @Description Move a folder and everything beneath it into another folder, or to the root when parent_id is null
+// @Tags folders
+// @Accept json
+// @Produce json
+// @Param folderID path string true "Folder ID"
+// @Param request body dto.MoveFolderRequest true "Folder move request"
+// @Router /folders/{folderID}/parent [put]
+func (h *FolderHandler) MoveFolder(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		return
+	}
+
+	var request dto.MoveFolderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	if err := h.folderService.MoveFolder(c, folderID, request.ParentID); err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Folder moved successfully"})
+}
@@ -2,7 +2,7 @@ package handler
 
 import (
 	"collab-service/internal/application"
-	"collab-service/internal/domain/entity"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,11 +22,11 @@ func NewManagerHandler(managerService *application.ManagerService) *ManagerHandl
 }
 
 // GetTeamAssets handles GET /teams/:teamId/assets
-// Returns all assets that team members own or can access
+// Returns all assets that members of the team and its sub-teams own or can access
 // @Security BearerAuth
 // GetTeamAssets godoc
 // @Summary Get all assets of a team
-// @Description Returns all assets that team members own or can access
+// @Description Returns all assets that members of the team or any of its sub-teams own or can access
 // @Tags teams,assets
 // @Accept json
 // @Produce json
@@ -39,13 +39,19 @@ func (h *ManagerHandler) GetTeamAssets(c *gin.Context) {
 		return
 	}
 
-	h.managerService.GetTeamAssets(c.Request.Context(), teamID.String())
+	assets, err := h.managerService.GetTeamAssets(c, teamID)
+	if errors.Is(err, application.ErrTeamNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
 
-	// For now return a placeholder response
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Get team assets endpoint",
-		"teamId":  teamID,
-		"assets":  []entity.Note{}, // Replace with actual assets
+		"teamId": teamID,
+		"assets": assets,
 	})
 }
 
@@ -67,13 +73,18 @@ func (h *ManagerHandler) GetUserAssets(c *gin.Context) {
 		return
 	}
 
-	h.managerService.GetUserAssets(c.Request.Context(), userID.String())
+	assets, err := h.managerService.GetUserAssets(c, userID)
+	if errors.Is(err, application.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
 
-	// For now return a placeholder response
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Get user assets endpoint",
-		"userId":  userID,
-		"assets":  []entity.Note{}, // Replace with actual assets
+		"userId": userID,
+		"assets": assets,
 	})
-
 }
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"collab-service/internal/application"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	collabWriteWait      = 10 * time.Second
+	collabPongWait       = 60 * time.Second
+	collabPingPeriod     = 30 * time.Second
+	collabMaxMessageSize = 1 << 20
+)
+
+// NoteCollabHandler phục vụ kết nối WebSocket chỉnh sửa note thời gian thực
+type NoteCollabHandler struct {
+	collabService *application.NoteCollabService
+	upgrader      websocket.Upgrader
+}
+
+func NewNoteCollabHandler(service *application.NoteCollabService) *NoteCollabHandler {
+	return &NoteCollabHandler{
+		collabService: service,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  4096,
+			WriteBufferSize: 4096,
+			// Xác thực bằng bearer token chứ không bằng cookie nên không cần chặn theo Origin
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// @Security BearerAuth
+// @Summary Edit a note in real time
+// @Description Upgrade to a WebSocket for collaborative editing. Browsers may pass the token as ?token= instead of the Authorization header.
+// @Description Messages are JSON: the client sends {"type":"op","baseRev":n,"op":[{"retain":n}|{"insert":"s"}|{"delete":n}],"opId":"..."} and {"type":"cursor","position":n,"selectionEnd":n};
+// @Description the server sends init, op, ack, cursor, presence and error messages.
+// @Tags notes
+// @Param id path string true "Note ID"
+// @Param token query string false "Access token for clients that cannot set headers"
+// @Router /notes/{id}/live [get]
+func (h *NoteCollabHandler) Live(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return
+	}
+	if !c.IsWebsocket() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "WebSocket upgrade required"})
+		return
+	}
+
+	session, err := h.collabService.Join(c, noteID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		h.collabService.Leave(session)
+		return
+	}
+	defer conn.Close()
+
+	done := make(chan struct{})
+	go h.writePump(conn, session, done)
+
+	conn.SetReadLimit(collabMaxMessageSize)
+	_ = conn.SetReadDeadline(time.Now().Add(collabPongWait))
+	conn.SetPongHandler(func(string) error {
+		h.collabService.Heartbeat(session)
+		return conn.SetReadDeadline(time.Now().Add(collabPongWait))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		h.collabService.HandleMessage(session, data)
+	}
+
+	h.collabService.Leave(session)
+	<-done
+}
+
+// writePump ghi message của phiên xuống WebSocket và gửi ping định kỳ
+func (h *NoteCollabHandler) writePump(conn *websocket.Conn, session *application.CollabSession, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(collabPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case data, ok := <-session.Outbound():
+			_ = conn.SetWriteDeadline(time.Now().Add(collabWriteWait))
+			if !ok {
+				// Phiên đã đóng (client rời đi hoặc đọc không kịp)
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				_ = conn.Close()
+				return
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				_ = conn.Close()
+				return
+			}
+		case <-ticker.C:
+			_ = conn.SetWriteDeadline(time.Now().Add(collabWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				_ = conn.Close()
+				return
+			}
+		}
+	}
+}
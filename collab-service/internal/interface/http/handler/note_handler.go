@@ -70,20 +70,70 @@ func (h *NoteHandler) GetByID(c *gin.Context) {
 		application.HandleError(c, err)
 		return
 	}
+	setETag(c, note.Version)
 	c.JSON(http.StatusOK, note)
 }
 
+// @Security BearerAuth
+// @Summary Explain access to a note
+// @Description Returns the effective access level of a user on a note and every share that contributed to it: ownership, direct shares, team shares and shares inherited from parent folders. Only the owner can explain another user's access.
+// @Tags notes
+// @Produce json
+// @Param id path string true "Note ID"
+// @Param userId query string false "User to explain (default the caller)"
+// @Success 200 {object} dto.AccessExplanationResponse
+// @Router /notes/{id}/permissions/explain [get]
+func (h *NoteHandler) ExplainAccess(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return
+	}
+	userID, ok := parseExplainUserID(c)
+	if !ok {
+		return
+	}
+
+	explanation, err := h.NoteService.ExplainAccess(c, noteID, userID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToAccessExplanationResponse(explanation))
+}
+
+// parseExplainUserID đọc query userId; trống là chính caller
+func parseExplainUserID(c *gin.Context) (*uuid.UUID, bool) {
+	raw := c.Query("userId")
+	if raw == "" {
+		return nil, true
+	}
+	userID, err := uuid.Parse(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return nil, false
+	}
+	return &userID, true
+}
+
 // GetAll godoc
 // @Security BearerAuth
 // @Summary Get all notes
-// @Description Get all notes that the user has access to
+// @Description Get all notes that the user has access to. Repeat tag to require several tags.
 // @Tags notes
 // @Produce json
+// @Param tag query []string false "Tag ID or name; notes must carry every given tag"
 // @Router /notes [get]
 func (h *NoteHandler) GetAll(c *gin.Context) {
 	userID, _ := middleware.GetUserInfoFromGin(c)
 
-	notes, err := h.NoteService.GetAllCanAccess(c, userID)
+	var notes []*entity.Note
+	var err error
+	if tags := c.QueryArray("tag"); len(tags) > 0 {
+		notes, err = h.NoteService.GetAllCanAccessByTags(c, tags)
+	} else {
+		notes, err = h.NoteService.GetAllCanAccess(c, userID)
+	}
 	if err != nil {
 		application.HandleError(c, err)
 		return
@@ -93,7 +143,7 @@ func (h *NoteHandler) GetAll(c *gin.Context) {
 
 // @Security BearerAuth
 // @Summary Share a note with another user
-// @Description Share a note with another user by ID and access level
+// @Description Share a note with another user by ID and access level. An optional expires_at makes the share temporary
 // @Tags notes
 // @Accept json
 // @Produce json
@@ -107,13 +157,21 @@ func (h *NoteHandler) ShareNote(c *gin.Context) {
 		return
 	}
 
-	noteID, err := uuid.Parse(c.Param("id"))
+	noteID, err := uuid.Parse(c.Param("noteID"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
 		return
 	}
 
-	if err := h.NoteService.ShareNote(c, noteID, req.UserID, req.AccessLevel); err != nil {
+	if req.TeamID != nil {
+		err = h.NoteService.ShareNoteWithTeam(c, noteID, *req.TeamID, req.AccessLevel, req.ExpiresAt)
+	} else if req.UserID != uuid.Nil {
+		err = h.NoteService.ShareNote(c, noteID, req.UserID, req.AccessLevel, req.ExpiresAt)
+	} else {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id or team_id is required"})
+		return
+	}
+	if err != nil {
 		application.HandleError(c, err)
 		return
 	}
@@ -150,6 +208,32 @@ func (h *NoteHandler) RevokeAccess(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
+// @Security BearerAuth
+// @Summary Revoke a team's access to a note
+// @Description Remove the share granted to a team on a note
+// @Tags notes
+// @Router /notes/{noteId}/team-shares/{teamId} [delete]
+func (h *NoteHandler) RevokeTeamAccess(c *gin.Context) {
+	parsedNoteID, err := uuid.Parse(c.Param("noteID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return
+	}
+
+	parsedTeamID, err := uuid.Parse(c.Param("teamID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	if err := h.NoteService.RevokeTeamAccess(c, parsedNoteID, parsedTeamID); err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
 // @Security BearerAuth
 // @Summary Update a note
 // @Description Update a note by its unique ID
@@ -157,6 +241,7 @@ func (h *NoteHandler) RevokeAccess(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Note ID"
+// @Param If-Match header string false "ETag from a previous read; the update fails with 412 if the note changed since"
 // @Param body body dto.UpdateNoteRequest true "Note details"
 // @Router /notes/{id} [put]
 func (h *NoteHandler) Update(c *gin.Context) {
@@ -173,11 +258,18 @@ func (h *NoteHandler) Update(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	note := &entity.Note{
 		ID:       parsedID,
 		Title:    req.Title,
 		Body:     req.Body,
 		FolderID: req.FolderID,
+		Version:  version,
 	}
 
 	if err := h.NoteService.Update(c, note); err != nil {
@@ -185,6 +277,7 @@ func (h *NoteHandler) Update(c *gin.Context) {
 		return
 	}
 
+	setETag(c, note.Version)
 	c.JSON(http.StatusOK, note)
 }
 
@@ -210,3 +303,70 @@ func (h *NoteHandler) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusOK, "Success")
 }
+
+// @Security BearerAuth
+// @Summary Move a note
+// @Description Move a note to another folder. Requires write access on both the source and destination folders.
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param noteID path string true "Note ID"
+// @Param body body dto.MoveNoteRequest true "Destination folder and share policy"
+// @Router /notes/{noteID}/move [post]
+func (h *NoteHandler) Move(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return
+	}
+
+	var req dto.MoveNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.SharePolicy == "" {
+		req.SharePolicy = entity.NoteSharesKeep
+	}
+
+	if err := h.NoteService.Move(c, noteID, req.FolderID, req.SharePolicy); err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Note moved successfully"})
+}
+
+// @Security BearerAuth
+// @Summary Copy a note
+// @Description Duplicate a note into another folder. Requires write access on both the source and destination folders.
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param noteID path string true "Note ID"
+// @Param body body dto.MoveNoteRequest true "Destination folder and share policy"
+// @Router /notes/{noteID}/copy [post]
+func (h *NoteHandler) Copy(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return
+	}
+
+	var req dto.MoveNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.SharePolicy == "" {
+		req.SharePolicy = entity.NoteSharesKeep
+	}
+
+	copied, err := h.NoteService.Copy(c, noteID, req.FolderID, req.SharePolicy)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, copied)
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"collab-service/internal/application"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// @Security BearerAuth
+// @Summary List note revisions
+// @Description Paginated revision history of a note, newest first
+// @Tags notes
+// @Produce json
+// @Param id path string true "Note ID"
+// @Param page query int false "Page number (default 1)"
+// @Param pageSize query int false "Page size (default 20, max 100)"
+// @Success 200 {object} application.NoteRevisionPage
+// @Router /notes/{id}/revisions [get]
+func (h *NoteHandler) ListRevisions(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", strconv.Itoa(application.DefaultRevisionPageSize)))
+
+	revisions, err := h.NoteService.ListRevisions(c, noteID, page, pageSize)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// @Security BearerAuth
+// @Summary Get a note revision
+// @Description Get the full content of a single revision
+// @Tags notes
+// @Produce json
+// @Param id path string true "Note ID"
+// @Param number path int true "Revision number"
+// @Router /notes/{id}/revisions/{number} [get]
+func (h *NoteHandler) GetRevision(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return
+	}
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil || number < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision number"})
+		return
+	}
+
+	revision, err := h.NoteService.GetRevision(c, noteID, number)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// @Security BearerAuth
+// @Summary Diff two note revisions
+// @Description Line-level diff of the note body between two revisions
+// @Tags notes
+// @Produce json
+// @Param id path string true "Note ID"
+// @Param from query int true "Old revision number"
+// @Param to query int false "New revision number (default latest)"
+// @Success 200 {object} application.NoteRevisionDiff
+// @Router /notes/{id}/diff [get]
+func (h *NoteHandler) DiffRevisions(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return
+	}
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil || from < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from revision"})
+		return
+	}
+	to, err := strconv.Atoi(c.DefaultQuery("to", "0"))
+	if err != nil || to < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to revision"})
+		return
+	}
+
+	diff, err := h.NoteService.DiffRevisions(c, noteID, from, to)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// @Security BearerAuth
+// @Summary Restore a note revision
+// @Description Restore the content of an old revision; the restore is recorded as a new revision
+// @Tags notes
+// @Produce json
+// @Param noteID path string true "Note ID"
+// @Param number path int true "Revision number"
+// @Router /notes/{noteID}/revisions/{number}/restore [post]
+func (h *NoteHandler) RestoreRevision(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return
+	}
+	number, err := strconv.Atoi(c.Param("number"))
+	if err != nil || number < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision number"})
+		return
+	}
+
+	revision, err := h.NoteService.RestoreRevision(c, noteID, number)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/interface/http/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// @Security BearerAuth
+// @Summary List note tags
+// @Description Tags on a note that are visible to the current user
+// @Tags notes
+// @Produce json
+// @Param id path string true "Note ID"
+// @Success 200 {array} dto.TagResponse
+// @Router /notes/{id}/tags [get]
+func (h *NoteHandler) ListTags(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return
+	}
+
+	tags, err := h.NoteService.ListNoteTags(c, noteID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToTagResponses(tags))
+}
+
+// @Security BearerAuth
+// @Summary Tag a note
+// @Description Attach a personal or team tag to a note. Requires write access on the note.
+// @Tags notes
+// @Param noteID path string true "Note ID"
+// @Param tagID path string true "Tag ID"
+// @Router /notes/{noteID}/tags/{tagID} [post]
+func (h *NoteHandler) AddTag(c *gin.Context) {
+	noteID, tagID, ok := parseNoteTagParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.NoteService.AddNoteTag(c, noteID, tagID); err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Security BearerAuth
+// @Summary Untag a note
+// @Description Remove a tag from a note. Requires write access on the note.
+// @Tags notes
+// @Param noteID path string true "Note ID"
+// @Param tagID path string true "Tag ID"
+// @Router /notes/{noteID}/tags/{tagID} [delete]
+func (h *NoteHandler) RemoveTag(c *gin.Context) {
+	noteID, tagID, ok := parseNoteTagParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.NoteService.RemoveNoteTag(c, noteID, tagID); err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func parseNoteTagParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	noteID, err := uuid.Parse(c.Param("noteID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	tagID, err := uuid.Parse(c.Param("tagID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return noteID, tagID, true
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/domain/entity"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler serves the in-app notification inbox of the current user
+type NotificationHandler struct {
+	notificationService *application.NotificationService
+}
+
+func NewNotificationHandler(service *application.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: service,
+	}
+}
+
+// @Security BearerAuth
+// @Summary List notifications
+// @Description Notifications of the current user (mentions, comments on their notes, shares, team membership), newest first
+// @Tags notifications
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param page query int false "Page number (default 1)"
+// @Param pageSize query int false "Page size (default 20, max 100)"
+// @Success 200 {object} application.NotificationPage
+// @Router /notifications [get]
+func (h *NotificationHandler) List(c *gin.Context) {
+	unreadOnly := false
+	if raw := c.Query("unread"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unread must be true or false"})
+			return
+		}
+		unreadOnly = parsed
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", strconv.Itoa(application.DefaultNotificationPageSize)))
+
+	result, err := h.notificationService.List(c, unreadOnly, page, pageSize)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Security BearerAuth
+// @Summary Count unread notifications
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} map[string]int64
+// @Router /notifications/unread-count [get]
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	count, err := h.notificationService.UnreadCount(c)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread": count})
+}
+
+// @Security BearerAuth
+// @Summary Mark a notification as read
+// @Tags notifications
+// @Param notificationID path string true "Notification ID"
+// @Router /notifications/{notificationID}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	h.setRead(c, true)
+}
+
+// @Security BearerAuth
+// @Summary Mark a notification as unread
+// @Tags notifications
+// @Param notificationID path string true "Notification ID"
+// @Router /notifications/{notificationID}/unread [post]
+func (h *NotificationHandler) MarkUnread(c *gin.Context) {
+	h.setRead(c, false)
+}
+
+// @Security BearerAuth
+// @Summary Mark all notifications as read
+// @Tags notifications
+// @Router /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	if err := h.notificationService.MarkAllRead(c); err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *NotificationHandler) setRead(c *gin.Context, read bool) {
+	if err := h.notificationService.SetRead(c, c.Param("notificationID"), read); err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Security BearerAuth
+// @Summary Get email notification preferences
+// @Description How each notification type is emailed: instant, daily or weekly digest, or off
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	preferences, err := h.notificationService.GetPreferences(c)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// @Security BearerAuth
+// @Summary Update email notification preferences
+// @Description Set the delivery of some notification types, e.g. {"USER_MENTIONED": "instant", "COMMENT_ADDED": "off"}
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param body body map[string]string true "Notification type to delivery (instant, daily, weekly, off)"
+// @Success 200 {object} map[string]string
+// @Router /notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	var req map[string]entity.NotificationDelivery
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preferences, err := h.notificationService.UpdatePreferences(c, req)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/domain/entity"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SearchHandler serves full-text search over notes and folders
+type SearchHandler struct {
+	searchService *application.SearchService
+}
+
+func NewSearchHandler(service *application.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: service,
+	}
+}
+
+// @Security BearerAuth
+// @Summary Search notes and folders
+// @Description Full-text search over note titles/bodies and folder names, ranked by relevance.
+// @Description Only assets the caller can access through direct, folder or team shares are returned.
+// @Tags search
+// @Produce json
+// @Param q query string true "Search text; supports \"quoted phrases\", OR and -exclusions"
+// @Param type query string false "Restrict to NOTE or FOLDER"
+// @Param folderId query string false "Only search inside this folder and its sub-folders"
+// @Param teamId query string false "Only assets shared with this team"
+// @Param ownerId query string false "Only assets owned by this user"
+// @Param from query string false "Updated at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Updated before (RFC3339 or YYYY-MM-DD)"
+// @Param page query int false "Page number (default 1)"
+// @Param pageSize query int false "Page size (default 20, max 100)"
+// @Success 200 {object} application.SearchPage
+// @Router /search [get]
+func (h *SearchHandler) Search(c *gin.Context) {
+	query, err := parseSearchQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", strconv.Itoa(application.DefaultSearchPageSize)))
+
+	results, err := h.searchService.Search(c, query, page, pageSize)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+func parseSearchQuery(c *gin.Context) (*entity.SearchQuery, error) {
+	query := &entity.SearchQuery{Text: c.Query("q")}
+
+	if raw := strings.ToUpper(strings.TrimSpace(c.Query("type"))); raw != "" {
+		switch t := entity.SearchHitType(raw); t {
+		case entity.SearchHitNote, entity.SearchHitFolder:
+			query.Types = []entity.SearchHitType{t}
+		default:
+			return nil, fmt.Errorf("invalid type %q, expected NOTE or FOLDER", raw)
+		}
+	}
+
+	var err error
+	if query.FolderID, err = optionalUUIDQuery(c, "folderId"); err != nil {
+		return nil, err
+	}
+	if query.TeamID, err = optionalUUIDQuery(c, "teamId"); err != nil {
+		return nil, err
+	}
+	if query.OwnerID, err = optionalUUIDQuery(c, "ownerId"); err != nil {
+		return nil, err
+	}
+	if query.UpdatedFrom, err = optionalTimeQuery(c, "from"); err != nil {
+		return nil, err
+	}
+	if query.UpdatedTo, err = optionalTimeQuery(c, "to"); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+func optionalUUIDQuery(c *gin.Context, key string) (*uuid.UUID, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+	return &id, nil
+}
+
+// optionalTimeQuery nhận RFC3339 hoặc ngày dạng YYYY-MM-DD (đầu ngày UTC)
+func optionalTimeQuery(c *gin.Context, key string) (*time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid %s, expected RFC3339 or YYYY-MM-DD", key)
+}
@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/domain/entity"
+	"collab-service/internal/interface/http/dto"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ShareLinkPasswordHeader mang mật khẩu của link công khai có đặt mật khẩu
+const ShareLinkPasswordHeader = "X-Share-Password"
+
+// ShareLinkHandler serves share link management for owners and the public /s/:token routes
+type ShareLinkHandler struct {
+	shareLinkService *application.ShareLinkService
+}
+
+func NewShareLinkHandler(service *application.ShareLinkService) *ShareLinkHandler {
+	return &ShareLinkHandler{
+		shareLinkService: service,
+	}
+}
+
+// @Security BearerAuth
+// @Summary Create a public link to a note
+// @Description Creates a revocable READ-only link. The token is only returned once.
+// @Tags share-links
+// @Accept json
+// @Produce json
+// @Param noteID path string true "Note ID"
+// @Param request body dto.CreateShareLinkRequest false "Optional password and expiry"
+// @Success 201 {object} dto.CreatedShareLinkResponse
+// @Router /notes/{noteID}/links [post]
+func (h *ShareLinkHandler) CreateNoteLink(c *gin.Context) {
+	h.create(c, entity.ShareLinkNote, "noteID")
+}
+
+// @Security BearerAuth
+// @Summary List public links of a note
+// @Tags share-links
+// @Produce json
+// @Param id path string true "Note ID"
+// @Success 200 {array} dto.ShareLinkResponse
+// @Router /notes/{id}/links [get]
+func (h *ShareLinkHandler) ListNoteLinks(c *gin.Context) {
+	h.list(c, entity.ShareLinkNote, "id")
+}
+
+// @Security BearerAuth
+// @Summary Revoke a public link of a note
+// @Tags share-links
+// @Param noteID path string true "Note ID"
+// @Param linkID path string true "Link ID"
+// @Router /notes/{noteID}/links/{linkID} [delete]
+func (h *ShareLinkHandler) RevokeNoteLink(c *gin.Context) {
+	h.revoke(c, entity.ShareLinkNote, "noteID")
+}
+
+// @Security BearerAuth
+// @Summary List accesses of a public note link
+// @Tags share-links
+// @Produce json
+// @Param id path string true "Note ID"
+// @Param linkID path string true "Link ID"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 50, max 200)"
+// @Success 200 {object} dto.ShareLinkAccessesResponse
+// @Router /notes/{id}/links/{linkID}/accesses [get]
+func (h *ShareLinkHandler) ListNoteLinkAccesses(c *gin.Context) {
+	h.listAccesses(c, entity.ShareLinkNote, "id")
+}
+
+// @Security BearerAuth
+// @Summary Create a public link to a folder
+// @Description Creates a revocable READ-only link to the folder, its sub-folders and notes. The token is only returned once.
+// @Tags share-links
+// @Accept json
+// @Produce json
+// @Param folderID path string true "Folder ID"
+// @Param request body dto.CreateShareLinkRequest false "Optional password and expiry"
+// @Success 201 {object} dto.CreatedShareLinkResponse
+// @Router /folders/{folderID}/links [post]
+func (h *ShareLinkHandler) CreateFolderLink(c *gin.Context) {
+	h.create(c, entity.ShareLinkFolder, "folderID")
+}
+
+// @Security BearerAuth
+// @Summary List public links of a folder
+// @Tags share-links
+// @Produce json
+// @Param folderID path string true "Folder ID"
+// @Success 200 {array} dto.ShareLinkResponse
+// @Router /folders/{folderID}/links [get]
+func (h *ShareLinkHandler) ListFolderLinks(c *gin.Context) {
+	h.list(c, entity.ShareLinkFolder, "folderID")
+}
+
+// @Security BearerAuth
+// @Summary Revoke a public link of a folder
+// @Tags share-links
+// @Param folderID path string true "Folder ID"
+// @Param linkID path string true "Link ID"
+// @Router /folders/{folderID}/links/{linkID} [delete]
+func (h *ShareLinkHandler) RevokeFolderLink(c *gin.Context) {
+	h.revoke(c, entity.ShareLinkFolder, "folderID")
+}
+
+// @Security BearerAuth
+// @Summary List accesses of a public folder link
+// @Tags share-links
+// @Produce json
+// @Param folderID path string true "Folder ID"
+// @Param linkID path string true "Link ID"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 50, max 200)"
+// @Success 200 {object} dto.ShareLinkAccessesResponse
+// @Router /folders/{folderID}/links/{linkID}/accesses [get]
+func (h *ShareLinkHandler) ListFolderLinkAccesses(c *gin.Context) {
+	h.listAccesses(c, entity.ShareLinkFolder, "folderID")
+}
+
+// @Summary Open a public link
+// @Description Returns the shared note, or the shared folder with its sub-folders and notes. No authentication; password protected links need the X-Share-Password header.
+// @Tags share-links
+// @Produce json
+// @Param token path string true "Link token"
+// @Param X-Share-Password header string false "Link password"
+// @Success 200 {object} dto.SharedContentResponse
+// @Failure 401 {object} object "Password required or wrong"
+// @Failure 404 {object} object "Unknown or revoked link"
+// @Failure 410 {object} object "Expired link"
+// @Router /s/{token} [get]
+func (h *ShareLinkHandler) Open(c *gin.Context) {
+	content, err := h.shareLinkService.Open(c, c.Param("token"), c.GetHeader(ShareLinkPasswordHeader))
+	h.respondShared(c, content, err)
+}
+
+// @Summary Open a note inside a public folder link
+// @Tags share-links
+// @Produce json
+// @Param token path string true "Link token"
+// @Param noteID path string true "Note ID"
+// @Param X-Share-Password header string false "Link password"
+// @Success 200 {object} dto.SharedContentResponse
+// @Router /s/{token}/notes/{noteID} [get]
+func (h *ShareLinkHandler) OpenNote(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return
+	}
+
+	content, err := h.shareLinkService.OpenNote(c, c.Param("token"), c.GetHeader(ShareLinkPasswordHeader), noteID)
+	h.respondShared(c, content, err)
+}
+
+// @Summary Open a sub-folder inside a public folder link
+// @Tags share-links
+// @Produce json
+// @Param token path string true "Link token"
+// @Param folderID path string true "Folder ID"
+// @Param X-Share-Password header string false "Link password"
+// @Success 200 {object} dto.SharedContentResponse
+// @Router /s/{token}/folders/{folderID} [get]
+func (h *ShareLinkHandler) OpenFolder(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		return
+	}
+
+	content, err := h.shareLinkService.OpenFolder(c, c.Param("token"), c.GetHeader(ShareLinkPasswordHeader), folderID)
+	h.respondShared(c, content, err)
+}
+
+// respondShared không cho cache hay index nội dung, để link bị thu hồi hoặc hết hạn không còn xem được qua proxy/search engine
+func (h *ShareLinkHandler) respondShared(c *gin.Context, content *application.SharedContent, err error) {
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+	c.Header("Referrer-Policy", "no-referrer")
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToSharedContentResponse(content))
+}
+
+func (h *ShareLinkHandler) create(c *gin.Context, assetType entity.ShareLinkAssetType, param string) {
+	assetID, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + assetTypeLabel(assetType) + " ID"})
+		return
+	}
+
+	var request dto.CreateShareLinkRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+	}
+
+	link, err := h.shareLinkService.Create(c, assetType, assetID, request.Password, request.ExpiresAt)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToCreatedShareLinkResponse(link))
+}
+
+func (h *ShareLinkHandler) list(c *gin.Context, assetType entity.ShareLinkAssetType, param string) {
+	assetID, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + assetTypeLabel(assetType) + " ID"})
+		return
+	}
+
+	links, err := h.shareLinkService.List(c, assetType, assetID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToShareLinkResponses(links))
+}
+
+func (h *ShareLinkHandler) revoke(c *gin.Context, assetType entity.ShareLinkAssetType, param string) {
+	assetID, linkID, ok := parseShareLinkParams(c, assetType, param)
+	if !ok {
+		return
+	}
+
+	if err := h.shareLinkService.Revoke(c, assetType, assetID, linkID); err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *ShareLinkHandler) listAccesses(c *gin.Context, assetType entity.ShareLinkAssetType, param string) {
+	assetID, linkID, ok := parseShareLinkParams(c, assetType, param)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(application.DefaultShareLinkAccessPageSize)))
+
+	accesses, err := h.shareLinkService.ListAccesses(c, assetType, assetID, linkID, page, pageSize)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToShareLinkAccessesResponse(accesses))
+}
+
+func parseShareLinkParams(c *gin.Context, assetType entity.ShareLinkAssetType, param string) (uuid.UUID, uuid.UUID, bool) {
+	assetID, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + assetTypeLabel(assetType) + " ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	linkID, err := uuid.Parse(c.Param("linkID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid link ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return assetID, linkID, true
+}
+
+func assetTypeLabel(assetType entity.ShareLinkAssetType) string {
+	if assetType == entity.ShareLinkNote {
+		return "note"
+	}
+	return "folder"
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/interface/http/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TagHandler serves management of personal and team tags
+type TagHandler struct {
+	tagService *application.TagService
+}
+
+func NewTagHandler(service *application.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: service,
+	}
+}
+
+// @Security BearerAuth
+// @Summary List tags
+// @Description Personal tags of the current user and tags of the teams they belong to
+// @Tags tags
+// @Produce json
+// @Param teamId query string false "Only tags of this team"
+// @Success 200 {array} dto.TagResponse
+// @Router /tags [get]
+func (h *TagHandler) List(c *gin.Context) {
+	teamID, err := optionalUUIDQuery(c, "teamId")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tags, err := h.tagService.ListTags(c, teamID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToTagResponses(tags))
+}
+
+// @Security BearerAuth
+// @Summary Create a tag
+// @Description Create a personal tag, or a team tag when team_id is set (team managers only)
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param body body dto.CreateTagRequest true "Tag details"
+// @Success 201 {object} dto.TagResponse
+// @Router /tags [post]
+func (h *TagHandler) Create(c *gin.Context) {
+	var req dto.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := h.tagService.CreateTag(c, req.Name, req.Color, req.TeamID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToTagResponse(tag))
+}
+
+// @Security BearerAuth
+// @Summary Update a tag
+// @Description Rename or recolor a tag
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param tagID path string true "Tag ID"
+// @Param body body dto.UpdateTagRequest true "Fields to change"
+// @Success 200 {object} dto.TagResponse
+// @Router /tags/{tagID} [put]
+func (h *TagHandler) Update(c *gin.Context) {
+	tagID, err := uuid.Parse(c.Param("tagID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	var req dto.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := h.tagService.UpdateTag(c, tagID, req.Name, req.Color)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToTagResponse(tag))
+}
+
+// @Security BearerAuth
+// @Summary Delete a tag
+// @Description Delete a tag and remove it from every note
+// @Tags tags
+// @Param tagID path string true "Tag ID"
+// @Router /tags/{tagID} [delete]
+func (h *TagHandler) Delete(c *gin.Context) {
+	tagID, err := uuid.Parse(c.Param("tagID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tag ID"})
+		return
+	}
+
+	if err := h.tagService.DeleteTag(c, tagID); err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"collab-service/internal/application"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TeamActivityHandler serves the team activity feed
+type TeamActivityHandler struct {
+	activityService *application.TeamActivityService
+}
+
+func NewTeamActivityHandler(service *application.TeamActivityService) *TeamActivityHandler {
+	return &TeamActivityHandler{
+		activityService: service,
+	}
+}
+
+// @Security BearerAuth
+// @Summary Get team activity
+// @Description Paginated feed of membership and lifecycle changes on a team, newest first
+// @Tags teams
+// @Produce json
+// @Param id path string true "Team ID (UUID)"
+// @Param type query string false "Comma separated event types, e.g. MEMBER_ADDED,MEMBER_REMOVED"
+// @Param page query int false "Page number (default 1)"
+// @Param pageSize query int false "Page size (default 20, max 100)"
+// @Success 200 {object} application.TeamActivityPage
+// @Router /teams/{id}/activity [get]
+func (h *TeamActivityHandler) GetActivity(c *gin.Context) {
+	teamId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var eventTypes []string
+	for _, raw := range c.QueryArray("type") {
+		for _, t := range strings.Split(raw, ",") {
+			if t = strings.ToUpper(strings.TrimSpace(t)); t != "" {
+				eventTypes = append(eventTypes, t)
+			}
+		}
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", strconv.Itoa(application.DefaultActivityPageSize)))
+
+	activities, err := h.activityService.ListActivity(c, teamId, eventTypes, page, pageSize)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, activities)
+}
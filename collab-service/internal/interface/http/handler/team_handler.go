@@ -2,8 +2,14 @@ package handler
 
 import (
 	"collab-service/internal/application"
+	"collab-service/internal/domain/entity"
 	"collab-service/internal/interface/http/dto"
+	"encoding/csv"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -35,7 +41,7 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 		return
 	}
 
-	team, err := h.teamService.CreateTeam(c, request.TeamName, request.Managers, request.Members)
+	team, err := h.teamService.CreateTeam(c, request.TeamName, request.Managers, request.Members, request.ParentID)
 	if err != nil {
 		application.HandleError(c, err)
 		return
@@ -78,15 +84,76 @@ func (h *TeamHandler) GetByID(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.ToResponse(team))
 }
 
+// @Security BearerAuth
+// @Summary Get a team hierarchy
+// @Description Get a team together with all of its nested sub-teams
+// @Tags teams
+// @Produce json
+// @Param teamId path string true "Team ID (UUID)"
+// @Success 200 {object} dto.TeamTreeResponse "Team tree"
+// @Failure 400 {object} object "Bad request"
+// @Failure 403 {object} object "Forbidden"
+// @Failure 404 {object} object "Team not found"
+// @Router /teams/{teamId}/tree [get]
+func (h *TeamHandler) GetTree(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	tree, err := h.teamService.GetTeamTree(c, teamID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToTreeResponse(tree))
+}
+
+// @Security BearerAuth
+// @Summary Move a team in the hierarchy
+// @Description Set or clear the parent of a team. Moving a team beneath its own sub-team is rejected.
+// @Tags teams
+// @Accept json
+// @Produce json
+// @Param teamId path string true "Team ID (UUID)"
+// @Param request body dto.MoveTeamRequest true "Move team request"
+// @Router /teams/{teamId}/parent [put]
+func (h *TeamHandler) MoveTeam(c *gin.Context) {
+	var request dto.MoveTeamRequest
+	teamId, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	team, err := h.teamService.MoveTeam(c, teamId, request.ParentID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToResponse(team))
+}
+
 // @Security BearerAuth
 // @Summary Get all teams of the current user
-// @Description Get all teams that the current user is a member of
+// @Description Get all teams that the current user is a member of. Archived teams are hidden unless includeArchived=true.
 // @Tags teams
+// @Param includeArchived query bool false "Include archived teams"
 // @Success 200 {array} dto.TeamResponse "List of teams"
 // @Failure 500 {object} object "Internal server error"
 // @Router /teams [get]
 func (h *TeamHandler) GetAllByUserID(c *gin.Context) {
-	teams, err := h.teamService.GetAllTeamsOfUser(c)
+	includeArchived := c.Query("includeArchived") == "true"
+
+	teams, err := h.teamService.GetAllTeamsOfUser(c, includeArchived)
 	if err != nil {
 		application.HandleError(c, err)
 		return
@@ -122,7 +189,7 @@ func (h *TeamHandler) AddMembers(c *gin.Context) {
 		return
 	}
 
-	team, err := h.teamService.AddMembers(c, teamId, request.MemberIDs)
+	team, err := h.teamService.AddMembers(c, teamId, request.MemberIDs, request.Role, request.ExpiresAt)
 	if err != nil {
 		application.HandleError(c, err)
 		return
@@ -251,12 +318,13 @@ func (h *TeamHandler) UpdateTeam(c *gin.Context) {
 }
 
 // @Security BearerAuth
-// @Summary Delete a team
-// @Description Delete an existing team
+// @Summary Archive a team
+// @Description Archive an existing team. Archived teams are read-only and are purged after the retention period unless restored.
 // @Tags teams
+// @Param teamId path string true "Team ID (UUID)"
 // @Router /teams/{teamId} [delete]
 func (h *TeamHandler) DeleteTeam(c *gin.Context) {
-	id := c.Param("id")
+	id := c.Param("teamId")
 
 	// Parse param thành UUID
 	teamID, err := uuid.Parse(id)
@@ -265,10 +333,157 @@ func (h *TeamHandler) DeleteTeam(c *gin.Context) {
 		return
 	}
 
-	if err := h.teamService.DeleteTeam(c, teamID); err != nil {
+	if err := h.teamService.ArchiveTeam(c, teamID); err != nil {
 		application.HandleError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusNoContent, nil)
 }
+
+// @Security BearerAuth
+// @Summary Restore an archived team
+// @Description Restore an archived team. Only the team owner can restore it.
+// @Tags teams
+// @Produce json
+// @Param teamId path string true "Team ID (UUID)"
+// @Router /teams/{teamId}/restore [post]
+func (h *TeamHandler) RestoreTeam(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	team, err := h.teamService.RestoreTeam(c, teamID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToResponse(team))
+}
+
+// @Security BearerAuth
+// @Summary Import team members
+// @Description Add members from a CSV file (text/csv) or JSON payload of emails or user IDs with roles. Use dryRun=true to only get the report.
+// @Tags teams
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param teamId path string true "Team ID (UUID)"
+// @Param dryRun query bool false "Validate without applying changes"
+// @Param request body dto.ImportMembersRequest false "Members to import (JSON)"
+// @Router /teams/{teamId}/members/import [post]
+func (h *TeamHandler) ImportMembers(c *gin.Context) {
+	teamId, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var rows []application.MemberImportRow
+	if strings.HasPrefix(c.ContentType(), "text/csv") {
+		rows, err = dto.ParseMemberImportCSV(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	} else {
+		var request dto.ImportMembersRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+		rows = request.ToImportRows()
+	}
+
+	report, err := h.teamService.ImportMembers(c, teamId, rows, c.Query("dryRun") == "true")
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Security BearerAuth
+// @Summary Export team members
+// @Description Export the team roster with emails as CSV (default) or JSON
+// @Tags teams
+// @Produce json
+// @Produce text/csv
+// @Param id path string true "Team ID (UUID)"
+// @Param format query string false "csv or json"
+// @Router /teams/{id}/members/export [get]
+func (h *TeamHandler) ExportMembers(c *gin.Context) {
+	teamId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	members, err := h.teamService.ExportMembers(c, teamId)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	if c.Query("format") == "json" {
+		c.JSON(http.StatusOK, members)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=team-%s-members.csv", teamId))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{"user_id", "username", "email", "role", "expires_at"})
+	for _, m := range members {
+		expiresAt := ""
+		if m.ExpiresAt != nil {
+			expiresAt = m.ExpiresAt.Format(time.RFC3339)
+		}
+		_ = writer.Write([]string{m.UserID.String(), m.Username, m.Email, string(m.Role), expiresAt})
+	}
+	writer.Flush()
+}
+
+// @Security BearerAuth
+// @Summary List team members
+// @Description List team members with role, join date and user details, filtered by role and paginated
+// @Tags teams
+// @Produce json
+// @Param id path string true "Team ID (UUID)"
+// @Param role query string false "OWNER, MANAGER, MEMBER or GUEST"
+// @Param page query int false "Page number (default 1)"
+// @Param pageSize query int false "Page size (default 20, max 100)"
+// @Success 200 {object} dto.TeamMembersResponse
+// @Router /teams/{id}/members [get]
+func (h *TeamHandler) ListMembers(c *gin.Context) {
+	teamId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	role := entity.TeamAccessRole(strings.ToUpper(c.Query("role")))
+	switch role {
+	case "", entity.TeamOwner, entity.TeamManager, entity.TeamMember, entity.TeamGuest:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", strconv.Itoa(application.DefaultMemberPageSize)))
+
+	members, err := h.teamService.ListMembers(c, teamId, role, page, pageSize)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToMembersResponse(members))
+}
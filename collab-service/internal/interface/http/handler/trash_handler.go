@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/interface/http/dto"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TrashHandler serves the per-user trash bin of deleted folders and notes
+type TrashHandler struct {
+	trashService *application.TrashService
+}
+
+func NewTrashHandler(service *application.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: service,
+	}
+}
+
+// @Security BearerAuth
+// @Summary List trash
+// @Description Folders and notes the current user deleted or owns that are still restorable, most recently deleted first
+// @Tags trash
+// @Produce json
+// @Success 200 {array} application.TrashItem
+// @Router /trash [get]
+func (h *TrashHandler) List(c *gin.Context) {
+	items, err := h.trashService.List(c)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// @Security BearerAuth
+// @Summary Restore a note from the trash
+// @Description Restore a deleted note together with its shares. The containing folder must not be in the trash.
+// @Tags trash
+// @Produce json
+// @Param noteID path string true "Note ID"
+// @Router /trash/notes/{noteID}/restore [post]
+func (h *TrashHandler) RestoreNote(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return
+	}
+
+	note, err := h.trashService.RestoreNote(c, noteID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
+
+// @Security BearerAuth
+// @Summary Restore a folder from the trash
+// @Description Restore a deleted folder with the sub-folders, notes and shares that were deleted along with it
+// @Tags trash
+// @Produce json
+// @Param folderID path string true "Folder ID"
+// @Success 200 {object} dto.FolderResponse
+// @Router /trash/folders/{folderID}/restore [post]
+func (h *TrashHandler) RestoreFolder(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		return
+	}
+
+	folder, err := h.trashService.RestoreFolder(c, folderID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToFolderResponse(folder))
+}
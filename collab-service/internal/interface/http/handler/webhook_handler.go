@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"collab-service/internal/application"
+	"collab-service/internal/interface/http/dto"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookHandler lets team managers register outbound webhooks for their team
+type WebhookHandler struct {
+	webhookService *application.WebhookService
+}
+
+func NewWebhookHandler(service *application.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: service,
+	}
+}
+
+// @Security BearerAuth
+// @Summary List team webhooks
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Team ID (UUID)"
+// @Success 200 {array} dto.WebhookResponse
+// @Router /teams/{id}/webhooks [get]
+func (h *WebhookHandler) List(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	webhooks, err := h.webhookService.List(c, teamID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToWebhookResponses(webhooks))
+}
+
+// @Security BearerAuth
+// @Summary Get a team webhook
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Team ID (UUID)"
+// @Param webhookId path string true "Webhook ID (UUID)"
+// @Success 200 {object} dto.WebhookResponse
+// @Router /teams/{id}/webhooks/{webhookId} [get]
+func (h *WebhookHandler) Get(c *gin.Context) {
+	teamID, webhookID, ok := parseWebhookParams(c, "id")
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.Get(c, teamID, webhookID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToWebhookResponse(webhook))
+}
+
+// @Security BearerAuth
+// @Summary Register a team webhook
+// @Description Deliveries are signed with HMAC-SHA256 of "timestamp.body" using the secret, sent in the X-Webhook-Signature header. The secret is only returned here and when rotated.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param teamId path string true "Team ID (UUID)"
+// @Param request body dto.CreateWebhookRequest true "Webhook"
+// @Success 201 {object} dto.WebhookSecretResponse
+// @Router /teams/{teamId}/webhooks [post]
+func (h *WebhookHandler) Create(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var request dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	webhook, err := h.webhookService.Create(c, teamID, request.URL, request.EventTypes, request.Secret)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToWebhookSecretResponse(webhook))
+}
+
+// @Security BearerAuth
+// @Summary Update a team webhook
+// @Description Change the URL or event types, or enable/disable the webhook. Re-enabling resets the failure count.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param teamId path string true "Team ID (UUID)"
+// @Param webhookId path string true "Webhook ID (UUID)"
+// @Param request body dto.UpdateWebhookRequest true "Fields to change"
+// @Success 200 {object} dto.WebhookResponse
+// @Router /teams/{teamId}/webhooks/{webhookId} [put]
+func (h *WebhookHandler) Update(c *gin.Context) {
+	teamID, webhookID, ok := parseWebhookParams(c, "teamId")
+	if !ok {
+		return
+	}
+
+	var request dto.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	webhook, err := h.webhookService.Update(c, teamID, webhookID, request.URL, request.EventTypes, request.Active)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToWebhookResponse(webhook))
+}
+
+// @Security BearerAuth
+// @Summary Rotate a webhook secret
+// @Tags webhooks
+// @Produce json
+// @Param teamId path string true "Team ID (UUID)"
+// @Param webhookId path string true "Webhook ID (UUID)"
+// @Success 200 {object} dto.WebhookSecretResponse
+// @Router /teams/{teamId}/webhooks/{webhookId}/rotate-secret [post]
+func (h *WebhookHandler) RotateSecret(c *gin.Context) {
+	teamID, webhookID, ok := parseWebhookParams(c, "teamId")
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.RotateSecret(c, teamID, webhookID)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToWebhookSecretResponse(webhook))
+}
+
+// @Security BearerAuth
+// @Summary Delete a team webhook
+// @Tags webhooks
+// @Param teamId path string true "Team ID (UUID)"
+// @Param webhookId path string true "Webhook ID (UUID)"
+// @Router /teams/{teamId}/webhooks/{webhookId} [delete]
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	teamID, webhookID, ok := parseWebhookParams(c, "teamId")
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.Delete(c, teamID, webhookID); err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// @Security BearerAuth
+// @Summary List webhook deliveries
+// @Description Delivery log of a webhook with status, attempts and the last response, newest first
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Team ID (UUID)"
+// @Param webhookId path string true "Webhook ID (UUID)"
+// @Param page query int false "Page number (default 1)"
+// @Param pageSize query int false "Page size (default 20, max 100)"
+// @Success 200 {object} dto.WebhookDeliveriesResponse
+// @Router /teams/{id}/webhooks/{webhookId}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	teamID, webhookID, ok := parseWebhookParams(c, "id")
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", strconv.Itoa(application.DefaultWebhookDeliveryPageSize)))
+
+	deliveries, err := h.webhookService.ListDeliveries(c, teamID, webhookID, page, pageSize)
+	if err != nil {
+		application.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToWebhookDeliveriesResponse(deliveries))
+}
+
+// parseWebhookParams đọc team ID (tên param khác nhau giữa route đọc và ghi) và webhook ID từ path
+func parseWebhookParams(c *gin.Context, teamParam string) (uuid.UUID, uuid.UUID, bool) {
+	teamID, err := uuid.Parse(c.Param(teamParam))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	webhookID, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return teamID, webhookID, true
+}
@@ -16,6 +16,12 @@ func AuthMiddleware() gin.HandlerFunc {
 
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" && c.IsWebsocket() {
+			// Trình duyệt không đặt được header cho WebSocket, cho phép truyền token qua query
+			if token := c.Query("token"); token != "" {
+				authHeader = "Bearer " + token
+			}
+		}
 		if authHeader == "" {
 			c.AbortWithStatusJSON(401, gin.H{"error": "Unauthorized: No Authorization header"})
 			return
@@ -27,6 +27,14 @@ func SetupRoutes() *gin.Engine {
 	bootstrap.InitTeamModule(router, database.GetDB())
 	bootstrap.InitFolderModule(router, database.GetDB())
 	bootstrap.InitNoteModule(router, database.GetDB())
+	bootstrap.InitShareLinkModule(router, database.GetDB())
+	bootstrap.InitShareExpiryModule(database.GetDB())
+	bootstrap.InitAccessRequestModule(router, database.GetDB())
+	bootstrap.InitTrashModule(router, database.GetDB())
+	bootstrap.InitSearchModule(router, database.GetDB())
+	bootstrap.InitTagModule(router, database.GetDB())
+	bootstrap.InitWebhookModule(router, database.GetDB())
+	bootstrap.InitNotificationModule(router)
 	bootstrap.InitManagerModule(router, database.GetDB())
 	bootstrap.InitUserModule(router)
 
@@ -78,7 +78,7 @@ type ComplexityRoot struct {
 		ParseToken func(childComplexity int, accessToken string) int
 		Ping       func(childComplexity int) int
 		User       func(childComplexity int, userID string) int
-		Users      func(childComplexity int, role *model.UserType, userIds []string) int
+		Users      func(childComplexity int, role *model.UserType, userIds []string, emails []string, usernames []string) int
 	}
 
 	User struct {
@@ -122,7 +122,7 @@ type MutationResolver interface {
 	AssignRole(ctx context.Context, userID string, role model.UserType) (*model.UserMutationResponse, error)
 }
 type QueryResolver interface {
-	Users(ctx context.Context, role *model.UserType, userIds []string) (*model.UsersQueryRespone, error)
+	Users(ctx context.Context, role *model.UserType, userIds []string, emails []string, usernames []string) (*model.UsersQueryRespone, error)
 	User(ctx context.Context, userID string) (*model.UserQueryResponse, error)
 	ParseToken(ctx context.Context, accessToken string) (*model.UserQueryResponse, error)
 	Ping(ctx context.Context) (model.QueryRespone, error)
@@ -325,7 +325,7 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 			return 0, false
 		}
 
-		return e.complexity.Query.Users(childComplexity, args["role"].(*model.UserType), args["userIds"].([]string)), true
+		return e.complexity.Query.Users(childComplexity, args["role"].(*model.UserType), args["userIds"].([]string), args["emails"].([]string), args["usernames"].([]string)), true
 
 	case "User.createdAt":
 		if e.complexity.User.CreatedAt == nil {
@@ -660,7 +660,7 @@ type AuthMutationResponse implements MutationResponse {
 }
 
 type Query {
-  users(role: UserType, userIds: [ID!]): UsersQueryRespone
+  users(role: UserType, userIds: [ID!], emails: [String!], usernames: [String!]): UsersQueryRespone
   user(userId: ID!): UserQueryResponse
   parseToken(accessToken: String!): UserQueryResponse
   ping: QueryRespone
@@ -801,6 +801,16 @@ func (ec *executionContext) field_Query_users_args(ctx context.Context, rawArgs
 		return nil, err
 	}
 	args["userIds"] = arg1
+	arg2, err := graphql.ProcessArgField(ctx, rawArgs, "emails", ec.unmarshalOString2ᚕstringᚄ)
+	if err != nil {
+		return nil, err
+	}
+	args["emails"] = arg2
+	arg3, err := graphql.ProcessArgField(ctx, rawArgs, "usernames", ec.unmarshalOString2ᚕstringᚄ)
+	if err != nil {
+		return nil, err
+	}
+	args["usernames"] = arg3
 	return args, nil
 }
 
@@ -1697,7 +1707,7 @@ func (ec *executionContext) _Query_users(ctx context.Context, field graphql.Coll
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (any, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Query().Users(rctx, fc.Args["role"].(*model.UserType), fc.Args["userIds"].([]string))
+		return ec.resolvers.Query().Users(rctx, fc.Args["role"].(*model.UserType), fc.Args["userIds"].([]string), fc.Args["emails"].([]string), fc.Args["usernames"].([]string))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -6361,6 +6371,24 @@ func (ec *executionContext) marshalOQueryRespone2userᚑserviceᚋinternalᚋgra
 	return ec._QueryRespone(ctx, sel, v)
 }
 
+func (ec *executionContext) unmarshalOString2ᚕstringᚄ(ctx context.Context, v any) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var vSlice []any
+	vSlice = graphql.CoerceList(v)
+	var err error
+	res := make([]string, len(vSlice))
+	for i := range vSlice {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
+		res[i], err = ec.unmarshalNString2string(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
 func (ec *executionContext) unmarshalOString2ᚕᚖstring(ctx context.Context, v any) ([]*string, error) {
 	if v == nil {
 		return nil, nil
@@ -248,8 +248,8 @@ func (r *mutationResolver) AssignRole(ctx context.Context, userID string, role m
 }
 
 // Users is the resolver for the users field.
-func (r *queryResolver) Users(ctx context.Context, role *model.UserType, userIds []string) (*model.UsersQueryRespone, error) {
-	users, err := r.Repository.QueryUsers(role, userIds)
+func (r *queryResolver) Users(ctx context.Context, role *model.UserType, userIds []string, emails []string, usernames []string) (*model.UsersQueryRespone, error) {
+	users, err := r.Repository.QueryUsers(role, userIds, emails, usernames)
 	if err != nil {
 		logger.Error("Failed to query users", err)
 		return nil, fmt.Errorf("failed to query users: %w", err)
@@ -1,6 +1,7 @@
 package persistence
 
 import (
+	"strings"
 	"user-service/internal/graphql/model"
 
 	"gorm.io/gorm"
@@ -63,7 +64,7 @@ func (r *UserRepository) GetUserByRole(role model.UserType) ([]*model.User, erro
 	return result, nil
 }
 
-func (r *UserRepository) QueryUsers(role *model.UserType, userIDs []string) ([]*model.User, error) {
+func (r *UserRepository) QueryUsers(role *model.UserType, userIDs []string, emails []string, usernames []string) ([]*model.User, error) {
 	var users []*User
 	query := r.db.Model(&User{})
 	if role != nil {
@@ -72,6 +73,20 @@ func (r *UserRepository) QueryUsers(role *model.UserType, userIDs []string) ([]*
 	if len(userIDs) > 0 {
 		query = query.Where("id IN ?", userIDs)
 	}
+	if len(emails) > 0 {
+		lowered := make([]string, len(emails))
+		for i, email := range emails {
+			lowered[i] = strings.ToLower(strings.TrimSpace(email))
+		}
+		query = query.Where("LOWER(email) IN ?", lowered)
+	}
+	if len(usernames) > 0 {
+		lowered := make([]string, len(usernames))
+		for i, username := range usernames {
+			lowered[i] = strings.ToLower(strings.TrimSpace(username))
+		}
+		query = query.Where("LOWER(username) IN ?", lowered)
+	}
 	if err := query.Find(&users).Error; err != nil {
 		return nil, err
 	}